@@ -21,6 +21,8 @@ func (h *GatewayHandler) ProductProxy(c *fiber.Ctx) error {
 	return proxy.Do(c, productServiceURL+c.OriginalURL())
 }
 
+// TransactionProxy meneruskan request apa adanya, termasuk header Idempotency-Key,
+// sehingga retry dari POS lewat gateway tetap diproses sekali oleh transaction-service.
 func (h *GatewayHandler) TransactionProxy(c *fiber.Ctx) error {
 	transactionServiceURL := os.Getenv("TRANSACTION_SERVICE_URL")
 	if transactionServiceURL == "" {
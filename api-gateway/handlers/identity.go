@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// header identitas yang dibaca service di belakang gateway. Nilainya hanya boleh berasal dari gateway.
+const (
+	UserIDHeader   = "X-User-ID"
+	UserRoleHeader = "X-User-Role"
+)
+
+type Identity struct {
+	UserID string
+	Role   string
+}
+
+// ParseIdentities membaca daftar token dengan format "token:user_id:role,token:user_id:role"
+func ParseIdentities(spec string) (map[string]Identity, error) {
+	identities := make(map[string]Identity)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid identity entry '%s', expected token:user_id:role", entry)
+		}
+		token := strings.TrimSpace(parts[0])
+		identity := Identity{
+			UserID: strings.TrimSpace(parts[1]),
+			Role:   strings.ToUpper(strings.TrimSpace(parts[2])),
+		}
+		if token == "" || identity.UserID == "" || identity.Role == "" {
+			return nil, fmt.Errorf("invalid identity entry '%s', token, user_id and role are required", entry)
+		}
+		if _, exists := identities[token]; exists {
+			return nil, fmt.Errorf("duplicate identity token for user '%s'", identity.UserID)
+		}
+		identities[token] = identity
+	}
+	return identities, nil
+}
+
+// IdentityMiddleware membuang X-User-ID/X-User-Role dari client lalu mengisinya dari token Bearer yang dikenal,
+// sehingga service di belakang gateway tidak pernah menerima identitas buatan client
+func IdentityMiddleware(identities map[string]Identity) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Request().Header.Del(UserIDHeader)
+		c.Request().Header.Del(UserRoleHeader)
+
+		authorization := strings.TrimSpace(c.Get(fiber.HeaderAuthorization))
+		if authorization == "" {
+			return c.Next()
+		}
+
+		token, found := strings.CutPrefix(authorization, "Bearer ")
+		identity, known := identities[strings.TrimSpace(token)]
+		if !found || !known {
+			return c.Status(401).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid or unknown bearer token",
+			})
+		}
+
+		// token hanya dipakai di gateway
+		c.Request().Header.Del(fiber.HeaderAuthorization)
+		c.Request().Header.Set(UserIDHeader, identity.UserID)
+		c.Request().Header.Set(UserRoleHeader, identity.Role)
+		return c.Next()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"api-gateway/handlers"
 	"api-gateway/routes"
 	"log"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -12,9 +13,19 @@ import (
 func main() {
 	app := fiber.New()
 
+	// identitas operator berasal dari token di GATEWAY_USERS, bukan dari header client
+	identities, err := handlers.ParseIdentities(os.Getenv("GATEWAY_USERS"))
+	if err != nil {
+		log.Fatalf("Invalid GATEWAY_USERS: %v", err)
+	}
+
 	// Middleware
-	app.Use(cors.New())
+	app.Use(cors.New(cors.Config{
+		AllowHeaders:  "Origin,Content-Type,Accept,Authorization,Idempotency-Key",
+		ExposeHeaders: "Idempotent-Replayed",
+	}))
 	app.Use(handlers.LoggingMiddleware)
+	app.Use(handlers.IdentityMiddleware(identities))
 
 	// Routes
 	routes.SetupRoutes(app)
@@ -12,10 +12,31 @@ func SetupRoutes(app *fiber.App) {
 	products := app.Group("/api/products")
 	products.Use(gatewayHandler.ProductProxy)
 
+	reservations := app.Group("/api/reservations")
+	reservations.Use(gatewayHandler.ProductProxy)
+
+	categories := app.Group("/api/categories")
+	categories.Use(gatewayHandler.ProductProxy)
+
 	// Transaction service routes
 	transactions := app.Group("/api/transactions")
 	transactions.Use(gatewayHandler.TransactionProxy)
 
+	carts := app.Group("/api/carts")
+	carts.Use(gatewayHandler.TransactionProxy)
+
+	promotions := app.Group("/api/promotions")
+	promotions.Use(gatewayHandler.TransactionProxy)
+
+	vouchers := app.Group("/api/vouchers")
+	vouchers.Use(gatewayHandler.TransactionProxy)
+
+	taxRates := app.Group("/api/tax-rates")
+	taxRates.Use(gatewayHandler.TransactionProxy)
+
+	businessDays := app.Group("/api/business-days")
+	businessDays.Use(gatewayHandler.TransactionProxy)
+
 	// Reporting service routes
 	reports := app.Group("/api/reports")
 	reports.Use(gatewayHandler.TransactionProxy)
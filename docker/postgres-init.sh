@@ -0,0 +1,9 @@
+#!/bin/bash
+# membuat satu database per service (hanya saat volume db masih kosong).
+# Skema dibuat oleh masing-masing service saat start lewat migrasi yang di-embed.
+set -e
+
+for service in product transaction; do
+	psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" \
+		-c "CREATE DATABASE mini_pos_${service};"
+done
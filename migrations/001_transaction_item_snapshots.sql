@@ -0,0 +1,32 @@
+-- Upgrade: snapshot nama produk, SKU dan harga satuan di transaction_items
+-- Jalankan sekali pada database yang dibuat dengan migration.sql versi lama.
+
+BEGIN;
+
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS product_name VARCHAR(100);
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS product_sku VARCHAR(64);
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS unit_price DECIMAL(15,2);
+
+-- Backfill baris lama. Harga satuan diturunkan dari subtotal (harga yang benar-benar dibayar),
+-- nama diambil dari data produk terakhir karena nama saat transaksi tidak pernah disimpan.
+UPDATE transaction_items ti
+SET unit_price = ROUND(ti.subtotal / ti.quantity, 2)
+WHERE ti.unit_price IS NULL;
+
+UPDATE transaction_items ti
+SET product_name = p.name
+FROM products p
+WHERE p.id = ti.product_id
+    AND ti.product_name IS NULL;
+
+UPDATE transaction_items
+SET product_name = 'Product ID ' || product_id
+WHERE product_name IS NULL;
+
+ALTER TABLE transaction_items ALTER COLUMN product_name SET NOT NULL;
+ALTER TABLE transaction_items ALTER COLUMN unit_price SET NOT NULL;
+
+ALTER TABLE transaction_items DROP CONSTRAINT IF EXISTS chk_transaction_items_unit_price;
+ALTER TABLE transaction_items ADD CONSTRAINT chk_transaction_items_unit_price CHECK (unit_price >= 0);
+
+COMMIT;
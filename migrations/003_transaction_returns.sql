@@ -0,0 +1,103 @@
+-- Upgrade: retur/refund dengan restock otomatis
+
+BEGIN;
+
+-- tabel transaction_returns (retur / refund atas transaksi)
+CREATE TABLE IF NOT EXISTS transaction_returns (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL,
+    reason_code VARCHAR(30) NOT NULL,
+    reason_note VARCHAR(255) NULL,
+    total_amount DECIMAL(15,2) NOT NULL CHECK (total_amount >= 0),
+    return_date TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_transaction_returns_transaction_id
+        FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+
+-- tabel transaction_return_items
+CREATE TABLE IF NOT EXISTS transaction_return_items (
+    id SERIAL PRIMARY KEY,
+    return_id INTEGER NOT NULL,
+    transaction_item_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    product_name VARCHAR(100) NOT NULL,
+    unit_price DECIMAL(15,2) NOT NULL CHECK (unit_price >= 0),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_transaction_return_items_return_id
+        FOREIGN KEY (return_id) REFERENCES transaction_returns(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transaction_return_items_transaction_item_id
+        FOREIGN KEY (transaction_item_id) REFERENCES transaction_items(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_returns_transaction_id ON transaction_returns(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_returns_return_date ON transaction_returns(return_date);
+CREATE INDEX IF NOT EXISTS idx_transaction_return_items_return_id ON transaction_return_items(return_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_return_items_transaction_item_id ON transaction_return_items(transaction_item_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_return_items_product_id ON transaction_return_items(product_id);
+
+DROP TRIGGER IF EXISTS trigger_transaction_returns_updated_at ON transaction_returns;
+CREATE TRIGGER trigger_transaction_returns_updated_at
+    BEFORE UPDATE ON transaction_returns
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS trigger_transaction_return_items_updated_at ON transaction_return_items;
+CREATE TRIGGER trigger_transaction_return_items_updated_at
+    BEFORE UPDATE ON transaction_return_items
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP VIEW IF EXISTS v_transaction_summary;
+DROP VIEW IF EXISTS v_product_sales_report;
+
+-- View untuk summary transaksi (refund tercatat sebagai revenue negatif)
+CREATE VIEW v_transaction_summary AS
+SELECT 
+    t.id,
+    t.transaction_date,
+    t.total_amount,
+    COUNT(ti.id) as total_items,
+    SUM(ti.quantity) as total_quantity,
+    COALESCE(r.refunded_amount, 0) as refunded_amount,
+    t.total_amount - COALESCE(r.refunded_amount, 0) as net_amount
+FROM transactions t
+LEFT JOIN transaction_items ti ON t.id = ti.transaction_id
+LEFT JOIN (
+    SELECT transaction_id, SUM(total_amount) as refunded_amount
+    FROM transaction_returns
+    GROUP BY transaction_id
+) r ON r.transaction_id = t.id
+WHERE t.deleted_at IS NULL
+GROUP BY t.id, t.transaction_date, t.total_amount, r.refunded_amount
+ORDER BY t.transaction_date DESC;
+
+-- View untuk laporan penjualan produk (total_revenue sudah dikurangi refund)
+CREATE VIEW v_product_sales_report AS
+SELECT 
+    p.id,
+    p.name as product_name,
+    p.price as current_price,
+    p.stock as current_stock,
+    COALESCE(SUM(ti.quantity), 0) as total_sold,
+    COALESCE(ri.total_returned, 0) as total_returned,
+    COALESCE(ri.total_refunded, 0) as total_refunded,
+    COALESCE(SUM(ti.subtotal), 0) - COALESCE(ri.total_refunded, 0) as total_revenue
+FROM products p
+LEFT JOIN transaction_items ti ON p.id = ti.product_id
+LEFT JOIN transactions t ON ti.transaction_id = t.id
+LEFT JOIN (
+    SELECT product_id, SUM(quantity) as total_returned, SUM(amount) as total_refunded
+    FROM transaction_return_items
+    GROUP BY product_id
+) ri ON ri.product_id = p.id
+WHERE p.deleted_at IS NULL 
+    AND (t.deleted_at IS NULL OR t.deleted_at IS NOT NULL)
+GROUP BY p.id, p.name, p.price, p.stock, ri.total_returned, ri.total_refunded
+ORDER BY total_sold DESC;
+
+COMMIT;
@@ -0,0 +1,33 @@
+-- Upgrade: pembayaran multi-tender dan kembalian
+-- Transaksi lama tidak memiliki data tender sehingga tidak muncul di laporan per metode pembayaran.
+
+BEGIN;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS paid_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS change_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+-- tabel transaction_payments (satu atau lebih tender per transaksi)
+CREATE TABLE IF NOT EXISTS transaction_payments (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL,
+    method VARCHAR(20) NOT NULL
+        CHECK (method IN ('CASH', 'DEBIT_CARD', 'CREDIT_CARD', 'QRIS', 'E_WALLET', 'BANK_TRANSFER')),
+    tendered_amount DECIMAL(15,2) NOT NULL CHECK (tendered_amount > 0),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount >= 0 AND amount <= tendered_amount),
+    reference VARCHAR(100) NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_transaction_payments_transaction_id
+        FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_payments_transaction_id ON transaction_payments(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_payments_method ON transaction_payments(method);
+
+DROP TRIGGER IF EXISTS trigger_transaction_payments_updated_at ON transaction_payments;
+CREATE TRIGGER trigger_transaction_payments_updated_at
+    BEFORE UPDATE ON transaction_payments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
@@ -0,0 +1,51 @@
+-- Upgrade: promosi otomatis (buy X get Y, harga paket, potongan persen)
+-- Transaksi lama tidak memakai promosi, sehingga promotion_discount_amount = 0.
+
+BEGIN;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS promotion_discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS promotion_discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('BUY_X_GET_Y', 'BUNDLE_PRICE', 'PERCENT_OFF')),
+    rule JSONB NOT NULL DEFAULT '{}',
+    priority INTEGER NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    starts_at TIMESTAMP WITH TIME ZONE NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NULL,
+    days_of_week INTEGER[] NOT NULL DEFAULT '{}',
+    start_time TIME NULL,
+    end_time TIME NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+CREATE TABLE IF NOT EXISTS transaction_item_promotions (
+    id SERIAL PRIMARY KEY,
+    transaction_item_id INTEGER NOT NULL,
+    promotion_id INTEGER NOT NULL,
+    promotion_name VARCHAR(100) NOT NULL,
+    discount_amount DECIMAL(15,2) NOT NULL CHECK (discount_amount >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_transaction_item_promotions_transaction_item_id
+        FOREIGN KEY (transaction_item_id) REFERENCES transaction_items(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transaction_item_promotions_promotion_id
+        FOREIGN KEY (promotion_id) REFERENCES promotions(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_active ON promotions(active) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_transaction_item_promotions_transaction_item_id ON transaction_item_promotions(transaction_item_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_item_promotions_promotion_id ON transaction_item_promotions(promotion_id);
+
+DROP TRIGGER IF EXISTS trigger_promotions_updated_at ON promotions;
+CREATE TRIGGER trigger_promotions_updated_at
+    BEFORE UPDATE ON promotions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
@@ -0,0 +1,53 @@
+-- Upgrade: voucher / kupon dengan batas pemakaian
+-- Transaksi lama tidak memakai voucher.
+
+BEGIN;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS customer_id VARCHAR(100) NULL;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS voucher_code VARCHAR(50) NULL;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS voucher_discount_amount DECIMAL(15,2) NULL;
+
+-- tabel vouchers (kode voucher/kupon, dibuat satuan atau per batch)
+CREATE TABLE IF NOT EXISTS vouchers (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    batch_code VARCHAR(50) NULL,
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('PERCENTAGE', 'FIXED')),
+    value DECIMAL(15,2) NOT NULL CHECK (value > 0),
+    max_discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (max_discount_amount >= 0), -- 0 = tanpa batas
+    min_spend DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (min_spend >= 0),
+    expires_at TIMESTAMP WITH TIME ZONE NULL,
+    usage_limit INTEGER NOT NULL DEFAULT 1 CHECK (usage_limit >= 0), -- 0 = tanpa batas
+    per_customer_limit INTEGER NOT NULL DEFAULT 0 CHECK (per_customer_limit >= 0), -- 0 = tanpa batas
+    used_count INTEGER NOT NULL DEFAULT 0 CHECK (used_count >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- tabel voucher_redemptions (pemakaian voucher per transaksi, released_at diisi saat transaksi di-void)
+CREATE TABLE IF NOT EXISTS voucher_redemptions (
+    id SERIAL PRIMARY KEY,
+    voucher_id INTEGER NOT NULL,
+    transaction_id INTEGER NOT NULL,
+    customer_id VARCHAR(100) NULL,
+    discount_amount DECIMAL(15,2) NOT NULL CHECK (discount_amount >= 0),
+    redeemed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP WITH TIME ZONE NULL,
+    CONSTRAINT fk_voucher_redemptions_voucher_id
+        FOREIGN KEY (voucher_id) REFERENCES vouchers(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_voucher_redemptions_transaction_id
+        FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_vouchers_batch_code ON vouchers(batch_code);
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_voucher_customer ON voucher_redemptions(voucher_id, customer_id);
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_transaction_id ON voucher_redemptions(transaction_id);
+
+DROP TRIGGER IF EXISTS trigger_vouchers_updated_at ON vouchers;
+CREATE TRIGGER trigger_vouchers_updated_at
+    BEFORE UPDATE ON vouchers
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
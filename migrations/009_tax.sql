@@ -0,0 +1,106 @@
+-- Upgrade: pajak (PPN) per baris dengan harga termasuk / belum termasuk pajak
+-- Transaksi lama tercatat tanpa pajak: kelas EXEMPT tarif 0, DPP = subtotal, total baris = subtotal.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS tax_rates (
+    tax_class VARCHAR(30) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    rate DECIMAL(5,2) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS trigger_tax_rates_updated_at ON tax_rates;
+CREATE TRIGGER trigger_tax_rates_updated_at
+    BEFORE UPDATE ON tax_rates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO tax_rates (tax_class, name, rate) VALUES
+('STANDARD', 'PPN 11%', 11.00),
+('EXEMPT', 'Bebas PPN', 0.00)
+ON CONFLICT (tax_class) DO NOTHING;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tax_class VARCHAR(30) NOT NULL DEFAULT 'STANDARD';
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS prices_include_tax BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS tax_class VARCHAR(30) NOT NULL DEFAULT 'EXEMPT';
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS taxable_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS total_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE transaction_return_items ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+UPDATE transaction_items SET taxable_amount = subtotal, total_amount = subtotal WHERE total_amount = 0;
+
+DROP VIEW IF EXISTS v_transaction_summary;
+DROP VIEW IF EXISTS v_product_sales_report;
+
+-- View untuk summary transaksi (refund tercatat sebagai revenue negatif, transaksi void bernilai net 0)
+CREATE VIEW v_transaction_summary AS
+SELECT 
+    t.id,
+    t.transaction_date,
+    t.gross_amount,
+    t.discount_amount,
+    t.tax_amount,
+    t.total_amount,
+    COUNT(ti.id) as total_items,
+    SUM(ti.quantity) as total_quantity,
+    COALESCE(r.refunded_amount, 0) as refunded_amount,
+    CASE
+        WHEN t.voided_at IS NOT NULL THEN 0
+        ELSE t.total_amount - COALESCE(r.refunded_amount, 0)
+    END as net_amount,
+    CASE WHEN t.voided_at IS NOT NULL THEN 'VOIDED' ELSE 'COMPLETED' END as status
+FROM transactions t
+LEFT JOIN transaction_items ti ON t.id = ti.transaction_id
+LEFT JOIN (
+    SELECT transaction_id, SUM(total_amount) as refunded_amount
+    FROM transaction_returns
+    GROUP BY transaction_id
+) r ON r.transaction_id = t.id
+WHERE t.deleted_at IS NULL
+GROUP BY t.id, t.transaction_date, t.gross_amount, t.discount_amount, t.tax_amount, t.total_amount, t.voided_at, r.refunded_amount
+ORDER BY t.transaction_date DESC;
+
+-- View untuk laporan penjualan produk (total_revenue net setelah diskon dan refund, transaksi void dihitung terpisah)
+CREATE VIEW v_product_sales_report AS
+SELECT 
+    p.id,
+    p.name as product_name,
+    p.price as current_price,
+    p.stock as current_stock,
+    COALESCE(s.total_sold, 0) as total_sold,
+    COALESCE(s.total_discount, 0) as total_discount,
+    COALESCE(ri.total_returned, 0) as total_returned,
+    COALESCE(ri.total_refunded, 0) as total_refunded,
+    COALESCE(s.gross_revenue, 0) - COALESCE(ri.total_refunded, 0) as total_revenue,
+    COALESCE(s.total_voided, 0) as total_voided
+FROM products p
+LEFT JOIN (
+    SELECT 
+        ti.product_id,
+        SUM(ti.quantity) FILTER (WHERE t.voided_at IS NULL) as total_sold,
+        SUM(ti.total_amount) FILTER (WHERE t.voided_at IS NULL) as gross_revenue,
+        SUM(ti.discount_amount) FILTER (WHERE t.voided_at IS NULL) as total_discount,
+        SUM(ti.quantity) FILTER (WHERE t.voided_at IS NOT NULL) as total_voided
+    FROM transaction_items ti
+    JOIN transactions t ON t.id = ti.transaction_id
+    WHERE t.deleted_at IS NULL
+    GROUP BY ti.product_id
+) s ON s.product_id = p.id
+LEFT JOIN (
+    SELECT product_id, SUM(quantity) as total_returned, SUM(amount) as total_refunded
+    FROM transaction_return_items
+    GROUP BY product_id
+) ri ON ri.product_id = p.id
+WHERE p.deleted_at IS NULL
+ORDER BY total_sold DESC;
+
+COMMIT;
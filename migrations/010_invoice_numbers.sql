@@ -0,0 +1,38 @@
+-- Upgrade: nomor invoice berurutan tanpa celah per toko & hari
+-- Transaksi lama diberi nomor dengan pola bawaan INV/{STORE}/{YYYYMMDD}/{SEQ:4} dan STORE_CODE
+-- STORE01, urut berdasarkan id per tanggal transaksi (zona waktu session database). Jika
+-- INVOICE_NUMBER_PATTERN atau STORE_CODE diubah, nomor lama tetap dan nomor baru memakai sequence
+-- key baru.
+
+BEGIN;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS invoice_number VARCHAR(100) NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_transactions_invoice_number ON transactions(invoice_number);
+
+-- tabel invoice_sequences (nomor urut terakhir per sequence key, mis. INV/STORE01/20261017/{SEQ:4})
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    sequence_key VARCHAR(150) PRIMARY KEY,
+    last_number BIGINT NOT NULL CHECK (last_number > 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+WITH numbered AS (
+    SELECT id,
+        TO_CHAR(transaction_date, 'YYYYMMDD') AS day,
+        ROW_NUMBER() OVER (PARTITION BY TO_CHAR(transaction_date, 'YYYYMMDD') ORDER BY id) AS seq
+    FROM transactions
+    WHERE invoice_number IS NULL
+)
+UPDATE transactions t
+SET invoice_number = 'INV/STORE01/' || n.day || '/' || LPAD(n.seq::TEXT, 4, '0')
+FROM numbered n
+WHERE t.id = n.id;
+
+INSERT INTO invoice_sequences (sequence_key, last_number)
+SELECT 'INV/STORE01/' || SPLIT_PART(invoice_number, '/', 3) || '/{SEQ:4}', MAX(SPLIT_PART(invoice_number, '/', 4)::BIGINT)
+FROM transactions
+WHERE invoice_number LIKE 'INV/STORE01/%'
+GROUP BY SPLIT_PART(invoice_number, '/', 3)
+ON CONFLICT (sequence_key) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,55 @@
+-- Upgrade: keranjang draft yang bisa di-park dan dilanjutkan (suspend/resume)
+
+BEGIN;
+
+-- tabel carts (keranjang draft per terminal, kedaluwarsa setelah CART_TTL tanpa perubahan)
+CREATE TABLE IF NOT EXISTS carts (
+    id SERIAL PRIMARY KEY,
+    terminal_id VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'PARKED', 'CHECKING_OUT', 'CHECKED_OUT')),
+    label VARCHAR(100) NULL,
+    customer_id VARCHAR(100) NULL,
+    discount_type VARCHAR(20) NULL CHECK (discount_type IN ('PERCENTAGE', 'FIXED')),
+    discount_value DECIMAL(15,2) NULL,
+    voucher_code VARCHAR(50) NULL,
+    created_by VARCHAR(100) NULL,
+    transaction_id INTEGER NULL,
+    parked_at TIMESTAMP WITH TIME ZONE NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_carts_transaction_id
+        FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL
+);
+
+-- tabel cart_items (baris keranjang, harga dihitung saat checkout)
+CREATE TABLE IF NOT EXISTS cart_items (
+    id SERIAL PRIMARY KEY,
+    cart_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    discount_type VARCHAR(20) NULL CHECK (discount_type IN ('PERCENTAGE', 'FIXED')),
+    discount_value DECIMAL(15,2) NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_cart_items_cart_id
+        FOREIGN KEY (cart_id) REFERENCES carts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_carts_terminal_status ON carts(terminal_id, status);
+CREATE INDEX IF NOT EXISTS idx_carts_expires_at ON carts(expires_at);
+CREATE INDEX IF NOT EXISTS idx_cart_items_cart_id ON cart_items(cart_id);
+
+DROP TRIGGER IF EXISTS trigger_carts_updated_at ON carts;
+CREATE TRIGGER trigger_carts_updated_at
+    BEFORE UPDATE ON carts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS trigger_cart_items_updated_at ON cart_items;
+CREATE TRIGGER trigger_cart_items_updated_at
+    BEFORE UPDATE ON cart_items
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
@@ -0,0 +1,77 @@
+-- Upgrade: reservasi stok dengan masa berlaku, stok tersedia = stok - reservasi aktif
+
+BEGIN;
+
+-- tabel stock_reservations (stok yang ditahan untuk keranjang/checkout sampai dikonfirmasi, dilepas atau kedaluwarsa)
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id SERIAL PRIMARY KEY,
+    reference VARCHAR(100) NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'CONFIRMED', 'RELEASED', 'EXPIRED')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE NULL,
+    released_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- tabel stock_reservation_items (jumlah yang ditahan per produk)
+CREATE TABLE IF NOT EXISTS stock_reservation_items (
+    reservation_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (reservation_id, product_id),
+    CONSTRAINT fk_stock_reservation_items_reservation_id
+        FOREIGN KEY (reservation_id) REFERENCES stock_reservations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_reservation_items_product_id
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_status_expires_at ON stock_reservations(status, expires_at);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_reference ON stock_reservations(reference) WHERE status = 'ACTIVE';
+CREATE INDEX IF NOT EXISTS idx_stock_reservation_items_product_id ON stock_reservation_items(product_id);
+
+DROP TRIGGER IF EXISTS trigger_stock_reservations_updated_at ON stock_reservations;
+CREATE TRIGGER trigger_stock_reservations_updated_at
+    BEFORE UPDATE ON stock_reservations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- kolom view berubah, CREATE OR REPLACE tidak bisa menyisipkan kolom
+DROP VIEW IF EXISTS v_low_stock_alert;
+-- View untuk alert stok rendah (berdasarkan stok tersedia = stok - reservasi aktif)
+CREATE VIEW v_low_stock_alert AS
+SELECT 
+    id,
+    name,
+    price,
+    stock,
+    reserved_stock,
+    available_stock,
+    CASE 
+        WHEN available_stock = 0 THEN 'OUT_OF_STOCK'
+        WHEN available_stock <= 5 THEN 'LOW_STOCK'
+        WHEN available_stock <= 10 THEN 'WARNING'
+        ELSE 'NORMAL'
+    END as stock_status
+FROM (
+    SELECT 
+        p.id,
+        p.name,
+        p.price,
+        p.stock,
+        COALESCE(r.quantity, 0) as reserved_stock,
+        GREATEST(p.stock - COALESCE(r.quantity, 0), 0) as available_stock
+    FROM products p
+    LEFT JOIN (
+        SELECT ri.product_id, SUM(ri.quantity) as quantity
+        FROM stock_reservation_items ri
+        JOIN stock_reservations sr ON sr.id = ri.reservation_id
+        WHERE sr.status = 'ACTIVE' AND sr.expires_at > NOW()
+        GROUP BY ri.product_id
+    ) r ON r.product_id = p.id
+    WHERE p.deleted_at IS NULL
+) stock_levels
+WHERE available_stock <= 10
+ORDER BY available_stock ASC;
+
+COMMIT;
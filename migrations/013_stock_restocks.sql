@@ -0,0 +1,39 @@
+-- Upgrade: stok hanya diubah lewat API product-service (reservasi saat checkout, restock saat void/retur)
+
+BEGIN;
+
+-- reservasi stok yang dikonfirmasi untuk transaksi, tanpa foreign key karena milik product-service
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS stock_reservation_id INTEGER NULL;
+
+-- tabel stock_restocks (barang yang dikembalikan ke stok karena void/retur, reference unik agar tidak diterapkan dua kali)
+CREATE TABLE IF NOT EXISTS stock_restocks (
+    id SERIAL PRIMARY KEY,
+    reference VARCHAR(100) NOT NULL UNIQUE,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('VOID', 'RETURN')),
+    status VARCHAR(20) NOT NULL DEFAULT 'APPLIED' CHECK (status IN ('APPLIED', 'CANCELLED')),
+    cancelled_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- tabel stock_restock_items (jumlah yang dikembalikan per produk)
+CREATE TABLE IF NOT EXISTS stock_restock_items (
+    restock_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (restock_id, product_id),
+    CONSTRAINT fk_stock_restock_items_restock_id
+        FOREIGN KEY (restock_id) REFERENCES stock_restocks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_restock_items_product_id
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_restock_items_product_id ON stock_restock_items(product_id);
+
+DROP TRIGGER IF EXISTS trigger_stock_restocks_updated_at ON stock_restocks;
+CREATE TRIGGER trigger_stock_restocks_updated_at
+    BEFORE UPDATE ON stock_restocks
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
@@ -0,0 +1,104 @@
+-- Database per service: transaction-service tidak lagi membaca tabel products.
+-- Untuk database lama (satu database mini_pos) script ini memutus ketergantungan tersebut:
+-- foreign key transaction_items -> products dihapus, data produk disalin ke product_replicas
+-- dan view laporan dibaca dari salinan itu. Setelah itu tabel bisa dipindah ke database masing-masing
+-- (lihat product-service/migrations dan transaction-service/migrations untuk pembagian tabel).
+
+BEGIN;
+
+ALTER TABLE transaction_items DROP CONSTRAINT IF EXISTS fk_transaction_items_product_id;
+
+CREATE TABLE IF NOT EXISTS product_replicas (
+    product_id INTEGER PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    price DECIMAL(15,2) NOT NULL DEFAULT 0,
+    stock INTEGER NOT NULL DEFAULT 0,
+    reserved_stock INTEGER NOT NULL DEFAULT 0,
+    available_stock INTEGER NOT NULL DEFAULT 0,
+    tax_class VARCHAR(30) NOT NULL DEFAULT 'STANDARD',
+    deleted_at TIMESTAMP WITH TIME ZONE NULL,
+    synced_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_replicas_deleted_at ON product_replicas(deleted_at);
+
+-- isi awal salinan, selanjutnya diperbarui oleh sinkronisasi transaction-service
+INSERT INTO product_replicas (product_id, name, price, stock, reserved_stock, available_stock, tax_class, deleted_at)
+SELECT
+    p.id,
+    p.name,
+    p.price,
+    p.stock,
+    COALESCE(r.reserved, 0),
+    p.stock - COALESCE(r.reserved, 0),
+    p.tax_class,
+    p.deleted_at
+FROM products p
+LEFT JOIN (
+    SELECT ri.product_id, SUM(ri.quantity) as reserved
+    FROM stock_reservation_items ri
+    JOIN stock_reservations sr ON sr.id = ri.reservation_id
+    WHERE sr.status = 'ACTIVE' AND sr.expires_at > CURRENT_TIMESTAMP
+    GROUP BY ri.product_id
+) r ON r.product_id = p.id
+ON CONFLICT (product_id) DO NOTHING;
+
+DROP VIEW IF EXISTS v_product_sales_report;
+DROP VIEW IF EXISTS v_low_stock_alert;
+
+-- View untuk laporan penjualan produk (total_revenue net setelah diskon dan refund, transaksi void dihitung terpisah).
+-- Nama, harga dan stok produk diambil dari product_replicas, bukan dari database product-service.
+CREATE VIEW v_product_sales_report AS
+SELECT 
+    p.product_id as id,
+    p.name as product_name,
+    p.price as current_price,
+    p.stock as current_stock,
+    COALESCE(s.total_sold, 0) as total_sold,
+    COALESCE(s.total_discount, 0) as total_discount,
+    COALESCE(ri.total_returned, 0) as total_returned,
+    COALESCE(ri.total_refunded, 0) as total_refunded,
+    COALESCE(s.gross_revenue, 0) - COALESCE(ri.total_refunded, 0) as total_revenue,
+    COALESCE(s.total_voided, 0) as total_voided
+FROM product_replicas p
+LEFT JOIN (
+    SELECT 
+        ti.product_id,
+        SUM(ti.quantity) FILTER (WHERE t.voided_at IS NULL) as total_sold,
+        SUM(ti.total_amount) FILTER (WHERE t.voided_at IS NULL) as gross_revenue,
+        SUM(ti.discount_amount) FILTER (WHERE t.voided_at IS NULL) as total_discount,
+        SUM(ti.quantity) FILTER (WHERE t.voided_at IS NOT NULL) as total_voided
+    FROM transaction_items ti
+    JOIN transactions t ON t.id = ti.transaction_id
+    WHERE t.deleted_at IS NULL
+    GROUP BY ti.product_id
+) s ON s.product_id = p.product_id
+LEFT JOIN (
+    SELECT product_id, SUM(quantity) as total_returned, SUM(amount) as total_refunded
+    FROM transaction_return_items
+    GROUP BY product_id
+) ri ON ri.product_id = p.product_id
+WHERE p.deleted_at IS NULL
+ORDER BY total_sold DESC;
+
+-- View untuk alert stok rendah (stok tersedia = stok - reservasi aktif, per sinkronisasi terakhir dari product-service)
+CREATE VIEW v_low_stock_alert AS
+SELECT 
+    product_id as id,
+    name,
+    price,
+    stock,
+    reserved_stock,
+    available_stock,
+    CASE 
+        WHEN available_stock = 0 THEN 'OUT_OF_STOCK'
+        WHEN available_stock <= 5 THEN 'LOW_STOCK'
+        WHEN available_stock <= 10 THEN 'WARNING'
+        ELSE 'NORMAL'
+    END as stock_status
+FROM product_replicas
+WHERE deleted_at IS NULL 
+    AND available_stock <= 10
+ORDER BY available_stock ASC;
+
+COMMIT;
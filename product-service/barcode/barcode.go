@@ -0,0 +1,157 @@
+package barcode
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// jenis barcode yang didukung
+const (
+	TypeEAN13    = "EAN13"
+	TypeUPCA     = "UPCA"
+	TypeCode128  = "CODE128"
+	TypeInternal = "INTERNAL" // kode toko sendiri, mis. label rak atau barang tanpa barcode pabrik
+	TypePLU      = "PLU"      // kode barang di label timbangan, lihat ScaleFormats
+)
+
+var (
+	digitsPattern   = regexp.MustCompile(`^[0-9]+$`)
+	internalPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._-]{0,31}$`)
+	skuPattern      = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._-]{0,63}$`)
+	skuInvalidChars = regexp.MustCompile(`[^A-Z0-9._-]+`)
+	pluPattern      = regexp.MustCompile(`^[1-9][0-9]{0,5}$`)
+)
+
+// Normalize memvalidasi barcode dan mengembalikan kode serta jenisnya. Jenis kosong dideteksi dari kode:
+// 13 digit EAN-13, 12 digit UPC-A, selain itu Code 128.
+func Normalize(code, codeType string) (string, string, error) {
+	code = strings.TrimSpace(code)
+	codeType = strings.ToUpper(strings.TrimSpace(codeType))
+	if code == "" {
+		return "", "", errors.New("invalid barcode, code is required")
+	}
+
+	if codeType == "" {
+		codeType = detect(code)
+	}
+
+	switch codeType {
+	case TypeEAN13:
+		if len(code) != 13 || !digitsPattern.MatchString(code) {
+			return "", "", fmt.Errorf("invalid EAN-13 barcode %s, must be 13 digits", code)
+		}
+		if !validCheckDigit(code) {
+			return "", "", fmt.Errorf("invalid EAN-13 barcode %s, wrong check digit", code)
+		}
+	case TypeUPCA:
+		if len(code) != 12 || !digitsPattern.MatchString(code) {
+			return "", "", fmt.Errorf("invalid UPC-A barcode %s, must be 12 digits", code)
+		}
+		if !validCheckDigit(code) {
+			return "", "", fmt.Errorf("invalid UPC-A barcode %s, wrong check digit", code)
+		}
+	case TypeCode128:
+		// check digit Code 128 ada di simbol, bukan di data, jadi yang divalidasi hanya karakternya
+		if len(code) > 48 {
+			return "", "", fmt.Errorf("invalid Code 128 barcode %s, at most 48 characters", code)
+		}
+		for _, r := range code {
+			if r < 32 || r > 126 {
+				return "", "", fmt.Errorf("invalid Code 128 barcode %s, only printable ASCII characters are allowed", code)
+			}
+		}
+	case TypeInternal:
+		code = strings.ToUpper(code)
+		if !internalPattern.MatchString(code) {
+			return "", "", fmt.Errorf("invalid internal barcode %s, use up to 32 letters, digits, dots, dashes or underscores", code)
+		}
+	case TypePLU:
+		// disimpan tanpa nol di depan agar cocok dengan kode barang di label berapa pun lebarnya
+		code = strings.TrimLeft(code, "0")
+		if !pluPattern.MatchString(code) {
+			return "", "", fmt.Errorf("invalid PLU %s, use 1 to 6 digits", code)
+		}
+	default:
+		return "", "", fmt.Errorf("invalid barcode type %s, use EAN13, UPCA, CODE128, INTERNAL or PLU", codeType)
+	}
+
+	return code, codeType, nil
+}
+
+// NormalizeSKU mengubah SKU ke huruf besar dan memvalidasi karakternya
+func NormalizeSKU(sku string) (string, error) {
+	sku = strings.ToUpper(strings.TrimSpace(sku))
+	if !skuPattern.MatchString(sku) {
+		return "", fmt.Errorf("invalid sku %s, use up to 64 letters, digits, dots, dashes or underscores", sku)
+	}
+	return sku, nil
+}
+
+// VariantSKU membentuk SKU varian dari SKU produk dan nilai opsinya, mis. TSHIRT-M-BLACK. Karakter yang
+// tidak diizinkan di SKU dibuang; hasil kosong jika SKU produk kosong atau hasilnya lebih dari 64 karakter.
+func VariantSKU(productSKU string, values []string) string {
+	if productSKU == "" {
+		return ""
+	}
+	parts := []string{productSKU}
+	for _, value := range values {
+		part := skuInvalidChars.ReplaceAllString(strings.ToUpper(value), "")
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	sku := strings.Join(parts, "-")
+	if !skuPattern.MatchString(sku) {
+		return ""
+	}
+	return sku
+}
+
+// LookupCandidates mengembalikan kode yang setara untuk pencarian hasil scan: UPC-A sama dengan EAN-13
+// berawalan 0, dan scanner bisa mengirim salah satunya
+func LookupCandidates(code string) []string {
+	code = strings.TrimSpace(code)
+	candidates := []string{code}
+	if digitsPattern.MatchString(code) {
+		switch {
+		case len(code) == 12:
+			candidates = append(candidates, "0"+code)
+		case len(code) == 13 && code[0] == '0':
+			candidates = append(candidates, code[1:])
+		}
+	} else if upper := strings.ToUpper(code); upper != code {
+		// kode internal disimpan dalam huruf besar
+		candidates = append(candidates, upper)
+	}
+	return candidates
+}
+
+func detect(code string) string {
+	if digitsPattern.MatchString(code) {
+		switch len(code) {
+		case 13:
+			return TypeEAN13
+		case 12:
+			return TypeUPCA
+		}
+	}
+	return TypeCode128
+}
+
+// validCheckDigit menghitung check digit GS1 (modulo 10) yang dipakai EAN-13 dan UPC-A:
+// dari kanan (tanpa check digit), digit posisi ganjil dikali 3
+func validCheckDigit(code string) bool {
+	sum := 0
+	body := code[:len(code)-1]
+	for i := len(body) - 1; i >= 0; i-- {
+		digit := int(body[i] - '0')
+		if (len(body)-1-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	check := (10 - sum%10) % 10
+	return check == int(code[len(code)-1]-'0')
+}
@@ -0,0 +1,56 @@
+package barcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidCheckDigit(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"4006381333931", true},
+		{"5901234123457", true},
+		{"1234567890128", true},
+		{"0000000000000", true},
+		{"4006381333932", false},
+		{"5901234123450", false},
+		{"036000291452", true}, // UPC-A
+		{"036000291453", false},
+		{"2100123007356", true}, // label timbangan
+		{"2100123007357", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := validCheckDigit(tt.code); got != tt.want {
+				t.Errorf("validCheckDigit(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupCandidates(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want []string
+	}{
+		{"UPC-A also as EAN-13", "036000291452", []string{"036000291452", "0036000291452"}},
+		{"EAN-13 with leading zero also as UPC-A", "0036000291452", []string{"0036000291452", "036000291452"}},
+		{"EAN-13 without leading zero", "4006381333931", []string{"4006381333931"}},
+		{"other digit lengths", "12345678", []string{"12345678"}},
+		{"internal code also upper case", "shelf-a1", []string{"shelf-a1", "SHELF-A1"}},
+		{"upper case code", "SHELF-A1", []string{"SHELF-A1"}},
+		{"whitespace trimmed", " 036000291452\n", []string{"036000291452", "0036000291452"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LookupCandidates(tt.code); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LookupCandidates(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package barcode
+
+import (
+	"fmt"
+	"product-service/money"
+	"product-service/quantity"
+	"strconv"
+	"strings"
+)
+
+// jenis nilai yang dicetak timbangan di label
+const (
+	ScaleWeight = "WEIGHT" // berat, dipakai sebagai quantity (mis. kg)
+	ScalePrice  = "PRICE"  // harga total, quantity dihitung dari harga satuan produk
+)
+
+// DefaultScaleFormats adalah format label timbangan EAN-13 yang umum: prefix 20-24 berat dalam gram,
+// prefix 25-29 harga dalam rupiah, keduanya dengan kode barang 5 digit
+const DefaultScaleFormats = "20-24:5:WEIGHT:3,25-29:5:PRICE:0"
+
+// ScaleFormat menjelaskan satu jenis label timbangan EAN-13:
+// prefix, kode barang ItemDigits digit, nilai (sisa digit) dengan Decimals desimal, lalu check digit
+type ScaleFormat struct {
+	PrefixFrom string
+	PrefixTo   string
+	ItemDigits int
+	Kind       string
+	Decimals   int
+}
+
+// ScaleFormats dicocokkan berurutan, format pertama yang prefix-nya cocok dipakai
+type ScaleFormats []ScaleFormat
+
+// ScaleLabel adalah hasil parsing label timbangan
+type ScaleLabel struct {
+	Code     string
+	Prefix   string
+	ItemCode string // tanpa nol di depan, dicocokkan dengan barcode PLU produk
+	Kind     string
+	Weight   quantity.Quantity // hanya untuk WEIGHT
+	Price    money.Money       // hanya untuk PRICE
+}
+
+// ParseScaleFormats membaca daftar format dipisah koma, masing-masing PREFIX:ITEM_DIGITS:KIND:DECIMALS.
+// PREFIX boleh satu prefix ("21") atau rentang dengan panjang sama ("20-24").
+func ParseScaleFormats(spec string) (ScaleFormats, error) {
+	var formats ScaleFormats
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("invalid scale barcode format %q, use PREFIX:ITEM_DIGITS:KIND:DECIMALS", entry)
+		}
+
+		from, to, ranged := strings.Cut(parts[0], "-")
+		if !ranged {
+			to = from
+		}
+		if from == "" || len(from) != len(to) || !digitsPattern.MatchString(from+to) || from > to {
+			return nil, fmt.Errorf("invalid scale barcode prefix %q", parts[0])
+		}
+
+		itemDigits, err := strconv.Atoi(parts[1])
+		valueDigits := 12 - len(from) - itemDigits
+		if err != nil || itemDigits < 1 || valueDigits < 1 {
+			return nil, fmt.Errorf("invalid scale barcode item digits %q for prefix %s", parts[1], parts[0])
+		}
+
+		kind := strings.ToUpper(parts[2])
+		if kind != ScaleWeight && kind != ScalePrice {
+			return nil, fmt.Errorf("invalid scale barcode kind %q, use WEIGHT or PRICE", parts[2])
+		}
+
+		decimals, err := strconv.Atoi(parts[3])
+		if err != nil || decimals < 0 || decimals > valueDigits {
+			return nil, fmt.Errorf("invalid scale barcode decimals %q for prefix %s", parts[3], parts[0])
+		}
+
+		formats = append(formats, ScaleFormat{
+			PrefixFrom: from,
+			PrefixTo:   to,
+			ItemDigits: itemDigits,
+			Kind:       kind,
+			Decimals:   decimals,
+		})
+	}
+	return formats, nil
+}
+
+// Parse mengurai kode sebagai label timbangan. ok=false jika kode bukan EAN-13 yang valid atau
+// prefix-nya tidak cocok dengan format mana pun.
+func (formats ScaleFormats) Parse(code string) (*ScaleLabel, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != 13 || !digitsPattern.MatchString(code) || !validCheckDigit(code) {
+		return nil, false
+	}
+
+	for _, format := range formats {
+		prefix := code[:len(format.PrefixFrom)]
+		if prefix < format.PrefixFrom || prefix > format.PrefixTo {
+			continue
+		}
+
+		itemEnd := len(prefix) + format.ItemDigits
+		itemCode := strings.TrimLeft(code[len(prefix):itemEnd], "0")
+		if itemCode == "" {
+			return nil, false
+		}
+
+		// nilai tanpa check digit, koma disisipkan sesuai jumlah desimal
+		digits := code[itemEnd:12]
+		value := digits[:len(digits)-format.Decimals] + "." + digits[len(digits)-format.Decimals:]
+
+		label := &ScaleLabel{Code: code, Prefix: prefix, ItemCode: itemCode, Kind: format.Kind}
+		if format.Kind == ScaleWeight {
+			weight, err := quantity.Parse(value)
+			if err != nil || weight <= 0 {
+				return nil, false
+			}
+			label.Weight = weight
+		} else {
+			price, err := money.Parse(value)
+			if err != nil || price <= 0 {
+				return nil, false
+			}
+			label.Price = price
+		}
+		return label, true
+	}
+	return nil, false
+}
@@ -0,0 +1,116 @@
+package barcode
+
+import (
+	"product-service/money"
+	"product-service/quantity"
+	"reflect"
+	"testing"
+)
+
+func TestParseScaleFormats(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		want        ScaleFormats
+		wantInvalid bool
+	}{
+		{"default", DefaultScaleFormats, ScaleFormats{
+			{PrefixFrom: "20", PrefixTo: "24", ItemDigits: 5, Kind: ScaleWeight, Decimals: 3},
+			{PrefixFrom: "25", PrefixTo: "29", ItemDigits: 5, Kind: ScalePrice, Decimals: 0},
+		}, false},
+		{"single prefix, lower case kind, blank entries", " 21:5:weight:3 , ,", ScaleFormats{
+			{PrefixFrom: "21", PrefixTo: "21", ItemDigits: 5, Kind: ScaleWeight, Decimals: 3},
+		}, false},
+		{"all value digits are decimals", "2:4:PRICE:6", ScaleFormats{
+			{PrefixFrom: "2", PrefixTo: "2", ItemDigits: 4, Kind: ScalePrice, Decimals: 6},
+		}, false},
+		{"empty", "", nil, false},
+		{"missing part", "20-24:5:WEIGHT", nil, true},
+		{"extra part", "20-24:5:WEIGHT:3:1", nil, true},
+		{"prefix lengths differ", "2-24:5:WEIGHT:3", nil, true},
+		{"reversed range", "24-20:5:WEIGHT:3", nil, true},
+		{"non-digit prefix", "2a:5:WEIGHT:3", nil, true},
+		{"empty prefix", ":5:WEIGHT:3", nil, true},
+		{"non-numeric item digits", "20:x:WEIGHT:3", nil, true},
+		{"zero item digits", "20:0:WEIGHT:3", nil, true},
+		{"no digits left for the value", "20:10:WEIGHT:0", nil, true},
+		{"unknown kind", "20:5:COUNT:3", nil, true},
+		{"more decimals than value digits", "20:5:WEIGHT:6", nil, true},
+		{"negative decimals", "20:5:WEIGHT:-1", nil, true},
+		{"one bad entry fails all", "20-24:5:WEIGHT:3,25:5:PRICE", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScaleFormats(tt.spec)
+			if tt.wantInvalid {
+				if err == nil {
+					t.Fatalf("ParseScaleFormats(%q) = %+v, want error", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseScaleFormats(%q) returned error: %v", tt.spec, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseScaleFormats(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaleFormatsParse(t *testing.T) {
+	defaults, err := ParseScaleFormats(DefaultScaleFormats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// format pertama yang cocok dipakai, walaupun format berikutnya juga cocok
+	priceFirst, err := ParseScaleFormats("21:5:PRICE:0," + DefaultScaleFormats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cents, err := ParseScaleFormats("02:4:PRICE:2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		formats ScaleFormats
+		code    string
+		want    *ScaleLabel
+	}{
+		{"weight in grams", defaults, "2100123007356",
+			&ScaleLabel{Code: "2100123007356", Prefix: "21", ItemCode: "123", Kind: ScaleWeight, Weight: 735}},
+		{"weight at range end", defaults, "2400012015005",
+			&ScaleLabel{Code: "2400012015005", Prefix: "24", ItemCode: "12", Kind: ScaleWeight, Weight: 1500}},
+		{"price in rupiah", defaults, "2500456150000",
+			&ScaleLabel{Code: "2500456150000", Prefix: "25", ItemCode: "456", Kind: ScalePrice, Price: money.New(15000)}},
+		{"price with decimals", cents, "0212340123459",
+			&ScaleLabel{Code: "0212340123459", Prefix: "02", ItemCode: "1234", Kind: ScalePrice, Price: 12345}},
+		{"first matching format wins", priceFirst, "2100123007356",
+			&ScaleLabel{Code: "2100123007356", Prefix: "21", ItemCode: "123", Kind: ScalePrice, Price: money.New(735)}},
+		{"whitespace trimmed", defaults, " 2100123007356 ",
+			&ScaleLabel{Code: "2100123007356", Prefix: "21", ItemCode: "123", Kind: ScaleWeight, Weight: quantity.Quantity(735)}},
+		{"zero item code", defaults, "2100000007356", nil},
+		{"zero weight", defaults, "2100123000005", nil},
+		{"prefix not configured", defaults, "3000123007358", nil},
+		{"ordinary EAN-13", defaults, "4006381333931", nil},
+		{"wrong check digit", defaults, "2100123007357", nil},
+		{"not 13 digits", defaults, "210012300735", nil},
+		{"not digits", defaults, "21001230073A6", nil},
+		{"no formats", nil, "2100123007356", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.formats.Parse(tt.code)
+			if ok != (tt.want != nil) {
+				t.Fatalf("Parse(%q) ok = %v, want %v", tt.code, ok, tt.want != nil)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
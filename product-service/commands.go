@@ -0,0 +1,148 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"product-service/dto"
+	"product-service/importer"
+	"product-service/migrations"
+	"product-service/repositories"
+	"product-service/services"
+	"strconv"
+	"strings"
+)
+
+const commandUsage = `usage: main [migrate [up | down [steps] | status] | seed | import [-dry-run] [-chunk-size N] [-mapping FIELD=COLUMN,...] [-sheet NAME] FILE]`
+
+// runCommand menjalankan perintah CLI selain menjalankan server, mis. "./main migrate down 1" atau "./main seed"
+func runCommand(db *sql.DB, args []string) error {
+	switch args[0] {
+	case "migrate":
+		action := "up"
+		if len(args) > 1 {
+			action = args[1]
+		}
+
+		switch action {
+		case "up":
+			return migrateUp(db)
+		case "down":
+			steps := 1
+			if len(args) > 2 {
+				var err error
+				if steps, err = strconv.Atoi(args[2]); err != nil || steps <= 0 {
+					return fmt.Errorf("invalid number of steps %q", args[2])
+				}
+			}
+			reverted, err := migrations.Down(db, steps)
+			for _, migration := range reverted {
+				log.Printf("Reverted migration %04d_%s", migration.Version, migration.Name)
+			}
+			return err
+		case "status":
+			statuses, err := migrations.GetStatus(db)
+			if err != nil {
+				return err
+			}
+			for _, status := range statuses {
+				appliedAt := "pending"
+				if status.AppliedAt != nil {
+					appliedAt = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+				}
+				fmt.Fprintf(os.Stdout, "%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+			}
+			return nil
+		}
+	case "seed":
+		seeded, err := migrations.Seed(db)
+		if err != nil {
+			return err
+		}
+		if seeded {
+			log.Println("Sample data inserted")
+		} else {
+			log.Println("Database already has data, seed skipped")
+		}
+		return nil
+	case "import":
+		return importProducts(args[1:])
+	}
+
+	return errors.New(commandUsage)
+}
+
+// importProducts menjalankan import produk dari file CSV/XLSX dengan aturan yang sama seperti
+// POST /api/products/import, progres ditulis per chunk dan baris yang error ditulis ke stdout
+func importProducts(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "validate only, nothing is saved")
+	chunkSize := flags.Int("chunk-size", 0, "rows per transaction, 0 applies all rows at once")
+	mappingSpec := flags.String("mapping", "", "column mapping, e.g. name=Nama Barang,price=Harga")
+	sheet := flags.String("sheet", "", "xlsx sheet name, default the first sheet")
+	if err := flags.Parse(args); err != nil {
+		return errors.New(commandUsage)
+	}
+	if flags.NArg() != 1 {
+		return errors.New(commandUsage)
+	}
+
+	mapping, err := importer.ParseMapping(*mappingSpec)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	table, err := importer.Read(file.Name(), file, *sheet)
+	if err != nil {
+		return err
+	}
+
+	service := services.NewProductImportService(repositories.NewProductRepository(), repositories.NewCategoryRepository())
+	report, err := service.ImportProducts(table, dto.ImportOptions{
+		Mapping:   mapping,
+		DryRun:    *dryRun,
+		ChunkSize: *chunkSize,
+	}, func(chunk dto.ImportChunkResult) {
+		state := "rolled back"
+		if chunk.Applied {
+			state = "applied"
+		} else if *dryRun {
+			state = "checked"
+		}
+		log.Printf("Chunk %d (lines %d-%d) %s, %d rows with errors, %d/%d rows processed",
+			chunk.Chunk, chunk.FromLine, chunk.ToLine, state, chunk.Failed, chunk.Processed, chunk.Total)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range report.Rows {
+		if row.Status == dto.ImportRowError {
+			fmt.Fprintf(os.Stdout, "line %d\t%s\t%s\n", row.Line, row.SKU, strings.Join(row.Errors, "; "))
+		}
+	}
+	log.Println(services.ImportSummary(report))
+	if report.Failed > 0 {
+		return fmt.Errorf("%d rows with errors", report.Failed)
+	}
+	return nil
+}
+
+func migrateUp(db *sql.DB) error {
+	applied, err := migrations.Up(db)
+	for _, migration := range applied {
+		log.Printf("Applied migration %04d_%s", migration.Version, migration.Name)
+	}
+	if err == nil && len(applied) == 0 {
+		log.Println("Database schema is up to date")
+	}
+	return err
+}
@@ -12,19 +12,24 @@ import (
 var DB *sql.DB
 
 func InitDatabase() {
-	host := os.Getenv("DB_HOST")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	port := os.Getenv("DB_PORT") 
-
-	if port == "" {
-		port = "5432"
+	// product-service punya database sendiri (mini_pos_product), terpisah dari transaction-service.
+	// PRODUCT_DATABASE_URL / DATABASE_URL dipakai jika ada, selain itu DSN dibangun dari DB_*
+	dsn := os.Getenv("PRODUCT_DATABASE_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DATABASE_URL")
 	}
+	if dsn == "" {
+		host := os.Getenv("DB_HOST")
+		user := os.Getenv("DB_USER")
+		password := os.Getenv("DB_PASSWORD")
+		dbname := getEnv("DB_NAME", "mini_pos_product")
+		sslmode := getEnv("DB_SSLMODE", "disable")
+		port := getEnv("DB_PORT", "5432")
 
-	// Build DSN
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		host, user, password, dbname, port)
+		// Build DSN
+		dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+			host, user, password, dbname, port, sslmode)
+	}
 
 	var err error
 	DB, err = sql.Open("postgres", dsn)
@@ -50,3 +55,10 @@ func CloseDatabase() {
 		DB.Close()
 	}
 }
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,37 @@
+package dto
+
+type CreateCategoryRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// nil atau 0 berarti kategori utama
+	ParentID *uint `json:"parent_id"`
+	// 0 berarti ditaruh paling akhir di antara saudaranya
+	SortOrder int `json:"sort_order" validate:"min=0"`
+}
+
+// UpdateCategoryRequest mengubah nama dan urutan, parent diubah lewat MoveCategoryRequest
+type UpdateCategoryRequest struct {
+	Name      string `json:"name,omitempty" validate:"omitempty,max=100"`
+	SortOrder *int   `json:"sort_order,omitempty" validate:"omitempty,min=1"`
+}
+
+// MoveCategoryRequest memindahkan kategori beserta subkategori dan produknya ke parent lain
+type MoveCategoryRequest struct {
+	// nil atau 0 memindahkan ke kategori utama
+	ParentID *uint `json:"parent_id"`
+	// 0 berarti ditaruh paling akhir di parent baru
+	SortOrder int `json:"sort_order" validate:"min=0"`
+}
+
+type CategoryResponse struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	ParentID     *uint  `json:"parent_id,omitempty"`
+	SortOrder    int    `json:"sort_order"`
+	Depth        int    `json:"depth"`
+	Path         string `json:"path"`          // mis. "Electronics > Computers > Peripherals"
+	ProductCount int    `json:"product_count"` // produk aktif langsung di kategori ini
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+	// subkategori, hanya diisi pada tampilan pohon
+	Children []CategoryResponse `json:"children,omitempty"`
+}
@@ -1,24 +1,122 @@
 package dto
 
+import (
+	"product-service/money"
+	"product-service/quantity"
+)
+
 type CreateProductRequest struct {
-	Name  string  `json:"name" validate:"required,min=1,max=100"`
-	Price float64 `json:"price" validate:"required,min=0"`
-	Stock int     `json:"stock" validate:"required,min=0"`
+	Name  string            `json:"name" validate:"required,min=1,max=100"`
+	Price money.Money       `json:"price" validate:"required,min=0"` // angka atau string desimal
+	Stock quantity.Quantity `json:"stock" validate:"required,min=0"` // desimal untuk barang timbang, mis. 12.5 (kg)
+	// kosong berarti STANDARD
+	TaxClass string           `json:"tax_class" validate:"omitempty,max=30"`
+	SKU      string           `json:"sku" validate:"omitempty,max=64"`
+	Barcodes []BarcodeRequest `json:"barcodes" validate:"omitempty,dive"`
+	// 0 berarti produk tanpa kategori
+	CategoryID uint `json:"category_id"`
+	// sumbu varian, semua kombinasinya dibuat sebagai varian dengan stok 0 (stok diisi per varian)
+	Options []OptionRequest `json:"options" validate:"omitempty,max=3,dive"`
 }
 
 type UpdateProductRequest struct {
-	Name  string  `json:"name,omitempty" validate:"omitempty"`
-	Price float64 `json:"price,omitempty" validate:"omitempty,gt=0"`
-	Stock int     `json:"stock,omitempty" validate:"omitempty,min=0"`
+	Name     string            `json:"name,omitempty" validate:"omitempty"`
+	Price    money.Money       `json:"price,omitempty" validate:"omitempty,gt=0"`
+	Stock    quantity.Quantity `json:"stock,omitempty" validate:"omitempty,min=0"`
+	TaxClass string            `json:"tax_class,omitempty" validate:"omitempty,max=30"`
+	// nil berarti tidak diubah, string kosong menghapus SKU
+	SKU *string `json:"sku,omitempty" validate:"omitempty,max=64"`
+	// nil berarti tidak diubah, daftar kosong menghapus semua barcode
+	Barcodes *[]BarcodeRequest `json:"barcodes,omitempty" validate:"omitempty,dive"`
+	// nil berarti tidak diubah, 0 melepas produk dari kategorinya
+	CategoryID *uint `json:"category_id,omitempty"`
+	// nil berarti tidak diubah, daftar kosong menghapus semua varian. Varian yang kombinasinya masih ada
+	// dipertahankan, varian yang kombinasinya hilang harus sudah berstok 0.
+	Options *[]OptionRequest `json:"options,omitempty" validate:"omitempty,max=3,dive"`
+}
+
+// OptionRequest adalah satu sumbu varian, mis. {"name": "Size", "values": ["S", "M", "L"]}
+type OptionRequest struct {
+	Name   string   `json:"name" validate:"required,max=50"`
+	Values []string `json:"values" validate:"required,min=1,max=20,dive,required,max=50"`
+}
+
+// UpdateVariantRequest mengubah satu varian, field yang tidak diisi tidak diubah
+type UpdateVariantRequest struct {
+	// string kosong menghapus SKU
+	SKU   *string            `json:"sku,omitempty" validate:"omitempty,max=64"`
+	Price money.Money        `json:"price,omitempty" validate:"omitempty,gt=0"`
+	Stock *quantity.Quantity `json:"stock,omitempty" validate:"omitempty,min=0"`
+	// daftar kosong menghapus semua barcode varian
+	Barcodes *[]BarcodeRequest `json:"barcodes,omitempty" validate:"omitempty,dive"`
+}
+
+// BarcodeRequest adalah barcode produk; type kosong dideteksi dari kode (13 digit EAN13, 12 digit UPCA, lainnya CODE128).
+// PLU adalah kode barang di label timbangan.
+type BarcodeRequest struct {
+	Code string `json:"code" validate:"required,max=48"`
+	Type string `json:"type" validate:"omitempty,oneof=EAN13 UPCA CODE128 INTERNAL PLU"`
+}
+
+type BarcodeResponse struct {
+	Code string `json:"code"`
+	Type string `json:"type"`
 }
 
 type ProductResponse struct {
-	ID        uint    `json:"id"`
-	Name      string  `json:"name"`
-	Price     float64 `json:"price"`
-	Stock     int     `json:"stock"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
+	ID             uint              `json:"id"`
+	Name           string            `json:"name"`
+	SKU            string            `json:"sku,omitempty"`
+	Barcodes       []BarcodeResponse `json:"barcodes"`
+	Price          money.Money       `json:"price"` // string desimal, mis. "15000.00"
+	Stock          quantity.Quantity `json:"stock"` // stok fisik
+	ReservedStock  quantity.Quantity `json:"reserved_stock"`
+	AvailableStock quantity.Quantity `json:"available_stock"` // stok dikurangi reservasi aktif
+	TaxClass       string            `json:"tax_class"`
+	CategoryID     *uint             `json:"category_id,omitempty"`
+	Options        []OptionResponse  `json:"options,omitempty"`
+	Variants       []VariantResponse `json:"variants,omitempty"` // stok produk adalah jumlah stok varian
+	CreatedAt      string            `json:"created_at"`
+	UpdatedAt      string            `json:"updated_at"`
+}
+
+type OptionResponse struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type VariantResponse struct {
+	ID             uint              `json:"id"`
+	SKU            string            `json:"sku,omitempty"`
+	OptionValues   []string          `json:"option_values"`
+	Title          string            `json:"title"` // mis. "M / Black"
+	Barcodes       []BarcodeResponse `json:"barcodes"`
+	Price          money.Money       `json:"price"`
+	Stock          quantity.Quantity `json:"stock"`
+	ReservedStock  quantity.Quantity `json:"reserved_stock"`
+	AvailableStock quantity.Quantity `json:"available_stock"`
+	CreatedAt      string            `json:"created_at"`
+	UpdatedAt      string            `json:"updated_at"`
+}
+
+// ProductLookupResponse adalah hasil scan, Scale hanya diisi jika barcode adalah label timbangan dan
+// Variant hanya diisi jika barcode/SKU milik salah satu varian
+type ProductLookupResponse struct {
+	ProductResponse
+	Variant *VariantResponse    `json:"variant,omitempty"`
+	Scale   *ScaleLabelResponse `json:"scale,omitempty"`
+}
+
+// ScaleLabelResponse adalah isi label timbangan beserta jumlah dan harga baris yang dihasilkan
+type ScaleLabelResponse struct {
+	Barcode  string             `json:"barcode"`
+	Prefix   string             `json:"prefix"`
+	ItemCode string             `json:"item_code"`
+	Kind     string             `json:"kind"`             // WEIGHT atau PRICE
+	Weight   *quantity.Quantity `json:"weight,omitempty"` // berat di label, hanya untuk WEIGHT
+	Price    *money.Money       `json:"price,omitempty"`  // harga total di label, hanya untuk PRICE
+	Quantity quantity.Quantity  `json:"quantity"`         // jumlah untuk baris transaksi
+	Amount   money.Money        `json:"amount"`           // harga baris: harga label atau harga satuan x berat
 }
 
 type ApiResponse struct {
@@ -0,0 +1,53 @@
+package dto
+
+// status baris import
+const (
+	ImportRowValid      = "VALID"       // lolos validasi, dry run tidak menyimpan apa pun
+	ImportRowApplied    = "APPLIED"     // tersimpan
+	ImportRowError      = "ERROR"       // tidak lolos validasi, lihat errors
+	ImportRowRolledBack = "ROLLED_BACK" // valid tetapi batal karena baris lain di batch/chunk yang sama error
+)
+
+// ImportOptions mengatur jalannya import produk dari endpoint maupun CLI
+type ImportOptions struct {
+	// field -> nama kolom di header, field yang tidak disebut dicocokkan dengan nama kolom yang umum
+	Mapping map[string]string
+	// hanya validasi; semua baris dijalankan dalam satu transaksi yang selalu di-rollback
+	DryRun bool
+	// 0 berarti semua baris diterapkan dalam satu transaksi (all or nothing), selain itu per chunk
+	ChunkSize int
+}
+
+type ImportReport struct {
+	DryRun    bool   `json:"dry_run"`
+	Mode      string `json:"mode"` // ATOMIC atau CHUNKED
+	TotalRows int    `json:"total_rows"`
+	Created   int    `json:"created"` // baris yang membuat produk baru (pada dry run: yang akan dibuat)
+	Updated   int    `json:"updated"` // baris yang mengupdate produk dengan SKU yang sama
+	Failed    int    `json:"failed"`  // baris dengan error
+	// baris valid yang tidak tersimpan karena batch/chunk-nya berisi error
+	RolledBack int                 `json:"rolled_back"`
+	Chunks     []ImportChunkResult `json:"chunks,omitempty"`
+	Rows       []ImportRowResult   `json:"rows"`
+}
+
+type ImportRowResult struct {
+	Line      int      `json:"line"` // nomor baris di file, sama dengan nomor baris di Excel
+	SKU       string   `json:"sku,omitempty"`
+	Action    string   `json:"action,omitempty"` // CREATE atau UPDATE
+	Status    string   `json:"status"`
+	ProductID uint     `json:"product_id,omitempty"` // kosong pada dry run untuk produk baru
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// ImportChunkResult adalah progres satu chunk, dilaporkan setiap chunk selesai
+type ImportChunkResult struct {
+	Chunk     int  `json:"chunk"` // mulai 1
+	FromLine  int  `json:"from_line"`
+	ToLine    int  `json:"to_line"`
+	Rows      int  `json:"rows"`
+	Failed    int  `json:"failed"`
+	Applied   bool `json:"applied"`
+	Processed int  `json:"processed"` // jumlah baris yang sudah diproses sampai chunk ini
+	Total     int  `json:"total"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "product-service/quantity"
+
+type CreateReservationRequest struct {
+	Reference string                   `json:"reference" validate:"max=100"`
+	Items     []ReservationItemRequest `json:"items" validate:"required,min=1,dive"`
+	// masa tahan dalam detik, 0 berarti RESERVATION_TTL
+	TTLSeconds int `json:"ttl_seconds" validate:"min=0"`
+}
+
+type ReservationItemRequest struct {
+	ProductID uint `json:"product_id" validate:"required"`
+	// wajib untuk produk yang punya varian, 0 berarti produk tanpa varian
+	VariantID uint              `json:"variant_id"`
+	Quantity  quantity.Quantity `json:"quantity" validate:"gt=0"`
+}
+
+type ReservationResponse struct {
+	ID          uint                      `json:"id"`
+	Reference   string                    `json:"reference,omitempty"`
+	Status      string                    `json:"status"`
+	Items       []ReservationItemResponse `json:"items"`
+	ExpiresAt   string                    `json:"expires_at"`
+	ConfirmedAt string                    `json:"confirmed_at,omitempty"`
+	ReleasedAt  string                    `json:"released_at,omitempty"`
+	CreatedAt   string                    `json:"created_at"`
+}
+
+type ReservationItemResponse struct {
+	ProductID uint              `json:"product_id"`
+	VariantID *uint             `json:"variant_id,omitempty"`
+	Quantity  quantity.Quantity `json:"quantity"`
+}
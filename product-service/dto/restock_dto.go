@@ -0,0 +1,34 @@
+package dto
+
+import "product-service/quantity"
+
+type CreateRestockRequest struct {
+	Reference string               `json:"reference" validate:"required,max=100"`
+	Reason    string               `json:"reason" validate:"required,oneof=VOID RETURN"`
+	Items     []RestockItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type RestockItemRequest struct {
+	ProductID uint `json:"product_id" validate:"required"`
+	// wajib untuk produk yang punya varian, 0 berarti produk tanpa varian
+	VariantID uint              `json:"variant_id"`
+	Quantity  quantity.Quantity `json:"quantity" validate:"gt=0"`
+}
+
+type RestockResponse struct {
+	ID        uint                  `json:"id"`
+	Reference string                `json:"reference"`
+	Reason    string                `json:"reason"`
+	Status    string                `json:"status"`
+	Items     []RestockItemResponse `json:"items"`
+	// false jika reference sudah pernah diterapkan dan stok tidak ditambah lagi
+	Applied     bool   `json:"applied"`
+	CancelledAt string `json:"cancelled_at,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type RestockItemResponse struct {
+	ProductID uint              `json:"product_id"`
+	VariantID *uint             `json:"variant_id,omitempty"`
+	Quantity  quantity.Quantity `json:"quantity"`
+}
@@ -0,0 +1,189 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Handler memproses satu event. Error berarti event akan dikirim ulang, jadi handler harus idempoten
+// (lihat deduplikasi di consumer).
+type Handler func(event Event) error
+
+// Broker adalah tujuan publish relay outbox. Implementasi lain (mis. Kafka, NATS) cukup memenuhi interface ini.
+type Broker interface {
+	Publish(event Event) error
+	// Subscribe mendaftarkan consumer bernama; setiap consumer menerima semua event
+	Subscribe(consumer string, handler Handler) error
+	Close() error
+}
+
+// NewBroker memilih implementasi dari konfigurasi: "memory" (hanya di dalam proses) atau "file"
+func NewBroker(kind, path string) (Broker, error) {
+	switch kind {
+	case "memory":
+		return NewMemoryBroker(), nil
+	case "file":
+		return NewFileBroker(path, time.Second)
+	}
+	return nil, fmt.Errorf("unknown event broker %q, use memory or file", kind)
+}
+
+// memoryBroker mengirim event langsung ke subscriber di proses yang sama
+type memoryBroker struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+func NewMemoryBroker() Broker {
+	return &memoryBroker{handlers: make(map[string]Handler)}
+}
+
+func (b *memoryBroker) Publish(event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for consumer, handler := range b.handlers {
+		if err := handler(event); err != nil {
+			return fmt.Errorf("consumer %s failed to handle event %s: %w", consumer, event.ID, err)
+		}
+	}
+	return nil
+}
+
+func (b *memoryBroker) Subscribe(consumer string, handler Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[consumer] = handler
+	return nil
+}
+
+func (b *memoryBroker) Close() error {
+	return nil
+}
+
+// fileBroker menulis event sebagai satu baris JSON per event ke file yang bisa dibaca service lain
+// (mis. volume bersama di docker-compose). Posisi baca tiap consumer disimpan di <file>.<consumer>.offset.
+type fileBroker struct {
+	path     string
+	interval time.Duration
+	mu       sync.Mutex
+	done     chan struct{}
+}
+
+func NewFileBroker(path string, interval time.Duration) (Broker, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create event directory: %w", err)
+	}
+	return &fileBroker{path: path, interval: interval, done: make(chan struct{})}, nil
+}
+
+func (b *fileBroker) Publish(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	file, err := os.OpenFile(b.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open event file: %w", err)
+	}
+	defer file.Close()
+
+	// satu write per event agar baris dari beberapa service tidak tercampur
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return file.Sync()
+}
+
+func (b *fileBroker) Subscribe(consumer string, handler Handler) error {
+	offsetPath := b.path + "." + consumer + ".offset"
+	offset, err := readOffset(offsetPath)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-b.done:
+				return
+			case <-ticker.C:
+			}
+
+			next, err := b.consume(offset, handler)
+			if next != offset {
+				offset = next
+				if err := os.WriteFile(offsetPath, []byte(strconv.FormatInt(offset, 10)), 0o644); err != nil {
+					log.Printf("event consumer %s: failed to save offset: %v", consumer, err)
+				}
+			}
+			if err != nil {
+				log.Printf("event consumer %s: %v", consumer, err)
+			}
+		}
+	}()
+	return nil
+}
+
+// consume membaca baris lengkap mulai dari offset dan mengembalikan offset setelah event terakhir yang berhasil
+func (b *fileBroker) consume(offset int64, handler Handler) (int64, error) {
+	file, err := os.Open(b.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return offset, nil
+		}
+		return offset, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, 0); err != nil {
+		return offset, err
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// baris terakhir yang belum lengkap dibaca lagi pada putaran berikutnya
+			return offset, nil
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &event); err != nil {
+			log.Printf("skipping malformed event at offset %d: %v", offset, err)
+		} else if err := handler(event); err != nil {
+			return offset, fmt.Errorf("failed to handle event %s: %w", event.ID, err)
+		}
+		offset += int64(len(line))
+	}
+}
+
+func (b *fileBroker) Close() error {
+	close(b.done)
+	return nil
+}
+
+func readOffset(path string) (int64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+}
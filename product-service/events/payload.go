@@ -0,0 +1,41 @@
+package events
+
+import (
+	"product-service/money"
+	"product-service/quantity"
+	"time"
+)
+
+// ProductPayload adalah isi event product.created, product.updated dan product.deleted
+type ProductPayload struct {
+	ID         uint              `json:"id"`
+	Name       string            `json:"name"`
+	SKU        string            `json:"sku,omitempty"`
+	Barcodes   []string          `json:"barcodes,omitempty"`
+	Price      money.Money       `json:"price"`
+	Stock      quantity.Quantity `json:"stock"`
+	TaxClass   string            `json:"tax_class"`
+	CategoryID *uint             `json:"category_id,omitempty"` // nil berarti produk tanpa kategori
+	DeletedAt  *time.Time        `json:"deleted_at,omitempty"`
+}
+
+// CategoryPayload adalah isi event category.created, category.updated (termasuk pindah parent) dan category.deleted
+type CategoryPayload struct {
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	ParentID  *uint      `json:"parent_id,omitempty"`
+	SortOrder int        `json:"sort_order"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// StockChangedPayload adalah isi event stock.changed, satu event per produk. Untuk produk yang punya varian,
+// stok adalah jumlah stok varian dan VariantID adalah varian yang berubah.
+type StockChangedPayload struct {
+	ProductID     uint              `json:"product_id"`
+	VariantID     *uint             `json:"variant_id,omitempty"`
+	PreviousStock quantity.Quantity `json:"previous_stock"`
+	Stock         quantity.Quantity `json:"stock"`
+	Delta         quantity.Quantity `json:"delta"`
+	Reason        string            `json:"reason"`
+	Reference     string            `json:"reference,omitempty"` // reservasi atau reference restock penyebab perubahan
+}
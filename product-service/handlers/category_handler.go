@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"product-service/dto"
+	"product-service/services"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CategoryHandler struct {
+	service services.CategoryService
+}
+
+func NewCategoryHandler(service services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		service: service,
+	}
+}
+
+func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
+	var req dto.CreateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body",
+		})
+	}
+
+	if msg := validateCategoryRequest(&req); msg != "" {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: msg,
+		})
+	}
+
+	category, err := h.service.CreateCategory(&req)
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.Status(201).JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Category created successfully",
+		Data:    category,
+	})
+}
+
+// GetAllCategories mengembalikan pohon kategori; ?flat=true mengembalikan daftar datar urut pohon
+func (h *CategoryHandler) GetAllCategories(c *fiber.Ctx) error {
+	categories, err := h.service.GetAllCategories(c.QueryBool("flat"))
+	if err != nil {
+		return c.Status(500).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Categories retrieved successfully",
+		Data:    categories,
+	})
+}
+
+func (h *CategoryHandler) GetCategory(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid category ID",
+		})
+	}
+
+	category, err := h.service.GetCategoryByID(uint(id))
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Category retrieved successfully",
+		Data:    category,
+	})
+}
+
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid category ID",
+		})
+	}
+
+	var req dto.UpdateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body",
+		})
+	}
+
+	if msg := validateCategoryRequest(&req); msg != "" {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: msg,
+		})
+	}
+
+	category, err := h.service.UpdateCategory(uint(id), &req)
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Category updated successfully",
+		Data:    category,
+	})
+}
+
+// MoveCategory memindahkan kategori beserta subkategorinya ke parent lain
+func (h *CategoryHandler) MoveCategory(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid category ID",
+		})
+	}
+
+	var req dto.MoveCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body",
+		})
+	}
+
+	if msg := validateCategoryRequest(&req); msg != "" {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: msg,
+		})
+	}
+
+	category, err := h.service.MoveCategory(uint(id), &req)
+	if err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Category moved successfully",
+		Data:    category,
+	})
+}
+
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid category ID",
+		})
+	}
+
+	if err := h.service.DeleteCategory(uint(id)); err != nil {
+		return categoryError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Category deleted successfully",
+	})
+}
+
+func validateCategoryRequest(req interface{}) string {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		errs := err.(validator.ValidationErrors)
+		var msg []string
+		for _, e := range errs {
+			switch e.Tag() {
+			case "required":
+				msg = append(msg, e.Field()+" is required")
+			case "max":
+				msg = append(msg, e.Field()+" must be at most "+e.Param()+" characters")
+			case "min":
+				msg = append(msg, e.Field()+" must be at least "+e.Param())
+			}
+		}
+		return strings.Join(msg, ", ")
+	}
+	return ""
+}
+
+// categoryError: parent_id yang tidak ada atau membentuk siklus 400, kategori tidak ada 404,
+// nama kembar atau kategori yang masih dipakai 409
+func categoryError(c *fiber.Ctx, err error) error {
+	message := err.Error()
+	statusCode := 500
+	switch {
+	case strings.Contains(message, "invalid"):
+		statusCode = 400
+	case strings.Contains(message, "category not found"):
+		statusCode = 404
+	case strings.Contains(message, "already"), strings.Contains(message, "cannot be deleted"):
+		statusCode = 409
+	}
+
+	return c.Status(statusCode).JSON(dto.ApiResponse{
+		Success: false,
+		Message: message,
+	})
+}
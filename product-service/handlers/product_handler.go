@@ -29,29 +29,23 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 		})
 	}
 
-	// validasii
-	if req.Name == "" {
+	// validasi yang sama dipakai import produk
+	if err := services.ValidateCreateProduct(&req); err != nil {
 		return c.Status(400).JSON(dto.ApiResponse{
 			Success: false,
-			Message: "Product name is required",
-		})
-	}
-	if req.Price <= 0 {
-		return c.Status(400).JSON(dto.ApiResponse{
-			Success: false,
-			Message: "Price must be greater than 0",
-		})
-	}
-	if req.Stock < 0 {
-		return c.Status(400).JSON(dto.ApiResponse{
-			Success: false,
-			Message: "Stock cannot be negative",
+			Message: err.Error(),
 		})
 	}
 
 	product, err := h.service.CreateProduct(&req)
 	if err != nil {
-		return c.Status(500).JSON(dto.ApiResponse{
+		statusCode := 500
+		if strings.Contains(err.Error(), "invalid") {
+			statusCode = 400
+		} else if strings.Contains(err.Error(), "already") {
+			statusCode = 409
+		}
+		return c.Status(statusCode).JSON(dto.ApiResponse{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -71,7 +65,20 @@ func (h *ProductHandler) GetAllProducts(c *fiber.Ctx) error {
 	sortBy := c.Query("sortBy", "created_at")
 	order := c.Query("order", "desc")
 
-	products, total, err := h.service.GetAllProducts(page, limit, search, sortBy, order)
+	// category termasuk semua subkategorinya
+	var categoryID uint64
+	if category := c.Query("category"); category != "" {
+		var err error
+		categoryID, err = strconv.ParseUint(category, 10, 32)
+		if err != nil {
+			return c.Status(400).JSON(dto.ApiResponse{
+				Success: false,
+				Message: "Invalid category ID",
+			})
+		}
+	}
+
+	products, total, err := h.service.GetAllProducts(page, limit, search, sortBy, order, uint(categoryID))
 	if err != nil {
 		return c.Status(500).JSON(dto.ApiResponse{
 			Success: false,
@@ -116,6 +123,39 @@ func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
 	})
 }
 
+// LookupProduct mencari produk dari hasil scan: GET /api/products/lookup?barcode=... atau ?sku=...
+// Label timbangan dikembalikan beserta field scale (berat/harga, quantity dan amount baris).
+func (h *ProductHandler) LookupProduct(c *fiber.Ctx) error {
+	code := strings.TrimSpace(c.Query("barcode"))
+	sku := strings.TrimSpace(c.Query("sku"))
+	if (code == "") == (sku == "") {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Provide either barcode or sku",
+		})
+	}
+
+	product, err := h.service.LookupProduct(code, sku)
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		} else if strings.Contains(err.Error(), "invalid") {
+			statusCode = 400
+		}
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Product retrieved successfully",
+		Data:    product,
+	})
+}
+
 func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 	var validate = validator.New()
 	idParam := c.Params("id")
@@ -148,6 +188,18 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 				if e.Tag() == "min" {
 					msg = append(msg, "Stock cannot be less than 0")
 				}
+			case "SKU":
+				msg = append(msg, "SKU must be at most 64 characters")
+			case "Code":
+				msg = append(msg, "Barcode code is required and must be at most 48 characters")
+			case "Type":
+				msg = append(msg, "Barcode type must be EAN13, UPCA, CODE128, INTERNAL or PLU")
+			case "Options":
+				msg = append(msg, "At most 3 options per product")
+			case "Name":
+				msg = append(msg, "Option name is required and must be at most 50 characters")
+			case "Values":
+				msg = append(msg, "Option values must have 1 to 20 values of at most 50 characters")
 			}
 		}
 
@@ -157,9 +209,28 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 		})
 	}
 
+	var barcodes []dto.BarcodeRequest
+	if req.Barcodes != nil {
+		barcodes = *req.Barcodes
+	}
+	if err := services.NormalizeIdentifiers(req.SKU, barcodes); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
 	product, err := h.service.UpdateProduct(uint(id), &req)
 	if err != nil {
-		return c.Status(500).JSON(dto.ApiResponse{
+		statusCode := 500
+		if strings.Contains(err.Error(), "invalid") {
+			statusCode = 400
+		} else if strings.Contains(err.Error(), "already") {
+			statusCode = 409
+		} else if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		}
+		return c.Status(statusCode).JSON(dto.ApiResponse{
 			Success: false,
 			Message: err.Error(),
 		})
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"product-service/dto"
+	"product-service/importer"
+	"product-service/services"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductImportHandler struct {
+	service services.ProductImportService
+}
+
+func NewProductImportHandler(service services.ProductImportService) *ProductImportHandler {
+	return &ProductImportHandler{
+		service: service,
+	}
+}
+
+// ImportProducts menerima multipart/form-data: file (.csv atau .xlsx), lalu opsional mapping
+// ("name=Nama Barang,price=Harga"), sheet, dry_run dan chunk_size
+func (h *ProductImportHandler) ImportProducts(c *fiber.Ctx) error {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "file is required (multipart/form-data, .csv or .xlsx)",
+		})
+	}
+
+	dryRun, err := strconv.ParseBool(c.FormValue("dry_run", "false"))
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid dry_run, use true or false",
+		})
+	}
+	chunkSize, err := strconv.Atoi(c.FormValue("chunk_size", "0"))
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid chunk_size",
+		})
+	}
+	mapping, err := importer.ParseMapping(c.FormValue("mapping"))
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid file: " + err.Error(),
+		})
+	}
+	defer file.Close()
+
+	table, err := importer.Read(header.Filename, file, c.FormValue("sheet"))
+	if err != nil {
+		return importError(c, err)
+	}
+
+	report, err := h.service.ImportProducts(table, dto.ImportOptions{
+		Mapping:   mapping,
+		DryRun:    dryRun,
+		ChunkSize: chunkSize,
+	}, nil)
+	if err != nil {
+		return importError(c, err)
+	}
+
+	// laporan selalu dikirim; import (bukan dry run) dengan baris error dijawab 400
+	statusCode := 200
+	if report.Failed > 0 && !report.DryRun {
+		statusCode = 400
+	}
+	return c.Status(statusCode).JSON(dto.ApiResponse{
+		Success: report.Failed == 0,
+		Message: services.ImportSummary(report),
+		Data:    report,
+	})
+}
+
+func importError(c *fiber.Ctx, err error) error {
+	statusCode := 500
+	if strings.Contains(err.Error(), "invalid") {
+		statusCode = 400
+	}
+	return c.Status(statusCode).JSON(dto.ApiResponse{
+		Success: false,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"product-service/dto"
+	"product-service/services"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetVariants mengembalikan matriks varian produk beserta stok dan barcodenya
+func (h *ProductHandler) GetVariants(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid product ID",
+		})
+	}
+
+	variants, err := h.service.GetVariants(uint(id))
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		}
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Variants retrieved successfully",
+		Data:    variants,
+	})
+}
+
+// UpdateVariant mengubah SKU, harga, stok atau barcode satu varian; stok produk ikut menyesuaikan
+func (h *ProductHandler) UpdateVariant(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid product ID",
+		})
+	}
+	variantID, err := strconv.ParseUint(c.Params("variantId"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid variant ID",
+		})
+	}
+
+	var req dto.UpdateVariantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body",
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		errs := err.(validator.ValidationErrors)
+		var msg []string
+		for _, e := range errs {
+			switch e.Field() {
+			case "Price":
+				msg = append(msg, "Price must be greater than 0")
+			case "Stock":
+				msg = append(msg, "Stock cannot be less than 0")
+			case "SKU":
+				msg = append(msg, "SKU must be at most 64 characters")
+			case "Code":
+				msg = append(msg, "Barcode code is required and must be at most 48 characters")
+			case "Type":
+				msg = append(msg, "Barcode type must be EAN13, UPCA, CODE128, INTERNAL or PLU")
+			}
+		}
+
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: strings.Join(msg, ", "),
+		})
+	}
+
+	var barcodes []dto.BarcodeRequest
+	if req.Barcodes != nil {
+		barcodes = *req.Barcodes
+	}
+	if err := services.NormalizeIdentifiers(req.SKU, barcodes); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	variant, err := h.service.UpdateVariant(uint(id), uint(variantID), &req)
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "invalid") {
+			statusCode = 400
+		} else if strings.Contains(err.Error(), "already") {
+			statusCode = 409
+		} else if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		}
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Variant updated successfully",
+		Data:    variant,
+	})
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"product-service/dto"
+	"product-service/services"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReservationHandler struct {
+	service services.ReservationService
+}
+
+func NewReservationHandler(service services.ReservationService) *ReservationHandler {
+	return &ReservationHandler{
+		service: service,
+	}
+}
+
+func (h *ReservationHandler) CreateReservation(c *fiber.Ctx) error {
+	var req dto.CreateReservationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body",
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		errs := err.(validator.ValidationErrors)
+		var msg []string
+		for _, e := range errs {
+			switch e.Tag() {
+			case "required":
+				msg = append(msg, e.Field()+" is required")
+			case "gt":
+				msg = append(msg, e.Field()+" must be greater than "+e.Param())
+			case "min":
+				msg = append(msg, e.Field()+" must be at least "+e.Param())
+			case "max":
+				msg = append(msg, e.Field()+" must be at most "+e.Param()+" characters")
+			}
+		}
+
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: strings.Join(msg, ", "),
+		})
+	}
+
+	reservation, err := h.service.CreateReservation(&req)
+	if err != nil {
+		return reservationError(c, err, nil)
+	}
+
+	return c.Status(201).JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Stock reserved successfully",
+		Data:    reservation,
+	})
+}
+
+func (h *ReservationHandler) GetReservation(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid reservation ID",
+		})
+	}
+
+	reservation, err := h.service.GetReservation(uint(id))
+	if err != nil {
+		return reservationError(c, err, nil)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Reservation retrieved successfully",
+		Data:    reservation,
+	})
+}
+
+func (h *ReservationHandler) ConfirmReservation(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid reservation ID",
+		})
+	}
+
+	reservation, err := h.service.ConfirmReservation(uint(id))
+	if err != nil {
+		return reservationError(c, err, reservation)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Reservation confirmed successfully",
+		Data:    reservation,
+	})
+}
+
+func (h *ReservationHandler) ReleaseReservation(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid reservation ID",
+		})
+	}
+
+	reservation, err := h.service.ReleaseReservation(uint(id))
+	if err != nil {
+		return reservationError(c, err, reservation)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Reservation released successfully",
+		Data:    reservation,
+	})
+}
+
+// reservationError menjawab error reservasi; reservation (boleh nil) dikirim di data saat permintaan ditolak
+// karena status reservasi, mis. release untuk reservasi yang sudah CONFIRMED
+func reservationError(c *fiber.Ctx, err error, reservation *dto.ReservationResponse) error {
+	message := err.Error()
+	statusCode := 500
+	switch {
+	case strings.Contains(message, "reservation not found"):
+		statusCode = 404
+	case strings.Contains(message, "has expired"):
+		statusCode = 410
+	case strings.Contains(message, "insufficient stock"), strings.HasPrefix(message, "reservation ") && strings.Contains(message, " is "):
+		statusCode = 409
+	case strings.Contains(message, "not found"), strings.Contains(message, "invalid"):
+		statusCode = 400
+	}
+
+	response := dto.ApiResponse{
+		Success: false,
+		Message: message,
+	}
+	if reservation != nil {
+		response.Data = reservation
+	}
+	return c.Status(statusCode).JSON(response)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"product-service/dto"
+	"product-service/services"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type RestockHandler struct {
+	service services.RestockService
+}
+
+func NewRestockHandler(service services.RestockService) *RestockHandler {
+	return &RestockHandler{
+		service: service,
+	}
+}
+
+// CreateRestock mengembalikan 201 jika stok ditambahkan, 200 jika reference sudah pernah diterapkan
+func (h *RestockHandler) CreateRestock(c *fiber.Ctx) error {
+	var req dto.CreateRestockRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body",
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		errs := err.(validator.ValidationErrors)
+		var msg []string
+		for _, e := range errs {
+			switch e.Tag() {
+			case "required":
+				msg = append(msg, e.Field()+" is required")
+			case "gt":
+				msg = append(msg, e.Field()+" must be greater than "+e.Param())
+			case "min":
+				msg = append(msg, e.Field()+" must have at least "+e.Param()+" entry")
+			case "max":
+				msg = append(msg, e.Field()+" must be at most "+e.Param()+" characters")
+			case "oneof":
+				msg = append(msg, e.Field()+" must be one of "+e.Param())
+			}
+		}
+
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: strings.Join(msg, ", "),
+		})
+	}
+
+	restock, err := h.service.CreateRestock(&req)
+	if err != nil {
+		return restockError(c, err)
+	}
+
+	statusCode := 200
+	message := "Restock already applied"
+	if restock.Applied {
+		statusCode = 201
+		message = "Stock restocked successfully"
+	}
+	return c.Status(statusCode).JSON(dto.ApiResponse{
+		Success: true,
+		Message: message,
+		Data:    restock,
+	})
+}
+
+func (h *RestockHandler) GetRestock(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid restock ID",
+		})
+	}
+
+	restock, err := h.service.GetRestock(uint(id))
+	if err != nil {
+		return restockError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Restock retrieved successfully",
+		Data:    restock,
+	})
+}
+
+func (h *RestockHandler) CancelRestock(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid restock ID",
+		})
+	}
+
+	restock, err := h.service.CancelRestock(uint(id))
+	if err != nil {
+		return restockError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Restock cancelled successfully",
+		Data:    restock,
+	})
+}
+
+func restockError(c *fiber.Ctx, err error) error {
+	message := err.Error()
+	statusCode := 500
+	switch {
+	case strings.Contains(message, "restock not found"):
+		statusCode = 404
+	case strings.Contains(message, "insufficient stock"):
+		statusCode = 409
+	case strings.Contains(message, "not found"), strings.Contains(message, "invalid"):
+		statusCode = 400
+	}
+
+	return c.Status(statusCode).JSON(dto.ApiResponse{
+		Success: false,
+		Message: message,
+	})
+}
@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// field produk yang bisa diimport
+const (
+	FieldSKU      = "sku"
+	FieldName     = "name"
+	FieldPrice    = "price"
+	FieldStock    = "stock"
+	FieldTaxClass = "tax_class"
+	FieldBarcodes = "barcodes"
+	FieldCategory = "category" // id kategori atau path, mis. "Electronics > Computers"
+)
+
+// Fields adalah semua field import sesuai urutan yang ditampilkan di pesan error
+var Fields = []string{FieldSKU, FieldName, FieldPrice, FieldStock, FieldTaxClass, FieldBarcodes, FieldCategory}
+
+// field yang wajib punya kolom; stok, kelas pajak, barcode dan kategori boleh tidak ada
+var requiredFields = []string{FieldSKU, FieldName, FieldPrice}
+
+// nama header yang dikenali tanpa mapping, dibandingkan setelah dinormalisasi (lihat headerKey)
+var fieldAliases = map[string][]string{
+	FieldSKU:      {"sku", "kode", "kode barang", "kode produk", "product sku"},
+	FieldName:     {"name", "nama", "nama barang", "nama produk", "product", "product name"},
+	FieldPrice:    {"price", "harga", "harga jual", "selling price"},
+	FieldStock:    {"stock", "stok", "qty", "quantity", "jumlah"},
+	FieldTaxClass: {"tax class", "kelas pajak", "pajak", "tax"},
+	FieldBarcodes: {"barcode", "barcodes"},
+	FieldCategory: {"category", "category id", "kategori"},
+}
+
+// Mapping adalah indeks kolom per field; field yang tidak ada di file tidak punya entri
+type Mapping map[string]int
+
+// ParseMapping membaca mapping kolom dari daftar dipisah koma, masing-masing FIELD=KOLOM,
+// mis. "name=Nama Barang,price=Harga Jual". KOLOM adalah teks header di file.
+func ParseMapping(spec string) (map[string]string, error) {
+	columns := make(map[string]string)
+	for _, entry := range strings.Split(spec, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		field, column, ok := strings.Cut(entry, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		column = strings.TrimSpace(column)
+		if !ok || field == "" || column == "" {
+			return nil, fmt.Errorf("invalid mapping %q, use FIELD=COLUMN", strings.TrimSpace(entry))
+		}
+		if _, known := fieldAliases[field]; !known {
+			return nil, fmt.Errorf("invalid mapping, unknown field %s (use %s)", field, strings.Join(Fields, ", "))
+		}
+		if _, exists := columns[field]; exists {
+			return nil, fmt.Errorf("invalid mapping, field %s is mapped more than once", field)
+		}
+		columns[field] = column
+	}
+	return columns, nil
+}
+
+// Resolve mencari kolom tiap field: kolom dari mapping lebih dulu, field lainnya dicocokkan
+// dengan nama header yang umum (mis. "Harga" untuk price)
+func (t *Table) Resolve(columns map[string]string) (Mapping, error) {
+	headers := make(map[string]int, len(t.Header))
+	for i, header := range t.Header {
+		key := headerKey(header)
+		if _, exists := headers[key]; !exists && key != "" {
+			headers[key] = i
+		}
+	}
+
+	mapping := make(Mapping, len(Fields))
+	for field, column := range columns {
+		index, ok := headers[headerKey(column)]
+		if !ok {
+			return nil, fmt.Errorf("invalid mapping, column %q for %s not found in header", column, field)
+		}
+		mapping[field] = index
+	}
+	for _, field := range Fields {
+		if _, mapped := mapping[field]; mapped {
+			continue
+		}
+		for _, alias := range fieldAliases[field] {
+			if index, ok := headers[headerKey(alias)]; ok {
+				mapping[field] = index
+				break
+			}
+		}
+	}
+
+	for _, field := range requiredFields {
+		if _, ok := mapping[field]; !ok {
+			return nil, fmt.Errorf("invalid file, no column for %s found, map it with %s=<column>", field, field)
+		}
+	}
+	return mapping, nil
+}
+
+// Value mengembalikan isi sel field di baris row; ok false jika field tidak punya kolom
+func (m Mapping) Value(row Row, field string) (value string, ok bool) {
+	index, ok := m[field]
+	if !ok {
+		return "", false
+	}
+	return row.Cell(index), true
+}
+
+// headerKey menyamakan penulisan header: huruf kecil, "_" dan "-" sama dengan spasi, spasi berlebih dibuang
+func headerKey(header string) string {
+	header = strings.ToLower(strings.TrimPrefix(header, "\ufeff"))
+	header = strings.NewReplacer("_", " ", "-", " ").Replace(header)
+	return strings.Join(strings.Fields(header), " ")
+}
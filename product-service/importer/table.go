@@ -0,0 +1,126 @@
+// Package importer membaca file produk (CSV atau XLSX) menjadi tabel teks dan memetakan kolomnya
+// ke field produk. Validasi isi sel dilakukan oleh service, package ini hanya membaca file.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// batas satu kali import, file yang lebih besar dipecah oleh pengguna. Ukuran file tetap di bawah
+// batas body default Fiber (4 MB) di gateway dan service.
+const (
+	MaxFileSize = 2 << 20
+	MaxRows     = 5000
+)
+
+// Table adalah isi satu sheet: header lalu baris data yang tidak kosong
+type Table struct {
+	Header []string
+	Rows   []Row
+}
+
+// Row adalah satu baris data; Line adalah nomor baris di file (mulai 1) untuk laporan error
+type Row struct {
+	Line  int
+	Cells []string
+}
+
+// Cell mengembalikan isi kolom index tanpa spasi di awal/akhir, kosong jika baris lebih pendek
+func (r Row) Cell(index int) string {
+	if index < 0 || index >= len(r.Cells) {
+		return ""
+	}
+	return strings.TrimSpace(r.Cells[index])
+}
+
+// Read membaca file sesuai ekstensinya (.csv atau .xlsx). sheet hanya dipakai XLSX, kosong berarti sheet pertama.
+func Read(filename string, r io.Reader, sheet string) (*Table, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > MaxFileSize {
+		return nil, fmt.Errorf("invalid file, larger than %d MB", MaxFileSize>>20)
+	}
+
+	var rows []Row
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".txt":
+		rows, err = readCSV(data)
+	case ".xlsx":
+		rows, err = readXLSX(data, sheet)
+	default:
+		return nil, fmt.Errorf("invalid file %s, use .csv or .xlsx", filepath.Base(filename))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newTable(rows)
+}
+
+// newTable memisahkan header (baris pertama yang tidak kosong) dari data, baris kosong dilewati
+func newTable(rows []Row) (*Table, error) {
+	table := &Table{}
+	for _, row := range rows {
+		if isBlank(row.Cells) {
+			continue
+		}
+		if table.Header == nil {
+			table.Header = row.Cells
+			continue
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	if table.Header == nil {
+		return nil, errors.New("invalid file, no header row found")
+	}
+	if len(table.Rows) == 0 {
+		return nil, errors.New("invalid file, no product rows found")
+	}
+	if len(table.Rows) > MaxRows {
+		return nil, fmt.Errorf("invalid file, %d rows exceed the limit of %d rows per import", len(table.Rows), MaxRows)
+	}
+	return table, nil
+}
+
+func isBlank(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// readCSV membaca CSV dengan pemisah koma atau titik koma (ekspor Excel berlocale Indonesia),
+// dipilih dari mana yang lebih banyak muncul di baris pertama. Nomor baris adalah nomor record.
+func readCSV(data []byte) ([]Row, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows []Row
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv file: %w", err)
+		}
+		rows = append(rows, Row{Line: len(rows) + 1, Cells: cells})
+	}
+}
@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     string
+		want     *Table
+		wantErr  string
+	}{
+		{
+			name:     "comma separated",
+			filename: "produk.csv",
+			data:     "sku,name,price\nKP-01,Kopi,15000\nKP-02,\"Teh, Manis\",8000\n",
+			want: &Table{
+				Header: []string{"sku", "name", "price"},
+				Rows: []Row{
+					{Line: 2, Cells: []string{"KP-01", "Kopi", "15000"}},
+					{Line: 3, Cells: []string{"KP-02", "Teh, Manis", "8000"}},
+				},
+			},
+		},
+		{
+			name:     "semicolon separated with bom and decimal comma",
+			filename: "PRODUK.CSV",
+			data:     "\xef\xbb\xbfKode;Nama;Harga\r\nKP-01;Kopi;15000,50\r\n",
+			want: &Table{
+				Header: []string{"Kode", "Nama", "Harga"},
+				Rows:   []Row{{Line: 2, Cells: []string{"KP-01", "Kopi", "15000,50"}}},
+			},
+		},
+		{
+			name:     "blank rows skipped, line numbers kept",
+			filename: "produk.txt",
+			data:     ",,\nsku,name\n,\nKP-01,Kopi,extra\nKP-02\n",
+			want: &Table{
+				Header: []string{"sku", "name"},
+				Rows: []Row{
+					{Line: 4, Cells: []string{"KP-01", "Kopi", "extra"}},
+					{Line: 5, Cells: []string{"KP-02"}},
+				},
+			},
+		},
+		{
+			name:     "stray quote read as text",
+			filename: "produk.csv",
+			data:     "sku,name\nKP-01,Kopi 5\" cup\n",
+			want: &Table{
+				Header: []string{"sku", "name"},
+				Rows:   []Row{{Line: 2, Cells: []string{"KP-01", "Kopi 5\" cup"}}},
+			},
+		},
+		{name: "unsupported extension", filename: "produk.xls", data: "sku\nKP-01\n", wantErr: "use .csv or .xlsx"},
+		{name: "empty file", filename: "produk.csv", data: "", wantErr: "no header row found"},
+		{name: "header only", filename: "produk.csv", data: "sku,name,price\n", wantErr: "no product rows found"},
+		{
+			name:     "unterminated quote runs to the end of file",
+			filename: "produk.csv",
+			data:     "sku,name\nKP-01,\"Kopi\nKP-02,Teh\n",
+			want: &Table{
+				Header: []string{"sku", "name"},
+				Rows:   []Row{{Line: 2, Cells: []string{"KP-01", "Kopi\nKP-02,Teh\n"}}},
+			},
+		},
+		{name: "file too large", filename: "produk.csv", data: strings.Repeat("a", MaxFileSize+1), wantErr: "larger than 2 MB"},
+		{
+			name:     "too many rows",
+			filename: "produk.csv",
+			data:     "sku\n" + strings.Repeat("KP\n", MaxRows+1),
+			wantErr:  "5001 rows exceed the limit of 5000 rows",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := Read(tt.filename, strings.NewReader(tt.data), "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(table, tt.want) {
+				t.Errorf("table = %+v, want %+v", table, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// XLSX dibaca langsung dari zip dan XML-nya (SpreadsheetML) tanpa library tambahan; hanya nilai sel
+// yang dibaca, format angka dan rumus diabaikan (nilai hasil rumus yang tersimpan tetap dipakai)
+
+// batas ukuran satu file XML di dalam zip setelah didekompresi
+const maxXLSXPartSize = 64 << 20
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxText adalah teks biasa (<t>) atau rich text (beberapa <r><t>)
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	text := t.T
+	for _, run := range t.Runs {
+		text += run.T
+	}
+	return text
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string   `xml:"r,attr"`
+			T      string   `xml:"t,attr"`
+			V      string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX membaca satu sheet menjadi baris sel teks; nomor baris dan posisi kolom mengikuti referensi sel
+// (mis. C5) sehingga sama dengan yang terlihat di Excel
+func readXLSX(data []byte, sheet string) ([]Row, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("invalid xlsx file, not a valid zip archive")
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := findSheet(files, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	var sharedStrings xlsxSharedStrings
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXLSXPart(file, &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("invalid xlsx file, %s is missing", sheetPath)
+	}
+	var worksheet xlsxWorksheet
+	if err := decodeXLSXPart(file, &worksheet); err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for _, row := range worksheet.Rows {
+		line := row.R
+		if line == 0 {
+			line = 1
+			if len(rows) > 0 {
+				line = rows[len(rows)-1].Line + 1
+			}
+		}
+
+		var cells []string
+		for _, cell := range row.Cells {
+			column := len(cells)
+			if cell.R != "" {
+				if column, err = columnIndex(cell.R); err != nil {
+					return nil, err
+				}
+			}
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+
+			value, err := cellValue(cell.T, cell.V, cell.Inline, sharedStrings.Items)
+			if err != nil {
+				return nil, fmt.Errorf("invalid xlsx cell %s: %w", cell.R, err)
+			}
+			cells[column] = value
+		}
+		rows = append(rows, Row{Line: line, Cells: cells})
+	}
+	return rows, nil
+}
+
+// findSheet mencari path XML sheet lewat workbook.xml dan relasinya
+func findSheet(files map[string]*zip.File, name string) (string, error) {
+	var workbook xlsxWorkbook
+	file, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("invalid xlsx file, xl/workbook.xml is missing")
+	}
+	if err := decodeXLSXPart(file, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("invalid xlsx file, workbook has no sheets")
+	}
+
+	rid := workbook.Sheets[0].RID
+	if name != "" {
+		rid = ""
+		for _, sheet := range workbook.Sheets {
+			if strings.EqualFold(sheet.Name, name) {
+				rid = sheet.RID
+				break
+			}
+		}
+		if rid == "" {
+			return "", fmt.Errorf("invalid sheet, %s not found in workbook", name)
+		}
+	}
+
+	var relationships xlsxRelationships
+	file, ok = files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "", errors.New("invalid xlsx file, xl/_rels/workbook.xml.rels is missing")
+	}
+	if err := decodeXLSXPart(file, &relationships); err != nil {
+		return "", err
+	}
+	for _, relationship := range relationships.Relationships {
+		if relationship.ID != rid {
+			continue
+		}
+		// target relatif terhadap folder xl/, atau absolut dari akar paket
+		if strings.HasPrefix(relationship.Target, "/") {
+			return strings.TrimPrefix(relationship.Target, "/"), nil
+		}
+		return path.Join("xl", relationship.Target), nil
+	}
+	return "", fmt.Errorf("invalid xlsx file, sheet relationship %s not found", rid)
+}
+
+func decodeXLSXPart(file *zip.File, v interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("invalid xlsx file, cannot open %s: %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxXLSXPartSize+1))
+	if err != nil {
+		return fmt.Errorf("invalid xlsx file, cannot read %s: %w", file.Name, err)
+	}
+	if len(data) > maxXLSXPartSize {
+		return fmt.Errorf("invalid xlsx file, %s is too large", file.Name)
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid xlsx file, cannot parse %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// cellValue mengubah nilai sel menjadi teks sesuai tipenya (atribut t)
+func cellValue(cellType, value string, inline xlsxText, sharedStrings []xlsxText) (string, error) {
+	switch cellType {
+	case "s":
+		index, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || index < 0 || index >= len(sharedStrings) {
+			return "", fmt.Errorf("shared string %q not found", value)
+		}
+		return sharedStrings[index].String(), nil
+	case "inlineStr":
+		return inline.String(), nil
+	case "b":
+		if value == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case "str", "e":
+		return value, nil
+	default:
+		// angka disimpan dalam notasi terpendek, notasi ilmiah (1.5E+3) ditulis ulang sebagai desimal biasa
+		if strings.ContainsAny(value, "eE") {
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return "", fmt.Errorf("invalid number %q", value)
+			}
+			return strconv.FormatFloat(number, 'f', -1, 64), nil
+		}
+		return value, nil
+	}
+}
+
+// columnIndex mengubah referensi sel seperti "C5" atau "AA12" menjadi indeks kolom mulai 0
+func columnIndex(ref string) (int, error) {
+	column := 0
+	letters := 0
+	for _, c := range strings.ToUpper(ref) {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		column = column*26 + int(c-'A'+1)
+		letters++
+	}
+	// kolom terakhir Excel adalah XFD (16384)
+	if letters == 0 || column > 16384 {
+		return 0, fmt.Errorf("invalid xlsx cell reference %q", ref)
+	}
+	return column - 1, nil
+}
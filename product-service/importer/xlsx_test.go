@@ -0,0 +1,195 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testWorkbook = `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Produk" sheetId="1" r:id="rId1"/><sheet name="Harga" sheetId="2" r:id="rId2"/></sheets></workbook>`
+	testRelationships = `<Relationships>
+<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="/xl/worksheets/sheet2.xml"/></Relationships>`
+	testSharedStrings = `<sst><si><t>SKU</t></si><si><t>Nama</t></si><si><r><t>Kopi </t></r><r><t>Susu</t></r></si></sst>`
+	testSheet         = `<worksheet><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>Harga</t></is></c></row>
+<row r="3"><c r="A3" t="str"><v>KP-01</v></c><c r="B3" t="s"><v>2</v></c><c r="C3"><v>1.5E+4</v></c></row>
+<row r="4"><c r="A4" t="str"><v>KP-02</v></c><c r="C4"><v>12500</v></c><c r="D4" t="b"><v>1</v></c></row>
+</sheetData></worksheet>`
+	testPriceSheet = `<worksheet><sheetData>
+<row><c><v>SKU</v></c><c><v>Harga</v></c></row>
+<row><c t="inlineStr"><is><t>KP-01</t></is></c><c><v>16000</v></c></row>
+</sheetData></worksheet>`
+)
+
+// xlsxFile membuat paket XLSX dari part standar, parts menimpa atau (dengan isi kosong) menghapus part
+func xlsxFile(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	files := map[string]string{
+		"xl/workbook.xml":            testWorkbook,
+		"xl/_rels/workbook.xml.rels": testRelationships,
+		"xl/sharedStrings.xml":       testSharedStrings,
+		"xl/worksheets/sheet1.xml":   testSheet,
+		"xl/worksheets/sheet2.xml":   testPriceSheet,
+	}
+	for name, content := range parts {
+		files[name] = content
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range files {
+		if content == "" {
+			continue
+		}
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadXLSX(t *testing.T) {
+	sheet := func(rows string) map[string]string {
+		return map[string]string{"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` + rows + `</sheetData></worksheet>`}
+	}
+	header := `<row r="1"><c r="A1" t="str"><v>SKU</v></c></row>`
+
+	tests := []struct {
+		name    string
+		data    []byte
+		sheet   string
+		want    *Table
+		wantErr string
+	}{
+		{
+			name: "first sheet with shared, rich and inline strings",
+			data: xlsxFile(t, nil),
+			want: &Table{
+				Header: []string{"SKU", "Nama", "Harga"},
+				Rows: []Row{
+					{Line: 3, Cells: []string{"KP-01", "Kopi Susu", "15000"}},
+					{Line: 4, Cells: []string{"KP-02", "", "12500", "TRUE"}},
+				},
+			},
+		},
+		{
+			name:  "sheet by name with absolute target and no cell references",
+			data:  xlsxFile(t, nil),
+			sheet: "harga",
+			want: &Table{
+				Header: []string{"SKU", "Harga"},
+				Rows:   []Row{{Line: 2, Cells: []string{"KP-01", "16000"}}},
+			},
+		},
+		{
+			name: "workbook without shared strings",
+			data: xlsxFile(t, map[string]string{"xl/sharedStrings.xml": "", "xl/worksheets/sheet1.xml": testPriceSheet}),
+			want: &Table{
+				Header: []string{"SKU", "Harga"},
+				Rows:   []Row{{Line: 2, Cells: []string{"KP-01", "16000"}}},
+			},
+		},
+		{name: "not a zip archive", data: []byte("SKU,Nama\nKP-01,Kopi"), wantErr: "not a valid zip archive"},
+		{name: "empty file", data: nil, wantErr: "not a valid zip archive"},
+		{name: "missing workbook", data: xlsxFile(t, map[string]string{"xl/workbook.xml": ""}), wantErr: "xl/workbook.xml is missing"},
+		{name: "workbook without sheets", data: xlsxFile(t, map[string]string{"xl/workbook.xml": "<workbook/>"}), wantErr: "workbook has no sheets"},
+		{name: "unknown sheet", data: xlsxFile(t, nil), sheet: "Stok", wantErr: "Stok not found in workbook"},
+		{name: "missing relationships", data: xlsxFile(t, map[string]string{"xl/_rels/workbook.xml.rels": ""}), wantErr: "workbook.xml.rels is missing"},
+		{
+			name:    "sheet without relationship",
+			data:    xlsxFile(t, map[string]string{"xl/_rels/workbook.xml.rels": "<Relationships/>"}),
+			wantErr: "sheet relationship rId1 not found",
+		},
+		{name: "missing worksheet", data: xlsxFile(t, map[string]string{"xl/worksheets/sheet1.xml": ""}), wantErr: "xl/worksheets/sheet1.xml is missing"},
+		{
+			name:    "broken worksheet xml",
+			data:    xlsxFile(t, map[string]string{"xl/worksheets/sheet1.xml": "<worksheet><sheetData><row>"}),
+			wantErr: "cannot parse xl/worksheets/sheet1.xml",
+		},
+		{
+			name:    "broken shared strings xml",
+			data:    xlsxFile(t, map[string]string{"xl/sharedStrings.xml": "<sst><si>"}),
+			wantErr: "cannot parse xl/sharedStrings.xml",
+		},
+		{
+			name:    "shared string out of range",
+			data:    xlsxFile(t, sheet(`<row r="1"><c r="A1" t="s"><v>9</v></c></row>`)),
+			wantErr: `invalid xlsx cell A1: shared string "9" not found`,
+		},
+		{
+			name:    "invalid cell reference",
+			data:    xlsxFile(t, sheet(`<row r="1"><c r="15" t="str"><v>SKU</v></c></row>`)),
+			wantErr: `invalid xlsx cell reference "15"`,
+		},
+		{
+			name:    "column beyond XFD",
+			data:    xlsxFile(t, sheet(`<row r="1"><c r="XFE1" t="str"><v>SKU</v></c></row>`)),
+			wantErr: `invalid xlsx cell reference "XFE1"`,
+		},
+		{
+			name:    "invalid number",
+			data:    xlsxFile(t, sheet(header+`<row r="2"><c r="A2"><v>1E+</v></c></row>`)),
+			wantErr: `invalid xlsx cell A2: invalid number "1E+"`,
+		},
+		{name: "header only", data: xlsxFile(t, sheet(header)), wantErr: "no product rows found"},
+		{name: "empty sheet", data: xlsxFile(t, sheet(`<row r="1"><c r="A1" t="str"><v> </v></c></row>`)), wantErr: "no header row found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := Read("produk.xlsx", bytes.NewReader(tt.data), tt.sheet)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(table, tt.want) {
+				t.Errorf("table = %+v, want %+v", table, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    int
+		wantErr bool
+	}{
+		{"A1", 0, false},
+		{"c5", 2, false},
+		{"Z9", 25, false},
+		{"AA12", 26, false},
+		{"XFD1048576", 16383, false},
+		{"XFE1", 0, true},
+		{"12", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := columnIndex(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("columnIndex(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("columnIndex(%q) = %d, want %d", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
@@ -22,11 +22,32 @@ func main() {
 	config.InitDatabase()
 	defer config.CloseDatabase()
 
+	// perintah CLI (migrate / seed) dijalankan lalu keluar tanpa menjalankan server
+	if len(os.Args) > 1 {
+		if err := runCommand(config.DB, os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// migrasi diterapkan saat start kecuali MIGRATE_ON_START=false (mis. jika dijalankan terpisah lewat "migrate")
+	if os.Getenv("MIGRATE_ON_START") != "false" {
+		if err := migrateUp(config.DB); err != nil {
+			log.Fatal("Failed to apply migrations: ", err)
+		}
+	}
+
+	// event domain dari outbox dikirim ke broker oleh relay
+	broker := routes.SetupEvents()
+	defer broker.Close()
+
 	app := fiber.New()
 	app.Use(cors.New())
 
 	// Setup routes
 	routes.SetupProductRoutes(app)
+	routes.SetupCategoryRoutes(app)
+	routes.SetupStockRoutes(app)
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.SendString("Product Service is running")
 	})
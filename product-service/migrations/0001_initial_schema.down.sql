@@ -0,0 +1,12 @@
+-- rollback 0001: menghapus seluruh skema product-service
+
+DROP FUNCTION IF EXISTS update_product_stock(INTEGER, INTEGER);
+DROP FUNCTION IF EXISTS check_stock_availability(INTEGER, INTEGER);
+
+DROP TABLE IF EXISTS stock_restock_items;
+DROP TABLE IF EXISTS stock_restocks;
+DROP TABLE IF EXISTS stock_reservation_items;
+DROP TABLE IF EXISTS stock_reservations;
+DROP TABLE IF EXISTS products;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
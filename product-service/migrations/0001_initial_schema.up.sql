@@ -0,0 +1,155 @@
+-- 0001 skema awal product-service: produk, reservasi stok dan restock.
+-- Memakai IF NOT EXISTS agar database lama yang dibuat dari migration.sql bisa diadopsi tanpa error.
+
+-- 1. BUAT TABEL
+
+-- tabel products
+CREATE TABLE IF NOT EXISTS products (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    price DECIMAL(15,2) NOT NULL CHECK (price >= 0),
+    stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    tax_class VARCHAR(30) NOT NULL DEFAULT 'STANDARD', -- tarif per kelas diatur di transaction-service (tabel tax_rates)
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- tabel stock_reservations (stok yang ditahan untuk keranjang/checkout sampai dikonfirmasi, dilepas atau kedaluwarsa)
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id SERIAL PRIMARY KEY,
+    reference VARCHAR(100) NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'CONFIRMED', 'RELEASED', 'EXPIRED')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE NULL,
+    released_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- tabel stock_reservation_items (jumlah yang ditahan per produk)
+CREATE TABLE IF NOT EXISTS stock_reservation_items (
+    reservation_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (reservation_id, product_id),
+    CONSTRAINT fk_stock_reservation_items_reservation_id
+        FOREIGN KEY (reservation_id) REFERENCES stock_reservations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_reservation_items_product_id
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT
+);
+
+-- tabel stock_restocks (barang yang dikembalikan ke stok karena void/retur, reference unik agar tidak diterapkan dua kali)
+CREATE TABLE IF NOT EXISTS stock_restocks (
+    id SERIAL PRIMARY KEY,
+    reference VARCHAR(100) NOT NULL UNIQUE,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('VOID', 'RETURN')),
+    status VARCHAR(20) NOT NULL DEFAULT 'APPLIED' CHECK (status IN ('APPLIED', 'CANCELLED')),
+    cancelled_at TIMESTAMP WITH TIME ZONE NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- tabel stock_restock_items (jumlah yang dikembalikan per produk)
+CREATE TABLE IF NOT EXISTS stock_restock_items (
+    restock_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (restock_id, product_id),
+    CONSTRAINT fk_stock_restock_items_restock_id
+        FOREIGN KEY (restock_id) REFERENCES stock_restocks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_stock_restock_items_product_id
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT
+);
+
+
+-- 2. BUAT INDEXES
+
+-- Index untuk produk
+CREATE INDEX IF NOT EXISTS idx_products_name ON products(name);
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_products_created_at ON products(created_at);
+
+-- Index untuk reservasi stok
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_status_expires_at ON stock_reservations(status, expires_at);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_reference ON stock_reservations(reference) WHERE status = 'ACTIVE';
+CREATE INDEX IF NOT EXISTS idx_stock_reservation_items_product_id ON stock_reservation_items(product_id);
+CREATE INDEX IF NOT EXISTS idx_stock_restock_items_product_id ON stock_restock_items(product_id);
+
+
+-- 3. CREATE TRIGGERS FOR UPDATED_AT
+
+-- Function untuk mengupdate kolom updated_at
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Triggers for products
+DROP TRIGGER IF EXISTS trigger_products_updated_at ON products;
+CREATE TRIGGER trigger_products_updated_at
+    BEFORE UPDATE ON products
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Triggers for stock_reservations / stock_restocks
+DROP TRIGGER IF EXISTS trigger_stock_reservations_updated_at ON stock_reservations;
+CREATE TRIGGER trigger_stock_reservations_updated_at
+    BEFORE UPDATE ON stock_reservations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS trigger_stock_restocks_updated_at ON stock_restocks;
+CREATE TRIGGER trigger_stock_restocks_updated_at
+    BEFORE UPDATE ON stock_restocks
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+
+-- 4. CREATE STORED PROCEDURES/FUNCTIONS
+
+-- Function untuk mengecek stok produk yang tersedia
+CREATE OR REPLACE FUNCTION check_stock_availability(p_product_id INTEGER, p_quantity INTEGER)
+RETURNS BOOLEAN AS $$
+DECLARE
+    current_stock INTEGER;
+BEGIN
+    SELECT stock INTO current_stock 
+    FROM products 
+    WHERE id = p_product_id AND deleted_at IS NULL;
+
+    IF current_stock IS NULL THEN
+        RETURN FALSE;
+    END IF;
+
+    RETURN current_stock >= p_quantity;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function untuk mengupdate stok produk setelah transaksi
+CREATE OR REPLACE FUNCTION update_product_stock(p_product_id INTEGER, p_quantity INTEGER)
+RETURNS BOOLEAN AS $$
+DECLARE
+    current_stock INTEGER;
+BEGIN
+    -- ambil stock saat ini
+    SELECT stock INTO current_stock 
+    FROM products 
+    WHERE id = p_product_id AND deleted_at IS NULL;
+
+    IF current_stock IS NULL OR current_stock < p_quantity THEN
+        RETURN FALSE;
+    END IF;
+
+    -- Update stock
+    UPDATE products 
+    SET stock = stock - p_quantity,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = p_product_id;
+
+    RETURN TRUE;
+END;
+$$ LANGUAGE plpgsql;
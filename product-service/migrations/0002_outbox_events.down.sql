@@ -0,0 +1,3 @@
+-- rollback 0002: menghapus tabel outbox
+
+DROP TABLE IF EXISTS outbox_events;
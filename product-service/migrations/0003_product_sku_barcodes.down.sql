@@ -0,0 +1,5 @@
+-- rollback 0003: menghapus barcode dan SKU produk
+
+DROP TABLE IF EXISTS product_barcodes;
+DROP INDEX IF EXISTS idx_products_sku;
+ALTER TABLE products DROP COLUMN IF EXISTS sku;
@@ -0,0 +1,18 @@
+-- 0003 SKU dan barcode produk untuk scanner kasir
+
+-- SKU unik di antara produk aktif, produk yang dihapus tidak mengunci SKU-nya
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sku VARCHAR(64) NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products(sku) WHERE deleted_at IS NULL;
+
+-- tabel product_barcodes (satu produk bisa punya beberapa barcode, setiap kode hanya milik satu produk)
+CREATE TABLE IF NOT EXISTS product_barcodes (
+    code VARCHAR(48) PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('EAN13', 'UPCA', 'CODE128', 'INTERNAL')),
+    position SMALLINT NOT NULL DEFAULT 0, -- urutan barcode sesuai input
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_product_barcodes_product_id
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_barcodes_product_id ON product_barcodes(product_id);
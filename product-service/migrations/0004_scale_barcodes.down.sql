@@ -0,0 +1,10 @@
+-- rollback 0004: kode PLU dihapus dan jumlah pecahan dibulatkan (stok ke bawah, jumlah reservasi/restock ke atas)
+
+DELETE FROM product_barcodes WHERE type = 'PLU';
+ALTER TABLE product_barcodes DROP CONSTRAINT IF EXISTS product_barcodes_type_check;
+ALTER TABLE product_barcodes ADD CONSTRAINT product_barcodes_type_check
+    CHECK (type IN ('EAN13', 'UPCA', 'CODE128', 'INTERNAL'));
+
+ALTER TABLE stock_restock_items ALTER COLUMN quantity TYPE INTEGER USING CEIL(quantity);
+ALTER TABLE stock_reservation_items ALTER COLUMN quantity TYPE INTEGER USING CEIL(quantity);
+ALTER TABLE products ALTER COLUMN stock TYPE INTEGER USING FLOOR(stock);
@@ -0,0 +1,10 @@
+-- 0004 barang timbang: stok dan jumlah reservasi/restock boleh pecahan (3 desimal, mis. 0.735 kg)
+-- dan barcode jenis PLU untuk kode barang di label timbangan
+
+ALTER TABLE products ALTER COLUMN stock TYPE DECIMAL(15,3);
+ALTER TABLE stock_reservation_items ALTER COLUMN quantity TYPE DECIMAL(15,3);
+ALTER TABLE stock_restock_items ALTER COLUMN quantity TYPE DECIMAL(15,3);
+
+ALTER TABLE product_barcodes DROP CONSTRAINT IF EXISTS product_barcodes_type_check;
+ALTER TABLE product_barcodes ADD CONSTRAINT product_barcodes_type_check
+    CHECK (type IN ('EAN13', 'UPCA', 'CODE128', 'INTERNAL', 'PLU'));
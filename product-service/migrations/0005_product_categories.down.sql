@@ -0,0 +1,6 @@
+-- rollback 0005: menghapus kategori produk
+
+DROP INDEX IF EXISTS idx_products_category_id;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS fk_products_category_id;
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,26 @@
+-- 0005 kategori produk bertingkat (parent/child) dengan urutan tampil per parent
+
+-- tabel categories (parent_id NULL berarti kategori utama)
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    parent_id INTEGER NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0, -- urutan di antara kategori dengan parent yang sama
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE NULL,
+    CONSTRAINT fk_categories_parent_id
+        FOREIGN KEY (parent_id) REFERENCES categories(id) ON DELETE RESTRICT,
+    CONSTRAINT chk_categories_parent_id CHECK (parent_id <> id)
+);
+
+-- nama kategori unik di antara kategori aktif dengan parent yang sama
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_parent_name
+    ON categories(COALESCE(parent_id, 0), LOWER(name)) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+-- setiap produk masuk paling banyak satu kategori
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id INTEGER NULL;
+ALTER TABLE products ADD CONSTRAINT fk_products_category_id
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products(category_id);
@@ -0,0 +1,29 @@
+-- rollback 0006: menghapus varian produk; baris reservasi/restock per varian digabung kembali per produk
+-- (satu reservasi/restock tidak pernah mencampur baris produk dan baris varian untuk produk yang sama),
+-- stok produk tetap berisi jumlah stok variannya
+
+DROP INDEX IF EXISTS idx_stock_restock_items_line;
+WITH merged AS (
+    DELETE FROM stock_restock_items WHERE variant_id IS NOT NULL
+    RETURNING restock_id, product_id, quantity
+)
+INSERT INTO stock_restock_items (restock_id, product_id, quantity)
+SELECT restock_id, product_id, SUM(quantity) FROM merged GROUP BY restock_id, product_id;
+ALTER TABLE stock_restock_items DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE stock_restock_items ADD PRIMARY KEY (restock_id, product_id);
+
+DROP INDEX IF EXISTS idx_stock_reservation_items_line;
+WITH merged AS (
+    DELETE FROM stock_reservation_items WHERE variant_id IS NOT NULL
+    RETURNING reservation_id, product_id, quantity
+)
+INSERT INTO stock_reservation_items (reservation_id, product_id, quantity)
+SELECT reservation_id, product_id, SUM(quantity) FROM merged GROUP BY reservation_id, product_id;
+ALTER TABLE stock_reservation_items DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE stock_reservation_items ADD PRIMARY KEY (reservation_id, product_id);
+
+DELETE FROM product_barcodes WHERE variant_id IS NOT NULL;
+ALTER TABLE product_barcodes DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS product_options;
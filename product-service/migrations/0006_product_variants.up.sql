@@ -0,0 +1,54 @@
+-- 0006 varian produk: sumbu opsi (mis. Size, Color) dan matriks varian dengan SKU, harga, stok dan barcode sendiri.
+-- Stok produk yang punya varian adalah jumlah stok variannya.
+
+-- tabel product_options (sumbu varian per produk, urut position)
+CREATE TABLE IF NOT EXISTS product_options (
+    product_id INTEGER NOT NULL,
+    position SMALLINT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    option_values TEXT[] NOT NULL,
+    PRIMARY KEY (product_id, position),
+    CONSTRAINT fk_product_options_product_id
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- tabel product_variants (satu baris per kombinasi nilai opsi)
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    sku VARCHAR(64) NULL,
+    option_values TEXT[] NOT NULL, -- satu nilai per sumbu, urut position product_options
+    price DECIMAL(15,2) NOT NULL CHECK (price >= 0),
+    stock DECIMAL(15,3) NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE NULL,
+    CONSTRAINT fk_product_variants_product_id
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_values
+    ON product_variants(product_id, option_values) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_sku ON product_variants(sku) WHERE deleted_at IS NULL;
+
+-- barcode varian; variant_id NULL berarti barcode milik produk
+ALTER TABLE product_barcodes ADD COLUMN IF NOT EXISTS variant_id INTEGER NULL;
+ALTER TABLE product_barcodes ADD CONSTRAINT fk_product_barcodes_variant_id
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_product_barcodes_variant_id ON product_barcodes(variant_id);
+
+-- reservasi dan restock per varian: satu baris per produk dan varian
+ALTER TABLE stock_reservation_items ADD COLUMN IF NOT EXISTS variant_id INTEGER NULL;
+ALTER TABLE stock_reservation_items ADD CONSTRAINT fk_stock_reservation_items_variant_id
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE RESTRICT;
+ALTER TABLE stock_reservation_items DROP CONSTRAINT IF EXISTS stock_reservation_items_pkey;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_reservation_items_line
+    ON stock_reservation_items(reservation_id, product_id, COALESCE(variant_id, 0));
+CREATE INDEX IF NOT EXISTS idx_stock_reservation_items_variant_id ON stock_reservation_items(variant_id);
+
+ALTER TABLE stock_restock_items ADD COLUMN IF NOT EXISTS variant_id INTEGER NULL;
+ALTER TABLE stock_restock_items ADD CONSTRAINT fk_stock_restock_items_variant_id
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE RESTRICT;
+ALTER TABLE stock_restock_items DROP CONSTRAINT IF EXISTS stock_restock_items_pkey;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_restock_items_line
+    ON stock_restock_items(restock_id, product_id, COALESCE(variant_id, 0));
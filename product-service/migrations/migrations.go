@@ -0,0 +1,279 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// file migrasi ikut di-embed ke binary: NNNN_nama.up.sql dan NNNN_nama.down.sql, seed.sql terpisah
+//
+//go:embed *.sql
+var files embed.FS
+
+const (
+	// lockKey adalah kunci pg_advisory_lock agar beberapa replika tidak menjalankan migrasi bersamaan
+	lockKey = 7270101
+
+	// seedGuard memastikan data contoh hanya dimasukkan ke database yang masih kosong
+	seedGuard = `SELECT EXISTS (SELECT 1 FROM products)`
+)
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status adalah migrasi beserta waktu diterapkan, AppliedAt nil berarti belum diterapkan
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Load membaca semua migrasi yang di-embed, urut berdasarkan versi
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %04d has conflicting names %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Up menerapkan semua migrasi yang belum tercatat di schema_migrations, masing-masing dalam satu DB transaction
+func Up(db *sql.DB) ([]Migration, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]Migration, 0)
+	err = withLock(db, func(ctx context.Context, conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if _, ok := versions[migration.Version]; ok {
+				continue
+			}
+			err := inTx(ctx, conn, migration.Up,
+				`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+				migration.Version, migration.Name, time.Now())
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down membatalkan steps migrasi terakhir yang sudah diterapkan, dari versi tertinggi
+func Down(db *sql.DB, steps int) ([]Migration, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	reverted := make([]Migration, 0)
+	err = withLock(db, func(ctx context.Context, conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		sorted := make([]int, 0, len(versions))
+		for version := range versions {
+			sorted = append(sorted, version)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+
+		for i := 0; i < steps && i < len(sorted); i++ {
+			migration, ok := byVersion[sorted[i]]
+			if !ok {
+				return fmt.Errorf("migration %04d is applied but not known to this build", sorted[i])
+			}
+			err := inTx(ctx, conn, migration.Down, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+			if err != nil {
+				return fmt.Errorf("rollback of migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// GetStatus menampilkan semua migrasi yang di-embed beserta status penerapannya
+func GetStatus(db *sql.DB) ([]Status, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	err = withLock(db, func(ctx context.Context, conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			status := Status{Version: migration.Version, Name: migration.Name}
+			if appliedAt, ok := versions[migration.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Seed memasukkan data contoh dari seed.sql. seeded=false jika database sudah berisi data.
+func Seed(db *sql.DB) (bool, error) {
+	content, err := fs.ReadFile(files, "seed.sql")
+	if err != nil {
+		return false, err
+	}
+
+	seeded := false
+	err = withLock(db, func(ctx context.Context, conn *sql.Conn) error {
+		var hasData bool
+		if err := conn.QueryRowContext(ctx, seedGuard).Scan(&hasData); err != nil {
+			return fmt.Errorf("failed to check existing data, run migrations first: %w", err)
+		}
+		if hasData {
+			return nil
+		}
+
+		if err := inTx(ctx, conn, string(content), ""); err != nil {
+			return fmt.Errorf("seed failed: %w", err)
+		}
+		seeded = true
+		return nil
+	})
+	return seeded, err
+}
+
+// withLock menjalankan fn di satu koneksi yang memegang advisory lock, replika lain menunggu sampai selesai
+func withLock(db *sql.DB, fn func(ctx context.Context, conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, lockKey)
+
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(ctx, conn)
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		versions[version] = appliedAt
+	}
+	return versions, rows.Err()
+}
+
+// inTx menjalankan script SQL lalu query pencatatan (jika ada) dalam satu DB transaction
+func inTx(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// tanpa argumen lib/pq memakai simple query protocol, sehingga script boleh berisi banyak statement
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if record != "" {
+		if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
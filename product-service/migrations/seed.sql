@@ -0,0 +1,74 @@
+-- data contoh product-service, dijalankan lewat perintah "seed" hanya jika tabel products masih kosong
+
+-- products dummy data
+INSERT INTO products (name, price, stock) VALUES
+('Laptop Dell Inspiron 15', 8500000.00, 5),
+('Mouse Wireless Logitech', 250000.00, 25),
+('Keyboard Mechanical RGB', 750000.00, 15),
+('Monitor LED 24 inch', 2200000.00, 8),
+('Headset Gaming', 450000.00, 12),
+('Webcam HD 1080p', 350000.00, 20),
+('Speaker Bluetooth', 180000.00, 30),
+('Hard Drive External 1TB', 650000.00, 10),
+('USB Flash Drive 32GB', 75000.00, 50),
+('Power Bank 10000mAh', 150000.00, 40);
+
+-- barang timbang, harga per kg; kode PLU dicetak timbangan di label (lihat SCALE_BARCODE_FORMATS)
+INSERT INTO products (name, price, stock) VALUES
+('Beef Tenderloin (per kg)', 145000.00, 12.500),
+('Oranges (per kg)', 28000.00, 40.000);
+
+INSERT INTO product_barcodes (code, product_id, type) VALUES
+('1001', 11, 'PLU'),
+('1002', 12, 'PLU');
+
+-- kategori bertingkat, urutan tampil per parent lewat sort_order
+INSERT INTO categories (name, parent_id, sort_order) VALUES
+('Electronics', NULL, 1),
+('Computers', 1, 1),
+('Peripherals', 2, 1),
+('Storage', 2, 2),
+('Audio', 1, 2),
+('Fresh Food', NULL, 2),
+('Meat', 6, 1),
+('Fruit', 6, 2);
+
+UPDATE products SET category_id = 2 WHERE id = 1;            -- Laptop
+UPDATE products SET category_id = 3 WHERE id IN (2, 3, 4, 6); -- Mouse, Keyboard, Monitor, Webcam
+UPDATE products SET category_id = 5 WHERE id IN (5, 7);       -- Headset, Speaker
+UPDATE products SET category_id = 4 WHERE id IN (8, 9);       -- Hard Drive, USB Flash Drive
+UPDATE products SET category_id = 1 WHERE id = 10;           -- Power Bank
+UPDATE products SET category_id = 7 WHERE id = 11;           -- Beef Tenderloin
+UPDATE products SET category_id = 8 WHERE id = 12;           -- Oranges
+
+-- produk dengan varian (Size x Color), stok produk adalah jumlah stok variannya
+INSERT INTO categories (name, parent_id, sort_order) VALUES
+('Apparel', NULL, 3);
+
+INSERT INTO products (name, sku, price, stock, category_id) VALUES
+('T-Shirt Basic', 'TSHIRT-BASIC', 99000.00, 36, 9);
+
+INSERT INTO product_options (product_id, position, name, option_values) VALUES
+(13, 0, 'Size', ARRAY['S', 'M', 'L']),
+(13, 1, 'Color', ARRAY['Black', 'White']);
+
+INSERT INTO product_variants (product_id, sku, option_values, price, stock) VALUES
+(13, 'TSHIRT-BASIC-S-BLACK', ARRAY['S', 'Black'], 99000.00, 5),
+(13, 'TSHIRT-BASIC-S-WHITE', ARRAY['S', 'White'], 99000.00, 5),
+(13, 'TSHIRT-BASIC-M-BLACK', ARRAY['M', 'Black'], 99000.00, 8),
+(13, 'TSHIRT-BASIC-M-WHITE', ARRAY['M', 'White'], 99000.00, 6),
+(13, 'TSHIRT-BASIC-L-BLACK', ARRAY['L', 'Black'], 109000.00, 7),
+(13, 'TSHIRT-BASIC-L-WHITE', ARRAY['L', 'White'], 109000.00, 5);
+
+INSERT INTO product_barcodes (code, product_id, variant_id, type) VALUES
+('8991234500010', 13, 3, 'EAN13'), -- M / Black
+('8991234500027', 13, 4, 'EAN13'); -- M / White
+
+
+-- Update stock setelah terjadi transaksi (transaksi dummy ada di migration transaction-service)
+UPDATE products SET stock = stock - 1 WHERE id = 1; -- Laptop
+UPDATE products SET stock = stock - 2 WHERE id = 2; -- Mouse (sold 2 times)
+UPDATE products SET stock = stock - 1 WHERE id = 3; -- Keyboard
+UPDATE products SET stock = stock - 1 WHERE id = 4; -- Monitor
+UPDATE products SET stock = stock - 1 WHERE id = 5; -- Headset
+UPDATE products SET stock = stock - 2 WHERE id = 9; -- USB Flash Drive
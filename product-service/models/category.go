@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Category adalah kategori produk bertingkat; ParentID nil berarti kategori utama
+type Category struct {
+	ID           uint       `json:"id"`
+	Name         string     `json:"name"`
+	ParentID     *uint      `json:"parent_id,omitempty"`
+	SortOrder    int        `json:"sort_order"`    // urutan di antara kategori dengan parent yang sama
+	Depth        int        `json:"depth"`         // 0 untuk kategori utama, dihitung saat dibaca
+	Path         string     `json:"path"`          // nama kategori dari akar, mis. "Electronics > Computers"
+	ProductCount int        `json:"product_count"` // produk aktif langsung di kategori ini, tanpa subkategori
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+}
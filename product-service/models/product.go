@@ -1,15 +1,41 @@
 package models
 
 import (
+	"product-service/money"
+	"product-service/quantity"
 	"time"
 )
 
 type Product struct {
-	ID        uint       `json:"id"`
-	Name      string     `json:"name"`
-	Price     float64    `json:"price"`
-	Stock     int        `json:"stock"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
-}
\ No newline at end of file
+	ID            uint              `json:"id"`
+	Name          string            `json:"name"`
+	SKU           string            `json:"sku,omitempty"` // unik di antara produk aktif
+	Barcodes      []ProductBarcode  `json:"barcodes"`
+	Price         money.Money       `json:"price"`
+	Stock         quantity.Quantity `json:"stock"`
+	ReservedStock quantity.Quantity `json:"reserved_stock"` // ditahan reservasi aktif, belum dikurangi dari Stock
+	TaxClass      string            `json:"tax_class"`      // kelas pajak, tarifnya diatur di transaction-service
+	CategoryID    *uint             `json:"category_id,omitempty"`
+	Options       []ProductOption   `json:"options"`  // sumbu varian, mis. Size dan Color
+	Variants      []ProductVariant  `json:"variants"` // jika ada varian, Stock adalah jumlah stok varian ditambah unit retur tanpa varian
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	DeletedAt     *time.Time        `json:"deleted_at,omitempty"`
+}
+
+// AvailableStock adalah stok yang masih bisa dijual/direservasi
+func (p *Product) AvailableStock() quantity.Quantity {
+	if p.ReservedStock >= p.Stock {
+		return 0
+	}
+	return p.Stock - p.ReservedStock
+}
+
+// ProductBarcode adalah satu barcode produk; satu produk boleh punya beberapa barcode (mis. kemasan berbeda)
+type ProductBarcode struct {
+	Code string `json:"code"`
+	Type string `json:"type"` // EAN13, UPCA, CODE128 atau INTERNAL
+}
+
+// kelas pajak default untuk produk baru (PPN tarif normal)
+const DefaultTaxClass = "STANDARD"
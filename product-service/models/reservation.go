@@ -0,0 +1,39 @@
+package models
+
+import (
+	"product-service/quantity"
+	"time"
+)
+
+const (
+	ReservationStatusActive    = "ACTIVE"
+	ReservationStatusConfirmed = "CONFIRMED" // stok sudah dikurangi
+	ReservationStatusReleased  = "RELEASED"
+	ReservationStatusExpired   = "EXPIRED"
+)
+
+// Reservation menahan stok untuk satu keranjang/checkout sampai dikonfirmasi, dilepas atau kedaluwarsa.
+// Stok tersedia = stok fisik - jumlah reservasi ACTIVE yang belum kedaluwarsa.
+type Reservation struct {
+	ID uint `json:"id"`
+	// penanda pemilik reservasi, mis. "cart:12"; reservasi ulang dengan reference yang sama menggantikan yang lama
+	Reference   string            `json:"reference,omitempty"`
+	Status      string            `json:"status"`
+	Items       []ReservationItem `json:"items"`
+	ExpiresAt   time.Time         `json:"expires_at"`
+	ConfirmedAt *time.Time        `json:"confirmed_at,omitempty"`
+	ReleasedAt  *time.Time        `json:"released_at,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+type ReservationItem struct {
+	ProductID uint              `json:"product_id"`
+	VariantID *uint             `json:"variant_id,omitempty"` // wajib untuk produk yang punya varian
+	Quantity  quantity.Quantity `json:"quantity"`
+}
+
+// Active berarti reservasi masih mengurangi stok tersedia
+func (r *Reservation) Active(now time.Time) bool {
+	return r.Status == ReservationStatusActive && now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"product-service/quantity"
+	"time"
+)
+
+const (
+	RestockStatusApplied   = "APPLIED"
+	RestockStatusCancelled = "CANCELLED" // kompensasi, stok dikurangi kembali
+)
+
+const (
+	RestockReasonVoid   = "VOID"
+	RestockReasonReturn = "RETURN"
+)
+
+// Restock mengembalikan barang ke stok (void atau retur dari transaction-service). Reference unik,
+// sehingga permintaan ulang dengan reference yang sama tidak menambah stok dua kali.
+type Restock struct {
+	ID          uint          `json:"id"`
+	Reference   string        `json:"reference"`
+	Reason      string        `json:"reason"`
+	Status      string        `json:"status"`
+	Items       []RestockItem `json:"items"`
+	CancelledAt *time.Time    `json:"cancelled_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+type RestockItem struct {
+	ProductID uint              `json:"product_id"`
+	VariantID *uint             `json:"variant_id,omitempty"` // wajib untuk produk yang punya varian
+	Quantity  quantity.Quantity `json:"quantity"`
+}
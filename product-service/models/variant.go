@@ -0,0 +1,68 @@
+package models
+
+import (
+	"product-service/money"
+	"product-service/quantity"
+	"strings"
+	"time"
+)
+
+// ProductOption adalah satu sumbu varian, mis. Size dengan nilai S, M dan L
+type ProductOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// ProductVariant adalah satu kombinasi nilai opsi dengan SKU, harga, stok dan barcode sendiri
+type ProductVariant struct {
+	ID        uint   `json:"id"`
+	ProductID uint   `json:"product_id"`
+	SKU       string `json:"sku,omitempty"` // unik di antara produk dan varian aktif
+	// satu nilai per sumbu opsi, urutannya sama dengan Product.Options
+	OptionValues  []string          `json:"option_values"`
+	Barcodes      []ProductBarcode  `json:"barcodes"`
+	Price         money.Money       `json:"price"`
+	Stock         quantity.Quantity `json:"stock"`
+	ReservedStock quantity.Quantity `json:"reserved_stock"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// Title adalah nama varian dari nilai opsinya, mis. "M / Black"
+func (v *ProductVariant) Title() string {
+	return strings.Join(v.OptionValues, " / ")
+}
+
+// AvailableStock adalah stok varian yang masih bisa dijual/direservasi
+func (v *ProductVariant) AvailableStock() quantity.Quantity {
+	if v.ReservedStock >= v.Stock {
+		return 0
+	}
+	return v.Stock - v.ReservedStock
+}
+
+// VariantKey membandingkan kombinasi nilai opsi tanpa membedakan huruf besar
+func VariantKey(values []string) string {
+	return strings.ToLower(strings.Join(values, "\x00"))
+}
+
+// VariantMatrix membentuk semua kombinasi nilai opsi, urut sumbu pertama lalu berikutnya
+// (mis. S/Black, S/White, M/Black, ...). Tanpa opsi hasilnya kosong.
+func VariantMatrix(options []ProductOption) [][]string {
+	if len(options) == 0 {
+		return nil
+	}
+	combinations := [][]string{{}}
+	for _, option := range options {
+		next := make([][]string, 0, len(combinations)*len(option.Values))
+		for _, combination := range combinations {
+			for _, value := range option.Values {
+				values := make([]string, len(combination), len(combination)+1)
+				copy(values, combination)
+				next = append(next, append(values, value))
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
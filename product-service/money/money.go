@@ -0,0 +1,182 @@
+// Package money menyimpan nilai uang sebagai bilangan bulat sen (2 desimal, sama dengan kolom
+// DECIMAL(15,2)) sehingga harga tidak pernah melewati float64. Format dan aturan pembulatannya
+// sama dengan package money di transaction-service: input dengan lebih dari 2 desimal dibulatkan
+// ke sen terdekat, setengah dibulatkan menjauhi nol.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money adalah nilai uang dalam sen, 150000 rupiah = Money(15000000)
+type Money int64
+
+// jumlah sen dalam satu rupiah
+const Scale = 100
+
+var ErrInvalidAmount = errors.New("invalid decimal amount")
+
+// New membuat Money dari rupiah utuh
+func New(rupiah int64) Money {
+	return Money(rupiah * Scale)
+}
+
+// Parse membaca desimal seperti "15000", "-2500.5" atau "1234.567" (dibulatkan ke sen)
+func Parse(s string) (Money, error) {
+	value, err := parseFixed(s)
+	return Money(value), err
+}
+
+func (m Money) String() string {
+	return formatFixed(int64(m), false)
+}
+
+// MarshalJSON menulis Money sebagai string "15000.00" agar client tidak membacanya sebagai float
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// UnmarshalJSON menerima angka (15000.5) maupun string ("15000.50")
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalFixed(data)
+	if err != nil {
+		return err
+	}
+	*m = Money(value)
+	return nil
+}
+
+// Scan membaca kolom DECIMAL/NUMERIC, NULL dibaca sebagai 0
+func (m *Money) Scan(src interface{}) error {
+	value, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*m = Money(value)
+	return nil
+}
+
+// Value menulis Money sebagai teks desimal agar PostgreSQL menyimpannya tanpa konversi float
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// MulDiv menghitung m * num / den dengan pembulatan ke sen, hasil kali dihitung dengan big.Int
+// agar tidak overflow
+func (m Money) MulDiv(num, den int64) Money {
+	if den == 0 {
+		panic("money: division by zero")
+	}
+	product := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(num))
+	return Money(divRound(product, big.NewInt(den)))
+}
+
+// divRound membagi dengan pembulatan half away from zero
+func divRound(num, den *big.Int) int64 {
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	// |remainder| * 2 >= |den| berarti dibulatkan menjauhi nol
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(new(big.Int).Abs(den)) >= 0 {
+		if (num.Sign() < 0) != (den.Sign() < 0) {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient.Int64()
+}
+
+// parseFixed membaca desimal menjadi bilangan bulat berskala 2 tanpa melewati float
+func parseFixed(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty value", ErrInvalidAmount)
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	for _, part := range []string{whole, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+			}
+		}
+	}
+
+	// digit ketiga setelah koma menentukan pembulatan
+	roundUp := len(fraction) > 2 && fraction[2] >= '5'
+	fraction = (fraction + "00")[:2]
+
+	value, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidAmount, s)
+	}
+	if roundUp {
+		value++
+	}
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// formatFixed menulis bilangan berskala 2, trim menghapus nol di belakang koma
+func formatFixed(value int64, trim bool) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	text := fmt.Sprintf("%s%d.%02d", sign, value/Scale, value%Scale)
+	if trim {
+		text = strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+	}
+	return text
+}
+
+func unmarshalFixed(data []byte) (int64, error) {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		return 0, nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return 0, err
+		}
+	}
+	return parseFixed(text)
+}
+
+func scanFixed(src interface{}) (int64, error) {
+	switch value := src.(type) {
+	case nil:
+		return 0, nil
+	case []byte:
+		return parseFixed(string(value))
+	case string:
+		return parseFixed(value)
+	case int64:
+		return value * Scale, nil
+	case float64:
+		return int64(math.Round(value * Scale)), nil
+	default:
+		return 0, fmt.Errorf("money: cannot scan %T", src)
+	}
+}
@@ -0,0 +1,380 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"product-service/config"
+	"product-service/events"
+	"product-service/models"
+	"time"
+)
+
+type CategoryRepository interface {
+	// Create menyimpan kategori baru; SortOrder 0 berarti ditaruh paling akhir di antara saudaranya
+	Create(category *models.Category) error
+	// GetAll mengambil semua kategori aktif urut pohon: parent sebelum child, saudara urut sort_order
+	GetAll() ([]models.Category, error)
+	GetByID(id uint) (*models.Category, error)
+	// Update mengubah nama dan urutan kategori, parent diubah lewat Move
+	Update(category *models.Category) error
+	// Move memindahkan kategori beserta subkategorinya ke parent lain (nil berarti kategori utama)
+	Move(id uint, parentID *uint, sortOrder int) error
+	Delete(id uint) error
+}
+
+type categoryRepository struct {
+	db *sql.DB
+}
+
+// categoryTreeQuery menyusun kategori aktif dari akar: depth, path nama dan sort_path untuk urutan pohon.
+// Kategori yang parent-nya sudah dihapus tidak ikut (Delete menolak kategori yang masih punya subkategori).
+const categoryTreeQuery = `
+	WITH RECURSIVE tree AS (
+		SELECT id, name, parent_id, sort_order, 0 AS depth, name::TEXT AS path,
+			ARRAY[sort_order, id] AS sort_path, created_at, updated_at
+		FROM categories
+		WHERE parent_id IS NULL AND deleted_at IS NULL
+		UNION ALL
+		SELECT c.id, c.name, c.parent_id, c.sort_order, t.depth + 1, t.path || ' > ' || c.name,
+			t.sort_path || ARRAY[c.sort_order, c.id], c.created_at, c.updated_at
+		FROM categories c
+		JOIN tree t ON c.parent_id = t.id
+		WHERE c.deleted_at IS NULL
+	)
+	SELECT id, name, parent_id, sort_order, depth, path,
+		(SELECT COUNT(*) FROM products p WHERE p.category_id = tree.id AND p.deleted_at IS NULL),
+		created_at, updated_at
+	FROM tree`
+
+func NewCategoryRepository() CategoryRepository {
+	return &categoryRepository{
+		db: config.DB,
+	}
+}
+
+func (r *categoryRepository) Create(category *models.Category) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCategories(tx); err != nil {
+		return err
+	}
+	if err := checkCategoryPlacement(tx, 0, category.Name, category.ParentID); err != nil {
+		return err
+	}
+	if category.SortOrder == 0 {
+		if category.SortOrder, err = nextSortOrder(tx, category.ParentID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	err = tx.QueryRow(`
+		INSERT INTO categories (name, parent_id, sort_order, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		RETURNING id, created_at, updated_at`,
+		category.Name, category.ParentID, category.SortOrder, now,
+	).Scan(&category.ID, &category.CreatedAt, &category.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := writeOutbox(tx, events.CategoryCreated, "category", category.ID, categoryPayload(category), now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *categoryRepository) GetAll() ([]models.Category, error) {
+	rows, err := r.db.Query(categoryTreeQuery + ` ORDER BY sort_path`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := make([]models.Category, 0)
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, *category)
+	}
+	return categories, rows.Err()
+}
+
+func (r *categoryRepository) GetByID(id uint) (*models.Category, error) {
+	return scanCategory(r.db.QueryRow(categoryTreeQuery+` WHERE id = $1`, id))
+}
+
+func (r *categoryRepository) Update(category *models.Category) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCategories(tx); err != nil {
+		return err
+	}
+	if err := checkCategoryPlacement(tx, category.ID, category.Name, category.ParentID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE categories
+		SET name = $1, sort_order = $2, updated_at = $3
+		WHERE id = $4 AND deleted_at IS NULL`,
+		category.Name, category.SortOrder, now, category.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := writeOutbox(tx, events.CategoryUpdated, "category", category.ID, categoryPayload(category), now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Move mengunci tabel categories selama pemindahan, sehingga dua pemindahan bersamaan tidak bisa
+// membentuk siklus (mis. A ke bawah B dan B ke bawah A)
+func (r *categoryRepository) Move(id uint, parentID *uint, sortOrder int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCategories(tx); err != nil {
+		return err
+	}
+
+	category := &models.Category{ID: id}
+	err = tx.QueryRow(`SELECT name FROM categories WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&category.Name)
+	if err != nil {
+		return err
+	}
+
+	if parentID != nil {
+		// parent baru tidak boleh kategori itu sendiri atau salah satu turunannya
+		parents, err := categoryParents(tx)
+		if err != nil {
+			return err
+		}
+		if isSelfOrDescendant(parents, id, *parentID) {
+			return fmt.Errorf("invalid parent_id, category %d cannot be moved under itself or its subcategory %d", id, *parentID)
+		}
+	}
+
+	category.ParentID = parentID
+	if err := checkCategoryPlacement(tx, id, category.Name, parentID); err != nil {
+		return err
+	}
+
+	category.SortOrder = sortOrder
+	if category.SortOrder == 0 {
+		if category.SortOrder, err = nextSortOrder(tx, parentID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(`
+		UPDATE categories
+		SET parent_id = $1, sort_order = $2, updated_at = $3
+		WHERE id = $4`,
+		parentID, category.SortOrder, now, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := writeOutbox(tx, events.CategoryUpdated, "category", id, categoryPayload(category), now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Delete menghapus kategori yang sudah kosong; kategori yang masih punya subkategori atau produk aktif ditolak
+func (r *categoryRepository) Delete(id uint) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCategories(tx); err != nil {
+		return err
+	}
+
+	// baris kategori dikunci lebih dulu, sehingga produk yang sedang dipindah ke kategori ini sudah terlihat
+	now := time.Now()
+	category := &models.Category{ID: id, DeletedAt: &now}
+	err = tx.QueryRow(`
+		UPDATE categories
+		SET deleted_at = $1
+		WHERE id = $2 AND deleted_at IS NULL
+		RETURNING name, parent_id, sort_order`,
+		now, id,
+	).Scan(&category.Name, &category.ParentID, &category.SortOrder)
+	if err != nil {
+		return err
+	}
+
+	var hasChildren, hasProducts bool
+	err = tx.QueryRow(`
+		SELECT
+			EXISTS (SELECT 1 FROM categories WHERE parent_id = $1 AND deleted_at IS NULL),
+			EXISTS (SELECT 1 FROM products WHERE category_id = $1 AND deleted_at IS NULL)`,
+		id,
+	).Scan(&hasChildren, &hasProducts)
+	if err != nil {
+		return fmt.Errorf("failed to check category usage: %w", err)
+	}
+	if hasChildren {
+		return fmt.Errorf("category %d cannot be deleted while it has subcategories", id)
+	}
+	if hasProducts {
+		return fmt.Errorf("category %d cannot be deleted while it has products", id)
+	}
+
+	if err := writeOutbox(tx, events.CategoryDeleted, "category", id, categoryPayload(category), now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockCategories menyerialkan semua perubahan kategori; mode ini tidak menghalangi pembacaan
+func lockCategories(tx *sql.Tx) error {
+	if _, err := tx.Exec(`LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("failed to lock categories: %w", err)
+	}
+	return nil
+}
+
+// categoryParents mengambil parent semua kategori, termasuk yang sudah dihapus
+func categoryParents(tx *sql.Tx) (map[uint]*uint, error) {
+	rows, err := tx.Query(`SELECT id, parent_id FROM categories`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check category subtree: %w", err)
+	}
+	defer rows.Close()
+
+	parents := make(map[uint]*uint)
+	for rows.Next() {
+		var id uint
+		var parentID *uint
+		if err := rows.Scan(&id, &parentID); err != nil {
+			return nil, fmt.Errorf("failed to check category subtree: %w", err)
+		}
+		parents[id] = parentID
+	}
+	return parents, rows.Err()
+}
+
+// isSelfOrDescendant menelusuri leluhur candidate sampai kategori utama; true jika bertemu id. Kategori yang
+// sudah dilewati tidak ditelusuri lagi, sehingga data parent yang berputar tidak membuat loop tanpa akhir.
+func isSelfOrDescendant(parents map[uint]*uint, id, candidate uint) bool {
+	visited := make(map[uint]bool)
+	for current := &candidate; current != nil && !visited[*current]; current = parents[*current] {
+		if *current == id {
+			return true
+		}
+		visited[*current] = true
+	}
+	return false
+}
+
+// checkCategoryPlacement memastikan parent ada dan nama belum dipakai saudara aktif lain (tanpa membedakan huruf besar)
+func checkCategoryPlacement(tx *sql.Tx, id uint, name string, parentID *uint) error {
+	if parentID != nil {
+		if err := checkCategory(tx, *parentID, "parent_id"); err != nil {
+			return err
+		}
+	}
+
+	var otherID uint
+	err := tx.QueryRow(`
+		SELECT id FROM categories
+		WHERE COALESCE(parent_id, 0) = $1 AND LOWER(name) = LOWER($2) AND id <> $3 AND deleted_at IS NULL`,
+		idOrZero(parentID), name, id,
+	).Scan(&otherID)
+	if err == nil {
+		return fmt.Errorf("category %s already exists at this level (category %d)", name, otherID)
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check category name: %w", err)
+	}
+	return nil
+}
+
+// checkCategory memastikan kategori aktif ada; baris dikunci FOR SHARE agar tidak terhapus sebelum commit
+func checkCategory(tx *sql.Tx, id uint, field string) error {
+	var found uint
+	err := tx.QueryRow(`SELECT id FROM categories WHERE id = $1 AND deleted_at IS NULL FOR SHARE`, id).Scan(&found)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("invalid %s, category %d not found", field, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check category: %w", err)
+	}
+	return nil
+}
+
+// nextSortOrder mengembalikan urutan setelah saudara terakhir di bawah parent
+func nextSortOrder(tx *sql.Tx, parentID *uint) (int, error) {
+	var sortOrder int
+	err := tx.QueryRow(
+		`SELECT COALESCE(MAX(sort_order), 0) + 1 FROM categories WHERE COALESCE(parent_id, 0) = $1 AND deleted_at IS NULL`,
+		idOrZero(parentID),
+	).Scan(&sortOrder)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get category sort order: %w", err)
+	}
+	return sortOrder, nil
+}
+
+func scanCategory(row rowScanner) (*models.Category, error) {
+	var category models.Category
+	err := row.Scan(
+		&category.ID,
+		&category.Name,
+		&category.ParentID,
+		&category.SortOrder,
+		&category.Depth,
+		&category.Path,
+		&category.ProductCount,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func categoryPayload(category *models.Category) events.CategoryPayload {
+	return events.CategoryPayload{
+		ID:        category.ID,
+		Name:      category.Name,
+		ParentID:  category.ParentID,
+		SortOrder: category.SortOrder,
+		DeletedAt: category.DeletedAt,
+	}
+}
+
+// idOrZero dipakai untuk membandingkan parent_id lewat COALESCE(parent_id, 0)
+func idOrZero(id *uint) int64 {
+	if id == nil {
+		return 0
+	}
+	return int64(*id)
+}
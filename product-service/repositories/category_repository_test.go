@@ -0,0 +1,44 @@
+package repositories
+
+import "testing"
+
+func TestIsSelfOrDescendant(t *testing.T) {
+	parent := func(id uint) *uint { return &id }
+	// 1 > 2 > 3 > 4, 1 > 5, 6 kategori utama; 7 dan 8 saling menjadi parent (data rusak)
+	parents := map[uint]*uint{
+		1: nil,
+		2: parent(1),
+		3: parent(2),
+		4: parent(3),
+		5: parent(1),
+		6: nil,
+		7: parent(8),
+		8: parent(7),
+	}
+
+	tests := []struct {
+		name      string
+		id        uint
+		candidate uint
+		want      bool
+	}{
+		{"under itself", 2, 2, true},
+		{"under its child", 2, 3, true},
+		{"under a deep descendant", 1, 4, true},
+		{"under its parent", 3, 2, false},
+		{"under a sibling subtree", 2, 5, false},
+		{"under another root", 1, 6, false},
+		{"root under a leaf of another tree", 6, 4, false},
+		{"unknown parent", 2, 99, false},
+		{"existing cycle not containing the category", 2, 7, false},
+		{"existing cycle containing the category", 8, 7, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSelfOrDescendant(parents, tt.id, tt.candidate); got != tt.want {
+				t.Errorf("isSelfOrDescendant(%d, %d) = %v, want %v", tt.id, tt.candidate, got, tt.want)
+			}
+		})
+	}
+}
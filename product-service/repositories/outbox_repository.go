@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"product-service/config"
+	"product-service/events"
+	"product-service/quantity"
+	"strconv"
+	"time"
+)
+
+type OutboxRepository interface {
+	// PublishPending mengirim paling banyak limit event yang belum terkirim, urut id. Event ditandai terkirim
+	// hanya setelah publish berhasil; event yang gagal dicoba lagi pada putaran berikutnya.
+	PublishPending(limit int, publish func(event events.Event) error) (int, error)
+}
+
+type outboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository() OutboxRepository {
+	return &outboxRepository{
+		db: config.DB,
+	}
+}
+
+func (r *outboxRepository) PublishPending(limit int, publish func(event events.Event) error) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED agar beberapa replika relay tidak mengirim batch yang sama bersamaan
+	rows, err := tx.Query(`
+		SELECT id, event_id, event_type, aggregate_type, aggregate_id, payload, occurred_at
+		FROM outbox_events
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	var ids []int64
+	var pending []events.Event
+	for rows.Next() {
+		var id int64
+		var event events.Event
+		var payload []byte
+		if err := rows.Scan(&id, &event.ID, &event.Type, &event.AggregateType, &event.AggregateID, &payload, &event.OccurredAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		event.Source = events.Source
+		event.Payload = payload
+		ids = append(ids, id)
+		pending = append(pending, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var publishErr error
+	for i, event := range pending {
+		if publishErr = publish(event); publishErr != nil {
+			// berhenti di event yang gagal agar urutan event tetap terjaga
+			_, err := tx.Exec(`UPDATE outbox_events SET attempts = attempts + 1, last_error = $1 WHERE id = $2`, publishErr.Error(), ids[i])
+			if err != nil {
+				return published, err
+			}
+			break
+		}
+		if _, err := tx.Exec(`UPDATE outbox_events SET published_at = $1, attempts = attempts + 1, last_error = NULL WHERE id = $2`, time.Now(), ids[i]); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
+
+// writeOutbox menyimpan event di DB transaction pemanggil, sehingga event hanya ada jika perubahannya ikut di-commit
+func writeOutbox(tx *sql.Tx, eventType, aggregateType string, aggregateID uint, payload interface{}, occurredAt time.Time) error {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO outbox_events (event_id, event_type, aggregate_type, aggregate_id, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		events.NewID(), eventType, aggregateType, strconv.FormatUint(uint64(aggregateID), 10), content, occurredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write %s event to outbox: %w", eventType, err)
+	}
+	return nil
+}
+
+// writeStockChanged menulis event stock.changed untuk satu produk; variantID diisi jika perubahan berasal dari varian
+func writeStockChanged(tx *sql.Tx, productID uint, variantID *uint, previousStock, stock quantity.Quantity, reason, reference string, occurredAt time.Time) error {
+	return writeOutbox(tx, events.StockChanged, "product", productID, events.StockChangedPayload{
+		ProductID:     productID,
+		VariantID:     variantID,
+		PreviousStock: previousStock,
+		Stock:         stock,
+		Delta:         stock - previousStock,
+		Reason:        reason,
+		Reference:     reference,
+	}, occurredAt)
+}
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"product-service/models"
+
+	"github.com/lib/pq"
+)
+
+// GetByBarcode mencari produk aktif yang punya salah satu kode (lihat barcode.LookupCandidates),
+// termasuk barcode variannya
+func (r *productRepository) GetByBarcode(codes []string) (*models.Product, error) {
+	return r.getOne(`id = (SELECT product_id FROM product_barcodes WHERE code = ANY($1) LIMIT 1)`, pq.Array(codes))
+}
+
+// GetByPLU mencari produk lewat kode barang di label timbangan
+func (r *productRepository) GetByPLU(itemCode string) (*models.Product, error) {
+	return r.getOne(`id = (SELECT product_id FROM product_barcodes WHERE code = $1 AND type = 'PLU')`, itemCode)
+}
+
+// GetBySKU mencari produk lewat SKU produk atau SKU salah satu variannya
+func (r *productRepository) GetBySKU(sku string) (*models.Product, error) {
+	return r.getOne(`(sku = $1 OR id = (SELECT product_id FROM product_variants WHERE sku = $1 AND deleted_at IS NULL))`, sku)
+}
+
+// barcodeCodes mengambil kode barcode saja untuk payload event
+func barcodeCodes(barcodes []models.ProductBarcode) []string {
+	codes := make([]string, len(barcodes))
+	for i, barcode := range barcodes {
+		codes[i] = barcode.Code
+	}
+	return codes
+}
+
+// getOne mengambil satu produk aktif beserta barcode dan variannya, sql.ErrNoRows jika tidak ada
+func (r *productRepository) getOne(condition string, arg interface{}) (*models.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE ` + condition + ` AND deleted_at IS NULL`
+
+	var product models.Product
+	err := r.db.QueryRow(query, arg).Scan(
+		&product.ID,
+		&product.Name,
+		&product.SKU,
+		&product.Price,
+		&product.Stock,
+		&product.ReservedStock,
+		&product.TaxClass,
+		&product.CategoryID,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	products := []models.Product{product}
+	if err := r.loadBarcodes(products); err != nil {
+		return nil, err
+	}
+	if err := r.loadVariants(products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+// loadBarcodes mengisi Barcodes semua produk dengan satu query, barcode varian diisi loadVariants
+func (r *productRepository) loadBarcodes(products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(products))
+	index := make(map[uint]int, len(products))
+	for i := range products {
+		ids[i] = int64(products[i].ID)
+		index[products[i].ID] = i
+		products[i].Barcodes = []models.ProductBarcode{}
+	}
+
+	rows, err := r.db.Query(`
+		SELECT product_id, code, type
+		FROM product_barcodes
+		WHERE product_id = ANY($1) AND variant_id IS NULL
+		ORDER BY product_id, position`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get product barcodes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID uint
+		var barcode models.ProductBarcode
+		if err := rows.Scan(&productID, &barcode.Code, &barcode.Type); err != nil {
+			return err
+		}
+		if i, ok := index[productID]; ok {
+			products[i].Barcodes = append(products[i].Barcodes, barcode)
+		}
+	}
+	return rows.Err()
+}
+
+// checkIdentifiers memastikan SKU dan barcode produk belum dipakai produk aktif lain atau varian mana pun
+func checkIdentifiers(tx *sql.Tx, product *models.Product) error {
+	if product.SKU != "" {
+		var otherID uint
+		err := tx.QueryRow(`
+			SELECT id FROM products WHERE sku = $1 AND id <> $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT product_id FROM product_variants WHERE sku = $1 AND deleted_at IS NULL
+			LIMIT 1`,
+			product.SKU, product.ID,
+		).Scan(&otherID)
+		if err == nil {
+			return fmt.Errorf("sku %s is already used by product %d", product.SKU, otherID)
+		}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("failed to check sku: %w", err)
+		}
+	}
+
+	if codes := barcodeCodes(product.Barcodes); len(codes) > 0 {
+		var code string
+		var otherID uint
+		err := tx.QueryRow(
+			`SELECT code, product_id FROM product_barcodes WHERE code = ANY($1) AND (product_id <> $2 OR variant_id IS NOT NULL) LIMIT 1`,
+			pq.Array(codes), product.ID,
+		).Scan(&code, &otherID)
+		if err == nil {
+			return fmt.Errorf("barcode %s is already assigned to product %d", code, otherID)
+		}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("failed to check barcodes: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// replaceBarcodes mengganti semua barcode produk sesuai urutan product.Barcodes, barcode varian tidak berubah
+func replaceBarcodes(tx *sql.Tx, product *models.Product) error {
+	if _, err := tx.Exec(`DELETE FROM product_barcodes WHERE product_id = $1 AND variant_id IS NULL`, product.ID); err != nil {
+		return fmt.Errorf("failed to replace barcodes: %w", err)
+	}
+	for i, barcode := range product.Barcodes {
+		_, err := tx.Exec(
+			`INSERT INTO product_barcodes (code, product_id, type, position) VALUES ($1, $2, $3, $4)`,
+			barcode.Code, product.ID, barcode.Type, i,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert barcode %s: %w", barcode.Code, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"fmt"
+	"product-service/models"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ProductImportItem adalah satu baris import yang sudah divalidasi: ID 0 membuat produk baru,
+// selain itu mengupdate produk tersebut (produk dengan SKU yang sama)
+type ProductImportItem struct {
+	ID      uint
+	Product *models.Product
+}
+
+// Import menerapkan semua item dalam satu transaksi. Tiap item dijalankan di savepoint sendiri sehingga
+// item yang gagal (mis. barcode sudah dipakai) tidak menghentikan pengecekan item berikutnya; errs sejajar
+// dengan items. Transaksi hanya di-commit jika commit true dan semua item berhasil, selain itu di-rollback
+// (dry run). committed bernilai true jika perubahan benar-benar tersimpan.
+func (r *productRepository) Import(items []ProductImportItem, commit bool) (errs []error, committed bool, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	// produk yang diupdate dikunci lebih dulu sesuai urutan id, sama dengan urutan lock reservasi
+	var ids []int64
+	for _, item := range items {
+		if item.ID != 0 {
+			ids = append(ids, int64(item.ID))
+		}
+	}
+	if len(ids) > 0 {
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		if _, err := tx.Exec(`SELECT id FROM products WHERE id = ANY($1) ORDER BY id FOR UPDATE`, pq.Array(ids)); err != nil {
+			return nil, false, fmt.Errorf("failed to lock products: %w", err)
+		}
+	}
+
+	errs = make([]error, len(items))
+	failed := false
+	for i, item := range items {
+		if _, err := tx.Exec(`SAVEPOINT import_item`); err != nil {
+			return nil, false, err
+		}
+
+		now := time.Now()
+		if item.ID == 0 {
+			errs[i] = createProduct(tx, item.Product, now)
+		} else {
+			errs[i] = updateProduct(tx, item.ID, item.Product, now)
+		}
+
+		if errs[i] != nil {
+			failed = true
+			if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT import_item`); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+		if _, err := tx.Exec(`RELEASE SAVEPOINT import_item`); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if !commit || failed {
+		return errs, false, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+	return errs, true, nil
+}
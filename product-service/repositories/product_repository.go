@@ -4,23 +4,45 @@ import (
 	"database/sql"
 	"fmt"
 	"product-service/config"
+	"product-service/events"
 	"product-service/models"
+	"product-service/quantity"
 	"time"
 )
 
 type ProductRepository interface {
 	Create(product *models.Product) error
-	GetAll(page, limit int, search, sortBy, order string) ([]models.Product, int, error)
+	// GetAll dengan categoryID > 0 hanya mengambil produk di kategori tersebut beserta subkategorinya
+	GetAll(page, limit int, search, sortBy, order string, categoryID uint) ([]models.Product, int, error)
 	GetByID(id uint) (*models.Product, error)
 	Update(id uint, product *models.Product) error
 	Delete(id uint) error
-	UpdateStock(id uint, newStock int) error
+	UpdateStock(id uint, newStock quantity.Quantity) error
+	// GetByBarcode mencari produk lewat salah satu kode barcode, dipakai saat scan di kasir
+	GetByBarcode(codes []string) (*models.Product, error)
+	GetBySKU(sku string) (*models.Product, error)
+	GetByPLU(itemCode string) (*models.Product, error)
+	// UpdateVariant menyimpan SKU, harga dan barcode varian; stok hanya diubah jika stock tidak nil
+	UpdateVariant(productID uint, variant *models.ProductVariant, stock *quantity.Quantity) error
+	// Import membuat/mengupdate produk hasil import dalam satu transaksi, lihat product_import.go
+	Import(items []ProductImportItem, commit bool) ([]error, bool, error)
 }
 
 type productRepository struct {
 	db *sql.DB
 }
 
+// reservedStockColumn menjumlahkan reservasi ACTIVE yang belum kedaluwarsa untuk produk
+const reservedStockColumn = `COALESCE((
+			SELECT SUM(ri.quantity)
+			FROM stock_reservation_items ri
+			JOIN stock_reservations sr ON sr.id = ri.reservation_id
+			WHERE ri.product_id = products.id AND sr.status = 'ACTIVE' AND sr.expires_at > NOW()
+		), 0) AS reserved_stock`
+
+// productColumns adalah kolom yang dibaca untuk models.Product, urutannya sama dengan Scan
+const productColumns = `id, name, COALESCE(sku, ''), price, stock, ` + reservedStockColumn + `, tax_class, category_id, created_at, updated_at`
+
 func NewProductRepository() ProductRepository {
 	return &productRepository{
 		db: config.DB,
@@ -28,25 +50,71 @@ func NewProductRepository() ProductRepository {
 }
 
 func (r *productRepository) Create(product *models.Product) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createProduct(tx, product, time.Now()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// createProduct menyimpan produk baru beserta barcode, varian dan event product.created di tx
+func createProduct(tx *sql.Tx, product *models.Product, now time.Time) error {
 	query := `
-		INSERT INTO products (name, price, stock, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5) 
+		INSERT INTO products (name, sku, price, stock, tax_class, category_id, created_at, updated_at) 
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8) 
 		RETURNING id, created_at, updated_at`
 
-	now := time.Now()
-	err := r.db.QueryRow(
+	// SKU dan barcode dicek lebih dulu agar pesan konflik lebih jelas daripada error unique index
+	if err := checkIdentifiers(tx, product); err != nil {
+		return err
+	}
+	if product.CategoryID != nil {
+		if err := checkCategory(tx, *product.CategoryID, "category_id"); err != nil {
+			return err
+		}
+	}
+
+	err := tx.QueryRow(
 		query,
 		product.Name,
+		product.SKU,
 		product.Price,
 		product.Stock,
+		product.TaxClass,
+		product.CategoryID,
 		now,
 		now,
 	).Scan(&product.ID, &product.CreatedAt, &product.UpdatedAt)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if err := replaceBarcodes(tx, product); err != nil {
+		return err
+	}
+	// varian baru selalu mulai dengan stok 0, stok produk tetap
+	if _, _, err := syncVariants(tx, product, now); err != nil {
+		return err
+	}
+
+	return writeOutbox(tx, events.ProductCreated, "product", product.ID, events.ProductPayload{
+		ID:         product.ID,
+		Name:       product.Name,
+		SKU:        product.SKU,
+		Barcodes:   barcodeCodes(product.Barcodes),
+		Price:      product.Price,
+		Stock:      product.Stock,
+		TaxClass:   product.TaxClass,
+		CategoryID: product.CategoryID,
+	}, now)
 }
 
-func (r *productRepository) GetAll(page, limit int, search, sortBy, order string) ([]models.Product, int, error) {
+func (r *productRepository) GetAll(page, limit int, search, sortBy, order string, categoryID uint) ([]models.Product, int, error) {
 	// Default values
 	if page <= 0 {
 		page = 1
@@ -65,7 +133,7 @@ func (r *productRepository) GetAll(page, limit int, search, sortBy, order string
 
 	// Base query
 	query := `
-		SELECT id, name, price, stock, created_at, updated_at 
+		SELECT ` + productColumns + `
 		FROM products 
 		WHERE deleted_at IS NULL
 	`
@@ -75,14 +143,29 @@ func (r *productRepository) GetAll(page, limit int, search, sortBy, order string
 		WHERE deleted_at IS NULL
 	`
 
-	// Filtering (search by name)
+	// Filtering (search by name atau SKU)
 	var args []interface{}
 	if search != "" {
-		query += " AND name ILIKE $1"
-		countQuery += " AND name ILIKE $1"
+		query += " AND (name ILIKE $1 OR sku ILIKE $1)"
+		countQuery += " AND (name ILIKE $1 OR sku ILIKE $1)"
 		args = append(args, "%"+search+"%")
 	}
 
+	// filter kategori termasuk semua subkategorinya
+	if categoryID > 0 {
+		args = append(args, categoryID)
+		categoryFilter := fmt.Sprintf(` AND category_id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM categories WHERE id = $%d AND deleted_at IS NULL
+				UNION ALL
+				SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+			)
+			SELECT id FROM subtree
+		)`, len(args))
+		query += categoryFilter
+		countQuery += categoryFilter
+	}
+
 	// Sorting + pagination
 	query += fmt.Sprintf(" ORDER BY %s %s LIMIT %d OFFSET %d", sortBy, order, limit, offset)
 
@@ -99,8 +182,12 @@ func (r *productRepository) GetAll(page, limit int, search, sortBy, order string
 		err := rows.Scan(
 			&product.ID,
 			&product.Name,
+			&product.SKU,
 			&product.Price,
 			&product.Stock,
+			&product.ReservedStock,
+			&product.TaxClass,
+			&product.CategoryID,
 			&product.CreatedAt,
 			&product.UpdatedAt,
 		)
@@ -109,6 +196,15 @@ func (r *productRepository) GetAll(page, limit int, search, sortBy, order string
 		}
 		products = append(products, product)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadBarcodes(products); err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadVariants(products); err != nil {
+		return nil, 0, err
+	}
 
 	// Hitung total data (tanpa limit/offset)
 	var total int
@@ -122,62 +218,181 @@ func (r *productRepository) GetAll(page, limit int, search, sortBy, order string
 
 
 func (r *productRepository) GetByID(id uint) (*models.Product, error) {
-	query := `
-		SELECT id, name, price, stock, created_at, updated_at 
-		FROM products 
-		WHERE id = $1 AND deleted_at IS NULL`
-
-	var product models.Product
-	err := r.db.QueryRow(query, id).Scan(
-		&product.ID,
-		&product.Name,
-		&product.Price,
-		&product.Stock,
-		&product.CreatedAt,
-		&product.UpdatedAt,
-	)
+	return r.getOne(`id = $1`, id)
+}
 
+func (r *productRepository) Update(id uint, product *models.Product) error {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer tx.Rollback()
 
-	return &product, nil
+	if err := updateProduct(tx, id, product, time.Now()); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *productRepository) Update(id uint, product *models.Product) error {
+// updateProduct mengunci lalu mengupdate produk di tx, termasuk barcode, varian dan event-nya
+func updateProduct(tx *sql.Tx, id uint, product *models.Product, now time.Time) error {
+	// stok lama dibutuhkan untuk event stock.changed
+	var previousStock quantity.Quantity
+	err := tx.QueryRow(`SELECT stock FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&previousStock)
+	if err != nil {
+		return err
+	}
+
+	product.ID = id
+	if err := checkIdentifiers(tx, product); err != nil {
+		return err
+	}
+	if product.CategoryID != nil {
+		if err := checkCategory(tx, *product.CategoryID, "category_id"); err != nil {
+			return err
+		}
+	}
+	if err := replaceBarcodes(tx, product); err != nil {
+		return err
+	}
+
+	withVariants, err := variantProducts(tx, []int64{int64(id)})
+	if err != nil {
+		return err
+	}
+
+	// stok produk yang punya varian tidak diambil dari request. Produk yang baru diberi varian memakai jumlah stok
+	// variannya; produk yang sudah punya varian tetap memakai stoknya, yang juga memuat unit tanpa varian dari
+	// restock (sinkronisasi tidak mengubah jumlah stok varian: varian baru 0, varian yang dihapus harus 0)
+	hasVariants, variantStock, err := syncVariants(tx, product, now)
+	if err != nil {
+		return err
+	}
+	if hasVariants {
+		product.Stock = variantStock
+		if withVariants[id] {
+			product.Stock = previousStock
+		}
+	}
+
 	query := `
 		UPDATE products 
-		SET name = $1, price = $2, stock = $3, updated_at = $4 
-		WHERE id = $5 AND deleted_at IS NULL`
+		SET name = $1, sku = NULLIF($2, ''), price = $3, stock = $4, tax_class = $5, category_id = $6, updated_at = $7 
+		WHERE id = $8 AND deleted_at IS NULL`
 
-	_, err := r.db.Exec(
+	_, err = tx.Exec(
 		query,
 		product.Name,
+		product.SKU,
 		product.Price,
 		product.Stock,
-		time.Now(),
+		product.TaxClass,
+		product.CategoryID,
+		now,
 		id,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	err = writeOutbox(tx, events.ProductUpdated, "product", id, events.ProductPayload{
+		ID:         id,
+		Name:       product.Name,
+		SKU:        product.SKU,
+		Barcodes:   barcodeCodes(product.Barcodes),
+		Price:      product.Price,
+		Stock:      product.Stock,
+		TaxClass:   product.TaxClass,
+		CategoryID: product.CategoryID,
+	}, now)
+	if err != nil {
+		return err
+	}
+	if product.Stock != previousStock {
+		if err := writeStockChanged(tx, id, nil, previousStock, product.Stock, events.StockReasonAdjustment, "", now); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (r *productRepository) Delete(id uint) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE products 
 		SET deleted_at = $1 
-		WHERE id = $2 AND deleted_at IS NULL`
+		WHERE id = $2 AND deleted_at IS NULL
+		RETURNING name, price, stock, tax_class, category_id`
+
+	now := time.Now()
+	payload := events.ProductPayload{ID: id, DeletedAt: &now}
+	err = tx.QueryRow(query, now, id).Scan(&payload.Name, &payload.Price, &payload.Stock, &payload.TaxClass, &payload.CategoryID)
+	if err == sql.ErrNoRows {
+		// sudah terhapus, tidak ada event baru
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// barcode dan SKU varian produk terhapus dilepas agar bisa dipakai produk lain
+	if _, err := tx.Exec(`DELETE FROM product_barcodes WHERE product_id = $1`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE product_variants SET deleted_at = $1 WHERE product_id = $2 AND deleted_at IS NULL`, now, id); err != nil {
+		return err
+	}
 
-	_, err := r.db.Exec(query, time.Now(), id)
-	return err
+	if err := writeOutbox(tx, events.ProductDeleted, "product", id, payload, now); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *productRepository) UpdateStock(id uint, newStock int) error {
+func (r *productRepository) UpdateStock(id uint, newStock quantity.Quantity) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previousStock quantity.Quantity
+	err = tx.QueryRow(`SELECT stock FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&previousStock)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	withVariants, err := variantProducts(tx, []int64{int64(id)})
+	if err != nil {
+		return err
+	}
+	if withVariants[id] {
+		return fmt.Errorf("invalid stock, product %d has variants, update the stock of each variant", id)
+	}
+
 	query := `
 		UPDATE products 
 		SET stock = $1, updated_at = $2 
 		WHERE id = $3 AND deleted_at IS NULL`
 
-	_, err := r.db.Exec(query, newStock, time.Now(), id)
-	return err
+	now := time.Now()
+	if _, err := tx.Exec(query, newStock, now, id); err != nil {
+		return err
+	}
+
+	if newStock != previousStock {
+		if err := writeStockChanged(tx, id, nil, previousStock, newStock, events.StockReasonAdjustment, "", now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
@@ -0,0 +1,503 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"product-service/barcode"
+	"product-service/events"
+	"product-service/models"
+	"product-service/quantity"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// UpdateVariant mengubah SKU, harga dan barcode satu varian, juga stoknya jika stock tidak nil. Perubahan stok
+// dihitung dari stok baris yang sudah dikunci, bukan variant.Stock yang dibaca sebelumnya, lalu ikut mengubah
+// stok produk (jumlah stok varian) dan menulis event stock.changed.
+func (r *productRepository) UpdateVariant(productID uint, variant *models.ProductVariant, stock *quantity.Quantity) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// produk dikunci lebih dulu, urutan lock sama dengan reservasi dan restock
+	var productStock quantity.Quantity
+	err = tx.QueryRow(`SELECT stock FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, productID).Scan(&productStock)
+	if err != nil {
+		return err
+	}
+
+	var previousStock quantity.Quantity
+	err = tx.QueryRow(
+		`SELECT stock FROM product_variants WHERE id = $1 AND product_id = $2 AND deleted_at IS NULL FOR UPDATE`,
+		variant.ID, productID,
+	).Scan(&previousStock)
+	if err != nil {
+		return err
+	}
+
+	variant.ProductID = productID
+	variant.Stock = previousStock
+	if stock != nil {
+		variant.Stock = *stock
+	}
+	if err := checkVariantIdentifiers(tx, variant); err != nil {
+		return err
+	}
+	if err := replaceVariantBarcodes(tx, variant); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(`
+		UPDATE product_variants
+		SET sku = NULLIF($1, ''), price = $2, stock = $3, updated_at = $4
+		WHERE id = $5`,
+		variant.SKU, variant.Price, variant.Stock, now, variant.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if variant.Stock != previousStock {
+		newProductStock := productStock + variant.Stock - previousStock
+		if _, err := tx.Exec(`UPDATE products SET stock = $1, updated_at = $2 WHERE id = $3`, newProductStock, now, productID); err != nil {
+			return err
+		}
+		if err := writeStockChanged(tx, productID, &variant.ID, productStock, newProductStock, events.StockReasonAdjustment, "", now); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// loadVariants mengisi Options dan Variants (beserta reservasi aktif dan barcodenya) semua produk
+func (r *productRepository) loadVariants(products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(products))
+	index := make(map[uint]int, len(products))
+	for i := range products {
+		ids[i] = int64(products[i].ID)
+		index[products[i].ID] = i
+		products[i].Options = []models.ProductOption{}
+		products[i].Variants = []models.ProductVariant{}
+	}
+
+	rows, err := r.db.Query(`
+		SELECT product_id, name, option_values
+		FROM product_options
+		WHERE product_id = ANY($1)
+		ORDER BY product_id, position`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get product options: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID uint
+		var option models.ProductOption
+		if err := rows.Scan(&productID, &option.Name, pq.Array(&option.Values)); err != nil {
+			return err
+		}
+		if i, ok := index[productID]; ok {
+			products[i].Options = append(products[i].Options, option)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	rows, err = r.db.Query(`
+		SELECT v.id, v.product_id, COALESCE(v.sku, ''), v.option_values, v.price, v.stock,
+			COALESCE((
+				SELECT SUM(ri.quantity)
+				FROM stock_reservation_items ri
+				JOIN stock_reservations sr ON sr.id = ri.reservation_id
+				WHERE ri.variant_id = v.id AND sr.status = 'ACTIVE' AND sr.expires_at > NOW()
+			), 0),
+			v.created_at, v.updated_at
+		FROM product_variants v
+		WHERE v.product_id = ANY($1) AND v.deleted_at IS NULL
+		ORDER BY v.product_id, v.id`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get product variants: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		variant := models.ProductVariant{Barcodes: []models.ProductBarcode{}}
+		err := rows.Scan(
+			&variant.ID,
+			&variant.ProductID,
+			&variant.SKU,
+			pq.Array(&variant.OptionValues),
+			&variant.Price,
+			&variant.Stock,
+			&variant.ReservedStock,
+			&variant.CreatedAt,
+			&variant.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		if i, ok := index[variant.ProductID]; ok {
+			products[i].Variants = append(products[i].Variants, variant)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	rows, err = r.db.Query(`
+		SELECT product_id, variant_id, code, type
+		FROM product_barcodes
+		WHERE product_id = ANY($1) AND variant_id IS NOT NULL
+		ORDER BY variant_id, position`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get variant barcodes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID, variantID uint
+		var variantBarcode models.ProductBarcode
+		if err := rows.Scan(&productID, &variantID, &variantBarcode.Code, &variantBarcode.Type); err != nil {
+			return err
+		}
+		i, ok := index[productID]
+		if !ok {
+			continue
+		}
+		for j := range products[i].Variants {
+			if products[i].Variants[j].ID == variantID {
+				products[i].Variants[j].Barcodes = append(products[i].Variants[j].Barcodes, variantBarcode)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range products {
+		sortVariants(&products[i])
+	}
+	return nil
+}
+
+// sortVariants mengurutkan varian sesuai urutan nilai opsi (matriks), bukan urutan dibuat
+func sortVariants(product *models.Product) {
+	position := func(variant *models.ProductVariant) []int {
+		positions := make([]int, len(variant.OptionValues))
+		for axis, value := range variant.OptionValues {
+			positions[axis] = len(product.Options)
+			if axis < len(product.Options) {
+				for k, optionValue := range product.Options[axis].Values {
+					if strings.EqualFold(optionValue, value) {
+						positions[axis] = k
+						break
+					}
+				}
+			}
+		}
+		return positions
+	}
+
+	sort.SliceStable(product.Variants, func(i, j int) bool {
+		a, b := position(&product.Variants[i]), position(&product.Variants[j])
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+}
+
+// syncVariants menyimpan opsi produk dan menyesuaikan varian aktif dengan matriksnya. Varian yang kombinasinya
+// masih ada dipertahankan (SKU, harga, stok dan barcodenya tetap), kombinasi baru dibuat dengan harga produk
+// dan stok 0, varian yang kombinasinya hilang dihapus jika stoknya sudah 0. Baris produk harus sudah dikunci
+// pemanggil. Mengembalikan apakah produk punya varian dan jumlah stok variannya.
+func syncVariants(tx *sql.Tx, product *models.Product, now time.Time) (bool, quantity.Quantity, error) {
+	if _, err := tx.Exec(`DELETE FROM product_options WHERE product_id = $1`, product.ID); err != nil {
+		return false, 0, fmt.Errorf("failed to replace product options: %w", err)
+	}
+	for i, option := range product.Options {
+		_, err := tx.Exec(
+			`INSERT INTO product_options (product_id, position, name, option_values) VALUES ($1, $2, $3, $4)`,
+			product.ID, i, option.Name, pq.Array(option.Values),
+		)
+		if err != nil {
+			return false, 0, fmt.Errorf("failed to insert option %s: %w", option.Name, err)
+		}
+	}
+
+	type existingVariant struct {
+		id     uint
+		values []string
+		stock  quantity.Quantity
+	}
+	rows, err := tx.Query(`
+		SELECT id, option_values, stock
+		FROM product_variants
+		WHERE product_id = $1 AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE`, product.ID)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to lock product variants: %w", err)
+	}
+	var existing []existingVariant
+	for rows.Next() {
+		var variant existingVariant
+		if err := rows.Scan(&variant.id, pq.Array(&variant.values), &variant.stock); err != nil {
+			rows.Close()
+			return false, 0, err
+		}
+		existing = append(existing, variant)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, 0, err
+	}
+
+	matrix := models.VariantMatrix(product.Options)
+	wanted := make(map[string][]string, len(matrix))
+	for _, values := range matrix {
+		wanted[models.VariantKey(values)] = values
+	}
+
+	for _, variant := range existing {
+		key := models.VariantKey(variant.values)
+		values, ok := wanted[key]
+		if !ok {
+			if variant.stock > 0 {
+				return false, 0, fmt.Errorf("invalid options, variant %s of product %d still has stock %s",
+					strings.Join(variant.values, " / "), product.ID, variant.stock)
+			}
+			if _, err := tx.Exec(`UPDATE product_variants SET deleted_at = $1, updated_at = $1 WHERE id = $2`, now, variant.id); err != nil {
+				return false, 0, fmt.Errorf("failed to delete variant %d: %w", variant.id, err)
+			}
+			if _, err := tx.Exec(`DELETE FROM product_barcodes WHERE variant_id = $1`, variant.id); err != nil {
+				return false, 0, fmt.Errorf("failed to delete barcodes of variant %d: %w", variant.id, err)
+			}
+			continue
+		}
+
+		delete(wanted, key)
+		// nilai opsi yang hanya berubah huruf besar/kecil mengikuti penulisan baru
+		if strings.Join(values, "\x00") != strings.Join(variant.values, "\x00") {
+			_, err := tx.Exec(`UPDATE product_variants SET option_values = $1, updated_at = $2 WHERE id = $3`, pq.Array(values), now, variant.id)
+			if err != nil {
+				return false, 0, fmt.Errorf("failed to update variant %d: %w", variant.id, err)
+			}
+		}
+	}
+
+	for _, values := range matrix {
+		if _, ok := wanted[models.VariantKey(values)]; !ok {
+			continue
+		}
+
+		// SKU varian dibentuk dari SKU produk, dikosongkan jika sudah dipakai
+		sku := barcode.VariantSKU(product.SKU, values)
+		if sku != "" {
+			taken, err := skuTaken(tx, sku, 0, 0)
+			if err != nil {
+				return false, 0, err
+			}
+			if taken {
+				sku = ""
+			}
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO product_variants (product_id, sku, option_values, price, stock, created_at, updated_at)
+			VALUES ($1, NULLIF($2, ''), $3, $4, 0, $5, $5)`,
+			product.ID, sku, pq.Array(values), product.Price, now,
+		)
+		if err != nil {
+			return false, 0, fmt.Errorf("failed to insert variant %s: %w", strings.Join(values, " / "), err)
+		}
+	}
+
+	var count int
+	var stock quantity.Quantity
+	err = tx.QueryRow(
+		`SELECT COUNT(*), COALESCE(SUM(stock), 0) FROM product_variants WHERE product_id = $1 AND deleted_at IS NULL`,
+		product.ID,
+	).Scan(&count, &stock)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to sum variant stock: %w", err)
+	}
+	return count > 0, stock, nil
+}
+
+// skuTaken memeriksa apakah SKU sudah dipakai produk atau varian aktif selain productID/variantID
+func skuTaken(tx *sql.Tx, sku string, productID, variantID uint) (bool, error) {
+	var taken bool
+	err := tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM products WHERE sku = $1 AND id <> $2 AND deleted_at IS NULL)
+			OR EXISTS (SELECT 1 FROM product_variants WHERE sku = $1 AND id <> $3 AND deleted_at IS NULL)`,
+		sku, productID, variantID,
+	).Scan(&taken)
+	if err != nil {
+		return false, fmt.Errorf("failed to check sku: %w", err)
+	}
+	return taken, nil
+}
+
+// checkVariantIdentifiers memastikan SKU dan barcode varian belum dipakai produk atau varian aktif lain
+func checkVariantIdentifiers(tx *sql.Tx, variant *models.ProductVariant) error {
+	if variant.SKU != "" {
+		taken, err := skuTaken(tx, variant.SKU, 0, variant.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return fmt.Errorf("sku %s is already used by another product or variant", variant.SKU)
+		}
+	}
+
+	if codes := barcodeCodes(variant.Barcodes); len(codes) > 0 {
+		var code string
+		var otherID uint
+		err := tx.QueryRow(`
+			SELECT code, product_id FROM product_barcodes
+			WHERE code = ANY($1) AND (variant_id IS NULL OR variant_id <> $2)
+			LIMIT 1`,
+			pq.Array(codes), variant.ID,
+		).Scan(&code, &otherID)
+		if err == nil {
+			return fmt.Errorf("barcode %s is already assigned to product %d", code, otherID)
+		}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("failed to check barcodes: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// replaceVariantBarcodes mengganti semua barcode varian sesuai urutan variant.Barcodes
+func replaceVariantBarcodes(tx *sql.Tx, variant *models.ProductVariant) error {
+	if _, err := tx.Exec(`DELETE FROM product_barcodes WHERE variant_id = $1`, variant.ID); err != nil {
+		return fmt.Errorf("failed to replace variant barcodes: %w", err)
+	}
+	for i, variantBarcode := range variant.Barcodes {
+		_, err := tx.Exec(
+			`INSERT INTO product_barcodes (code, product_id, variant_id, type, position) VALUES ($1, $2, $3, $4, $5)`,
+			variantBarcode.Code, variant.ProductID, variant.ID, variantBarcode.Type, i,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert barcode %s: %w", variantBarcode.Code, err)
+		}
+	}
+	return nil
+}
+
+// variantProducts mengembalikan produk (dari ids) yang punya varian aktif; stoknya hanya boleh diubah per varian
+func variantProducts(tx *sql.Tx, ids []int64) (map[uint]bool, error) {
+	rows, err := tx.Query(`
+		SELECT DISTINCT product_id
+		FROM product_variants
+		WHERE product_id = ANY($1) AND deleted_at IS NULL`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product variants: %w", err)
+	}
+	defer rows.Close()
+
+	products := make(map[uint]bool)
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		products[id] = true
+	}
+	return products, rows.Err()
+}
+
+type variantStock struct {
+	ProductID uint
+	Stock     quantity.Quantity
+}
+
+// lockVariantStock mengunci varian aktif (FOR UPDATE, urut id) setelah produknya dikunci dan mengembalikan stok fisiknya
+func lockVariantStock(tx *sql.Tx, ids []int64) (map[uint]variantStock, error) {
+	stock := make(map[uint]variantStock)
+	if len(ids) == 0 {
+		return stock, nil
+	}
+
+	rows, err := tx.Query(`
+		SELECT id, product_id, stock
+		FROM product_variants
+		WHERE id = ANY($1) AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock product variants: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint
+		var onHand variantStock
+		if err := rows.Scan(&id, &onHand.ProductID, &onHand.Stock); err != nil {
+			return nil, err
+		}
+		stock[id] = onHand
+	}
+	return stock, rows.Err()
+}
+
+// activeReservedVariantStock menjumlahkan quantity reservasi ACTIVE yang belum kedaluwarsa per varian
+func activeReservedVariantStock(tx *sql.Tx, ids []int64, now time.Time) (map[uint]quantity.Quantity, error) {
+	reserved := make(map[uint]quantity.Quantity)
+	if len(ids) == 0 {
+		return reserved, nil
+	}
+
+	rows, err := tx.Query(`
+		SELECT ri.variant_id, SUM(ri.quantity)
+		FROM stock_reservation_items ri
+		JOIN stock_reservations sr ON sr.id = ri.reservation_id
+		WHERE ri.variant_id = ANY($1) AND sr.status = $2 AND sr.expires_at > $3
+		GROUP BY ri.variant_id`, pq.Array(ids), models.ReservationStatusActive, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserved variant stock: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint
+		var reservedQuantity quantity.Quantity
+		if err := rows.Scan(&id, &reservedQuantity); err != nil {
+			return nil, err
+		}
+		reserved[id] = reservedQuantity
+	}
+	return reserved, rows.Err()
+}
+
+// addVariantStock menambah (delta positif) atau mengurangi stok varian; stok tidak boleh menjadi negatif.
+// sql.ErrNoRows jika varian bukan milik produk atau stoknya tidak cukup.
+func addVariantStock(tx *sql.Tx, productID, variantID uint, delta quantity.Quantity, now time.Time) error {
+	var stock quantity.Quantity
+	return tx.QueryRow(`
+		UPDATE product_variants
+		SET stock = stock + $1, updated_at = $2
+		WHERE id = $3 AND product_id = $4 AND stock + $1 >= 0
+		RETURNING stock`,
+		delta, now, variantID, productID,
+	).Scan(&stock)
+}
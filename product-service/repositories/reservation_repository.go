@@ -0,0 +1,394 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"product-service/config"
+	"product-service/events"
+	"product-service/models"
+	"product-service/quantity"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ReservationRepository interface {
+	// Create mengunci baris produk, mengecek stok tersedia lalu menyimpan reservasi dalam satu transaksi.
+	// Reservasi ACTIVE lain dengan reference yang sama dilepas lebih dulu.
+	Create(reservation *models.Reservation, now time.Time) error
+	GetByID(id uint) (*models.Reservation, error)
+	// Confirm mengurangi stok produk sesuai reservasi dan menandainya CONFIRMED. Reservasi yang sudah tidak
+	// ACTIVE ditolak dengan error beserta reservasinya (status terkini); yang lewat expires_at ditandai EXPIRED.
+	Confirm(id uint, now time.Time) (*models.Reservation, error)
+	// Release menandai reservasi ACTIVE menjadi RELEASED. Reservasi CONFIRMED ditolak dengan error beserta reservasinya.
+	Release(id uint, now time.Time) (*models.Reservation, error)
+	// ExpireOverdue menandai reservasi ACTIVE yang sudah lewat expires_at menjadi EXPIRED
+	ExpireOverdue(now time.Time) (int64, error)
+}
+
+type reservationRepository struct {
+	db *sql.DB
+}
+
+func NewReservationRepository() ReservationRepository {
+	return &reservationRepository{
+		db: config.DB,
+	}
+}
+
+const reservationColumns = `id, COALESCE(reference, ''), status, expires_at, confirmed_at, released_at, created_at, updated_at`
+
+func (r *reservationRepository) Create(reservation *models.Reservation, now time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if reservation.Reference != "" {
+		_, err = tx.Exec(`
+			UPDATE stock_reservations
+			SET status = $1, released_at = $2, updated_at = $2
+			WHERE reference = $3 AND status = $4`,
+			models.ReservationStatusReleased, now, reservation.Reference, models.ReservationStatusActive,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to release previous reservation: %w", err)
+		}
+	}
+
+	ids := make([]int64, 0, len(reservation.Items))
+	var variantIDs []int64
+	for _, item := range reservation.Items {
+		ids = append(ids, int64(item.ProductID))
+		if item.VariantID != nil {
+			variantIDs = append(variantIDs, int64(*item.VariantID))
+		}
+	}
+
+	// kunci baris produk berurutan (lalu variannya) agar dua reservasi untuk produk yang sama tidak saling mendahului
+	stock, err := lockProductStock(tx, ids)
+	if err != nil {
+		return err
+	}
+	variantStock, err := lockVariantStock(tx, variantIDs)
+	if err != nil {
+		return err
+	}
+	withVariants, err := variantProducts(tx, ids)
+	if err != nil {
+		return err
+	}
+	reserved, err := activeReservedStock(tx, ids, now)
+	if err != nil {
+		return err
+	}
+	reservedVariants, err := activeReservedVariantStock(tx, variantIDs, now)
+	if err != nil {
+		return err
+	}
+
+	// baris produk/varian yang sama dijumlahkan dulu agar stok tersedia dicek terhadap total yang diminta
+	type stockKey struct{ productID, variantID uint }
+	keyOf := func(item models.ReservationItem) stockKey {
+		key := stockKey{productID: item.ProductID}
+		if item.VariantID != nil {
+			key.variantID = *item.VariantID
+		}
+		return key
+	}
+	requested := make(map[stockKey]quantity.Quantity, len(reservation.Items))
+	for _, item := range reservation.Items {
+		requested[keyOf(item)] += item.Quantity
+	}
+
+	for _, item := range reservation.Items {
+		total := requested[keyOf(item)]
+		onHand, ok := stock[item.ProductID]
+		if !ok {
+			return fmt.Errorf("product with ID %d not found", item.ProductID)
+		}
+
+		// stok produk yang punya varian adalah jumlah stok varian, cukup dicek per varian
+		if item.VariantID != nil {
+			variant, ok := variantStock[*item.VariantID]
+			if !ok || variant.ProductID != item.ProductID {
+				return fmt.Errorf("variant %d of product %d not found", *item.VariantID, item.ProductID)
+			}
+			available := variant.Stock - reservedVariants[*item.VariantID]
+			if available < total {
+				if available < 0 {
+					available = 0
+				}
+				return fmt.Errorf("insufficient stock for product %d variant %d: available %s, requested %s",
+					item.ProductID, *item.VariantID, available, total)
+			}
+			continue
+		}
+		if withVariants[item.ProductID] {
+			return fmt.Errorf("invalid item, product %d has variants, variant_id is required", item.ProductID)
+		}
+
+		available := onHand - reserved[item.ProductID]
+		if available < total {
+			if available < 0 {
+				available = 0
+			}
+			return fmt.Errorf("insufficient stock for product %d: available %s, requested %s", item.ProductID, available, total)
+		}
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO stock_reservations (reference, status, expires_at, created_at, updated_at)
+		VALUES (NULLIF($1, ''), $2, $3, $4, $4)
+		RETURNING id, created_at, updated_at`,
+		reservation.Reference, models.ReservationStatusActive, reservation.ExpiresAt, now,
+	).Scan(&reservation.ID, &reservation.CreatedAt, &reservation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert reservation: %w", err)
+	}
+	reservation.Status = models.ReservationStatusActive
+
+	for _, item := range reservation.Items {
+		_, err = tx.Exec(
+			`INSERT INTO stock_reservation_items (reservation_id, product_id, variant_id, quantity) VALUES ($1, $2, $3, $4)`,
+			reservation.ID, item.ProductID, item.VariantID, item.Quantity,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert reservation item: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *reservationRepository) GetByID(id uint) (*models.Reservation, error) {
+	reservation, err := scanReservation(r.db.QueryRow(`SELECT `+reservationColumns+` FROM stock_reservations WHERE id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	reservation.Items, err = getReservationItems(r.db, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation items: %w", err)
+	}
+	return reservation, nil
+}
+
+func (r *reservationRepository) Confirm(id uint, now time.Time) (*models.Reservation, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	reservation, err := scanReservation(tx.QueryRow(`SELECT `+reservationColumns+` FROM stock_reservations WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		return nil, err
+	}
+	reservation.Items, err = getReservationItems(tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation items: %w", err)
+	}
+
+	switch {
+	case reservation.Status == models.ReservationStatusConfirmed:
+		// konfirmasi ulang (retry) tidak mengurangi stok dua kali
+		return reservation, nil
+	case reservation.Status != models.ReservationStatusActive:
+		return reservation, fmt.Errorf("reservation %d is %s", id, reservation.Status)
+	case !reservation.Active(now):
+		// ditandai EXPIRED sekarang agar pemanggil tahu pasti stoknya tidak akan dikurangi
+		_, err := tx.Exec(`UPDATE stock_reservations SET status = $1, updated_at = $2 WHERE id = $3`,
+			models.ReservationStatusExpired, now, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expire reservation: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		reservation.Status = models.ReservationStatusExpired
+		return reservation, fmt.Errorf("reservation %d has expired", id)
+	}
+
+	// urut per product_id lalu variant_id (lihat getReservationItems) agar urutan lock sama dengan Create
+	for _, item := range reservation.Items {
+		var stock quantity.Quantity
+		err := tx.QueryRow(`
+			UPDATE products
+			SET stock = stock - $1, updated_at = $2
+			WHERE id = $3 AND deleted_at IS NULL AND stock >= $1
+			RETURNING stock`,
+			item.Quantity, now, item.ProductID,
+		).Scan(&stock)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("insufficient stock for product %d", item.ProductID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to update stock: %w", err)
+		}
+		if item.VariantID != nil {
+			err := addVariantStock(tx, item.ProductID, *item.VariantID, -item.Quantity, now)
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("insufficient stock for product %d variant %d", item.ProductID, *item.VariantID)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to update variant stock: %w", err)
+			}
+		}
+		reference := fmt.Sprintf("reservation:%d", id)
+		if err := writeStockChanged(tx, item.ProductID, item.VariantID, stock+item.Quantity, stock, events.StockReasonSale, reference, now); err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.QueryRow(`
+		UPDATE stock_reservations
+		SET status = $1, confirmed_at = $2, updated_at = $2
+		WHERE id = $3
+		RETURNING confirmed_at, updated_at`,
+		models.ReservationStatusConfirmed, now, id,
+	).Scan(&reservation.ConfirmedAt, &reservation.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm reservation: %w", err)
+	}
+	reservation.Status = models.ReservationStatusConfirmed
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (r *reservationRepository) Release(id uint, now time.Time) (*models.Reservation, error) {
+	_, err := r.db.Exec(`
+		UPDATE stock_reservations
+		SET status = $1, released_at = $2, updated_at = $2
+		WHERE id = $3 AND status = $4`,
+		models.ReservationStatusReleased, now, id, models.ReservationStatusActive,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release reservation: %w", err)
+	}
+
+	reservation, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status == models.ReservationStatusConfirmed {
+		return reservation, fmt.Errorf("reservation %d is already CONFIRMED", id)
+	}
+	// RELEASED atau EXPIRED: melepas ulang tidak dianggap error
+	return reservation, nil
+}
+
+func (r *reservationRepository) ExpireOverdue(now time.Time) (int64, error) {
+	res, err := r.db.Exec(`
+		UPDATE stock_reservations
+		SET status = $1, updated_at = $2
+		WHERE status = $3 AND expires_at <= $2`,
+		models.ReservationStatusExpired, now, models.ReservationStatusActive,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire reservations: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// lockProductStock mengunci produk (FOR UPDATE, urut id) dan mengembalikan stok fisiknya
+func lockProductStock(tx *sql.Tx, ids []int64) (map[uint]quantity.Quantity, error) {
+	rows, err := tx.Query(`
+		SELECT id, stock
+		FROM products
+		WHERE id = ANY($1) AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock products: %w", err)
+	}
+	defer rows.Close()
+
+	stock := make(map[uint]quantity.Quantity)
+	for rows.Next() {
+		var id uint
+		var onHand quantity.Quantity
+		if err := rows.Scan(&id, &onHand); err != nil {
+			return nil, err
+		}
+		stock[id] = onHand
+	}
+	return stock, rows.Err()
+}
+
+// activeReservedStock menjumlahkan quantity reservasi ACTIVE yang belum kedaluwarsa per produk
+func activeReservedStock(tx *sql.Tx, ids []int64, now time.Time) (map[uint]quantity.Quantity, error) {
+	rows, err := tx.Query(`
+		SELECT ri.product_id, SUM(ri.quantity)
+		FROM stock_reservation_items ri
+		JOIN stock_reservations sr ON sr.id = ri.reservation_id
+		WHERE ri.product_id = ANY($1) AND sr.status = $2 AND sr.expires_at > $3
+		GROUP BY ri.product_id`, pq.Array(ids), models.ReservationStatusActive, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserved stock: %w", err)
+	}
+	defer rows.Close()
+
+	reserved := make(map[uint]quantity.Quantity)
+	for rows.Next() {
+		var id uint
+		var reservedQuantity quantity.Quantity
+		if err := rows.Scan(&id, &reservedQuantity); err != nil {
+			return nil, err
+		}
+		reserved[id] = reservedQuantity
+	}
+	return reserved, rows.Err()
+}
+
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func getReservationItems(q queryer, reservationID uint) ([]models.ReservationItem, error) {
+	rows, err := q.Query(`
+		SELECT product_id, variant_id, quantity
+		FROM stock_reservation_items
+		WHERE reservation_id = $1
+		ORDER BY product_id, variant_id`, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.ReservationItem
+	for rows.Next() {
+		var item models.ReservationItem
+		if err := rows.Scan(&item.ProductID, &item.VariantID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanReservation(row rowScanner) (*models.Reservation, error) {
+	var reservation models.Reservation
+	err := row.Scan(
+		&reservation.ID,
+		&reservation.Reference,
+		&reservation.Status,
+		&reservation.ExpiresAt,
+		&reservation.ConfirmedAt,
+		&reservation.ReleasedAt,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
@@ -0,0 +1,258 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"product-service/config"
+	"product-service/events"
+	"product-service/models"
+	"product-service/quantity"
+	"time"
+)
+
+type RestockRepository interface {
+	// Create menambah stok sesuai restock. Jika reference sudah APPLIED stok tidak berubah dan hasilnya false;
+	// reference yang sudah CANCELLED diterapkan lagi dengan item yang tersimpan.
+	Create(restock *models.Restock, now time.Time) (bool, error)
+	GetByID(id uint) (*models.Restock, error)
+	// Cancel mengurangi kembali stok yang ditambahkan restock (kompensasi saga)
+	Cancel(id uint, now time.Time) (*models.Restock, error)
+}
+
+type restockRepository struct {
+	db *sql.DB
+}
+
+func NewRestockRepository() RestockRepository {
+	return &restockRepository{
+		db: config.DB,
+	}
+}
+
+const restockColumns = `id, reference, reason, status, cancelled_at, created_at, updated_at`
+
+func (r *restockRepository) Create(restock *models.Restock, now time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// serialisasi per reference, baris restock mungkin belum ada untuk dikunci
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, restock.Reference); err != nil {
+		return false, fmt.Errorf("failed to lock restock reference: %w", err)
+	}
+
+	existing, err := scanRestock(tx.QueryRow(`SELECT `+restockColumns+` FROM stock_restocks WHERE reference = $1`, restock.Reference))
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to get restock: %w", err)
+	}
+
+	if existing != nil {
+		existing.Items, err = getRestockItems(tx, existing.ID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get restock items: %w", err)
+		}
+		*restock = *existing
+		if existing.Status == models.RestockStatusApplied {
+			return false, nil
+		}
+
+		if err := addStock(tx, restock.Items, restock.Reference, now); err != nil {
+			return false, err
+		}
+		err = tx.QueryRow(`
+			UPDATE stock_restocks
+			SET status = $1, cancelled_at = NULL, updated_at = $2
+			WHERE id = $3
+			RETURNING updated_at`,
+			models.RestockStatusApplied, now, restock.ID,
+		).Scan(&restock.UpdatedAt)
+		if err != nil {
+			return false, fmt.Errorf("failed to reapply restock: %w", err)
+		}
+		restock.Status = models.RestockStatusApplied
+		restock.CancelledAt = nil
+		return true, tx.Commit()
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO stock_restocks (reference, reason, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		RETURNING id, created_at, updated_at`,
+		restock.Reference, restock.Reason, models.RestockStatusApplied, now,
+	).Scan(&restock.ID, &restock.CreatedAt, &restock.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert restock: %w", err)
+	}
+	restock.Status = models.RestockStatusApplied
+
+	for _, item := range restock.Items {
+		_, err = tx.Exec(
+			`INSERT INTO stock_restock_items (restock_id, product_id, variant_id, quantity) VALUES ($1, $2, $3, $4)`,
+			restock.ID, item.ProductID, item.VariantID, item.Quantity,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to insert restock item: %w", err)
+		}
+	}
+
+	if err := addStock(tx, restock.Items, restock.Reference, now); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *restockRepository) GetByID(id uint) (*models.Restock, error) {
+	restock, err := scanRestock(r.db.QueryRow(`SELECT `+restockColumns+` FROM stock_restocks WHERE id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	restock.Items, err = getRestockItems(r.db, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restock items: %w", err)
+	}
+	return restock, nil
+}
+
+func (r *restockRepository) Cancel(id uint, now time.Time) (*models.Restock, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	restock, err := scanRestock(tx.QueryRow(`SELECT `+restockColumns+` FROM stock_restocks WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		return nil, err
+	}
+	restock.Items, err = getRestockItems(tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restock items: %w", err)
+	}
+	if restock.Status == models.RestockStatusCancelled {
+		return restock, nil
+	}
+
+	for _, item := range restock.Items {
+		var stock quantity.Quantity
+		err := tx.QueryRow(`
+			UPDATE products
+			SET stock = stock - $1, updated_at = $2
+			WHERE id = $3 AND stock >= $1
+			RETURNING stock`,
+			item.Quantity, now, item.ProductID,
+		).Scan(&stock)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("insufficient stock for product %d to cancel restock", item.ProductID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to update stock: %w", err)
+		}
+		if item.VariantID != nil {
+			err := addVariantStock(tx, item.ProductID, *item.VariantID, -item.Quantity, now)
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("insufficient stock for product %d variant %d to cancel restock", item.ProductID, *item.VariantID)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to update variant stock: %w", err)
+			}
+		}
+		if err := writeStockChanged(tx, item.ProductID, item.VariantID, stock+item.Quantity, stock, events.StockReasonRestockUndo, restock.Reference, now); err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.QueryRow(`
+		UPDATE stock_restocks
+		SET status = $1, cancelled_at = $2, updated_at = $2
+		WHERE id = $3
+		RETURNING cancelled_at, updated_at`,
+		models.RestockStatusCancelled, now, id,
+	).Scan(&restock.CancelledAt, &restock.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel restock: %w", err)
+	}
+	restock.Status = models.RestockStatusCancelled
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return restock, nil
+}
+
+// addStock menambah stok per produk (dan variannya), termasuk produk yang sudah di-soft delete. Restock berasal
+// dari baris transaksi (void/retur): baris tanpa varian dari produk yang kemudian diberi varian, mis. penjualan
+// sebelum varian dibuat, menambah stok baris produk saja sebagai stok yang belum dibagi ke varian.
+func addStock(tx *sql.Tx, items []models.RestockItem, reference string, now time.Time) error {
+	for _, item := range items {
+		var stock quantity.Quantity
+		err := tx.QueryRow(`
+			UPDATE products
+			SET stock = stock + $1, updated_at = $2
+			WHERE id = $3
+			RETURNING stock`,
+			item.Quantity, now, item.ProductID,
+		).Scan(&stock)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product with ID %d not found", item.ProductID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restock product %d: %w", item.ProductID, err)
+		}
+		if item.VariantID != nil {
+			err := addVariantStock(tx, item.ProductID, *item.VariantID, item.Quantity, now)
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("variant %d of product %d not found", *item.VariantID, item.ProductID)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to restock variant %d: %w", *item.VariantID, err)
+			}
+		}
+		if err := writeStockChanged(tx, item.ProductID, item.VariantID, stock-item.Quantity, stock, events.StockReasonRestock, reference, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getRestockItems(q queryer, restockID uint) ([]models.RestockItem, error) {
+	rows, err := q.Query(`
+		SELECT product_id, variant_id, quantity
+		FROM stock_restock_items
+		WHERE restock_id = $1
+		ORDER BY product_id, variant_id`, restockID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.RestockItem
+	for rows.Next() {
+		var item models.RestockItem
+		if err := rows.Scan(&item.ProductID, &item.VariantID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func scanRestock(row rowScanner) (*models.Restock, error) {
+	var restock models.Restock
+	err := row.Scan(
+		&restock.ID,
+		&restock.Reference,
+		&restock.Reason,
+		&restock.Status,
+		&restock.CancelledAt,
+		&restock.CreatedAt,
+		&restock.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &restock, nil
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"product-service/handlers"
+	"product-service/repositories"
+	"product-service/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupCategoryRoutes mendaftarkan CRUD kategori produk bertingkat
+func SetupCategoryRoutes(app *fiber.App) {
+	categoryRepo := repositories.NewCategoryRepository()
+	categoryService := services.NewCategoryService(categoryRepo)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+
+	categories := app.Group("/api/categories")
+
+	categories.Post("/", categoryHandler.CreateCategory)
+	categories.Get("/", categoryHandler.GetAllCategories)
+	categories.Get("/:id", categoryHandler.GetCategory)
+	categories.Put("/:id", categoryHandler.UpdateCategory)
+	categories.Post("/:id/move", categoryHandler.MoveCategory)
+	categories.Delete("/:id", categoryHandler.DeleteCategory)
+}
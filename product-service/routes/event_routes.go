@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"log"
+	"os"
+	"product-service/events"
+	"product-service/repositories"
+	"product-service/services"
+	"strconv"
+	"time"
+)
+
+// SetupEvents membuat broker dari EVENT_BROKER (file atau memory) dan menjalankan relay outbox.
+// Dengan broker file, service lain membaca event dari EVENT_BROKER_FILE yang sama.
+func SetupEvents() events.Broker {
+	broker, err := events.NewBroker(getStringEnv("EVENT_BROKER", "file"), getStringEnv("EVENT_BROKER_FILE", "data/events.jsonl"))
+	if err != nil {
+		log.Fatalf("Invalid event broker configuration: %v", err)
+	}
+
+	relay := services.NewOutboxRelay(repositories.NewOutboxRepository(), broker, getIntEnv("OUTBOX_BATCH_SIZE", 100))
+	relay.Start(getDurationEnv("OUTBOX_RELAY_INTERVAL", time.Second))
+
+	return broker
+}
+
+func getStringEnv(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		log.Printf("Invalid %s value %q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return number
+}
@@ -1,6 +1,9 @@
 package routes
 
 import (
+	"log"
+	"os"
+	"product-service/barcode"
 	"product-service/handlers"
 	"product-service/repositories"
 	"product-service/services"
@@ -11,15 +14,36 @@ import (
 func SetupProductRoutes(app *fiber.App) {
 	// inisialisasi layer/dependency
 	productRepo := repositories.NewProductRepository()
-	productService := services.NewProductService(productRepo)
+	productService := services.NewProductService(productRepo, getScaleFormats())
 	productHandler := handlers.NewProductHandler(productService)
+	importService := services.NewProductImportService(productRepo, repositories.NewCategoryRepository())
+	importHandler := handlers.NewProductImportHandler(importService)
 
 	api := app.Group("/api")
 	products := api.Group("/products")
 	
 	products.Post("/", productHandler.CreateProduct)
 	products.Get("/", productHandler.GetAllProducts)
+	products.Get("/lookup", productHandler.LookupProduct) // sebelum /:id
+	products.Post("/import", importHandler.ImportProducts)
 	products.Get("/:id", productHandler.GetProduct)
 	products.Put("/:id", productHandler.UpdateProduct)
 	products.Delete("/:id", productHandler.DeleteProduct)
+	products.Get("/:id/variants", productHandler.GetVariants)
+	products.Put("/:id/variants/:variantId", productHandler.UpdateVariant)
+}
+
+// getScaleFormats membaca format label timbangan dari SCALE_BARCODE_FORMATS, kosong berarti default
+func getScaleFormats() barcode.ScaleFormats {
+	spec := os.Getenv("SCALE_BARCODE_FORMATS")
+	if spec == "" {
+		spec = barcode.DefaultScaleFormats
+	}
+
+	formats, err := barcode.ParseScaleFormats(spec)
+	if err != nil {
+		log.Printf("Invalid SCALE_BARCODE_FORMATS: %v, using default %s", err, barcode.DefaultScaleFormats)
+		formats, _ = barcode.ParseScaleFormats(barcode.DefaultScaleFormats)
+	}
+	return formats
 }
@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"log"
+	"os"
+	"product-service/handlers"
+	"product-service/repositories"
+	"product-service/services"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupStockRoutes mendaftarkan API stok yang dipakai transaction-service: reservasi dan restock
+func SetupStockRoutes(app *fiber.App) {
+	reservationRepo := repositories.NewReservationRepository()
+	reservationService := services.NewReservationService(
+		reservationRepo,
+		getDurationEnv("RESERVATION_TTL", 15*time.Minute),
+		getDurationEnv("RESERVATION_MAX_TTL", 24*time.Hour),
+	)
+	reservationHandler := handlers.NewReservationHandler(reservationService)
+
+	// reservasi kedaluwarsa sudah tidak dihitung walau belum disapu, sweeper hanya merapikan statusnya
+	reservationService.StartSweeper(getDurationEnv("RESERVATION_SWEEP_INTERVAL", time.Minute))
+
+	reservations := app.Group("/api/reservations")
+
+	reservations.Post("/", reservationHandler.CreateReservation)
+	reservations.Get("/:id", reservationHandler.GetReservation)
+	reservations.Post("/:id/confirm", reservationHandler.ConfirmReservation)
+	reservations.Post("/:id/release", reservationHandler.ReleaseReservation)
+
+	restockRepo := repositories.NewRestockRepository()
+	restockService := services.NewRestockService(restockRepo)
+	restockHandler := handlers.NewRestockHandler(restockService)
+
+	restocks := app.Group("/api/stock/restocks")
+
+	restocks.Post("/", restockHandler.CreateRestock)
+	restocks.Get("/:id", restockHandler.GetRestock)
+	restocks.Post("/:id/cancel", restockHandler.CancelRestock)
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("Invalid %s value %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
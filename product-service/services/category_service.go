@@ -0,0 +1,180 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"product-service/dto"
+	"product-service/models"
+	"product-service/repositories"
+	"strings"
+)
+
+type CategoryService interface {
+	CreateCategory(req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error)
+	// GetAllCategories mengembalikan pohon kategori, atau daftar datar urut pohon jika flat=true
+	GetAllCategories(flat bool) ([]dto.CategoryResponse, error)
+	// GetCategoryByID mengembalikan kategori beserta seluruh subkategorinya
+	GetCategoryByID(id uint) (*dto.CategoryResponse, error)
+	UpdateCategory(id uint, req *dto.UpdateCategoryRequest) (*dto.CategoryResponse, error)
+	MoveCategory(id uint, req *dto.MoveCategoryRequest) (*dto.CategoryResponse, error)
+	DeleteCategory(id uint) error
+}
+
+type categoryService struct {
+	repo repositories.CategoryRepository
+}
+
+func NewCategoryService(repo repositories.CategoryRepository) CategoryService {
+	return &categoryService{
+		repo: repo,
+	}
+}
+
+func (s *categoryService) CreateCategory(req *dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("invalid name, category name is required")
+	}
+
+	category := &models.Category{
+		Name:      name,
+		ParentID:  nilIfZero(req.ParentID),
+		SortOrder: req.SortOrder,
+	}
+	if err := s.repo.Create(category); err != nil {
+		return nil, err
+	}
+
+	return s.GetCategoryByID(category.ID)
+}
+
+func (s *categoryService) GetAllCategories(flat bool) ([]dto.CategoryResponse, error) {
+	categories, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if flat {
+		responses := make([]dto.CategoryResponse, 0, len(categories))
+		for i := range categories {
+			responses = append(responses, *categoryToResponse(&categories[i]))
+		}
+		return responses, nil
+	}
+	return buildCategoryTree(categories, nil), nil
+}
+
+func (s *categoryService) GetCategoryByID(id uint) (*dto.CategoryResponse, error) {
+	category, err := s.repo.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	categories, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	response := categoryToResponse(category)
+	response.Children = buildCategoryTree(categories, &category.ID)
+	return response, nil
+}
+
+func (s *categoryService) UpdateCategory(id uint, req *dto.UpdateCategoryRequest) (*dto.CategoryResponse, error) {
+	category, err := s.repo.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	// update kolom yang diisi saja
+	if name := strings.TrimSpace(req.Name); name != "" {
+		category.Name = name
+	}
+	if req.SortOrder != nil {
+		category.SortOrder = *req.SortOrder
+	}
+
+	if err := s.repo.Update(category); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	return s.GetCategoryByID(id)
+}
+
+func (s *categoryService) MoveCategory(id uint, req *dto.MoveCategoryRequest) (*dto.CategoryResponse, error) {
+	if err := s.repo.Move(id, nilIfZero(req.ParentID), req.SortOrder); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	return s.GetCategoryByID(id)
+}
+
+func (s *categoryService) DeleteCategory(id uint) error {
+	if err := s.repo.Delete(id); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("category not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// buildCategoryTree menyusun anak dari parentID (nil untuk kategori utama). Urutan categories sudah
+// urut pohon dari repository, sehingga urutan saudara tetap mengikuti sort_order.
+func buildCategoryTree(categories []models.Category, parentID *uint) []dto.CategoryResponse {
+	var children []dto.CategoryResponse
+	for i := range categories {
+		category := &categories[i]
+		if !sameParent(category.ParentID, parentID) {
+			continue
+		}
+		response := categoryToResponse(category)
+		response.Children = buildCategoryTree(categories, &category.ID)
+		children = append(children, *response)
+	}
+	if children == nil && parentID == nil {
+		return []dto.CategoryResponse{}
+	}
+	return children
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// nilIfZero memperlakukan id 0 sama dengan kosong, mis. parent_id 0 berarti kategori utama
+func nilIfZero(id *uint) *uint {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	return id
+}
+
+func categoryToResponse(category *models.Category) *dto.CategoryResponse {
+	return &dto.CategoryResponse{
+		ID:           category.ID,
+		Name:         category.Name,
+		ParentID:     category.ParentID,
+		SortOrder:    category.SortOrder,
+		Depth:        category.Depth,
+		Path:         category.Path,
+		ProductCount: category.ProductCount,
+		CreatedAt:    category.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:    category.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"log"
+	"product-service/events"
+	"product-service/repositories"
+	"time"
+)
+
+// OutboxRelay mengirim event dari tabel outbox ke broker. Pengiriman bersifat at-least-once:
+// event yang sudah terkirim tapi gagal ditandai akan dikirim ulang dengan ID yang sama.
+type OutboxRelay interface {
+	Start(interval time.Duration)
+}
+
+type outboxRelay struct {
+	repo      repositories.OutboxRepository
+	broker    events.Broker
+	batchSize int
+}
+
+func NewOutboxRelay(repo repositories.OutboxRepository, broker events.Broker, batchSize int) OutboxRelay {
+	return &outboxRelay{
+		repo:      repo,
+		broker:    broker,
+		batchSize: batchSize,
+	}
+}
+
+func (r *outboxRelay) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			// batch penuh berarti masih ada antrean, langsung lanjut tanpa menunggu tick berikutnya
+			for {
+				published, err := r.repo.PublishPending(r.batchSize, r.broker.Publish)
+				if err != nil {
+					log.Printf("outbox relay: %v", err)
+					break
+				}
+				if published < r.batchSize {
+					break
+				}
+			}
+		}
+	}()
+}
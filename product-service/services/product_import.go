@@ -0,0 +1,420 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"product-service/dto"
+	"product-service/importer"
+	"product-service/models"
+	"product-service/money"
+	"product-service/quantity"
+	"product-service/repositories"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// mode import
+const (
+	ImportModeAtomic  = "ATOMIC"
+	ImportModeChunked = "CHUNKED"
+)
+
+// aksi baris import, produk dicocokkan lewat SKU
+const (
+	importActionCreate = "CREATE"
+	importActionUpdate = "UPDATE"
+)
+
+type ProductImportService interface {
+	// ImportProducts memvalidasi lalu membuat/mengupdate produk dari tabel hasil importer.Read.
+	// progress (boleh nil) dipanggil setiap chunk selesai. Error hanya dikembalikan jika import tidak
+	// bisa berjalan (mis. mapping salah), error per baris ada di report.
+	ImportProducts(table *importer.Table, opts dto.ImportOptions, progress func(dto.ImportChunkResult)) (*dto.ImportReport, error)
+}
+
+type productImportService struct {
+	productRepo  repositories.ProductRepository
+	categoryRepo repositories.CategoryRepository
+}
+
+func NewProductImportService(productRepo repositories.ProductRepository, categoryRepo repositories.CategoryRepository) ProductImportService {
+	return &productImportService{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *productImportService) ImportProducts(table *importer.Table, opts dto.ImportOptions, progress func(dto.ImportChunkResult)) (*dto.ImportReport, error) {
+	if opts.ChunkSize < 0 {
+		return nil, errors.New("invalid chunk_size, must be 0 (all rows at once) or more")
+	}
+	mapping, err := table.Resolve(opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories *categoryIndex
+	if _, ok := mapping[importer.FieldCategory]; ok {
+		all, err := s.categoryRepo.GetAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load categories: %w", err)
+		}
+		categories = newCategoryIndex(all)
+	}
+
+	report := &dto.ImportReport{
+		DryRun:    opts.DryRun,
+		Mode:      ImportModeAtomic,
+		TotalRows: len(table.Rows),
+		Rows:      make([]dto.ImportRowResult, len(table.Rows)),
+	}
+	if opts.ChunkSize > 0 {
+		report.Mode = ImportModeChunked
+	}
+
+	// validasi semua baris lebih dulu, termasuk SKU dan barcode yang muncul dua kali di file
+	items := make([]*repositories.ProductImportItem, len(table.Rows))
+	skuLines := make(map[string]int)
+	barcodeLines := make(map[string]int)
+	for i, row := range table.Rows {
+		result := &report.Rows[i]
+		result.Line = row.Line
+
+		req, cells, errs := parseImportRow(row, mapping, categories)
+		result.SKU = req.SKU
+		if line, exists := skuLines[req.SKU]; exists && req.SKU != "" {
+			errs = append(errs, fmt.Sprintf("sku %s is already listed on line %d", req.SKU, line))
+		} else if req.SKU != "" {
+			skuLines[req.SKU] = row.Line
+		}
+		for _, b := range req.Barcodes {
+			if line, exists := barcodeLines[b.Code]; exists {
+				errs = append(errs, fmt.Sprintf("barcode %s is already listed on line %d", b.Code, line))
+			} else {
+				barcodeLines[b.Code] = row.Line
+			}
+		}
+
+		if len(errs) == 0 {
+			item, err := s.importItem(req, cells)
+			if err != nil {
+				if !strings.Contains(err.Error(), "invalid") && !strings.Contains(err.Error(), "already") {
+					return nil, err
+				}
+				errs = append(errs, err.Error())
+			} else {
+				items[i] = item
+				result.Action = importActionCreate
+				if item.ID != 0 {
+					result.Action = importActionUpdate
+				}
+			}
+		}
+		if len(errs) > 0 {
+			result.Status = dto.ImportRowError
+			result.Errors = errs
+		}
+	}
+
+	// dry run selalu satu transaksi agar tiap baris melihat baris sebelumnya, mis. barcode yang dipindah
+	chunkSize := opts.ChunkSize
+	if chunkSize == 0 || opts.DryRun {
+		chunkSize = len(table.Rows)
+	}
+	for start := 0; start < len(table.Rows); start += chunkSize {
+		end := start + chunkSize
+		if end > len(table.Rows) {
+			end = len(table.Rows)
+		}
+
+		chunk, err := s.importChunk(report, items, start, end, opts.DryRun)
+		if err != nil {
+			if report.Mode == ImportModeChunked && start > 0 {
+				return nil, fmt.Errorf("import stopped at line %d, rows before it were applied: %w", report.Rows[start].Line, err)
+			}
+			return nil, err
+		}
+		chunk.Chunk = len(report.Chunks) + 1
+		chunk.Processed = end
+		chunk.Total = len(table.Rows)
+		report.Chunks = append(report.Chunks, *chunk)
+		if progress != nil {
+			progress(*chunk)
+		}
+	}
+
+	for _, row := range report.Rows {
+		switch row.Status {
+		case dto.ImportRowError:
+			report.Failed++
+		case dto.ImportRowRolledBack:
+			report.RolledBack++
+		default:
+			if row.Action == importActionUpdate {
+				report.Updated++
+			} else {
+				report.Created++
+			}
+		}
+	}
+	return report, nil
+}
+
+// importChunk menjalankan baris [start, end) dalam satu transaksi yang hanya di-commit jika semua barisnya valid
+func (s *productImportService) importChunk(report *dto.ImportReport, items []*repositories.ProductImportItem, start, end int, dryRun bool) (*dto.ImportChunkResult, error) {
+	var batch []repositories.ProductImportItem
+	var positions []int
+	invalid := 0
+	for i := start; i < end; i++ {
+		if items[i] == nil {
+			invalid++
+			continue
+		}
+		batch = append(batch, *items[i])
+		positions = append(positions, i)
+	}
+
+	// baris yang sudah tidak valid tetap tidak disimpan, tetapi baris lain tetap dicek ke database
+	committed := false
+	if len(batch) > 0 {
+		errs, ok, err := s.productRepo.Import(batch, !dryRun && invalid == 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import products: %w", err)
+		}
+		committed = ok
+		for k, err := range errs {
+			if err != nil {
+				row := &report.Rows[positions[k]]
+				row.Status = dto.ImportRowError
+				row.Errors = append(row.Errors, importErrorMessage(err))
+			}
+		}
+	}
+
+	chunk := &dto.ImportChunkResult{
+		FromLine: report.Rows[start].Line,
+		ToLine:   report.Rows[end-1].Line,
+		Rows:     end - start,
+		Applied:  committed,
+	}
+	for k, i := range positions {
+		row := &report.Rows[i]
+		if row.Status == dto.ImportRowError {
+			continue
+		}
+		switch {
+		case committed:
+			row.Status = dto.ImportRowApplied
+			row.ProductID = batch[k].Product.ID
+		case dryRun:
+			row.Status = dto.ImportRowValid
+			if batch[k].ID != 0 {
+				row.ProductID = batch[k].ID
+			}
+		default:
+			row.Status = dto.ImportRowRolledBack
+		}
+	}
+	for i := start; i < end; i++ {
+		if report.Rows[i].Status == dto.ImportRowError {
+			chunk.Failed++
+		}
+	}
+	return chunk, nil
+}
+
+// importCells mencatat kolom opsional yang diisi di baris; untuk produk yang sudah ada, kolom kosong
+// tidak mengubah nilai lamanya
+type importCells struct {
+	stock    bool
+	taxClass bool
+	barcodes bool
+	category bool
+}
+
+// parseImportRow membaca satu baris menjadi CreateProductRequest lalu menjalankan aturan yang sama
+// dengan POST /api/products; semua error baris dikumpulkan untuk laporan
+func parseImportRow(row importer.Row, mapping importer.Mapping, categories *categoryIndex) (*dto.CreateProductRequest, importCells, []string) {
+	req := &dto.CreateProductRequest{}
+	var cells importCells
+	var errs []string
+
+	req.SKU, _ = mapping.Value(row, importer.FieldSKU)
+	req.Name, _ = mapping.Value(row, importer.FieldName)
+
+	if value, _ := mapping.Value(row, importer.FieldPrice); value != "" {
+		price, err := money.Parse(value)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid price %q", value))
+		}
+		req.Price = price
+	}
+	if value, _ := mapping.Value(row, importer.FieldStock); value != "" {
+		stock, err := quantity.Parse(value)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid stock %q", value))
+		}
+		req.Stock = stock
+		cells.stock = true
+	}
+	if value, _ := mapping.Value(row, importer.FieldTaxClass); value != "" {
+		req.TaxClass = value
+		cells.taxClass = true
+	}
+	// beberapa barcode dalam satu sel dipisah titik koma, koma, garis tegak atau spasi
+	if value, _ := mapping.Value(row, importer.FieldBarcodes); value != "" {
+		codes := strings.FieldsFunc(value, func(r rune) bool {
+			return r == ';' || r == ',' || r == '|' || unicode.IsSpace(r)
+		})
+		for _, code := range codes {
+			req.Barcodes = append(req.Barcodes, dto.BarcodeRequest{Code: code})
+		}
+		cells.barcodes = true
+	}
+	if value, _ := mapping.Value(row, importer.FieldCategory); value != "" {
+		categoryID, err := categories.resolve(value)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		req.CategoryID = categoryID
+		cells.category = true
+	}
+	if len(errs) > 0 {
+		return req, cells, errs
+	}
+
+	// SKU wajib agar import yang diulang (mis. setelah chunk gagal) mengupdate produk yang sama
+	if strings.TrimSpace(req.SKU) == "" {
+		return req, cells, []string{"invalid sku, sku is required to import a product"}
+	}
+	if err := ValidateCreateProduct(req); err != nil {
+		return req, cells, []string{err.Error()}
+	}
+	taxClass, err := normalizeTaxClass(req.TaxClass)
+	if err != nil {
+		return req, cells, []string{err.Error()}
+	}
+	req.TaxClass = taxClass
+	return req, cells, nil
+}
+
+// importItem menentukan apakah baris membuat produk baru atau mengupdate produk dengan SKU yang sama.
+// Update hanya mengubah nama dan harga serta kolom opsional yang diisi; opsi dan varian tidak berubah.
+func (s *productImportService) importItem(req *dto.CreateProductRequest, cells importCells) (*repositories.ProductImportItem, error) {
+	product := &models.Product{
+		Name:     req.Name,
+		SKU:      req.SKU,
+		Barcodes: barcodesToModel(req.Barcodes),
+		Price:    req.Price,
+		Stock:    req.Stock,
+		TaxClass: req.TaxClass,
+	}
+	if req.CategoryID > 0 {
+		categoryID := req.CategoryID
+		product.CategoryID = &categoryID
+	}
+
+	existing, err := s.productRepo.GetBySKU(req.SKU)
+	if err == sql.ErrNoRows {
+		return &repositories.ProductImportItem{Product: product}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up sku %s: %w", req.SKU, err)
+	}
+	if existing.SKU != req.SKU {
+		return nil, fmt.Errorf("sku %s is already used by a variant of product %d", req.SKU, existing.ID)
+	}
+
+	product.Options = existing.Options
+	if !cells.stock {
+		product.Stock = existing.Stock
+	} else if len(existing.Variants) > 0 && req.Stock != existing.Stock {
+		return nil, fmt.Errorf("invalid stock, product %d has variants, set the stock of each variant", existing.ID)
+	}
+	if !cells.taxClass {
+		product.TaxClass = existing.TaxClass
+	}
+	if !cells.barcodes {
+		product.Barcodes = existing.Barcodes
+	}
+	if !cells.category {
+		product.CategoryID = existing.CategoryID
+	}
+	return &repositories.ProductImportItem{ID: existing.ID, Product: product}, nil
+}
+
+// ImportSummary meringkas hasil import untuk pesan response dan log CLI
+func ImportSummary(report *dto.ImportReport) string {
+	if report.DryRun {
+		return fmt.Sprintf("Dry run: %d of %d rows valid (%d to create, %d to update), %d rows with errors",
+			report.Created+report.Updated, report.TotalRows, report.Created, report.Updated, report.Failed)
+	}
+	summary := fmt.Sprintf("Import finished: %d created, %d updated, %d rows with errors", report.Created, report.Updated, report.Failed)
+	if report.RolledBack > 0 {
+		summary += fmt.Sprintf(", %d valid rows not applied", report.RolledBack)
+	}
+	return summary
+}
+
+// importErrorMessage merapikan error dari repository untuk laporan baris
+func importErrorMessage(err error) string {
+	if errors.Is(err, sql.ErrNoRows) {
+		return "product not found"
+	}
+	return err.Error()
+}
+
+// categoryIndex mencocokkan isi kolom kategori dengan id, path lengkap ("Electronics > Computers")
+// atau nama kategori jika nama tersebut hanya dipakai satu kategori
+type categoryIndex struct {
+	ids   map[uint]bool
+	paths map[string]uint
+	names map[string][]uint
+}
+
+func newCategoryIndex(categories []models.Category) *categoryIndex {
+	index := &categoryIndex{
+		ids:   make(map[uint]bool, len(categories)),
+		paths: make(map[string]uint, len(categories)),
+		names: make(map[string][]uint, len(categories)),
+	}
+	for _, category := range categories {
+		index.ids[category.ID] = true
+		index.paths[categoryPathKey(category.Path)] = category.ID
+		name := strings.ToLower(strings.TrimSpace(category.Name))
+		index.names[name] = append(index.names[name], category.ID)
+	}
+	return index
+}
+
+func (c *categoryIndex) resolve(value string) (uint, error) {
+	if id, err := strconv.ParseUint(value, 10, 32); err == nil {
+		if !c.ids[uint(id)] {
+			return 0, fmt.Errorf("invalid category, category %d not found", id)
+		}
+		return uint(id), nil
+	}
+	if id, ok := c.paths[categoryPathKey(value)]; ok {
+		return id, nil
+	}
+
+	switch ids := c.names[strings.ToLower(value)]; len(ids) {
+	case 0:
+		return 0, fmt.Errorf("invalid category, %s not found", value)
+	case 1:
+		return ids[0], nil
+	default:
+		return 0, fmt.Errorf("invalid category, %s matches %d categories, use the full path", value, len(ids))
+	}
+}
+
+// categoryPathKey menyamakan penulisan path: huruf kecil dan spasi di sekitar ">" diabaikan
+func categoryPathKey(path string) string {
+	parts := strings.Split(strings.ToLower(path), ">")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return strings.Join(parts, " > ")
+}
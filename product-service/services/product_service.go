@@ -3,48 +3,89 @@ package services
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"product-service/barcode"
 	"product-service/dto"
 	"product-service/models"
+	"product-service/quantity"
 	"product-service/repositories"
+	"regexp"
+	"strings"
 )
 
+var taxClassPattern = regexp.MustCompile(`^[A-Z0-9_]{1,30}$`)
+
 type ProductService interface {
 	CreateProduct(req *dto.CreateProductRequest) (*dto.ProductResponse, error)
-	GetAllProducts(page, limit int, search, sortBy, order string) ([]dto.ProductResponse, int, error)
+	// GetAllProducts dengan categoryID > 0 termasuk produk di semua subkategorinya
+	GetAllProducts(page, limit int, search, sortBy, order string, categoryID uint) ([]dto.ProductResponse, int, error)
 	GetProductByID(id uint) (*dto.ProductResponse, error)
 	UpdateProduct(id uint, req *dto.UpdateProductRequest) (*dto.ProductResponse, error)
 	DeleteProduct(id uint) error
-	UpdateStock(id uint, newStock int) error
+	UpdateStock(id uint, newStock quantity.Quantity) error
+	// LookupProduct mencari produk lewat hasil scan barcode atau SKU, salah satu saja yang diisi
+	LookupProduct(code, sku string) (*dto.ProductLookupResponse, error)
+	GetVariants(productID uint) ([]dto.VariantResponse, error)
+	UpdateVariant(productID, variantID uint, req *dto.UpdateVariantRequest) (*dto.VariantResponse, error)
 }
 
 
 type productService struct {
 	repo repositories.ProductRepository
+	// format label timbangan, barcode yang tidak terdaftar dicoba sebagai label
+	scaleFormats barcode.ScaleFormats
 }
 
-func NewProductService(repo repositories.ProductRepository) ProductService {
+func NewProductService(repo repositories.ProductRepository, scaleFormats barcode.ScaleFormats) ProductService {
 	return &productService{
-		repo: repo,
+		repo:         repo,
+		scaleFormats: scaleFormats,
 	}
 }
 
 func (s *productService) CreateProduct(req *dto.CreateProductRequest) (*dto.ProductResponse, error) {
+	taxClass, err := normalizeTaxClass(req.TaxClass)
+	if err != nil {
+		return nil, err
+	}
+	options, err := optionsToModel(req.Options)
+	if err != nil {
+		return nil, err
+	}
+	// stok produk yang punya varian adalah jumlah stok varian, diisi lewat update varian
+	if len(options) > 0 && req.Stock != 0 {
+		return nil, errors.New("invalid stock, products with options start at 0, set the stock of each variant")
+	}
+
 	product := &models.Product{
-		Name:  req.Name,
-		Price: req.Price,
-		Stock: req.Stock,
+		Name:     req.Name,
+		SKU:      req.SKU,
+		Barcodes: barcodesToModel(req.Barcodes),
+		Price:    req.Price,
+		Stock:    req.Stock,
+		TaxClass: taxClass,
+		Options:  options,
+	}
+	if req.CategoryID > 0 {
+		categoryID := req.CategoryID
+		product.CategoryID = &categoryID
 	}
 
-	err := s.repo.Create(product)
+	err = s.repo.Create(product)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.modelToResponse(product), nil
+	// ambil ulang agar varian yang dibuat dari opsi ikut di response
+	createdProduct, err := s.repo.GetByID(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.modelToResponse(createdProduct), nil
 }
 
-func (s *productService) GetAllProducts(page, limit int, search, sortBy, order string) ([]dto.ProductResponse, int, error) {
-	products, total, err := s.repo.GetAll(page, limit, search, sortBy, order)
+func (s *productService) GetAllProducts(page, limit int, search, sortBy, order string, categoryID uint) ([]dto.ProductResponse, int, error) {
+	products, total, err := s.repo.GetAll(page, limit, search, sortBy, order, categoryID)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -82,9 +123,14 @@ func (s *productService) UpdateProduct(id uint, req *dto.UpdateProductRequest) (
 
 	// Update kolom yang diubah saja
 	updateData := &models.Product{
-		Name:  existingProduct.Name,
-		Price: existingProduct.Price,
-		Stock: existingProduct.Stock,
+		Name:       existingProduct.Name,
+		SKU:        existingProduct.SKU,
+		Barcodes:   existingProduct.Barcodes,
+		Price:      existingProduct.Price,
+		Stock:      existingProduct.Stock,
+		TaxClass:   existingProduct.TaxClass,
+		CategoryID: existingProduct.CategoryID,
+		Options:    existingProduct.Options,
 	}
 
 	if req.Name != "" {
@@ -96,6 +142,29 @@ func (s *productService) UpdateProduct(id uint, req *dto.UpdateProductRequest) (
 	if req.Stock >= 0 {
 		updateData.Stock = req.Stock
 	}
+	if req.TaxClass != "" {
+		taxClass, err := normalizeTaxClass(req.TaxClass)
+		if err != nil {
+			return nil, err
+		}
+		updateData.TaxClass = taxClass
+	}
+	if req.SKU != nil {
+		updateData.SKU = *req.SKU
+	}
+	if req.Barcodes != nil {
+		updateData.Barcodes = barcodesToModel(*req.Barcodes)
+	}
+	if req.CategoryID != nil {
+		updateData.CategoryID = nilIfZero(req.CategoryID)
+	}
+	if req.Options != nil {
+		options, err := optionsToModel(*req.Options)
+		if err != nil {
+			return nil, err
+		}
+		updateData.Options = options
+	}
 
 	err = s.repo.Update(id, updateData)
 	if err != nil {
@@ -119,7 +188,7 @@ func (s *productService) DeleteProduct(id uint) error {
 	return s.repo.Delete(id)
 }
 
-func (s *productService) UpdateStock(id uint, newStock int) error {
+func (s *productService) UpdateStock(id uint, newStock quantity.Quantity) error {
 	_, err := s.repo.GetByID(id)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -131,13 +200,157 @@ func (s *productService) UpdateStock(id uint, newStock int) error {
 	return s.repo.UpdateStock(id, newStock)
 }
 
+func (s *productService) LookupProduct(code, sku string) (*dto.ProductLookupResponse, error) {
+	var product *models.Product
+	var label *barcode.ScaleLabel
+	// kode yang dicari, untuk menentukan varian pemilik barcode
+	var codes []string
+	var err error
+	if code != "" {
+		// barcode terdaftar didahulukan, baru dicoba sebagai label timbangan
+		codes = barcode.LookupCandidates(code)
+		product, err = s.repo.GetByBarcode(codes)
+		if err == sql.ErrNoRows {
+			if parsed, ok := s.scaleFormats.Parse(code); ok {
+				label = parsed
+				codes = []string{label.ItemCode}
+				product, err = s.repo.GetByPLU(label.ItemCode)
+			}
+		}
+	} else {
+		sku = strings.ToUpper(strings.TrimSpace(sku))
+		product, err = s.repo.GetBySKU(sku)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+
+	var variant *models.ProductVariant
+	if codes != nil {
+		variant = variantWithBarcode(product, codes)
+	} else if sku != product.SKU {
+		variant = findVariant(product, func(v *models.ProductVariant) bool { return v.SKU == sku })
+	}
+
+	response := &dto.ProductLookupResponse{ProductResponse: *s.modelToResponse(product)}
+	priced := product
+	if variant != nil {
+		response.Variant = variantToResponse(variant)
+		// label timbangan varian memakai harga varian
+		variantProduct := *product
+		variantProduct.Price = variant.Price
+		priced = &variantProduct
+	}
+	if label != nil {
+		scale, err := scaleLabelToResponse(label, priced)
+		if err != nil {
+			return nil, err
+		}
+		response.Scale = scale
+	}
+	return response, nil
+}
+
+func (s *productService) GetVariants(productID uint) ([]dto.VariantResponse, error) {
+	product, err := s.repo.GetByID(productID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+
+	variants := make([]dto.VariantResponse, 0, len(product.Variants))
+	for i := range product.Variants {
+		variants = append(variants, *variantToResponse(&product.Variants[i]))
+	}
+	return variants, nil
+}
+
 func (s *productService) modelToResponse(product *models.Product) *dto.ProductResponse {
+	barcodes := make([]dto.BarcodeResponse, 0, len(product.Barcodes))
+	for _, b := range product.Barcodes {
+		barcodes = append(barcodes, dto.BarcodeResponse{Code: b.Code, Type: b.Type})
+	}
+
+	var options []dto.OptionResponse
+	for _, option := range product.Options {
+		options = append(options, dto.OptionResponse{Name: option.Name, Values: option.Values})
+	}
+	var variants []dto.VariantResponse
+	for i := range product.Variants {
+		variants = append(variants, *variantToResponse(&product.Variants[i]))
+	}
+
 	return &dto.ProductResponse{
-		ID:        product.ID,
-		Name:      product.Name,
-		Price:     product.Price,
-		Stock:     product.Stock,
-		CreatedAt: product.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt: product.UpdatedAt.Format("2006-01-02 15:04:05"),
+		ID:             product.ID,
+		Name:           product.Name,
+		SKU:            product.SKU,
+		Barcodes:       barcodes,
+		Price:          product.Price,
+		Stock:          product.Stock,
+		ReservedStock:  product.ReservedStock,
+		AvailableStock: product.AvailableStock(),
+		TaxClass:       product.TaxClass,
+		CategoryID:     product.CategoryID,
+		Options:        options,
+		Variants:       variants,
+		CreatedAt:      product.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:      product.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// normalizeTaxClass mengubah kelas pajak ke huruf besar, kosong berarti STANDARD
+func normalizeTaxClass(taxClass string) (string, error) {
+	taxClass = strings.ToUpper(strings.TrimSpace(taxClass))
+	if taxClass == "" {
+		return models.DefaultTaxClass, nil
+	}
+	if !taxClassPattern.MatchString(taxClass) {
+		return "", errors.New("invalid tax_class, use letters, digits and underscores only")
+	}
+	return taxClass, nil
+}
+
+// barcodesToModel menyalin barcode yang sudah dinormalisasi handler, urutan dipertahankan
+func barcodesToModel(barcodes []dto.BarcodeRequest) []models.ProductBarcode {
+	result := make([]models.ProductBarcode, len(barcodes))
+	for i, b := range barcodes {
+		result[i] = models.ProductBarcode{Code: b.Code, Type: b.Type}
+	}
+	return result
+}
+
+// scaleLabelToResponse menghitung jumlah dan harga baris dari label: label berat memakai harga
+// satuan produk, label harga memakai harga di label dan jumlahnya dihitung balik dari harga satuan
+func scaleLabelToResponse(label *barcode.ScaleLabel, product *models.Product) (*dto.ScaleLabelResponse, error) {
+	response := &dto.ScaleLabelResponse{
+		Barcode:  label.Code,
+		Prefix:   label.Prefix,
+		ItemCode: label.ItemCode,
+		Kind:     label.Kind,
+	}
+
+	if label.Kind == barcode.ScaleWeight {
+		weight := label.Weight
+		response.Weight = &weight
+		response.Quantity = weight
+		response.Amount = weight.Amount(product.Price)
+		return response, nil
+	}
+
+	if product.Price <= 0 {
+		return nil, fmt.Errorf("invalid scale label, product %d has no unit price", product.ID)
+	}
+	price := label.Price
+	response.Price = &price
+	response.Quantity = quantity.ForAmount(price, product.Price)
+	response.Amount = price
+	if response.Quantity <= 0 {
+		return nil, fmt.Errorf("invalid scale label, price %s is too low for product %d", price, product.ID)
 	}
+	return response, nil
 }
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"product-service/barcode"
+	"product-service/dto"
+	"strings"
+)
+
+// ValidateCreateProduct menjalankan aturan produk baru yang tidak butuh database, dipakai POST /api/products
+// dan import produk. SKU dan barcode dinormalisasi di tempat.
+func ValidateCreateProduct(req *dto.CreateProductRequest) error {
+	if req.Name == "" {
+		return errors.New("Product name is required")
+	}
+	if req.Price <= 0 {
+		return errors.New("Price must be greater than 0")
+	}
+	if req.Stock < 0 {
+		return errors.New("Stock cannot be negative")
+	}
+
+	// SKU dan barcode dinormalisasi dan check digit-nya divalidasi sebelum disimpan
+	return NormalizeIdentifiers(&req.SKU, req.Barcodes)
+}
+
+// NormalizeIdentifiers menormalisasi SKU (jika diisi) dan barcode di tempat. Barcode yang sama
+// tidak boleh muncul dua kali dalam satu produk.
+func NormalizeIdentifiers(sku *string, barcodes []dto.BarcodeRequest) error {
+	if sku != nil && strings.TrimSpace(*sku) != "" {
+		normalized, err := barcode.NormalizeSKU(*sku)
+		if err != nil {
+			return err
+		}
+		*sku = normalized
+	} else if sku != nil {
+		*sku = ""
+	}
+
+	seen := make(map[string]bool, len(barcodes))
+	for i := range barcodes {
+		code, codeType, err := barcode.Normalize(barcodes[i].Code, barcodes[i].Type)
+		if err != nil {
+			return err
+		}
+		if seen[code] {
+			return fmt.Errorf("invalid barcodes, %s is listed more than once", code)
+		}
+		seen[code] = true
+		barcodes[i].Code = code
+		barcodes[i].Type = codeType
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"product-service/dto"
+	"product-service/models"
+	"strings"
+)
+
+// batas matriks varian per produk
+const (
+	maxOptions      = 3
+	maxOptionValues = 20
+	maxVariants     = 100
+)
+
+func (s *productService) UpdateVariant(productID, variantID uint, req *dto.UpdateVariantRequest) (*dto.VariantResponse, error) {
+	product, err := s.repo.GetByID(productID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("product not found")
+		}
+		return nil, err
+	}
+	existing := findVariant(product, func(v *models.ProductVariant) bool { return v.ID == variantID })
+	if existing == nil {
+		return nil, errors.New("variant not found")
+	}
+
+	// update kolom yang diubah saja; stok ditulis repository dari baris yang dikunci, hanya jika diminta
+	variant := *existing
+	if req.SKU != nil {
+		variant.SKU = *req.SKU
+	}
+	if req.Price > 0 {
+		variant.Price = req.Price
+	}
+	if req.Barcodes != nil {
+		variant.Barcodes = barcodesToModel(*req.Barcodes)
+	}
+
+	if err := s.repo.UpdateVariant(productID, &variant, req.Stock); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("variant not found")
+		}
+		return nil, err
+	}
+
+	updatedProduct, err := s.repo.GetByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	updated := findVariant(updatedProduct, func(v *models.ProductVariant) bool { return v.ID == variantID })
+	if updated == nil {
+		return nil, errors.New("variant not found")
+	}
+	return variantToResponse(updated), nil
+}
+
+// optionsToModel merapikan sumbu varian: nama dan nilai di-trim, nama sumbu dan nilai dalam satu sumbu
+// tidak boleh kembar (tanpa membedakan huruf besar), dan jumlah kombinasinya dibatasi maxVariants
+func optionsToModel(options []dto.OptionRequest) ([]models.ProductOption, error) {
+	if len(options) > maxOptions {
+		return nil, fmt.Errorf("invalid options, at most %d options per product", maxOptions)
+	}
+
+	result := make([]models.ProductOption, 0, len(options))
+	names := make(map[string]bool, len(options))
+	variants := 1
+	for _, option := range options {
+		name := strings.TrimSpace(option.Name)
+		if name == "" {
+			return nil, errors.New("invalid options, option name is required")
+		}
+		if names[strings.ToLower(name)] {
+			return nil, fmt.Errorf("invalid options, option %s is listed more than once", name)
+		}
+		names[strings.ToLower(name)] = true
+
+		if len(option.Values) == 0 || len(option.Values) > maxOptionValues {
+			return nil, fmt.Errorf("invalid options, option %s must have 1 to %d values", name, maxOptionValues)
+		}
+		values := make([]string, 0, len(option.Values))
+		seen := make(map[string]bool, len(option.Values))
+		for _, value := range option.Values {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return nil, fmt.Errorf("invalid options, option %s has an empty value", name)
+			}
+			if seen[strings.ToLower(value)] {
+				return nil, fmt.Errorf("invalid options, value %s of option %s is listed more than once", value, name)
+			}
+			seen[strings.ToLower(value)] = true
+			values = append(values, value)
+		}
+
+		variants *= len(values)
+		result = append(result, models.ProductOption{Name: name, Values: values})
+	}
+	if variants > maxVariants {
+		return nil, fmt.Errorf("invalid options, %d combinations exceed the limit of %d variants per product", variants, maxVariants)
+	}
+	return result, nil
+}
+
+// findVariant mengembalikan varian pertama yang cocok, nil jika tidak ada
+func findVariant(product *models.Product, match func(*models.ProductVariant) bool) *models.ProductVariant {
+	for i := range product.Variants {
+		if match(&product.Variants[i]) {
+			return &product.Variants[i]
+		}
+	}
+	return nil
+}
+
+// variantWithBarcode mencari varian pemilik salah satu kode hasil scan (lihat barcode.LookupCandidates)
+func variantWithBarcode(product *models.Product, codes []string) *models.ProductVariant {
+	return findVariant(product, func(v *models.ProductVariant) bool {
+		for _, b := range v.Barcodes {
+			for _, code := range codes {
+				if b.Code == code {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+func variantToResponse(variant *models.ProductVariant) *dto.VariantResponse {
+	barcodes := make([]dto.BarcodeResponse, 0, len(variant.Barcodes))
+	for _, b := range variant.Barcodes {
+		barcodes = append(barcodes, dto.BarcodeResponse{Code: b.Code, Type: b.Type})
+	}
+
+	return &dto.VariantResponse{
+		ID:             variant.ID,
+		SKU:            variant.SKU,
+		OptionValues:   variant.OptionValues,
+		Title:          variant.Title(),
+		Barcodes:       barcodes,
+		Price:          variant.Price,
+		Stock:          variant.Stock,
+		ReservedStock:  variant.ReservedStock,
+		AvailableStock: variant.AvailableStock(),
+		CreatedAt:      variant.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:      variant.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// stockLine adalah kunci baris reservasi/restock; variantID 0 berarti produk tanpa varian
+type stockLine struct {
+	productID uint
+	variantID uint
+}
+
+func stockLineOf(productID uint, variantID *uint) stockLine {
+	if variantID == nil {
+		return stockLine{productID: productID}
+	}
+	return stockLine{productID, *variantID}
+}
+
+func (l stockLine) variant() *uint {
+	if l.variantID == 0 {
+		return nil
+	}
+	id := l.variantID
+	return &id
+}
+
+// less mengurutkan per product_id lalu variant_id, urutan yang sama dengan urutan lock di repository
+func (l stockLine) less(other stockLine) bool {
+	if l.productID != other.productID {
+		return l.productID < other.productID
+	}
+	return l.variantID < other.variantID
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"product-service/dto"
+	"product-service/models"
+	"product-service/quantity"
+	"product-service/repositories"
+	"sort"
+	"time"
+)
+
+type ReservationService interface {
+	CreateReservation(req *dto.CreateReservationRequest) (*dto.ReservationResponse, error)
+	GetReservation(id uint) (*dto.ReservationResponse, error)
+	// ConfirmReservation dan ReleaseReservation yang ditolak karena status reservasi tetap mengembalikan
+	// reservasinya bersama error, agar pemanggil bisa membaca statusnya
+	ConfirmReservation(id uint) (*dto.ReservationResponse, error)
+	ReleaseReservation(id uint) (*dto.ReservationResponse, error)
+	// StartSweeper menjalankan goroutine yang menandai reservasi kedaluwarsa setiap interval
+	StartSweeper(interval time.Duration)
+}
+
+type reservationService struct {
+	repo repositories.ReservationRepository
+	// masa tahan default dan batas maksimum ttl_seconds
+	ttl    time.Duration
+	maxTTL time.Duration
+}
+
+func NewReservationService(repo repositories.ReservationRepository, ttl, maxTTL time.Duration) ReservationService {
+	return &reservationService{
+		repo:   repo,
+		ttl:    ttl,
+		maxTTL: maxTTL,
+	}
+}
+
+func (s *reservationService) CreateReservation(req *dto.CreateReservationRequest) (*dto.ReservationResponse, error) {
+	ttl := s.ttl
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl > s.maxTTL {
+		return nil, fmt.Errorf("invalid ttl_seconds, maximum is %d", int(s.maxTTL.Seconds()))
+	}
+
+	// produk dan varian yang sama digabung, urut product_id lalu variant_id agar urutan lock konsisten
+	quantities := make(map[stockLine]quantity.Quantity)
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, errors.New("invalid quantity, must be greater than 0")
+		}
+		quantities[stockLine{item.ProductID, item.VariantID}] += item.Quantity
+	}
+	items := make([]models.ReservationItem, 0, len(quantities))
+	for line, quantity := range quantities {
+		items = append(items, models.ReservationItem{ProductID: line.productID, VariantID: line.variant(), Quantity: quantity})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return stockLineOf(items[i].ProductID, items[i].VariantID).less(stockLineOf(items[j].ProductID, items[j].VariantID))
+	})
+
+	now := time.Now()
+	reservation := &models.Reservation{
+		Reference: req.Reference,
+		Items:     items,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := s.repo.Create(reservation, now); err != nil {
+		return nil, err
+	}
+
+	return reservationToResponse(reservation), nil
+}
+
+func (s *reservationService) GetReservation(id uint) (*dto.ReservationResponse, error) {
+	reservation, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, reservationNotFound(err)
+	}
+	return reservationToResponse(reservation), nil
+}
+
+func (s *reservationService) ConfirmReservation(id uint) (*dto.ReservationResponse, error) {
+	reservation, err := s.repo.Confirm(id, time.Now())
+	if err != nil {
+		return reservationState(reservation), reservationNotFound(err)
+	}
+	return reservationToResponse(reservation), nil
+}
+
+func (s *reservationService) ReleaseReservation(id uint) (*dto.ReservationResponse, error) {
+	reservation, err := s.repo.Release(id, time.Now())
+	if err != nil {
+		return reservationState(reservation), reservationNotFound(err)
+	}
+	return reservationToResponse(reservation), nil
+}
+
+func (s *reservationService) StartSweeper(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			expired, err := s.repo.ExpireOverdue(time.Now())
+			if err != nil {
+				log.Printf("reservation sweeper: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("reservation sweeper: %d reservation(s) expired", expired)
+			}
+		}
+	}()
+}
+
+func reservationNotFound(err error) error {
+	if err == sql.ErrNoRows {
+		return errors.New("reservation not found")
+	}
+	return err
+}
+
+// reservationState mengembalikan reservasi yang menyertai penolakan, nil jika tidak ada
+func reservationState(reservation *models.Reservation) *dto.ReservationResponse {
+	if reservation == nil {
+		return nil
+	}
+	return reservationToResponse(reservation)
+}
+
+func reservationToResponse(reservation *models.Reservation) *dto.ReservationResponse {
+	response := &dto.ReservationResponse{
+		ID:        reservation.ID,
+		Reference: reservation.Reference,
+		Status:    reservation.Status,
+		Items:     make([]dto.ReservationItemResponse, 0, len(reservation.Items)),
+		ExpiresAt: reservation.ExpiresAt.Format("2006-01-02 15:04:05"),
+		CreatedAt: reservation.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	for _, item := range reservation.Items {
+		response.Items = append(response.Items, dto.ReservationItemResponse{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		})
+	}
+	if reservation.ConfirmedAt != nil {
+		response.ConfirmedAt = reservation.ConfirmedAt.Format("2006-01-02 15:04:05")
+	}
+	if reservation.ReleasedAt != nil {
+		response.ReleasedAt = reservation.ReleasedAt.Format("2006-01-02 15:04:05")
+	}
+	return response
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"product-service/dto"
+	"product-service/models"
+	"product-service/quantity"
+	"product-service/repositories"
+	"sort"
+	"strings"
+	"time"
+)
+
+type RestockService interface {
+	CreateRestock(req *dto.CreateRestockRequest) (*dto.RestockResponse, error)
+	GetRestock(id uint) (*dto.RestockResponse, error)
+	CancelRestock(id uint) (*dto.RestockResponse, error)
+}
+
+type restockService struct {
+	repo repositories.RestockRepository
+}
+
+func NewRestockService(repo repositories.RestockRepository) RestockService {
+	return &restockService{
+		repo: repo,
+	}
+}
+
+func (s *restockService) CreateRestock(req *dto.CreateRestockRequest) (*dto.RestockResponse, error) {
+	reference := strings.TrimSpace(req.Reference)
+	if reference == "" {
+		return nil, errors.New("invalid reference, must not be empty")
+	}
+
+	quantities := make(map[stockLine]quantity.Quantity)
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, errors.New("invalid quantity, must be greater than 0")
+		}
+		quantities[stockLine{item.ProductID, item.VariantID}] += item.Quantity
+	}
+	items := make([]models.RestockItem, 0, len(quantities))
+	for line, quantity := range quantities {
+		items = append(items, models.RestockItem{ProductID: line.productID, VariantID: line.variant(), Quantity: quantity})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return stockLineOf(items[i].ProductID, items[i].VariantID).less(stockLineOf(items[j].ProductID, items[j].VariantID))
+	})
+
+	restock := &models.Restock{
+		Reference: reference,
+		Reason:    req.Reason,
+		Items:     items,
+	}
+	applied, err := s.repo.Create(restock, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := restockToResponse(restock)
+	response.Applied = applied
+	return response, nil
+}
+
+func (s *restockService) GetRestock(id uint) (*dto.RestockResponse, error) {
+	restock, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, restockNotFound(err)
+	}
+	return restockToResponse(restock), nil
+}
+
+func (s *restockService) CancelRestock(id uint) (*dto.RestockResponse, error) {
+	restock, err := s.repo.Cancel(id, time.Now())
+	if err != nil {
+		return nil, restockNotFound(err)
+	}
+	return restockToResponse(restock), nil
+}
+
+func restockNotFound(err error) error {
+	if err == sql.ErrNoRows {
+		return errors.New("restock not found")
+	}
+	return err
+}
+
+func restockToResponse(restock *models.Restock) *dto.RestockResponse {
+	response := &dto.RestockResponse{
+		ID:        restock.ID,
+		Reference: restock.Reference,
+		Reason:    restock.Reason,
+		Status:    restock.Status,
+		Items:     make([]dto.RestockItemResponse, 0, len(restock.Items)),
+		CreatedAt: restock.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	for _, item := range restock.Items {
+		response.Items = append(response.Items, dto.RestockItemResponse{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		})
+	}
+	if restock.CancelledAt != nil {
+		response.CancelledAt = restock.CancelledAt.Format("2006-01-02 15:04:05")
+	}
+	return response
+}
@@ -1,18 +1,70 @@
 package clients
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"time"
+	"transaction-service/money"
+	"transaction-service/quantity"
 )
 
 type ProductResponse struct {
-	ID    uint    `json:"id"`
-	Name  string  `json:"name"`
-	Price float64 `json:"price"`
-	Stock int     `json:"stock"`
+	ID             uint              `json:"id"`
+	Name           string            `json:"name"`
+	SKU            string            `json:"sku,omitempty"`
+	Price          money.Money       `json:"price"`
+	Stock          quantity.Quantity `json:"stock"`
+	ReservedStock  quantity.Quantity `json:"reserved_stock"`
+	AvailableStock quantity.Quantity `json:"available_stock"`
+	TaxClass       string            `json:"tax_class"`
+	CategoryID     *uint             `json:"category_id,omitempty"`
+	// matriks varian; produk yang punya varian dijual per varian
+	Variants []VariantResponse `json:"variants,omitempty"`
+	// hanya diisi Lookup jika barcode atau SKU milik salah satu varian
+	Variant *VariantResponse `json:"variant,omitempty"`
+	// hanya diisi Lookup jika barcode adalah label timbangan
+	Scale *ScaleLabel `json:"scale,omitempty"`
+}
+
+// VariantResponse adalah satu varian produk, mis. "M / Black" dengan SKU, harga dan stok sendiri
+type VariantResponse struct {
+	ID             uint              `json:"id"`
+	SKU            string            `json:"sku,omitempty"`
+	Title          string            `json:"title"`
+	Price          money.Money       `json:"price"`
+	Stock          quantity.Quantity `json:"stock"`
+	AvailableStock quantity.Quantity `json:"available_stock"`
+}
+
+// FindVariant mengembalikan varian produk dengan id tersebut, nil jika tidak ada
+func (p *ProductResponse) FindVariant(id uint) *VariantResponse {
+	for i := range p.Variants {
+		if p.Variants[i].ID == id {
+			return &p.Variants[i]
+		}
+	}
+	return nil
+}
+
+// ScaleLabel adalah hasil lookup label timbangan: quantity (berat) dan harga baris dari label
+type ScaleLabel struct {
+	Barcode  string            `json:"barcode"`
+	Kind     string            `json:"kind"`
+	Quantity quantity.Quantity `json:"quantity"`
+	Amount   money.Money       `json:"amount"`
+}
+
+// CategoryResponse adalah kategori produk dari GET /api/categories?flat=true
+type CategoryResponse struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	ParentID  *uint  `json:"parent_id,omitempty"`
+	SortOrder int    `json:"sort_order"`
 }
 
 type ApiResponse struct {
@@ -21,10 +73,56 @@ type ApiResponse struct {
 	Data    ProductResponse `json:"data"`
 }
 
+// StockItem adalah jumlah per produk (atau per varian) untuk reservasi dan restock
+type StockItem struct {
+	ProductID uint              `json:"product_id"`
+	VariantID uint              `json:"variant_id,omitempty"` // wajib untuk produk yang punya varian
+	Quantity  quantity.Quantity `json:"quantity"`
+}
+
+// status reservasi stok di product-service
+const (
+	ReservationActive    = "ACTIVE"
+	ReservationConfirmed = "CONFIRMED"
+	ReservationReleased  = "RELEASED"
+	ReservationExpired   = "EXPIRED"
+)
+
+type ReservationResponse struct {
+	ID        uint   `json:"id"`
+	Reference string `json:"reference,omitempty"`
+	Status    string `json:"status"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type RestockResponse struct {
+	ID        uint   `json:"id"`
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+	Applied   bool   `json:"applied"` // false jika reference sudah pernah diterapkan
+}
+
 type ProductClient interface {
 	GetByID(id uint) (*ProductResponse, error)
 	GetMultiple(ids []uint) (map[uint]*ProductResponse, error)
 	GetByIDWithFallback(id uint) (*ProductResponse, bool) // Returns product and exists flag
+	// Lookup mencari produk lewat barcode hasil scan atau SKU, salah satu saja yang diisi
+	Lookup(barcode, sku string) (*ProductResponse, error)
+	// ListAll mengambil semua produk aktif halaman demi halaman, dipakai untuk sinkronisasi product_replicas
+	ListAll() ([]ProductResponse, error)
+	// ListCategories mengambil semua kategori aktif, dipakai untuk sinkronisasi category_replicas
+	ListCategories() ([]CategoryResponse, error)
+
+	// stok hanya diubah lewat API product-service: reservasi untuk keranjang dan checkout, restock saat void/retur.
+	// Reservasi ACTIVE lain dengan reference yang sama diganti; ttl 0 memakai masa tahan default product-service.
+	Reserve(reference string, items []StockItem, ttl time.Duration) (*ReservationResponse, error)
+	// ConfirmReservation dan ReleaseReservation mengembalikan reservasi dengan status terkininya setiap kali
+	// product-service menjawab, juga bersama error jika permintaannya ditolak karena status reservasi (mis. release
+	// untuk reservasi CONFIRMED). Reservasi nil dengan error berarti hasilnya tidak diketahui (timeout, 5xx).
+	ConfirmReservation(id uint) (*ReservationResponse, error)
+	ReleaseReservation(id uint) (*ReservationResponse, error)
+	Restock(reference, reason string, items []StockItem) (*RestockResponse, error)
+	CancelRestock(id uint) error
 }
 
 type productClient struct {
@@ -70,6 +168,42 @@ func (c *productClient) GetByID(id uint) (*ProductResponse, error) {
 	return &apiResp.Data, nil
 }
 
+func (c *productClient) Lookup(barcode, sku string) (*ProductResponse, error) {
+	query := url.Values{}
+	identifier := "barcode " + barcode
+	if barcode != "" {
+		query.Set("barcode", barcode)
+	} else {
+		query.Set("sku", sku)
+		identifier = "SKU " + sku
+	}
+
+	resp, err := c.client.Get(c.baseURL + "/api/products/lookup?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to call product service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("product with %s not found", identifier)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("product service returned status %d", resp.StatusCode)
+	}
+
+	var apiResp ApiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("product service error: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
 func (c *productClient) GetByIDWithFallback(id uint) (*ProductResponse, bool) {
 	product, err := c.GetByID(id)
 	if err != nil {
@@ -100,3 +234,154 @@ func (c *productClient) GetMultiple(ids []uint) (map[uint]*ProductResponse, erro
 
 	return products, nil
 }
+
+func (c *productClient) ListAll() ([]ProductResponse, error) {
+	const pageSize = 100
+	products := make([]ProductResponse, 0)
+
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/api/products?page=%d&limit=%d&sortBy=id&order=asc", c.baseURL, page, pageSize)
+
+		resp, err := c.client.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call product service: %w", err)
+		}
+
+		var apiResp struct {
+			Success bool   `json:"success"`
+			Message string `json:"message"`
+			Data    struct {
+				Items []ProductResponse `json:"items"`
+				Total int               `json:"total"`
+			} `json:"data"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&apiResp)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("product service returned status %d", resp.StatusCode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		if !apiResp.Success {
+			return nil, fmt.Errorf("product service error: %s", apiResp.Message)
+		}
+
+		products = append(products, apiResp.Data.Items...)
+		if len(apiResp.Data.Items) < pageSize || len(products) >= apiResp.Data.Total {
+			return products, nil
+		}
+	}
+}
+
+func (c *productClient) ListCategories() ([]CategoryResponse, error) {
+	resp, err := c.client.Get(c.baseURL + "/api/categories?flat=true")
+	if err != nil {
+		return nil, fmt.Errorf("failed to call product service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("product service returned status %d", resp.StatusCode)
+	}
+
+	var apiResp struct {
+		Success bool               `json:"success"`
+		Message string             `json:"message"`
+		Data    []CategoryResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !apiResp.Success {
+		return nil, fmt.Errorf("product service error: %s", apiResp.Message)
+	}
+
+	return apiResp.Data, nil
+}
+
+func (c *productClient) Reserve(reference string, items []StockItem, ttl time.Duration) (*ReservationResponse, error) {
+	var reservation ReservationResponse
+	body := map[string]interface{}{"reference": reference, "items": items, "ttl_seconds": int(ttl.Seconds())}
+	if err := c.post("/api/reservations", body, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (c *productClient) ConfirmReservation(id uint) (*ReservationResponse, error) {
+	return c.reservationAction(fmt.Sprintf("/api/reservations/%d/confirm", id))
+}
+
+func (c *productClient) ReleaseReservation(id uint) (*ReservationResponse, error) {
+	return c.reservationAction(fmt.Sprintf("/api/reservations/%d/release", id))
+}
+
+// reservationAction menjalankan confirm/release; penolakan product-service menyertakan reservasinya di data
+func (c *productClient) reservationAction(path string) (*ReservationResponse, error) {
+	var reservation ReservationResponse
+	err := c.post(path, nil, &reservation)
+	if reservation.Status == "" {
+		if err == nil {
+			return nil, errors.New("product service returned a reservation without status")
+		}
+		return nil, err
+	}
+	return &reservation, err
+}
+
+func (c *productClient) Restock(reference, reason string, items []StockItem) (*RestockResponse, error) {
+	var restock RestockResponse
+	body := map[string]interface{}{"reference": reference, "reason": reason, "items": items}
+	if err := c.post("/api/stock/restocks", body, &restock); err != nil {
+		return nil, err
+	}
+	return &restock, nil
+}
+
+func (c *productClient) CancelRestock(id uint) error {
+	return c.post(fmt.Sprintf("/api/stock/restocks/%d/cancel", id), nil, nil)
+}
+
+// post mengirim request JSON ke product-service dan men-decode field data ke out, juga untuk jawaban gagal
+// yang menyertakan data. Pesan error dari product-service diteruskan apa adanya, mis. "insufficient stock for
+// product 3: available 1, requested 2".
+func (c *productClient) post(path string, body interface{}, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	resp, err := c.client.Post(c.baseURL+path, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to call product service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var apiResp struct {
+		Success bool            `json:"success"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("product service returned status %d", resp.StatusCode)
+	}
+
+	failed := resp.StatusCode >= 300 || !apiResp.Success
+	if out != nil && len(apiResp.Data) > 0 && string(apiResp.Data) != "null" {
+		if err := json.Unmarshal(apiResp.Data, out); err != nil && !failed {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	if failed {
+		if apiResp.Message != "" {
+			return errors.New(apiResp.Message)
+		}
+		return fmt.Errorf("product service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"transaction-service/migrations"
+)
+
+const commandUsage = `usage: main [migrate [up | down [steps] | status] | seed]`
+
+// runCommand menjalankan perintah CLI selain menjalankan server, mis. "./main migrate down 1" atau "./main seed"
+func runCommand(db *sql.DB, args []string) error {
+	switch args[0] {
+	case "migrate":
+		action := "up"
+		if len(args) > 1 {
+			action = args[1]
+		}
+
+		switch action {
+		case "up":
+			return migrateUp(db)
+		case "down":
+			steps := 1
+			if len(args) > 2 {
+				var err error
+				if steps, err = strconv.Atoi(args[2]); err != nil || steps <= 0 {
+					return fmt.Errorf("invalid number of steps %q", args[2])
+				}
+			}
+			reverted, err := migrations.Down(db, steps)
+			for _, migration := range reverted {
+				log.Printf("Reverted migration %04d_%s", migration.Version, migration.Name)
+			}
+			return err
+		case "status":
+			statuses, err := migrations.GetStatus(db)
+			if err != nil {
+				return err
+			}
+			for _, status := range statuses {
+				appliedAt := "pending"
+				if status.AppliedAt != nil {
+					appliedAt = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+				}
+				fmt.Fprintf(os.Stdout, "%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+			}
+			return nil
+		}
+	case "seed":
+		seeded, err := migrations.Seed(db)
+		if err != nil {
+			return err
+		}
+		if seeded {
+			log.Println("Sample data inserted")
+		} else {
+			log.Println("Database already has data, seed skipped")
+		}
+		return nil
+	}
+
+	return errors.New(commandUsage)
+}
+
+func migrateUp(db *sql.DB) error {
+	applied, err := migrations.Up(db)
+	for _, migration := range applied {
+		log.Printf("Applied migration %04d_%s", migration.Version, migration.Name)
+	}
+	if err == nil && len(applied) == 0 {
+		log.Println("Database schema is up to date")
+	}
+	return err
+}
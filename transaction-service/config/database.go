@@ -13,21 +13,19 @@ import (
 var DB *sql.DB
 
 func InitDatabase() {
-	var dsn string
-
-	if databaseURL := os.Getenv("DATABASE_URL"); databaseURL != "" {
-		dsn = databaseURL
-	} else {
+	// transaction-service punya database sendiri (mini_pos_transaction), terpisah dari product-service.
+	// TRANSACTION_DATABASE_URL / DATABASE_URL dipakai jika ada, selain itu DSN dibangun dari DB_*
+	dsn := os.Getenv("TRANSACTION_DATABASE_URL")
+	if dsn == "" {
+		dsn = os.Getenv("DATABASE_URL")
+	}
+	if dsn == "" {
 		host := os.Getenv("DB_HOST")
 		user := os.Getenv("DB_USER")
 		password := os.Getenv("DB_PASSWORD")
-		dbname := os.Getenv("DB_NAME")
-		sslmode := os.Getenv("DB_SSLMODE")
-		port := os.Getenv("DB_PORT")
-
-		if port == "" {
-			port = "5432"
-		}
+		dbname := getEnv("DB_NAME", "mini_pos_transaction")
+		sslmode := getEnv("DB_SSLMODE", "disable")
+		port := getEnv("DB_PORT", "5432")
 
 		dsn = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 			host, port, user, password, dbname, sslmode)
@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"transaction-service/money"
+	"transaction-service/quantity"
+)
+
+type CreateCartRequest struct {
+	TerminalID  string           `json:"terminal_id" validate:"required,max=50"`
+	CustomerID  string           `json:"customer_id" validate:"max=100"`
+	Discount    *DiscountRequest `json:"discount"`
+	VoucherCode string           `json:"voucher_code" validate:"max=50"`
+}
+
+// UpdateCartRequest mengganti pelanggan, diskon keranjang dan voucher; field kosong menghapus nilainya
+type UpdateCartRequest struct {
+	CustomerID  string           `json:"customer_id" validate:"max=100"`
+	Discount    *DiscountRequest `json:"discount"`
+	VoucherCode string           `json:"voucher_code" validate:"max=50"`
+}
+
+// CartItemRequest menambah baris; produk yang sama tanpa diskon digabung ke baris yang sudah ada
+type CartItemRequest struct {
+	ProductID uint              `json:"product_id" validate:"required"`
+	VariantID uint              `json:"variant_id"` // wajib untuk produk yang punya varian
+	Quantity  quantity.Quantity `json:"quantity" validate:"gt=0"`
+	Discount  *DiscountRequest  `json:"discount"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity quantity.Quantity `json:"quantity" validate:"gt=0"`
+	Discount *DiscountRequest  `json:"discount"`
+}
+
+type ParkCartRequest struct {
+	Label string `json:"label" validate:"required,max=100"`
+}
+
+// ResumeCartRequest boleh memindahkan keranjang ke terminal lain
+type ResumeCartRequest struct {
+	TerminalID string `json:"terminal_id" validate:"max=50"`
+}
+
+type CheckoutCartRequest struct {
+	Payments []PaymentRequest `json:"payments" validate:"required,min=1,dive"`
+}
+
+type CartResponse struct {
+	ID            uint          `json:"id"`
+	TerminalID    string        `json:"terminal_id"`
+	Status        string        `json:"status"`
+	Label         string        `json:"label,omitempty"`
+	CustomerID    string        `json:"customer_id,omitempty"`
+	Discount      *CartDiscount `json:"discount,omitempty"`
+	VoucherCode   string        `json:"voucher_code,omitempty"`
+	CreatedBy     string        `json:"created_by,omitempty"`
+	TransactionID *uint         `json:"transaction_id,omitempty"`
+	// reservasi yang menahan stok baris keranjang sampai checkout atau keranjang kedaluwarsa
+	StockReservationID *uint              `json:"stock_reservation_id,omitempty"`
+	Items              []CartItemResponse `json:"items"`
+	// harga keranjang saat ini, tidak diisi pada daftar keranjang
+	Pricing      *PricingPreviewResponse `json:"pricing,omitempty"`
+	PricingError string                  `json:"pricing_error,omitempty"`
+	ParkedAt     string                  `json:"parked_at,omitempty"`
+	ExpiresAt    string                  `json:"expires_at"`
+	CreatedAt    string                  `json:"created_at"`
+	UpdatedAt    string                  `json:"updated_at"`
+}
+
+type CartItemResponse struct {
+	ID        uint              `json:"id"`
+	ProductID uint              `json:"product_id"`
+	VariantID *uint             `json:"variant_id,omitempty"`
+	Quantity  quantity.Quantity `json:"quantity"`
+	Discount  *CartDiscount     `json:"discount,omitempty"`
+}
+
+// CartDiscount adalah diskon yang diminta, nilai rupiahnya baru dihitung saat checkout
+type CartDiscount struct {
+	Type  string      `json:"type"`
+	Value money.Money `json:"value"`
+}
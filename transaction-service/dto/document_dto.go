@@ -0,0 +1,23 @@
+package dto
+
+// ReceiptRequest diambil dari query string GET /api/transactions/:id/receipt
+type ReceiptRequest struct {
+	Format string // escpos (default) atau text
+	Width  int    // lebar kertas dalam mm, 58 atau 80
+	Code   string // QR, BARCODE atau NONE
+	// kirim perintah buka laci kas, hanya untuk struk penjualan tunai (bukan cetak ulang)
+	OpenDrawer bool
+}
+
+// Document adalah struk atau invoice yang sudah dirender beserta content type-nya
+type Document struct {
+	ContentType string
+	FileName    string
+	Content     []byte
+}
+
+// InvoiceRequest diambil dari path dan query string GET /api/transactions/:id/invoice.pdf|.html
+type InvoiceRequest struct {
+	Format   string // pdf atau html
+	PageSize string // A4 atau A5
+}
@@ -0,0 +1,53 @@
+package dto
+
+import "transaction-service/money"
+
+type PromotionRequest struct {
+	Name        string               `json:"name" validate:"required,max=100"`
+	Description string               `json:"description" validate:"max=255"`
+	Type        string               `json:"type" validate:"required,oneof=BUY_X_GET_Y BUNDLE_PRICE PERCENT_OFF"`
+	Rule        PromotionRuleRequest `json:"rule"`
+	Priority    int                  `json:"priority"`
+	Stackable   bool                 `json:"stackable"`
+	Active      *bool                `json:"active"`
+	// format RFC3339, kosong berarti tanpa batas
+	StartsAt   string `json:"starts_at"`
+	EndsAt     string `json:"ends_at"`
+	DaysOfWeek []int  `json:"days_of_week" validate:"dive,min=0,max=6"`
+	// format HH:MM
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type PromotionRuleRequest struct {
+	ProductIDs      []uint              `json:"product_ids"`
+	CategoryIDs     []uint              `json:"category_ids"`
+	BuyQuantity     int                 `json:"buy_quantity" validate:"min=0"`
+	GetQuantity     int                 `json:"get_quantity" validate:"min=0"`
+	BundleItems     []BundleItemRequest `json:"bundle_items" validate:"dive"`
+	BundlePrice     money.Money         `json:"bundle_price" validate:"min=0"`
+	DiscountPercent money.Percent       `json:"discount_percent" validate:"min=0"`
+}
+
+type BundleItemRequest struct {
+	ProductID uint `json:"product_id" validate:"required"`
+	Quantity  int  `json:"quantity" validate:"gt=0"`
+}
+
+type PromotionResponse struct {
+	ID          uint                 `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Type        string               `json:"type"`
+	Rule        PromotionRuleRequest `json:"rule"`
+	Priority    int                  `json:"priority"`
+	Stackable   bool                 `json:"stackable"`
+	Active      bool                 `json:"active"`
+	StartsAt    string               `json:"starts_at,omitempty"`
+	EndsAt      string               `json:"ends_at,omitempty"`
+	DaysOfWeek  []int                `json:"days_of_week,omitempty"`
+	StartTime   string               `json:"start_time,omitempty"`
+	EndTime     string               `json:"end_time,omitempty"`
+	CreatedAt   string               `json:"created_at"`
+	UpdatedAt   string               `json:"updated_at"`
+}
@@ -1,33 +1,102 @@
 package dto
 
-import "time"
+import (
+	"time"
+	"transaction-service/money"
+	"transaction-service/quantity"
+)
 
-//summary transaction
+// summary transaction
 type TransactionSummaryDTO struct {
-	ID              uint      `json:"id"`
-	TransactionDate time.Time `json:"transaction_date"`
-	TotalAmount     float64   `json:"total_amount"`
-	TotalItems      int       `json:"total_items"`
-	TotalQuantity   int       `json:"total_quantity"`
+	ID              uint              `json:"id"`
+	ReturnID        *uint             `json:"return_id"` // diisi untuk baris retur, tanggalnya tanggal retur
+	TransactionDate time.Time         `json:"transaction_date"`
+	GrossAmount     money.Money       `json:"gross_amount"`
+	DiscountAmount  money.Money       `json:"discount_amount"`
+	TaxAmount       money.Money       `json:"tax_amount"`
+	TotalAmount     money.Money       `json:"total_amount"`
+	TotalItems      int               `json:"total_items"`
+	TotalQuantity   quantity.Quantity `json:"total_quantity"`
+	RefundedAmount  money.Money       `json:"refunded_amount"`
+	NetAmount       money.Money       `json:"net_amount"` // 0 untuk transaksi void
+	Status          string            `json:"status"`     // COMPLETED, VOIDED atau RETURNED
 }
 
 // sales report per product
 type ProductSalesReportDTO struct {
-	ID           uint    `json:"id"`
-	ProductName  string  `json:"product_name"`
-	CurrentPrice float64 `json:"current_price"`
-	CurrentStock int     `json:"current_stock"`
-	TotalSold    int     `json:"total_sold"`
-	TotalRevenue float64 `json:"total_revenue"`
+	ID            uint              `json:"id"`
+	ProductName   string            `json:"product_name"`
+	CurrentPrice  money.Money       `json:"current_price"`
+	CurrentStock  quantity.Quantity `json:"current_stock"`
+	TotalSold     quantity.Quantity `json:"total_sold"`
+	TotalDiscount money.Money       `json:"total_discount"`
+	TotalReturned quantity.Quantity `json:"total_returned"`
+	TotalRefunded money.Money       `json:"total_refunded"`
+	TotalRevenue  money.Money       `json:"total_revenue"` // sudah dikurangi refund
+	TotalVoided   quantity.Quantity `json:"total_voided"`  // quantity dari transaksi void, tidak termasuk total_sold
+	// rincian per varian, hanya untuk produk yang pernah terjual per varian
+	Variants []VariantSalesDTO `json:"variants,omitempty"`
+}
+
+// VariantSalesDTO adalah penjualan satu varian produk, nama varian diambil dari snapshot transaksi terakhir
+type VariantSalesDTO struct {
+	VariantID     uint              `json:"variant_id"`
+	VariantName   string            `json:"variant_name"`
+	TotalSold     quantity.Quantity `json:"total_sold"`
+	TotalDiscount money.Money       `json:"total_discount"`
+	TotalReturned quantity.Quantity `json:"total_returned"`
+	TotalRefunded money.Money       `json:"total_refunded"`
+	TotalRevenue  money.Money       `json:"total_revenue"` // sudah dikurangi refund
+	TotalVoided   quantity.Quantity `json:"total_voided"`
+}
+
+// penjualan per kategori, termasuk semua subkategorinya (produk dihitung menurut kategorinya saat ini)
+type CategorySalesReportDTO struct {
+	ID            uint              `json:"id"`
+	Name          string            `json:"name"`
+	ParentID      *uint             `json:"parent_id,omitempty"`
+	Depth         int               `json:"depth"`
+	Path          string            `json:"path"`          // mis. "Electronics > Computers"
+	ProductCount  int               `json:"product_count"` // produk aktif di kategori dan subkategorinya
+	TotalSold     quantity.Quantity `json:"total_sold"`
+	TotalDiscount money.Money       `json:"total_discount"`
+	TotalReturned quantity.Quantity `json:"total_returned"`
+	TotalRefunded money.Money       `json:"total_refunded"`
+	TotalRevenue  money.Money       `json:"total_revenue"` // sudah dikurangi refund
+	TotalVoided   quantity.Quantity `json:"total_voided"`
+}
+
+// pendapatan per metode pembayaran (tender)
+type PaymentMethodSummaryDTO struct {
+	Method           string      `json:"method"`
+	TransactionCount int         `json:"transaction_count"`
+	TotalAmount      money.Money `json:"total_amount"`
+	ReturnCount      int         `json:"return_count"`
+	RefundedAmount   money.Money `json:"refunded_amount"` // refund dalam periode, dibagi pro rata ke tender transaksinya
+	NetAmount        money.Money `json:"net_amount"`
+}
+
+// rekap pajak per kelas dan tarif untuk pelaporan (penjualan dikurangi retur dalam periode)
+type TaxSummaryDTO struct {
+	TaxClass              string        `json:"tax_class"`
+	TaxRate               money.Percent `json:"tax_rate"`
+	TaxableAmount         money.Money   `json:"taxable_amount"`
+	TaxAmount             money.Money   `json:"tax_amount"`
+	RefundedTaxableAmount money.Money   `json:"refunded_taxable_amount"`
+	RefundedTaxAmount     money.Money   `json:"refunded_tax_amount"`
+	NetTaxableAmount      money.Money   `json:"net_taxable_amount"`
+	NetTaxAmount          money.Money   `json:"net_tax_amount"`
 }
 
 // alert jika stock produk menipis
 type LowStockAlertDTO struct {
-	ID          uint    `json:"id"`
-	Name        string  `json:"name"`
-	Price       float64 `json:"price"`
-	Stock       int     `json:"stock"`
-	StockStatus string  `json:"stock_status"`
+	ID             uint              `json:"id"`
+	Name           string            `json:"name"`
+	Price          money.Money       `json:"price"`
+	Stock          quantity.Quantity `json:"stock"`
+	ReservedStock  quantity.Quantity `json:"reserved_stock"`
+	AvailableStock quantity.Quantity `json:"available_stock"` // stok - reservasi aktif, dasar stock_status
+	StockStatus    string            `json:"stock_status"`
 }
 
 // filter untuk laporan
@@ -36,4 +105,11 @@ type ReportingFilterDTO struct {
 	EndDate   *time.Time `json:"end_date,omitempty" form:"end_date"`
 	Limit     int        `json:"limit,omitempty" form:"limit"`
 	Offset    int        `json:"offset,omitempty" form:"offset"`
+	// Entry membatasi baris ringkasan transaksi ke penjualan atau retur saja, kosong berarti keduanya
+	Entry string `json:"-" form:"-"`
 }
+
+const (
+	SummaryEntrySale   = "SALE"
+	SummaryEntryReturn = "RETURN"
+)
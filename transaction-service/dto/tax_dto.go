@@ -0,0 +1,24 @@
+package dto
+
+import "transaction-service/money"
+
+type TaxRateRequest struct {
+	Name   string        `json:"name" validate:"required,max=100"`
+	Rate   money.Percent `json:"rate" validate:"min=0"`
+	Active *bool         `json:"active"`
+}
+
+type TaxRateResponse struct {
+	TaxClass  string        `json:"tax_class"`
+	Name      string        `json:"name"`
+	Rate      money.Percent `json:"rate"`
+	Active    bool          `json:"active"`
+	UpdatedAt string        `json:"updated_at"`
+}
+
+type TaxBreakdownResponse struct {
+	TaxClass      string        `json:"tax_class"`
+	Rate          money.Percent `json:"rate"`
+	TaxableAmount money.Money   `json:"taxable_amount"`
+	TaxAmount     money.Money   `json:"tax_amount"`
+}
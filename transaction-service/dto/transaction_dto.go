@@ -1,33 +1,223 @@
 package dto
 
-type CreateTransactionRequest struct {
+import (
+	"transaction-service/money"
+	"transaction-service/quantity"
+)
+
+// PricingRequest adalah isi keranjang yang dihitung harganya, dipakai untuk preview dan checkout
+type PricingRequest struct {
 	Items []TransactionItemRequest `json:"items" validate:"required,dive"`
+	// diskon keranjang, dihitung setelah promosi dan diskon baris
+	Discount *DiscountRequest `json:"discount"`
+	// kode voucher, dihitung paling akhir dari total setelah semua diskon
+	VoucherCode string `json:"voucher_code" validate:"max=50"`
+	// wajib diisi jika voucher punya batas pemakaian per pelanggan
+	CustomerID string `json:"customer_id" validate:"max=100"`
+	// baris sudah ditahan reservasi keranjang yang akan digantikan, unitnya ikut dihitung tersedia
+	StockHeld bool `json:"-"`
+}
+
+type CreateTransactionRequest struct {
+	PricingRequest
+	Payments []PaymentRequest `json:"payments" validate:"required,min=1,dive"`
 }
 
+// TransactionItemRequest menunjuk produk lewat salah satu dari product_id, barcode (hasil scan) atau sku.
+// Produk yang punya varian butuh variant_id bersama product_id, atau barcode/sku milik varian tersebut.
 type TransactionItemRequest struct {
-	ProductID uint `json:"product_id" validate:"required"`
-	Quantity  int  `json:"quantity" validate:"gt=0"`
+	ProductID uint   `json:"product_id,omitempty"`
+	VariantID uint   `json:"variant_id,omitempty"`
+	Barcode   string `json:"barcode,omitempty" validate:"max=48"`
+	SKU       string `json:"sku,omitempty" validate:"max=64"`
+	// boleh kosong untuk label timbangan, quantity diambil dari label; selain itu harus bilangan bulat
+	Quantity quantity.Quantity `json:"quantity" validate:"gte=0"`
+	Discount *DiscountRequest  `json:"discount"`
+}
+
+// Type PERCENTAGE memakai Value 0-100, FIXED memakai Value dalam rupiah
+type DiscountRequest struct {
+	Type  string      `json:"type" validate:"required,oneof=PERCENTAGE FIXED"`
+	Value money.Money `json:"value" validate:"gt=0"`
+}
+
+// Operator adalah kasir yang menjalankan request, diambil dari header
+type Operator struct {
+	UserID string
+	Role   string
+}
+
+// satu tender pembayaran, Amount adalah uang yang diterima (boleh lebih untuk CASH)
+type PaymentRequest struct {
+	Method    string      `json:"method" validate:"required"`
+	Amount    money.Money `json:"amount" validate:"gt=0"`
+	Reference string      `json:"reference" validate:"max=100"`
 }
 
 type TransactionResponse struct {
-	ID               uint                      `json:"id"`
-	TransactionDate  string                    `json:"transaction_date"`
-	TotalAmount      float64                   `json:"total_amount"`
-	TransactionItems []TransactionItemResponse `json:"transaction_items"`
-	CreatedAt        string                    `json:"created_at"`
+	ID                      uint                      `json:"id"`
+	InvoiceNumber           string                    `json:"invoice_number"`
+	TransactionDate         string                    `json:"transaction_date"`
+	GrossAmount             money.Money               `json:"gross_amount"`
+	DiscountAmount          money.Money               `json:"discount_amount"`
+	PromotionDiscountAmount money.Money               `json:"promotion_discount_amount"`
+	CartDiscount            *DiscountResponse         `json:"cart_discount,omitempty"`
+	CustomerID              string                    `json:"customer_id,omitempty"`
+	Voucher                 *AppliedVoucherResponse   `json:"voucher,omitempty"`
+	PricesIncludeTax        bool                      `json:"prices_include_tax"`
+	TaxAmount               money.Money               `json:"tax_amount"`
+	TaxBreakdown            []TaxBreakdownResponse    `json:"tax_breakdown"`
+	TotalAmount             money.Money               `json:"total_amount"`
+	PaidAmount              money.Money               `json:"paid_amount"`
+	ChangeAmount            money.Money               `json:"change_amount"`
+	Status                  string                    `json:"status"`
+	TransactionItems        []TransactionItemResponse `json:"transaction_items"`
+	Payments                []PaymentResponse         `json:"payments"`
+	VoidedAt                string                    `json:"voided_at,omitempty"`
+	VoidedBy                string                    `json:"voided_by,omitempty"`
+	VoidReason              string                    `json:"void_reason,omitempty"`
+	// true jika pengurangan stok di product-service belum pasti; dikonfirmasi ulang di latar belakang dan
+	// transaksi di-void oleh system jika ternyata stok tidak jadi dikurangi
+	StockPending bool   `json:"stock_pending,omitempty"`
+	CreatedAt    string `json:"created_at"`
 }
 
 type TransactionItemResponse struct {
-	ID          uint    `json:"id"`
-	ProductID   uint    `json:"product_id"`
-	ProductName string  `json:"product_name"`
-	Price       float64 `json:"price"`
-	Quantity    int     `json:"quantity"`
-	Subtotal    float64 `json:"subtotal"`
+	ID          uint              `json:"id"`
+	ProductID   uint              `json:"product_id"`
+	ProductName string            `json:"product_name"`
+	ProductSKU  string            `json:"product_sku,omitempty"`
+	VariantID   *uint             `json:"variant_id,omitempty"`
+	VariantName string            `json:"variant_name,omitempty"`
+	Price       money.Money       `json:"price"`
+	Quantity    quantity.Quantity `json:"quantity"`
+	// GrossAmount = Price * Quantity (label harga timbangan: harga di label), Subtotal = GrossAmount - DiscountAmount
+	GrossAmount             money.Money                `json:"gross_amount"`
+	PromotionDiscountAmount money.Money                `json:"promotion_discount_amount"`
+	Promotions              []AppliedPromotionResponse `json:"promotions,omitempty"`
+	LineDiscount            *DiscountResponse          `json:"line_discount,omitempty"`
+	DiscountAmount          money.Money                `json:"discount_amount"`
+	Subtotal                money.Money                `json:"subtotal"`
+	TaxClass                string                     `json:"tax_class"`
+	TaxRate                 money.Percent              `json:"tax_rate"`
+	TaxableAmount           money.Money                `json:"taxable_amount"`
+	TaxAmount               money.Money                `json:"tax_amount"`
+	// jumlah yang dibayar untuk baris ini (Subtotal, ditambah TaxAmount jika harga belum termasuk pajak)
+	TotalAmount money.Money `json:"total_amount"`
+}
+
+type AppliedPromotionResponse struct {
+	PromotionID    uint        `json:"promotion_id"`
+	PromotionName  string      `json:"promotion_name"`
+	DiscountAmount money.Money `json:"discount_amount"`
+}
+
+type AppliedVoucherResponse struct {
+	Code           string      `json:"code"`
+	DiscountAmount money.Money `json:"discount_amount"`
+}
+
+// PricingPreviewResponse adalah hasil hitung keranjang tanpa menyimpan transaksi
+type PricingPreviewResponse struct {
+	Items                   []TransactionItemResponse `json:"items"`
+	GrossAmount             money.Money               `json:"gross_amount"`
+	PromotionDiscountAmount money.Money               `json:"promotion_discount_amount"`
+	DiscountAmount          money.Money               `json:"discount_amount"`
+	CartDiscount            *DiscountResponse         `json:"cart_discount,omitempty"`
+	Voucher                 *AppliedVoucherResponse   `json:"voucher,omitempty"`
+	PricesIncludeTax        bool                      `json:"prices_include_tax"`
+	TaxAmount               money.Money               `json:"tax_amount"`
+	TaxBreakdown            []TaxBreakdownResponse    `json:"tax_breakdown"`
+	TotalAmount             money.Money               `json:"total_amount"`
+	// hanya diisi untuk preview keranjang, lihat PricingWarning
+	Warnings []PricingWarning `json:"warnings,omitempty"`
+}
+
+const (
+	WarningProductUnavailable    = "PRODUCT_UNAVAILABLE"
+	WarningInsufficientStock     = "INSUFFICIENT_STOCK"
+	WarningInvalidDiscount       = "INVALID_DISCOUNT"
+	WarningDiscountLimitExceeded = "DISCOUNT_LIMIT_EXCEEDED"
+	WarningVoucherNotApplicable  = "VOUCHER_NOT_APPLICABLE"
+	// produk baris kini bervarian, pilih varian lalu simpan ulang barisnya
+	WarningVariantRequired = "VARIANT_REQUIRED"
+)
+
+// PricingWarning adalah masalah keranjang yang akan menggagalkan checkout jika tidak diperbaiki
+type PricingWarning struct {
+	Code      string `json:"code"`
+	ProductID uint   `json:"product_id,omitempty"`
+	// stok tersedia, hanya untuk INSUFFICIENT_STOCK
+	Available *quantity.Quantity `json:"available,omitempty"`
+	Message   string             `json:"message"`
+}
+
+type DiscountResponse struct {
+	Type   string      `json:"type"`
+	Value  money.Money `json:"value"`
+	Amount money.Money `json:"amount"`
+}
+
+type PaymentResponse struct {
+	ID             uint        `json:"id"`
+	Method         string      `json:"method"`
+	TenderedAmount money.Money `json:"tendered_amount"`
+	Amount         money.Money `json:"amount"`
+	Reference      string      `json:"reference,omitempty"`
 }
 
 type ApiResponse struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
-}
\ No newline at end of file
+}
+
+// Items kosong berarti retur penuh untuk semua sisa item transaksi
+type CreateReturnRequest struct {
+	ReasonCode string              `json:"reason_code" validate:"required"`
+	ReasonNote string              `json:"reason_note" validate:"max=255"`
+	Items      []ReturnItemRequest `json:"items" validate:"dive"`
+}
+
+type ReturnItemRequest struct {
+	TransactionItemID uint              `json:"transaction_item_id" validate:"required"`
+	Quantity          quantity.Quantity `json:"quantity" validate:"gt=0"`
+}
+
+type ReturnResponse struct {
+	ID            uint                 `json:"id"`
+	TransactionID uint                 `json:"transaction_id"`
+	ReasonCode    string               `json:"reason_code"`
+	ReasonNote    string               `json:"reason_note,omitempty"`
+	TotalAmount   money.Money          `json:"total_amount"`
+	ReturnDate    string               `json:"return_date"`
+	Items         []ReturnItemResponse `json:"items"`
+	// stok retur belum pasti kembali ke product-service, diulang oleh rekonsiliasi stok
+	RestockPending bool `json:"restock_pending,omitempty"`
+}
+
+type ReturnItemResponse struct {
+	ID                uint              `json:"id"`
+	TransactionItemID uint              `json:"transaction_item_id"`
+	ProductID         uint              `json:"product_id"`
+	ProductName       string            `json:"product_name"`
+	UnitPrice         money.Money       `json:"unit_price"`
+	Quantity          quantity.Quantity `json:"quantity"`
+	Amount            money.Money       `json:"amount"`
+	TaxAmount         money.Money       `json:"tax_amount"`
+}
+
+type VoidTransactionRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type CloseBusinessDayRequest struct {
+	// format YYYY-MM-DD, default hari ini
+	BusinessDate string `json:"business_date"`
+}
+
+type BusinessDayResponse struct {
+	BusinessDate string `json:"business_date"`
+	ClosedAt     string `json:"closed_at"`
+	ClosedBy     string `json:"closed_by"`
+}
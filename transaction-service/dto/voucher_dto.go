@@ -0,0 +1,58 @@
+package dto
+
+import "transaction-service/money"
+
+type CreateVoucherRequest struct {
+	// kosong berarti kode dibuat otomatis
+	Code string `json:"code" validate:"max=50"`
+	VoucherRuleRequest
+}
+
+type CreateVoucherBatchRequest struct {
+	// awalan kode, mis. "PROMO" menghasilkan PROMO-7KQ2M9XA
+	Prefix string `json:"prefix" validate:"max=20"`
+	Count  int    `json:"count" validate:"required,gt=0,max=1000"`
+	VoucherRuleRequest
+}
+
+type VoucherRuleRequest struct {
+	DiscountType      string      `json:"discount_type" validate:"required,oneof=PERCENTAGE FIXED"`
+	Value             money.Money `json:"value" validate:"gt=0"`
+	MaxDiscountAmount money.Money `json:"max_discount_amount" validate:"min=0"`
+	MinSpend          money.Money `json:"min_spend" validate:"min=0"`
+	// format RFC3339, kosong berarti tidak kedaluwarsa
+	ExpiresAt string `json:"expires_at"`
+	// nil berarti 1 (sekali pakai), 0 berarti tanpa batas
+	UsageLimit       *int `json:"usage_limit" validate:"omitempty,min=0"`
+	PerCustomerLimit int  `json:"per_customer_limit" validate:"min=0"`
+}
+
+type VoucherResponse struct {
+	ID                uint        `json:"id"`
+	Code              string      `json:"code"`
+	BatchCode         string      `json:"batch_code,omitempty"`
+	DiscountType      string      `json:"discount_type"`
+	Value             money.Money `json:"value"`
+	MaxDiscountAmount money.Money `json:"max_discount_amount"`
+	MinSpend          money.Money `json:"min_spend"`
+	ExpiresAt         string      `json:"expires_at,omitempty"`
+	UsageLimit        int         `json:"usage_limit"`
+	PerCustomerLimit  int         `json:"per_customer_limit"`
+	UsedCount         int         `json:"used_count"`
+	Active            bool        `json:"active"`
+	CreatedAt         string      `json:"created_at"`
+}
+
+type VoucherBatchResponse struct {
+	BatchCode string            `json:"batch_code"`
+	Vouchers  []VoucherResponse `json:"vouchers"`
+}
+
+type VoucherRedemptionResponse struct {
+	ID             uint        `json:"id"`
+	TransactionID  uint        `json:"transaction_id"`
+	CustomerID     string      `json:"customer_id,omitempty"`
+	DiscountAmount money.Money `json:"discount_amount"`
+	RedeemedAt     string      `json:"redeemed_at"`
+	ReleasedAt     string      `json:"released_at,omitempty"`
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// tipe event yang diterbitkan transaction-service
+const (
+	TransactionCreated = "transaction.created"
+)
+
+// tipe event dari product-service yang dikonsumsi untuk memperbarui product_replicas dan category_replicas
+const (
+	ProductCreated = "product.created"
+	ProductUpdated = "product.updated"
+	ProductDeleted = "product.deleted"
+	StockChanged   = "stock.changed"
+
+	CategoryCreated = "category.created"
+	CategoryUpdated = "category.updated"
+	CategoryDeleted = "category.deleted"
+
+	// StockReasonSale adalah stok berkurang karena reservasi dikonfirmasi, reservasinya ikut selesai
+	StockReasonSale = "SALE"
+)
+
+// Event adalah domain event yang dikirim lewat outbox. ID unik per event dan sama di setiap
+// pengiriman ulang, sehingga consumer bisa membuang duplikat.
+type Event struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Source        string          `json:"source"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// Source adalah nama service penerbit event
+const Source = "transaction-service"
+
+// NewID membuat UUID v4 acak untuk event
+func NewID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,66 @@
+package events
+
+import (
+	"time"
+	"transaction-service/money"
+	"transaction-service/quantity"
+)
+
+// TransactionCreatedPayload adalah isi event transaction.created
+type TransactionCreatedPayload struct {
+	ID              uint                        `json:"id"`
+	InvoiceNumber   string                      `json:"invoice_number"`
+	TransactionDate time.Time                   `json:"transaction_date"`
+	CustomerID      string                      `json:"customer_id,omitempty"`
+	GrossAmount     money.Money                 `json:"gross_amount"`
+	DiscountAmount  money.Money                 `json:"discount_amount"`
+	TaxAmount       money.Money                 `json:"tax_amount"`
+	TotalAmount     money.Money                 `json:"total_amount"`
+	Items           []TransactionItemPayload    `json:"items"`
+	Payments        []TransactionPaymentPayload `json:"payments"`
+}
+
+type TransactionItemPayload struct {
+	ProductID   uint              `json:"product_id"`
+	VariantID   *uint             `json:"variant_id,omitempty"`
+	ProductName string            `json:"product_name"`
+	VariantName string            `json:"variant_name,omitempty"`
+	Quantity    quantity.Quantity `json:"quantity"`
+	UnitPrice   money.Money       `json:"unit_price"`
+	TotalAmount money.Money       `json:"total_amount"`
+}
+
+type TransactionPaymentPayload struct {
+	Method string      `json:"method"`
+	Amount money.Money `json:"amount"`
+}
+
+// ProductPayload adalah isi event product.created, product.updated dan product.deleted dari product-service
+type ProductPayload struct {
+	ID         uint              `json:"id"`
+	Name       string            `json:"name"`
+	Price      money.Money       `json:"price"`
+	Stock      quantity.Quantity `json:"stock"`
+	TaxClass   string            `json:"tax_class"`
+	CategoryID *uint             `json:"category_id,omitempty"`
+	DeletedAt  *time.Time        `json:"deleted_at,omitempty"`
+}
+
+// CategoryPayload adalah isi event category.created, category.updated dan category.deleted dari product-service
+type CategoryPayload struct {
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	ParentID  *uint      `json:"parent_id,omitempty"`
+	SortOrder int        `json:"sort_order"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// StockChangedPayload adalah isi event stock.changed dari product-service
+type StockChangedPayload struct {
+	ProductID     uint              `json:"product_id"`
+	PreviousStock quantity.Quantity `json:"previous_stock"`
+	Stock         quantity.Quantity `json:"stock"`
+	Delta         quantity.Quantity `json:"delta"`
+	Reason        string            `json:"reason"`
+	Reference     string            `json:"reference,omitempty"`
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"transaction-service/dto"
+	"transaction-service/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BusinessDayHandler struct {
+	service services.BusinessDayService
+}
+
+func NewBusinessDayHandler(service services.BusinessDayService) *BusinessDayHandler {
+	return &BusinessDayHandler{service: service}
+}
+
+func (h *BusinessDayHandler) CloseBusinessDay(c *fiber.Ctx) error {
+	userID, ok, err := requireUserID(c)
+	if !ok {
+		return err
+	}
+
+	var req dto.CloseBusinessDayRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(dto.ApiResponse{
+				Success: false,
+				Message: "Invalid request body: " + err.Error(),
+			})
+		}
+	}
+
+	day, err := h.service.CloseBusinessDay(&req, userID)
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "already been closed") {
+			statusCode = 409
+		} else if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "cannot") || strings.Contains(err.Error(), "required") {
+			statusCode = 400
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Business day closed successfully",
+		Data:    day,
+	})
+}
+
+func (h *BusinessDayHandler) GetClosedBusinessDays(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "30"))
+
+	days, err := h.service.GetClosedBusinessDays(limit)
+	if err != nil {
+		return c.Status(500).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Closed business days retrieved successfully",
+		Data:    days,
+	})
+}
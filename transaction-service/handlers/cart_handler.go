@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"transaction-service/dto"
+	"transaction-service/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CartHandler struct {
+	service services.CartService
+}
+
+func NewCartHandler(service services.CartService) *CartHandler {
+	return &CartHandler{service: service}
+}
+
+func (h *CartHandler) CreateCart(c *fiber.Ctx) error {
+	var req dto.CreateCartRequest
+	if errResponse := parseCartBody(c, &req); errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+
+	cart, err := h.service.CreateCart(&req, currentOperator(c))
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return c.Status(201).JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Cart created successfully",
+		Data:    cart,
+	})
+}
+
+// GetCarts menampilkan keranjang per terminal, mis. ?terminal_id=POS-01&status=PARKED
+func (h *CartHandler) GetCarts(c *fiber.Ctx) error {
+	carts, err := h.service.GetCarts(c.Query("terminal_id"), c.Query("status"))
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Carts retrieved successfully",
+		Data:    carts,
+	})
+}
+
+func (h *CartHandler) GetCart(c *fiber.Ctx) error {
+	id, errResponse := cartIDParam(c, "id")
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+
+	cart, err := h.service.GetCart(id, currentOperator(c))
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Cart retrieved successfully",
+		Data:    cart,
+	})
+}
+
+func (h *CartHandler) UpdateCart(c *fiber.Ctx) error {
+	id, errResponse := cartIDParam(c, "id")
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+	var req dto.UpdateCartRequest
+	if errResponse := parseCartBody(c, &req); errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+
+	cart, err := h.service.UpdateCart(id, &req, currentOperator(c))
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Cart updated successfully",
+		Data:    cart,
+	})
+}
+
+func (h *CartHandler) DeleteCart(c *fiber.Ctx) error {
+	id, errResponse := cartIDParam(c, "id")
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+
+	if err := h.service.DeleteCart(id); err != nil {
+		return cartError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Cart deleted successfully",
+	})
+}
+
+func (h *CartHandler) AddItem(c *fiber.Ctx) error {
+	id, errResponse := cartIDParam(c, "id")
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+	var req dto.CartItemRequest
+	if errResponse := parseCartBody(c, &req); errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+
+	cart, err := h.service.AddItem(id, &req, currentOperator(c))
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Cart item added successfully",
+		Data:    cart,
+	})
+}
+
+func (h *CartHandler) UpdateItem(c *fiber.Ctx) error {
+	id, errResponse := cartIDParam(c, "id")
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+	itemID, errResponse := cartIDParam(c, "itemId")
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+	var req dto.UpdateCartItemRequest
+	if errResponse := parseCartBody(c, &req); errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+
+	cart, err := h.service.UpdateItem(id, itemID, &req, currentOperator(c))
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Cart item updated successfully",
+		Data:    cart,
+	})
+}
+
+func (h *CartHandler) RemoveItem(c *fiber.Ctx) error {
+	id, errResponse := cartIDParam(c, "id")
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+	itemID, errResponse := cartIDParam(c, "itemId")
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+
+	cart, err := h.service.RemoveItem(id, itemID, currentOperator(c))
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Cart item removed successfully",
+		Data:    cart,
+	})
+}
+
+func (h *CartHandler) ParkCart(c *fiber.Ctx) error {
+	id, errResponse := cartIDParam(c, "id")
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+	var req dto.ParkCartRequest
+	if errResponse := parseCartBody(c, &req); errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+
+	cart, err := h.service.ParkCart(id, &req, currentOperator(c))
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Cart parked successfully",
+		Data:    cart,
+	})
+}
+
+func (h *CartHandler) ResumeCart(c *fiber.Ctx) error {
+	id, errResponse := cartIDParam(c, "id")
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+	// body opsional
+	var req dto.ResumeCartRequest
+	if len(c.Body()) > 0 {
+		if errResponse := parseCartBody(c, &req); errResponse != nil {
+			return c.Status(400).JSON(errResponse)
+		}
+	}
+
+	cart, err := h.service.ResumeCart(id, &req, currentOperator(c))
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Cart resumed successfully",
+		Data:    cart,
+	})
+}
+
+func (h *CartHandler) Checkout(c *fiber.Ctx) error {
+	id, errResponse := cartIDParam(c, "id")
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+	var req dto.CheckoutCartRequest
+	if errResponse := parseCartBody(c, &req); errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+
+	transaction, err := h.service.Checkout(id, &req, currentOperator(c))
+	if err != nil {
+		return cartError(c, err)
+	}
+
+	return c.Status(201).JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Transaction created successfully",
+		Data:    transaction,
+	})
+}
+
+func cartIDParam(c *fiber.Ctx, name string) (uint, *dto.ApiResponse) {
+	id, err := strconv.ParseUint(c.Params(name), 10, 32)
+	if err != nil {
+		message := "Invalid cart ID"
+		if name == "itemId" {
+			message = "Invalid cart item ID"
+		}
+		return 0, &dto.ApiResponse{
+			Success: false,
+			Message: message,
+		}
+	}
+	return uint(id), nil
+}
+
+func parseCartBody(c *fiber.Ctx, req interface{}) *dto.ApiResponse {
+	if err := c.BodyParser(req); err != nil {
+		return &dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		}
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		errs := err.(validator.ValidationErrors)
+		var messages []string
+		for _, e := range errs {
+			switch e.Tag() {
+			case "required":
+				messages = append(messages, e.Field()+" is required")
+			case "gt":
+				messages = append(messages, e.Field()+" must be greater than "+e.Param())
+			case "min":
+				messages = append(messages, e.Field()+" must have at least "+e.Param()+" entry")
+			case "max":
+				messages = append(messages, e.Field()+" must be at most "+e.Param()+" characters")
+			case "oneof":
+				messages = append(messages, e.Field()+" must be one of "+e.Param())
+			}
+		}
+
+		return &dto.ApiResponse{
+			Success: false,
+			Message: strings.Join(messages, ", "),
+		}
+	}
+
+	return nil
+}
+
+// cartError memetakan error keranjang dan error checkout (dari CreateTransaction) ke status HTTP
+func cartError(c *fiber.Ctx, err error) error {
+	message := err.Error()
+	statusCode := 500
+	switch {
+	case strings.Contains(message, "exceeds the maximum"):
+		statusCode = 403
+	case strings.Contains(message, "cart not found"), strings.Contains(message, "cart item not found"):
+		statusCode = 404
+	case strings.HasPrefix(message, "cart ") && strings.Contains(message, "has expired"):
+		statusCode = 410
+	case strings.Contains(message, "checked out"), strings.Contains(message, "parked"), strings.Contains(message, "no longer open"):
+		statusCode = 409
+	case strings.Contains(message, "not found"), strings.Contains(message, "insufficient stock"),
+		strings.Contains(message, "payment"), strings.Contains(message, "discount"), strings.Contains(message, "voucher"),
+		strings.Contains(message, "tax rate"), strings.Contains(message, "quantity"), strings.Contains(message, "required"),
+		strings.Contains(message, "invalid"), strings.Contains(message, "no items"):
+		statusCode = 400
+	}
+
+	return c.Status(statusCode).JSON(dto.ApiResponse{
+		Success: false,
+		Message: message,
+	})
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"transaction-service/dto"
+	"transaction-service/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+)
+
+// IdempotencyMiddleware menyimpan response pertama untuk setiap Idempotency-Key
+// dan memutar ulang response tersebut untuk retry dengan body yang sama.
+func IdempotencyMiddleware(service services.IdempotencyService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > 255 {
+			return c.Status(400).JSON(dto.ApiResponse{
+				Success: false,
+				Message: "Idempotency-Key must be at most 255 characters",
+			})
+		}
+
+		// hash mencakup method dan path agar key tidak bisa dipakai lintas endpoint
+		hasher := sha256.New()
+		hasher.Write([]byte(c.Method() + " " + c.Path() + "\n"))
+		hasher.Write(c.Body())
+		requestHash := hex.EncodeToString(hasher.Sum(nil))
+
+		stored, leaseToken, err := service.Begin(key, requestHash)
+		if err != nil {
+			if errors.Is(err, services.ErrIdempotencyKeyReused) || errors.Is(err, services.ErrIdempotencyKeyInFlight) {
+				return c.Status(409).JSON(dto.ApiResponse{
+					Success: false,
+					Message: err.Error(),
+				})
+			}
+			return c.Status(500).JSON(dto.ApiResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+
+		if stored != nil {
+			c.Set(IdempotencyReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(stored.ResponseStatus).Send(stored.ResponseBody)
+		}
+
+		if err := c.Next(); err != nil {
+			if abortErr := service.Abort(key, leaseToken); abortErr != nil {
+				log.Printf("Warning: failed to release idempotency key %s: %v", key, abortErr)
+			}
+			return err
+		}
+
+		// error server tidak disimpan supaya client bisa retry dengan key yang sama
+		status := c.Response().StatusCode()
+		if status >= 500 {
+			if err := service.Abort(key, leaseToken); err != nil {
+				log.Printf("Warning: failed to release idempotency key %s: %v", key, err)
+			}
+			return nil
+		}
+
+		// key tidak dilepas jika response gagal disimpan: request sudah diproses, retry baru boleh
+		// mengambil alih key setelah lease-nya habis. Jika lease sudah diambil alih, key milik retry tersebut
+		// tidak ditimpa dan response request ini tetap dikirim.
+		body := append([]byte(nil), c.Response().Body()...)
+		if err := service.Complete(key, leaseToken, status, body); err != nil {
+			if errors.Is(err, services.ErrIdempotencyLeaseLost) {
+				log.Printf("Warning: response for idempotency key %s not stored, its lease was taken over by a retry", key)
+				return nil
+			}
+			log.Printf("Warning: failed to store response for idempotency key %s: %v", key, err)
+			return c.Status(500).JSON(dto.ApiResponse{
+				Success: false,
+				Message: "failed to store response for idempotency key: " + err.Error(),
+			})
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"transaction-service/dto"
+	"transaction-service/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InvoiceHandler struct {
+	service services.InvoiceService
+}
+
+func NewInvoiceHandler(service services.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{service: service}
+}
+
+// GetInvoicePDF dan GetInvoiceHTML mengembalikan invoice transaksi, ukuran kertas lewat ?size=A4|A5
+func (h *InvoiceHandler) GetInvoicePDF(c *fiber.Ctx) error {
+	return h.renderInvoice(c, services.InvoiceFormatPDF)
+}
+
+func (h *InvoiceHandler) GetInvoiceHTML(c *fiber.Ctx) error {
+	return h.renderInvoice(c, services.InvoiceFormatHTML)
+}
+
+func (h *InvoiceHandler) renderInvoice(c *fiber.Ctx, format string) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid transaction ID",
+		})
+	}
+
+	invoice, err := h.service.RenderInvoice(uint(id), &dto.InvoiceRequest{
+		Format:   format,
+		PageSize: c.Query("size"),
+	})
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		} else if strings.Contains(err.Error(), "invalid") {
+			statusCode = 400
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	disposition := "inline"
+	if c.QueryBool("download", false) {
+		disposition = "attachment"
+	}
+	c.Set(fiber.HeaderContentType, invoice.ContentType)
+	c.Set(fiber.HeaderContentDisposition, disposition+`; filename="`+invoice.FileName+`"`)
+	return c.Send(invoice.Content)
+}
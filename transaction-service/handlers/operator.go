@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"strings"
+	"transaction-service/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// header identitas kasir/operator yang diisi API gateway dari token Bearer, header dari client dibuang gateway
+const (
+	UserIDHeader   = "X-User-ID"
+	UserRoleHeader = "X-User-Role"
+)
+
+const userHeadersTrustedKey = "userHeadersTrusted"
+
+// UserHeadersMiddleware menandai apakah header identitas boleh dipercaya, yaitu service ini hanya bisa diakses
+// lewat API gateway. Selama false, X-User-ID tidak dicatat sebagai operator dan void serta penutupan hari usaha
+// ditolak; role dibatasi service sendiri (TransactionOptions.TrustRoleHeader).
+func UserHeadersMiddleware(trusted bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(userHeadersTrustedKey, trusted)
+		return c.Next()
+	}
+}
+
+func userHeadersTrusted(c *fiber.Ctx) bool {
+	trusted, _ := c.Locals(userHeadersTrustedKey).(bool)
+	return trusted
+}
+
+// currentUserID mengembalikan user dari X-User-ID, kosong jika headernya tidak dipercaya
+func currentUserID(c *fiber.Ctx) string {
+	if !userHeadersTrusted(c) {
+		return ""
+	}
+	return strings.TrimSpace(c.Get(UserIDHeader))
+}
+
+// requireUserID mengambil operator untuk audit trail (void, penutupan hari usaha). Jika gagal, response error
+// sudah dikirim dan ok false.
+func requireUserID(c *fiber.Ctx) (userID string, ok bool, err error) {
+	if !userHeadersTrusted(c) {
+		return "", false, c.Status(403).JSON(dto.ApiResponse{
+			Success: false,
+			Message: UserIDHeader + " is not trusted, send the request through the API gateway",
+		})
+	}
+
+	userID = currentUserID(c)
+	if userID == "" {
+		return "", false, c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: UserIDHeader + " header is required",
+		})
+	}
+	return userID, true, nil
+}
+
+func currentOperator(c *fiber.Ctx) dto.Operator {
+	return dto.Operator{
+		UserID: currentUserID(c),
+		Role:   strings.ToUpper(strings.TrimSpace(c.Get(UserRoleHeader))),
+	}
+}
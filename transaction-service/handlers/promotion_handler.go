@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"transaction-service/dto"
+	"transaction-service/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromotionHandler struct {
+	service services.PromotionService
+}
+
+func NewPromotionHandler(service services.PromotionService) *PromotionHandler {
+	return &PromotionHandler{service: service}
+}
+
+func (h *PromotionHandler) CreatePromotion(c *fiber.Ctx) error {
+	req, errResponse := parsePromotionRequest(c)
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+
+	promotion, err := h.service.CreatePromotion(req)
+	if err != nil {
+		statusCode := 400
+		if strings.HasPrefix(err.Error(), "failed to") {
+			statusCode = 500
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Promotion created successfully",
+		Data:    promotion,
+	})
+}
+
+func (h *PromotionHandler) GetAllPromotions(c *fiber.Ctx) error {
+	activeOnly := c.Query("active") == "true"
+
+	promotions, err := h.service.GetAllPromotions(activeOnly)
+	if err != nil {
+		return c.Status(500).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Promotions retrieved successfully",
+		Data:    promotions,
+	})
+}
+
+func (h *PromotionHandler) GetPromotion(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid promotion ID",
+		})
+	}
+
+	promotion, err := h.service.GetPromotionByID(uint(id))
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Promotion retrieved successfully",
+		Data:    promotion,
+	})
+}
+
+func (h *PromotionHandler) UpdatePromotion(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid promotion ID",
+		})
+	}
+
+	req, errResponse := parsePromotionRequest(c)
+	if errResponse != nil {
+		return c.Status(400).JSON(errResponse)
+	}
+
+	promotion, err := h.service.UpdatePromotion(uint(id), req)
+	if err != nil {
+		statusCode := 400
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		} else if strings.HasPrefix(err.Error(), "failed to") {
+			statusCode = 500
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Promotion updated successfully",
+		Data:    promotion,
+	})
+}
+
+func (h *PromotionHandler) DeletePromotion(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid promotion ID",
+		})
+	}
+
+	if err := h.service.DeletePromotion(uint(id)); err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Promotion deleted successfully",
+	})
+}
+
+func parsePromotionRequest(c *fiber.Ctx) (*dto.PromotionRequest, *dto.ApiResponse) {
+	var req dto.PromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, &dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		}
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		errs := err.(validator.ValidationErrors)
+		var messages []string
+		for _, e := range errs {
+			switch e.Tag() {
+			case "required":
+				messages = append(messages, e.Field()+" is required")
+			case "gt":
+				messages = append(messages, e.Field()+" must be greater than "+e.Param())
+			case "min":
+				messages = append(messages, e.Field()+" must be at least "+e.Param())
+			case "max":
+				messages = append(messages, e.Field()+" must be at most "+e.Param())
+			case "oneof":
+				messages = append(messages, e.Field()+" must be one of "+e.Param())
+			}
+		}
+
+		return nil, &dto.ApiResponse{
+			Success: false,
+			Message: strings.Join(messages, ", "),
+		}
+	}
+
+	return &req, nil
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"transaction-service/dto"
+	"transaction-service/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReceiptHandler struct {
+	service services.ReceiptService
+}
+
+func NewReceiptHandler(service services.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{service: service}
+}
+
+// GetReceipt mengembalikan struk ESC/POS (byte stream) atau preview teks
+func (h *ReceiptHandler) GetReceipt(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid transaction ID",
+		})
+	}
+
+	req := dto.ReceiptRequest{
+		Format:     c.Query("format"),
+		Code:       c.Query("code"),
+		OpenDrawer: c.QueryBool("drawer", false),
+	}
+	if width := c.Query("width"); width != "" {
+		req.Width, err = strconv.Atoi(width)
+		if err != nil {
+			return c.Status(400).JSON(dto.ApiResponse{
+				Success: false,
+				Message: "Invalid receipt width",
+			})
+		}
+	}
+
+	receipt, err := h.service.RenderReceipt(uint(id), &req)
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		} else if strings.Contains(err.Error(), "invalid") {
+			statusCode = 400
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, receipt.ContentType)
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="`+receipt.FileName+`"`)
+	return c.Send(receipt.Content)
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"time"
 	"transaction-service/dto"
+	"transaction-service/money"
 	"transaction-service/services"
 
 	"github.com/gofiber/fiber/v2"
@@ -128,3 +129,110 @@ func (h *ReportingHandler) GetDashboardSummary(c *fiber.Ctx) error {
 		"data":    dashboard,
 	})
 }
+
+func (h *ReportingHandler) GetPaymentMethodSummary(c *fiber.Ctx) error {
+	filter := dto.ReportingFilterDTO{}
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		if startDate, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			filter.StartDate = &startDate
+		}
+	}
+
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		if endDate, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			filter.EndDate = &endDate
+		}
+	}
+
+	summaries, err := h.reportingService.GetPaymentMethodSummary(filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   true,
+			"message": "Failed to get payment method summary",
+			"data":    nil,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error":   false,
+		"message": "Payment method summary retrieved successfully",
+		"data":    summaries,
+		"count":   len(summaries),
+	})
+}
+
+func (h *ReportingHandler) GetTaxSummary(c *fiber.Ctx) error {
+	filter := dto.ReportingFilterDTO{}
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		if startDate, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			filter.StartDate = &startDate
+		}
+	}
+
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		if endDate, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			filter.EndDate = &endDate
+		}
+	}
+
+	summaries, err := h.reportingService.GetTaxSummary(filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   true,
+			"message": "Failed to get tax summary",
+			"data":    nil,
+		})
+	}
+
+	var totalTaxable, totalTax money.Money
+	for _, summary := range summaries {
+		totalTaxable += summary.NetTaxableAmount
+		totalTax += summary.NetTaxAmount
+	}
+
+	return c.JSON(fiber.Map{
+		"error":   false,
+		"message": "Tax summary retrieved successfully",
+		"data":    summaries,
+		"count":   len(summaries),
+		"totals": fiber.Map{
+			"net_taxable_amount": totalTaxable,
+			"net_tax_amount":     totalTax,
+		},
+	})
+}
+
+// GetCategorySalesReport menampilkan penjualan per kategori (termasuk subkategori), urut pohon kategori
+func (h *ReportingHandler) GetCategorySalesReport(c *fiber.Ctx) error {
+	filter := dto.ReportingFilterDTO{}
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		if startDate, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			filter.StartDate = &startDate
+		}
+	}
+
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		if endDate, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			filter.EndDate = &endDate
+		}
+	}
+
+	reports, err := h.reportingService.GetCategorySalesReport(filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   true,
+			"message": "Failed to get category sales report",
+			"data":    nil,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error":   false,
+		"message": "Category sales report retrieved successfully",
+		"data":    reports,
+		"count":   len(reports),
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"strings"
+	"transaction-service/dto"
+	"transaction-service/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TaxRateHandler struct {
+	service services.TaxRateService
+}
+
+func NewTaxRateHandler(service services.TaxRateService) *TaxRateHandler {
+	return &TaxRateHandler{service: service}
+}
+
+func (h *TaxRateHandler) GetAllTaxRates(c *fiber.Ctx) error {
+	rates, err := h.service.GetAllTaxRates()
+	if err != nil {
+		return c.Status(500).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Tax rates retrieved successfully",
+		Data:    rates,
+	})
+}
+
+func (h *TaxRateHandler) SetTaxRate(c *fiber.Ctx) error {
+	var req dto.TaxRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		errs := err.(validator.ValidationErrors)
+		var messages []string
+		for _, e := range errs {
+			switch e.Tag() {
+			case "required":
+				messages = append(messages, e.Field()+" is required")
+			case "min":
+				messages = append(messages, e.Field()+" must be at least "+e.Param())
+			case "max":
+				messages = append(messages, e.Field()+" must be at most "+e.Param())
+			}
+		}
+
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: strings.Join(messages, ", "),
+		})
+	}
+
+	rate, err := h.service.SetTaxRate(c.Params("class"), &req)
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "invalid") {
+			statusCode = 400
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Tax rate saved successfully",
+		Data:    rate,
+	})
+}
@@ -46,6 +46,14 @@ func (h *TransactionHandler) CreateTransaction(c *fiber.Ctx) error {
 				messages = append(messages, e.Field()+" is required")
 			case "gt":
 				messages = append(messages, e.Field()+" must be greater than "+e.Param())
+			case "gte":
+				messages = append(messages, e.Field()+" must be at least "+e.Param())
+			case "min":
+				messages = append(messages, e.Field()+" must have at least "+e.Param()+" entry")
+			case "oneof":
+				messages = append(messages, e.Field()+" must be one of "+e.Param())
+			case "max":
+				messages = append(messages, e.Field()+" must be at most "+e.Param()+" characters")
 			}
 		}
 
@@ -55,12 +63,18 @@ func (h *TransactionHandler) CreateTransaction(c *fiber.Ctx) error {
 		})
 	}
 
-	transaction, err := h.service.CreateTransaction(&req)
+	transaction, err := h.service.CreateTransaction(&req, currentOperator(c))
 	if err != nil {
 		statusCode := 500
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "insufficient stock") {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "insufficient stock") ||
+			strings.Contains(err.Error(), "payment") || strings.Contains(err.Error(), "discount") ||
+			strings.Contains(err.Error(), "voucher") || strings.Contains(err.Error(), "tax rate") ||
+			strings.Contains(err.Error(), "invalid") {
 			statusCode = 400
 		}
+		if strings.Contains(err.Error(), "exceeds the maximum") {
+			statusCode = 403
+		}
 
 		return c.Status(statusCode).JSON(dto.ApiResponse{
 			Success: false,
@@ -75,6 +89,72 @@ func (h *TransactionHandler) CreateTransaction(c *fiber.Ctx) error {
 	})
 }
 
+// PreviewPricing menghitung promosi, diskon dan total keranjang tanpa membuat transaksi
+func (h *TransactionHandler) PreviewPricing(c *fiber.Ctx) error {
+	var req dto.PricingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	if len(req.Items) == 0 {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Transaction items are required",
+		})
+	}
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		errs := err.(validator.ValidationErrors)
+		var messages []string
+		for _, e := range errs {
+			switch e.Tag() {
+			case "required":
+				messages = append(messages, e.Field()+" is required")
+			case "gt":
+				messages = append(messages, e.Field()+" must be greater than "+e.Param())
+			case "gte":
+				messages = append(messages, e.Field()+" must be at least "+e.Param())
+			case "oneof":
+				messages = append(messages, e.Field()+" must be one of "+e.Param())
+			case "max":
+				messages = append(messages, e.Field()+" must be at most "+e.Param()+" characters")
+			}
+		}
+
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: strings.Join(messages, ", "),
+		})
+	}
+
+	preview, err := h.service.PreviewPricing(&req, currentOperator(c))
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "insufficient stock") ||
+			strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "voucher") ||
+			strings.Contains(err.Error(), "tax rate") {
+			statusCode = 400
+		}
+		if strings.Contains(err.Error(), "exceeds the maximum") {
+			statusCode = 403
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Pricing preview calculated successfully",
+		Data:    preview,
+	})
+}
+
 func (h *TransactionHandler) GetAllTransactions(c *fiber.Ctx) error {
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
@@ -146,3 +226,157 @@ func (h *TransactionHandler) GetTransaction(c *fiber.Ctx) error {
 		Data:    transaction,
 	})
 }
+
+func (h *TransactionHandler) CreateReturn(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid transaction ID",
+		})
+	}
+
+	var req dto.CreateReturnRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		errs := err.(validator.ValidationErrors)
+		var messages []string
+		for _, e := range errs {
+			switch e.Tag() {
+			case "required":
+				messages = append(messages, e.Field()+" is required")
+			case "gt":
+				messages = append(messages, e.Field()+" must be greater than "+e.Param())
+			case "max":
+				messages = append(messages, e.Field()+" must be at most "+e.Param()+" characters")
+			}
+		}
+
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: strings.Join(messages, ", "),
+		})
+	}
+
+	ret, err := h.service.CreateReturn(uint(id), &req)
+	if err != nil {
+		statusCode := 400
+		if strings.Contains(err.Error(), "transaction not found") {
+			statusCode = 404
+		} else if strings.Contains(err.Error(), "still pending") {
+			statusCode = 409
+		} else if strings.HasPrefix(err.Error(), "failed to") {
+			statusCode = 500
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Return created successfully",
+		Data:    ret,
+	})
+}
+
+func (h *TransactionHandler) GetReturns(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid transaction ID",
+		})
+	}
+
+	returns, err := h.service.GetReturns(uint(id))
+	if err != nil {
+		statusCode := 500
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Returns retrieved successfully",
+		Data:    returns,
+	})
+}
+
+func (h *TransactionHandler) VoidTransaction(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid transaction ID",
+		})
+	}
+
+	userID, ok, err := requireUserID(c)
+	if !ok {
+		return err
+	}
+
+	var req dto.VoidTransactionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		errs := err.(validator.ValidationErrors)
+		var messages []string
+		for _, e := range errs {
+			switch e.Tag() {
+			case "required":
+				messages = append(messages, e.Field()+" is required")
+			case "max":
+				messages = append(messages, e.Field()+" must be at most "+e.Param()+" characters")
+			}
+		}
+
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: strings.Join(messages, ", "),
+		})
+	}
+
+	transaction, err := h.service.VoidTransaction(uint(id), userID, &req)
+	if err != nil {
+		statusCode := 409
+		if strings.Contains(err.Error(), "not found") {
+			statusCode = 404
+		} else if strings.HasPrefix(err.Error(), "failed to") {
+			statusCode = 500
+		}
+
+		return c.Status(statusCode).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Transaction voided successfully",
+		Data:    transaction,
+	})
+}
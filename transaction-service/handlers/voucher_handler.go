@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"strings"
+	"transaction-service/dto"
+	"transaction-service/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type VoucherHandler struct {
+	service services.VoucherService
+}
+
+func NewVoucherHandler(service services.VoucherService) *VoucherHandler {
+	return &VoucherHandler{service: service}
+}
+
+func (h *VoucherHandler) CreateVoucher(c *fiber.Ctx) error {
+	var req dto.CreateVoucherRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	if message := validateVoucherRequest(&req); message != "" {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: message,
+		})
+	}
+
+	voucher, err := h.service.CreateVoucher(&req)
+	if err != nil {
+		return c.Status(voucherErrorStatus(err)).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Voucher created successfully",
+		Data:    voucher,
+	})
+}
+
+func (h *VoucherHandler) CreateVoucherBatch(c *fiber.Ctx) error {
+	var req dto.CreateVoucherBatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	if message := validateVoucherRequest(&req); message != "" {
+		return c.Status(400).JSON(dto.ApiResponse{
+			Success: false,
+			Message: message,
+		})
+	}
+
+	batch, err := h.service.CreateVoucherBatch(&req)
+	if err != nil {
+		return c.Status(voucherErrorStatus(err)).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Voucher batch created successfully",
+		Data:    batch,
+	})
+}
+
+func (h *VoucherHandler) GetAllVouchers(c *fiber.Ctx) error {
+	vouchers, err := h.service.GetAllVouchers(c.Query("batch_code"))
+	if err != nil {
+		return c.Status(500).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Vouchers retrieved successfully",
+		Data:    vouchers,
+	})
+}
+
+func (h *VoucherHandler) GetVoucher(c *fiber.Ctx) error {
+	voucher, err := h.service.GetVoucherByCode(c.Params("code"))
+	if err != nil {
+		return c.Status(voucherErrorStatus(err)).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Voucher retrieved successfully",
+		Data:    voucher,
+	})
+}
+
+func (h *VoucherHandler) DeactivateVoucher(c *fiber.Ctx) error {
+	voucher, err := h.service.DeactivateVoucher(c.Params("code"))
+	if err != nil {
+		return c.Status(voucherErrorStatus(err)).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Voucher deactivated successfully",
+		Data:    voucher,
+	})
+}
+
+func (h *VoucherHandler) GetRedemptions(c *fiber.Ctx) error {
+	redemptions, err := h.service.GetRedemptions(c.Params("code"))
+	if err != nil {
+		return c.Status(voucherErrorStatus(err)).JSON(dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.ApiResponse{
+		Success: true,
+		Message: "Voucher redemptions retrieved successfully",
+		Data:    redemptions,
+	})
+}
+
+func voucherErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return 404
+	case strings.Contains(err.Error(), "already exists"):
+		return 409
+	case strings.HasPrefix(err.Error(), "failed to"):
+		return 500
+	case strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "must") ||
+		strings.Contains(err.Error(), "cannot") || strings.Contains(err.Error(), "only applies"):
+		return 400
+	default:
+		return 500
+	}
+}
+
+func validateVoucherRequest(req interface{}) string {
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		errs := err.(validator.ValidationErrors)
+		var messages []string
+		for _, e := range errs {
+			switch e.Tag() {
+			case "required":
+				messages = append(messages, e.Field()+" is required")
+			case "gt":
+				messages = append(messages, e.Field()+" must be greater than "+e.Param())
+			case "min":
+				messages = append(messages, e.Field()+" must be at least "+e.Param())
+			case "max":
+				messages = append(messages, e.Field()+" must be at most "+e.Param())
+			case "oneof":
+				messages = append(messages, e.Field()+" must be one of "+e.Param())
+			}
+		}
+		return strings.Join(messages, ", ")
+	}
+	return ""
+}
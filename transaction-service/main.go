@@ -25,6 +25,25 @@ func main() {
 	config.InitDatabase()
 	defer config.CloseDatabase()
 
+	// perintah CLI (migrate / seed) dijalankan lalu keluar tanpa menjalankan server
+	if len(os.Args) > 1 {
+		if err := runCommand(config.DB, os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// migrasi diterapkan saat start kecuali MIGRATE_ON_START=false (mis. jika dijalankan terpisah lewat "migrate")
+	if os.Getenv("MIGRATE_ON_START") != "false" {
+		if err := migrateUp(config.DB); err != nil {
+			log.Fatal("Failed to apply migrations: ", err)
+		}
+	}
+
+	// event domain dari outbox dikirim ke broker oleh relay, event produk memperbarui product_replicas
+	broker := routes.SetupEvents()
+	defer broker.Close()
+
 	app := fiber.New(fiber.Config{
 		ErrorHandler: func(ctx *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
@@ -41,9 +60,10 @@ func main() {
 	})
 
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
+		AllowOrigins:  "*",
+		AllowMethods:  "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
+		AllowHeaders:  "Origin,Content-Type,Accept,Authorization,Idempotency-Key,X-User-ID,X-User-Role",
+		ExposeHeaders: "Idempotent-Replayed",
 	}))
 	app.Use(logger.New(logger.Config{
 		Format: "[${ip}]:${port} ${status} - ${method} ${path} - ${latency}\n",
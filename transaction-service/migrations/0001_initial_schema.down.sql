@@ -0,0 +1,26 @@
+-- rollback 0001: menghapus seluruh skema transaction-service
+
+DROP FUNCTION IF EXISTS calculate_transaction_total(INTEGER);
+
+DROP VIEW IF EXISTS v_low_stock_alert;
+DROP VIEW IF EXISTS v_product_sales_report;
+DROP VIEW IF EXISTS v_transaction_summary;
+
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS product_replicas;
+DROP TABLE IF EXISTS invoice_sequences;
+DROP TABLE IF EXISTS cart_items;
+DROP TABLE IF EXISTS carts;
+DROP TABLE IF EXISTS business_days;
+DROP TABLE IF EXISTS transaction_return_items;
+DROP TABLE IF EXISTS transaction_returns;
+DROP TABLE IF EXISTS transaction_payments;
+DROP TABLE IF EXISTS voucher_redemptions;
+DROP TABLE IF EXISTS vouchers;
+DROP TABLE IF EXISTS transaction_item_promotions;
+DROP TABLE IF EXISTS promotions;
+DROP TABLE IF EXISTS transaction_items;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS tax_rates;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
@@ -0,0 +1,499 @@
+-- 0001 skema awal transaction-service: transaksi, pembayaran, retur, promosi, voucher, keranjang dan laporan.
+-- Memakai IF NOT EXISTS agar database lama yang dibuat dari migration.sql bisa diadopsi tanpa error.
+
+-- 1. BUAT TABEL
+
+-- tabel tax_rates (tarif pajak per kelas pajak produk, mis. PPN 11%)
+CREATE TABLE IF NOT EXISTS tax_rates (
+    tax_class VARCHAR(30) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    rate DECIMAL(5,2) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- tabel transactions
+CREATE TABLE IF NOT EXISTS transactions (
+    id SERIAL PRIMARY KEY,
+    invoice_number VARCHAR(100) NULL UNIQUE, -- nomor urut per toko & periode, diisi di DB transaction checkout
+    transaction_date TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    gross_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (gross_amount >= 0),
+    discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (discount_amount >= 0), -- promosi + diskon manual
+    promotion_discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (promotion_discount_amount >= 0),
+    cart_discount_type VARCHAR(20) NULL CHECK (cart_discount_type IN ('PERCENTAGE', 'FIXED')),
+    cart_discount_value DECIMAL(15,2) NULL,
+    cart_discount_amount DECIMAL(15,2) NULL,
+    customer_id VARCHAR(100) NULL,
+    voucher_code VARCHAR(50) NULL, -- snapshot kode voucher yang ditukarkan
+    voucher_discount_amount DECIMAL(15,2) NULL,
+    prices_include_tax BOOLEAN NOT NULL DEFAULT TRUE, -- harga termasuk pajak (pajak diekstrak) atau belum (pajak ditambahkan)
+    tax_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (tax_amount >= 0),
+    total_amount DECIMAL(15,2) NOT NULL CHECK (total_amount >= 0), -- jumlah yang dibayar, termasuk pajak
+    paid_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (paid_amount >= 0),
+    change_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (change_amount >= 0),
+    voided_at TIMESTAMP WITH TIME ZONE NULL,
+    voided_by VARCHAR(100) NULL,
+    void_reason VARCHAR(255) NULL,
+    stock_reservation_id INTEGER NULL, -- reservasi stok di product-service yang dikonfirmasi saat checkout
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- tabel transaction_items
+CREATE TABLE IF NOT EXISTS transaction_items (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL, -- id produk di product-service
+    product_name VARCHAR(100) NOT NULL, -- snapshot nama produk saat transaksi
+    product_sku VARCHAR(64) NULL,       -- snapshot SKU (jika ada)
+    unit_price DECIMAL(15,2) NOT NULL CHECK (unit_price >= 0), -- snapshot harga satuan
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    gross_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (gross_amount >= 0), -- unit_price * quantity
+    promotion_discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (promotion_discount_amount >= 0),
+    discount_type VARCHAR(20) NULL CHECK (discount_type IN ('PERCENTAGE', 'FIXED')),
+    discount_value DECIMAL(15,2) NULL,
+    line_discount_amount DECIMAL(15,2) NULL,
+    discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (discount_amount >= 0), -- promosi + diskon baris + alokasi diskon keranjang
+    subtotal DECIMAL(15,2) NOT NULL CHECK (subtotal >= 0), -- net setelah diskon
+    tax_class VARCHAR(30) NOT NULL DEFAULT 'EXEMPT', -- snapshot kelas pajak produk
+    tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0 CHECK (tax_rate >= 0), -- snapshot tarif pajak (persen)
+    taxable_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (taxable_amount >= 0), -- DPP
+    tax_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (tax_amount >= 0),
+    total_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (total_amount >= 0), -- subtotal, ditambah pajak jika harga belum termasuk pajak
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_transaction_items_transaction_id 
+        FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+);
+
+-- tabel promotions (aturan promosi otomatis, rule disimpan sebagai JSONB sesuai type)
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('BUY_X_GET_Y', 'BUNDLE_PRICE', 'PERCENT_OFF')),
+    rule JSONB NOT NULL DEFAULT '{}',
+    priority INTEGER NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    starts_at TIMESTAMP WITH TIME ZONE NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NULL,
+    days_of_week INTEGER[] NOT NULL DEFAULT '{}', -- 0 = Minggu ... 6 = Sabtu, kosong = setiap hari
+    start_time TIME NULL, -- jam berlaku harian, NULL = sepanjang hari
+    end_time TIME NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- tabel transaction_item_promotions (promosi yang dipakai per baris transaksi)
+CREATE TABLE IF NOT EXISTS transaction_item_promotions (
+    id SERIAL PRIMARY KEY,
+    transaction_item_id INTEGER NOT NULL,
+    promotion_id INTEGER NOT NULL,
+    promotion_name VARCHAR(100) NOT NULL, -- snapshot nama promosi
+    discount_amount DECIMAL(15,2) NOT NULL CHECK (discount_amount >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_transaction_item_promotions_transaction_item_id
+        FOREIGN KEY (transaction_item_id) REFERENCES transaction_items(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transaction_item_promotions_promotion_id
+        FOREIGN KEY (promotion_id) REFERENCES promotions(id) ON DELETE RESTRICT
+);
+
+-- tabel vouchers (kode voucher/kupon, dibuat satuan atau per batch)
+CREATE TABLE IF NOT EXISTS vouchers (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    batch_code VARCHAR(50) NULL,
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('PERCENTAGE', 'FIXED')),
+    value DECIMAL(15,2) NOT NULL CHECK (value > 0),
+    max_discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (max_discount_amount >= 0), -- 0 = tanpa batas
+    min_spend DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (min_spend >= 0),
+    expires_at TIMESTAMP WITH TIME ZONE NULL,
+    usage_limit INTEGER NOT NULL DEFAULT 1 CHECK (usage_limit >= 0), -- 0 = tanpa batas
+    per_customer_limit INTEGER NOT NULL DEFAULT 0 CHECK (per_customer_limit >= 0), -- 0 = tanpa batas
+    used_count INTEGER NOT NULL DEFAULT 0 CHECK (used_count >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- tabel voucher_redemptions (pemakaian voucher per transaksi, released_at diisi saat transaksi di-void)
+CREATE TABLE IF NOT EXISTS voucher_redemptions (
+    id SERIAL PRIMARY KEY,
+    voucher_id INTEGER NOT NULL,
+    transaction_id INTEGER NOT NULL,
+    customer_id VARCHAR(100) NULL,
+    discount_amount DECIMAL(15,2) NOT NULL CHECK (discount_amount >= 0),
+    redeemed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP WITH TIME ZONE NULL,
+    CONSTRAINT fk_voucher_redemptions_voucher_id
+        FOREIGN KEY (voucher_id) REFERENCES vouchers(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_voucher_redemptions_transaction_id
+        FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+);
+
+-- tabel transaction_payments (satu atau lebih tender per transaksi)
+CREATE TABLE IF NOT EXISTS transaction_payments (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL,
+    method VARCHAR(20) NOT NULL
+        CHECK (method IN ('CASH', 'DEBIT_CARD', 'CREDIT_CARD', 'QRIS', 'E_WALLET', 'BANK_TRANSFER')),
+    tendered_amount DECIMAL(15,2) NOT NULL CHECK (tendered_amount > 0),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount >= 0 AND amount <= tendered_amount),
+    reference VARCHAR(100) NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_transaction_payments_transaction_id
+        FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+);
+
+-- tabel transaction_returns (retur / refund atas transaksi)
+CREATE TABLE IF NOT EXISTS transaction_returns (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL,
+    reason_code VARCHAR(30) NOT NULL,
+    reason_note VARCHAR(255) NULL,
+    total_amount DECIMAL(15,2) NOT NULL CHECK (total_amount >= 0),
+    return_date TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_transaction_returns_transaction_id
+        FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+
+-- tabel transaction_return_items
+CREATE TABLE IF NOT EXISTS transaction_return_items (
+    id SERIAL PRIMARY KEY,
+    return_id INTEGER NOT NULL,
+    transaction_item_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    product_name VARCHAR(100) NOT NULL,
+    unit_price DECIMAL(15,2) NOT NULL CHECK (unit_price >= 0),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount >= 0),
+    tax_amount DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (tax_amount >= 0), -- bagian pajak dari amount
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_transaction_return_items_return_id
+        FOREIGN KEY (return_id) REFERENCES transaction_returns(id) ON DELETE CASCADE,
+    CONSTRAINT fk_transaction_return_items_transaction_item_id
+        FOREIGN KEY (transaction_item_id) REFERENCES transaction_items(id) ON DELETE RESTRICT
+);
+
+-- tabel business_days (hari usaha yang sudah ditutup, transaksinya tidak bisa di-void)
+CREATE TABLE IF NOT EXISTS business_days (
+    business_date DATE PRIMARY KEY,
+    closed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_by VARCHAR(100) NOT NULL
+);
+
+-- tabel carts (keranjang draft per terminal, kedaluwarsa setelah CART_TTL tanpa perubahan)
+CREATE TABLE IF NOT EXISTS carts (
+    id SERIAL PRIMARY KEY,
+    terminal_id VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'PARKED', 'CHECKING_OUT', 'CHECKED_OUT')),
+    label VARCHAR(100) NULL,
+    customer_id VARCHAR(100) NULL,
+    discount_type VARCHAR(20) NULL CHECK (discount_type IN ('PERCENTAGE', 'FIXED')),
+    discount_value DECIMAL(15,2) NULL,
+    voucher_code VARCHAR(50) NULL,
+    created_by VARCHAR(100) NULL,
+    transaction_id INTEGER NULL,
+    parked_at TIMESTAMP WITH TIME ZONE NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_carts_transaction_id
+        FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL
+);
+
+-- tabel cart_items (baris keranjang, harga dihitung saat checkout)
+CREATE TABLE IF NOT EXISTS cart_items (
+    id SERIAL PRIMARY KEY,
+    cart_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    discount_type VARCHAR(20) NULL CHECK (discount_type IN ('PERCENTAGE', 'FIXED')),
+    discount_value DECIMAL(15,2) NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_cart_items_cart_id
+        FOREIGN KEY (cart_id) REFERENCES carts(id) ON DELETE CASCADE
+);
+
+-- tabel invoice_sequences (nomor urut terakhir per sequence key, mis. INV/STORE01/20261017/{SEQ:4})
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    sequence_key VARCHAR(150) PRIMARY KEY,
+    last_number BIGINT NOT NULL CHECK (last_number > 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- tabel product_replicas (salinan data produk dari product-service untuk laporan, disinkronkan berkala)
+CREATE TABLE IF NOT EXISTS product_replicas (
+    product_id INTEGER PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    price DECIMAL(15,2) NOT NULL DEFAULT 0,
+    stock INTEGER NOT NULL DEFAULT 0,
+    reserved_stock INTEGER NOT NULL DEFAULT 0,
+    available_stock INTEGER NOT NULL DEFAULT 0,
+    tax_class VARCHAR(30) NOT NULL DEFAULT 'STANDARD',
+    deleted_at TIMESTAMP WITH TIME ZONE NULL, -- produk sudah tidak ada di product-service
+    synced_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- tabel idempotency_keys (response POST /api/transactions yang disimpan untuk retry)
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key VARCHAR(255) PRIMARY KEY,
+    request_hash CHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PROCESSING',
+    response_status INTEGER NULL,
+    response_body BYTEA NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+
+-- 2. BUAT INDEXES
+
+-- Index untuk transaksi
+CREATE INDEX IF NOT EXISTS idx_transactions_transaction_date ON transactions(transaction_date);
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions(created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_voided_at ON transactions(voided_at);
+
+-- Index untuk item_transaksi
+CREATE INDEX IF NOT EXISTS idx_transaction_items_transaction_id ON transaction_items(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_items_product_id ON transaction_items(product_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_items_created_at ON transaction_items(created_at);
+
+-- Index untuk pembayaran
+CREATE INDEX IF NOT EXISTS idx_transaction_payments_transaction_id ON transaction_payments(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_payments_method ON transaction_payments(method);
+
+-- Index untuk retur
+CREATE INDEX IF NOT EXISTS idx_transaction_returns_transaction_id ON transaction_returns(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_returns_return_date ON transaction_returns(return_date);
+CREATE INDEX IF NOT EXISTS idx_transaction_return_items_return_id ON transaction_return_items(return_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_return_items_transaction_item_id ON transaction_return_items(transaction_item_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_return_items_product_id ON transaction_return_items(product_id);
+
+-- Index untuk promosi
+CREATE INDEX IF NOT EXISTS idx_promotions_active ON promotions(active) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_transaction_item_promotions_transaction_item_id ON transaction_item_promotions(transaction_item_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_item_promotions_promotion_id ON transaction_item_promotions(promotion_id);
+
+-- Index untuk voucher
+CREATE INDEX IF NOT EXISTS idx_vouchers_batch_code ON vouchers(batch_code);
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_voucher_customer ON voucher_redemptions(voucher_id, customer_id);
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_transaction_id ON voucher_redemptions(transaction_id);
+
+-- Index untuk keranjang
+CREATE INDEX IF NOT EXISTS idx_carts_terminal_status ON carts(terminal_id, status);
+CREATE INDEX IF NOT EXISTS idx_carts_expires_at ON carts(expires_at);
+CREATE INDEX IF NOT EXISTS idx_cart_items_cart_id ON cart_items(cart_id);
+
+-- Index untuk salinan produk
+CREATE INDEX IF NOT EXISTS idx_product_replicas_deleted_at ON product_replicas(deleted_at);
+
+-- Index untuk idempotency_keys
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+
+-- 3. CREATE TRIGGERS FOR UPDATED_AT
+
+-- Function untuk mengupdate kolom updated_at
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Triggers untuk transaksi
+DROP TRIGGER IF EXISTS trigger_transactions_updated_at ON transactions;
+CREATE TRIGGER trigger_transactions_updated_at
+    BEFORE UPDATE ON transactions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Triggers for transaction_items
+DROP TRIGGER IF EXISTS trigger_item_transactions_updated_at ON transaction_items;
+CREATE TRIGGER trigger_item_transactions_updated_at
+    BEFORE UPDATE ON transaction_items
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Triggers for transaction_payments
+DROP TRIGGER IF EXISTS trigger_transaction_payments_updated_at ON transaction_payments;
+CREATE TRIGGER trigger_transaction_payments_updated_at
+    BEFORE UPDATE ON transaction_payments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Triggers for retur
+DROP TRIGGER IF EXISTS trigger_transaction_returns_updated_at ON transaction_returns;
+CREATE TRIGGER trigger_transaction_returns_updated_at
+    BEFORE UPDATE ON transaction_returns
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS trigger_transaction_return_items_updated_at ON transaction_return_items;
+CREATE TRIGGER trigger_transaction_return_items_updated_at
+    BEFORE UPDATE ON transaction_return_items
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Triggers for promotions
+DROP TRIGGER IF EXISTS trigger_promotions_updated_at ON promotions;
+CREATE TRIGGER trigger_promotions_updated_at
+    BEFORE UPDATE ON promotions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Triggers for vouchers
+DROP TRIGGER IF EXISTS trigger_vouchers_updated_at ON vouchers;
+CREATE TRIGGER trigger_vouchers_updated_at
+    BEFORE UPDATE ON vouchers
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Triggers for carts
+DROP TRIGGER IF EXISTS trigger_carts_updated_at ON carts;
+CREATE TRIGGER trigger_carts_updated_at
+    BEFORE UPDATE ON carts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP TRIGGER IF EXISTS trigger_cart_items_updated_at ON cart_items;
+CREATE TRIGGER trigger_cart_items_updated_at
+    BEFORE UPDATE ON cart_items
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Triggers for tax_rates
+DROP TRIGGER IF EXISTS trigger_tax_rates_updated_at ON tax_rates;
+CREATE TRIGGER trigger_tax_rates_updated_at
+    BEFORE UPDATE ON tax_rates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+
+-- 4. DATA REFERENSI
+
+-- tarif pajak awal (data referensi, bukan data contoh), tarif bisa diubah lewat PUT /api/tax-rates/:class
+INSERT INTO tax_rates (tax_class, name, rate) VALUES
+('STANDARD', 'PPN 11%', 11.00),
+('EXEMPT', 'Bebas PPN', 0.00)
+ON CONFLICT (tax_class) DO NOTHING;
+
+
+-- 5. CREATE VIEWS FOR REPORTING
+
+-- View untuk summary transaksi (refund tercatat sebagai revenue negatif, transaksi void bernilai net 0)
+DROP VIEW IF EXISTS v_transaction_summary;
+CREATE VIEW v_transaction_summary AS
+SELECT 
+    t.id,
+    t.transaction_date,
+    t.gross_amount,
+    t.discount_amount,
+    t.tax_amount,
+    t.total_amount,
+    COUNT(ti.id) as total_items,
+    SUM(ti.quantity) as total_quantity,
+    COALESCE(r.refunded_amount, 0) as refunded_amount,
+    CASE
+        WHEN t.voided_at IS NOT NULL THEN 0
+        ELSE t.total_amount - COALESCE(r.refunded_amount, 0)
+    END as net_amount,
+    CASE WHEN t.voided_at IS NOT NULL THEN 'VOIDED' ELSE 'COMPLETED' END as status
+FROM transactions t
+LEFT JOIN transaction_items ti ON t.id = ti.transaction_id
+LEFT JOIN (
+    SELECT transaction_id, SUM(total_amount) as refunded_amount
+    FROM transaction_returns
+    GROUP BY transaction_id
+) r ON r.transaction_id = t.id
+WHERE t.deleted_at IS NULL
+GROUP BY t.id, t.transaction_date, t.gross_amount, t.discount_amount, t.tax_amount, t.total_amount, t.voided_at, r.refunded_amount
+ORDER BY t.transaction_date DESC;
+
+-- View untuk laporan penjualan produk (total_revenue net setelah diskon dan refund, transaksi void dihitung terpisah).
+-- Nama, harga dan stok produk diambil dari product_replicas, bukan dari database product-service.
+DROP VIEW IF EXISTS v_product_sales_report;
+CREATE VIEW v_product_sales_report AS
+SELECT 
+    p.product_id as id,
+    p.name as product_name,
+    p.price as current_price,
+    p.stock as current_stock,
+    COALESCE(s.total_sold, 0) as total_sold,
+    COALESCE(s.total_discount, 0) as total_discount,
+    COALESCE(ri.total_returned, 0) as total_returned,
+    COALESCE(ri.total_refunded, 0) as total_refunded,
+    COALESCE(s.gross_revenue, 0) - COALESCE(ri.total_refunded, 0) as total_revenue,
+    COALESCE(s.total_voided, 0) as total_voided
+FROM product_replicas p
+LEFT JOIN (
+    SELECT 
+        ti.product_id,
+        SUM(ti.quantity) FILTER (WHERE t.voided_at IS NULL) as total_sold,
+        SUM(ti.total_amount) FILTER (WHERE t.voided_at IS NULL) as gross_revenue,
+        SUM(ti.discount_amount) FILTER (WHERE t.voided_at IS NULL) as total_discount,
+        SUM(ti.quantity) FILTER (WHERE t.voided_at IS NOT NULL) as total_voided
+    FROM transaction_items ti
+    JOIN transactions t ON t.id = ti.transaction_id
+    WHERE t.deleted_at IS NULL
+    GROUP BY ti.product_id
+) s ON s.product_id = p.product_id
+LEFT JOIN (
+    SELECT product_id, SUM(quantity) as total_returned, SUM(amount) as total_refunded
+    FROM transaction_return_items
+    GROUP BY product_id
+) ri ON ri.product_id = p.product_id
+WHERE p.deleted_at IS NULL
+ORDER BY total_sold DESC;
+
+-- View untuk alert stok rendah (stok tersedia = stok - reservasi aktif, per sinkronisasi terakhir dari product-service)
+DROP VIEW IF EXISTS v_low_stock_alert;
+CREATE VIEW v_low_stock_alert AS
+SELECT 
+    product_id as id,
+    name,
+    price,
+    stock,
+    reserved_stock,
+    available_stock,
+    CASE 
+        WHEN available_stock = 0 THEN 'OUT_OF_STOCK'
+        WHEN available_stock <= 5 THEN 'LOW_STOCK'
+        WHEN available_stock <= 10 THEN 'WARNING'
+        ELSE 'NORMAL'
+    END as stock_status
+FROM product_replicas
+WHERE deleted_at IS NULL 
+    AND available_stock <= 10
+ORDER BY available_stock ASC;
+
+
+-- 6. CREATE STORED PROCEDURES/FUNCTIONS
+
+-- Function untuk menghitung total transaksi
+CREATE OR REPLACE FUNCTION calculate_transaction_total(p_transaction_id INTEGER)
+RETURNS DECIMAL(15,2) AS $$
+DECLARE
+    total_amount DECIMAL(15,2);
+BEGIN
+    SELECT COALESCE(SUM(subtotal), 0) 
+    INTO total_amount
+    FROM transaction_items 
+    WHERE transaction_id = p_transaction_id;
+
+    RETURN total_amount;
+END;
+$$ LANGUAGE plpgsql;
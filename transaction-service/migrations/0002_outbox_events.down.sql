@@ -0,0 +1,4 @@
+-- rollback 0002: menghapus tabel outbox dan deduplikasi event
+
+DROP TABLE IF EXISTS processed_events;
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,98 @@
+-- rollback 0003: jumlah pecahan dibulatkan (quantity baris ke atas, stok salinan ke bawah)
+
+DROP VIEW IF EXISTS v_low_stock_alert;
+DROP VIEW IF EXISTS v_product_sales_report;
+DROP VIEW IF EXISTS v_transaction_summary;
+
+ALTER TABLE product_replicas ALTER COLUMN available_stock TYPE INTEGER USING FLOOR(available_stock);
+ALTER TABLE product_replicas ALTER COLUMN reserved_stock TYPE INTEGER USING CEIL(reserved_stock);
+ALTER TABLE product_replicas ALTER COLUMN stock TYPE INTEGER USING FLOOR(stock);
+ALTER TABLE cart_items ALTER COLUMN quantity TYPE INTEGER USING CEIL(quantity);
+ALTER TABLE transaction_return_items ALTER COLUMN quantity TYPE INTEGER USING CEIL(quantity);
+ALTER TABLE transaction_items ALTER COLUMN quantity TYPE INTEGER USING CEIL(quantity);
+
+-- View untuk summary transaksi (refund tercatat sebagai revenue negatif, transaksi void bernilai net 0)
+DROP VIEW IF EXISTS v_transaction_summary;
+CREATE VIEW v_transaction_summary AS
+SELECT 
+    t.id,
+    t.transaction_date,
+    t.gross_amount,
+    t.discount_amount,
+    t.tax_amount,
+    t.total_amount,
+    COUNT(ti.id) as total_items,
+    SUM(ti.quantity) as total_quantity,
+    COALESCE(r.refunded_amount, 0) as refunded_amount,
+    CASE
+        WHEN t.voided_at IS NOT NULL THEN 0
+        ELSE t.total_amount - COALESCE(r.refunded_amount, 0)
+    END as net_amount,
+    CASE WHEN t.voided_at IS NOT NULL THEN 'VOIDED' ELSE 'COMPLETED' END as status
+FROM transactions t
+LEFT JOIN transaction_items ti ON t.id = ti.transaction_id
+LEFT JOIN (
+    SELECT transaction_id, SUM(total_amount) as refunded_amount
+    FROM transaction_returns
+    GROUP BY transaction_id
+) r ON r.transaction_id = t.id
+WHERE t.deleted_at IS NULL
+GROUP BY t.id, t.transaction_date, t.gross_amount, t.discount_amount, t.tax_amount, t.total_amount, t.voided_at, r.refunded_amount
+ORDER BY t.transaction_date DESC;
+
+-- View untuk laporan penjualan produk (total_revenue net setelah diskon dan refund, transaksi void dihitung terpisah).
+-- Nama, harga dan stok produk diambil dari product_replicas, bukan dari database product-service.
+DROP VIEW IF EXISTS v_product_sales_report;
+CREATE VIEW v_product_sales_report AS
+SELECT 
+    p.product_id as id,
+    p.name as product_name,
+    p.price as current_price,
+    p.stock as current_stock,
+    COALESCE(s.total_sold, 0) as total_sold,
+    COALESCE(s.total_discount, 0) as total_discount,
+    COALESCE(ri.total_returned, 0) as total_returned,
+    COALESCE(ri.total_refunded, 0) as total_refunded,
+    COALESCE(s.gross_revenue, 0) - COALESCE(ri.total_refunded, 0) as total_revenue,
+    COALESCE(s.total_voided, 0) as total_voided
+FROM product_replicas p
+LEFT JOIN (
+    SELECT 
+        ti.product_id,
+        SUM(ti.quantity) FILTER (WHERE t.voided_at IS NULL) as total_sold,
+        SUM(ti.total_amount) FILTER (WHERE t.voided_at IS NULL) as gross_revenue,
+        SUM(ti.discount_amount) FILTER (WHERE t.voided_at IS NULL) as total_discount,
+        SUM(ti.quantity) FILTER (WHERE t.voided_at IS NOT NULL) as total_voided
+    FROM transaction_items ti
+    JOIN transactions t ON t.id = ti.transaction_id
+    WHERE t.deleted_at IS NULL
+    GROUP BY ti.product_id
+) s ON s.product_id = p.product_id
+LEFT JOIN (
+    SELECT product_id, SUM(quantity) as total_returned, SUM(amount) as total_refunded
+    FROM transaction_return_items
+    GROUP BY product_id
+) ri ON ri.product_id = p.product_id
+WHERE p.deleted_at IS NULL
+ORDER BY total_sold DESC;
+
+-- View untuk alert stok rendah (stok tersedia = stok - reservasi aktif, per sinkronisasi terakhir dari product-service)
+DROP VIEW IF EXISTS v_low_stock_alert;
+CREATE VIEW v_low_stock_alert AS
+SELECT 
+    product_id as id,
+    name,
+    price,
+    stock,
+    reserved_stock,
+    available_stock,
+    CASE 
+        WHEN available_stock = 0 THEN 'OUT_OF_STOCK'
+        WHEN available_stock <= 5 THEN 'LOW_STOCK'
+        WHEN available_stock <= 10 THEN 'WARNING'
+        ELSE 'NORMAL'
+    END as stock_status
+FROM product_replicas
+WHERE deleted_at IS NULL 
+    AND available_stock <= 10
+ORDER BY available_stock ASC;
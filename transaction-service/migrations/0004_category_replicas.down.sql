@@ -0,0 +1,5 @@
+-- rollback 0004: menghapus salinan kategori
+
+DROP INDEX IF EXISTS idx_product_replicas_category_id;
+ALTER TABLE product_replicas DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS category_replicas;
@@ -0,0 +1,17 @@
+-- 0004 salinan kategori produk dari product-service untuk laporan penjualan per kategori
+
+-- tabel category_replicas (pohon kategori; parent_id tanpa foreign key karena event bisa datang tidak berurutan)
+CREATE TABLE IF NOT EXISTS category_replicas (
+    category_id INTEGER PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    parent_id INTEGER NULL,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    deleted_at TIMESTAMP WITH TIME ZONE NULL, -- kategori sudah dihapus di product-service
+    synced_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_replicas_parent_id ON category_replicas(parent_id);
+
+-- kategori produk saat ini; penjualan dilaporkan menurut kategori terakhir produk
+ALTER TABLE product_replicas ADD COLUMN IF NOT EXISTS category_id INTEGER NULL;
+CREATE INDEX IF NOT EXISTS idx_product_replicas_category_id ON product_replicas(category_id);
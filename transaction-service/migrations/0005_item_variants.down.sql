@@ -0,0 +1,7 @@
+-- rollback 0005: menghapus varian dari baris transaksi dan keranjang
+
+ALTER TABLE cart_items DROP COLUMN IF EXISTS variant_id;
+
+DROP INDEX IF EXISTS idx_transaction_items_product_variant;
+ALTER TABLE transaction_items DROP COLUMN IF EXISTS variant_name;
+ALTER TABLE transaction_items DROP COLUMN IF EXISTS variant_id;
@@ -0,0 +1,7 @@
+-- 0005 varian produk: baris transaksi dan keranjang bisa menunjuk satu varian (id varian di product-service)
+
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS variant_id INTEGER NULL;
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS variant_name VARCHAR(160) NULL; -- snapshot judul varian, mis. "M / Black"
+CREATE INDEX IF NOT EXISTS idx_transaction_items_product_variant ON transaction_items(product_id, variant_id);
+
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS variant_id INTEGER NULL;
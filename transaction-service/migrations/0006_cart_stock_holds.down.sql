@@ -0,0 +1,3 @@
+-- rollback 0006: keranjang tidak lagi menyimpan reservasi stok
+
+ALTER TABLE carts DROP COLUMN IF EXISTS stock_reservation_id;
@@ -0,0 +1,4 @@
+-- 0006 keranjang menahan stok: reservasi product-service dengan reference cart:<id>, diperbarui setiap baris
+-- keranjang berubah dan dikonfirmasi saat checkout
+
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS stock_reservation_id INTEGER NULL;
@@ -0,0 +1,4 @@
+-- rollback 0007: menghapus penanda rekonsiliasi stok
+
+DROP INDEX IF EXISTS idx_transactions_stock_pending;
+ALTER TABLE transactions DROP COLUMN IF EXISTS stock_pending_at;
@@ -0,0 +1,5 @@
+-- 0007 rekonsiliasi stok: transaksi yang hasil konfirmasi reservasinya tidak diketahui (timeout, 5xx)
+-- ditandai lalu konfirmasinya diulang di latar belakang sampai pasti CONFIRMED atau dilepas (transaksi di-void)
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS stock_pending_at TIMESTAMP WITH TIME ZONE NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_stock_pending ON transactions(stock_pending_at) WHERE stock_pending_at IS NOT NULL;
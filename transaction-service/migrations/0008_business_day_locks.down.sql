@@ -0,0 +1,6 @@
+-- rollback 0008: menghapus baris hari yang belum ditutup dan mewajibkan lagi closed_at/closed_by
+
+DELETE FROM business_days WHERE closed_at IS NULL;
+ALTER TABLE business_days ALTER COLUMN closed_by SET NOT NULL;
+ALTER TABLE business_days ALTER COLUMN closed_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE business_days ALTER COLUMN closed_at SET NOT NULL;
@@ -0,0 +1,6 @@
+-- 0008 baris business_days juga dibuat untuk hari yang belum ditutup (closed_at NULL) agar void dan
+-- penutupan hari mengunci baris yang sama: void memakai FOR SHARE, penutupan memakai FOR UPDATE
+
+ALTER TABLE business_days ALTER COLUMN closed_at DROP NOT NULL;
+ALTER TABLE business_days ALTER COLUMN closed_at DROP DEFAULT;
+ALTER TABLE business_days ALTER COLUMN closed_by DROP NOT NULL;
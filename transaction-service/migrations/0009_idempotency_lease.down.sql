@@ -0,0 +1,3 @@
+-- rollback 0009: menghapus lease idempotency key
+
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS locked_until;
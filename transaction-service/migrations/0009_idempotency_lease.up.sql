@@ -0,0 +1,4 @@
+-- 0009 lease untuk key yang masih PROCESSING: jika proses yang memegang key mati sebelum menyimpan response,
+-- retry dengan key dan body yang sama boleh mengambil alih key setelah locked_until lewat
+
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE NULL;
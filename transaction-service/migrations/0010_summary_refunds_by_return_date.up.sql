@@ -0,0 +1,43 @@
+-- 0010 v_transaction_summary menjadi buku besar: satu baris per penjualan pada tanggal transaksi dan satu baris
+-- per retur pada tanggal retur, sehingga filter tanggal menghitung refund di periode returnya, bukan periode jualnya
+
+DROP VIEW IF EXISTS v_transaction_summary;
+CREATE VIEW v_transaction_summary AS
+SELECT
+    t.id,
+    NULL::INTEGER as return_id,
+    t.transaction_date,
+    t.gross_amount,
+    t.discount_amount,
+    t.tax_amount,
+    t.total_amount,
+    COUNT(ti.id) as total_items,
+    COALESCE(SUM(ti.quantity), 0) as total_quantity,
+    0::DECIMAL(15,2) as refunded_amount,
+    CASE WHEN t.voided_at IS NOT NULL THEN 0 ELSE t.total_amount END as net_amount,
+    CASE WHEN t.voided_at IS NOT NULL THEN 'VOIDED' ELSE 'COMPLETED' END as status
+FROM transactions t
+LEFT JOIN transaction_items ti ON t.id = ti.transaction_id
+WHERE t.deleted_at IS NULL
+GROUP BY t.id, t.transaction_date, t.gross_amount, t.discount_amount, t.tax_amount, t.total_amount, t.voided_at
+UNION ALL
+-- retur tercatat sebagai revenue negatif pada tanggal retur
+SELECT
+    t.id,
+    tr.id as return_id,
+    tr.return_date as transaction_date,
+    0 as gross_amount,
+    0 as discount_amount,
+    0 as tax_amount,
+    0 as total_amount,
+    COUNT(ri.id) as total_items,
+    COALESCE(SUM(ri.quantity), 0) as total_quantity,
+    tr.total_amount as refunded_amount,
+    -tr.total_amount as net_amount,
+    'RETURNED' as status
+FROM transaction_returns tr
+JOIN transactions t ON t.id = tr.transaction_id
+LEFT JOIN transaction_return_items ri ON ri.return_id = tr.id
+WHERE t.deleted_at IS NULL
+GROUP BY t.id, tr.id, tr.return_date, tr.total_amount
+ORDER BY transaction_date DESC;
@@ -0,0 +1,3 @@
+-- rollback 0011: menghapus penanda baris timbangan
+
+ALTER TABLE transaction_items DROP COLUMN IF EXISTS sold_by_weight;
@@ -0,0 +1,5 @@
+-- 0011 baris transaksi mencatat apakah quantity-nya berasal dari label timbangan: hanya baris itu yang boleh
+-- diretur dalam pecahan. Baris lama dengan quantity pecahan pasti berasal dari label timbangan.
+
+ALTER TABLE transaction_items ADD COLUMN IF NOT EXISTS sold_by_weight BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE transaction_items SET sold_by_weight = TRUE WHERE quantity <> TRUNC(quantity);
@@ -0,0 +1,4 @@
+-- rollback 0012: menghapus penanda restock retur
+
+DROP INDEX IF EXISTS idx_transaction_returns_restock_pending;
+ALTER TABLE transaction_returns DROP COLUMN IF EXISTS restock_pending_at;
@@ -0,0 +1,5 @@
+-- 0012 retur disimpan sebelum stoknya dikembalikan ke product-service. Retur yang restock-nya belum berhasil
+-- ditandai lalu restock-nya diulang di latar belakang dengan reference yang sama sampai berhasil.
+
+ALTER TABLE transaction_returns ADD COLUMN IF NOT EXISTS restock_pending_at TIMESTAMP WITH TIME ZONE NULL;
+CREATE INDEX IF NOT EXISTS idx_transaction_returns_restock_pending ON transaction_returns(restock_pending_at) WHERE restock_pending_at IS NOT NULL;
@@ -0,0 +1,3 @@
+-- rollback 0013: menghapus token lease idempotency key
+
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS lease_token;
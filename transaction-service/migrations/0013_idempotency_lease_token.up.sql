@@ -0,0 +1,4 @@
+-- 0013 setiap klaim key PROCESSING (baru atau ambil alih) mendapat lease_token baru. Complete dan Release hanya
+-- berlaku untuk pemegang token, sehingga request yang lease-nya sudah diambil alih tidak menimpa atau menghapus key.
+
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS lease_token UUID NULL;
@@ -0,0 +1,60 @@
+-- data contoh transaction-service, dijalankan lewat perintah "seed" hanya jika tabel transactions masih kosong
+
+-- salinan kategori dummy, sama dengan seed product-service
+INSERT INTO category_replicas (category_id, name, parent_id, sort_order) VALUES
+(1, 'Electronics', NULL, 1),
+(2, 'Computers', 1, 1),
+(3, 'Peripherals', 2, 1),
+(4, 'Storage', 2, 2),
+(5, 'Audio', 1, 2),
+(6, 'Fresh Food', NULL, 2),
+(7, 'Meat', 6, 1),
+(8, 'Fruit', 6, 2)
+ON CONFLICT (category_id) DO NOTHING;
+
+-- salinan produk dummy (stok setelah transaksi dummy), dilewati jika sinkronisasi dari product-service sudah berjalan
+INSERT INTO product_replicas (product_id, name, price, stock, reserved_stock, available_stock, tax_class, category_id) VALUES
+(1, 'Laptop Dell Inspiron 15', 8500000.00, 4, 0, 4, 'STANDARD', 2),
+(2, 'Mouse Wireless Logitech', 250000.00, 23, 0, 23, 'STANDARD', 3),
+(3, 'Keyboard Mechanical RGB', 750000.00, 14, 0, 14, 'STANDARD', 3),
+(4, 'Monitor LED 24 inch', 2200000.00, 7, 0, 7, 'STANDARD', 3),
+(5, 'Headset Gaming', 450000.00, 11, 0, 11, 'STANDARD', 5),
+(6, 'Webcam HD 1080p', 350000.00, 20, 0, 20, 'STANDARD', 3),
+(7, 'Speaker Bluetooth', 180000.00, 30, 0, 30, 'STANDARD', 5),
+(8, 'Hard Drive External 1TB', 650000.00, 10, 0, 10, 'STANDARD', 4),
+(9, 'USB Flash Drive 32GB', 75000.00, 48, 0, 48, 'STANDARD', 4),
+(10, 'Power Bank 10000mAh', 150000.00, 40, 0, 40, 'STANDARD', 1)
+ON CONFLICT (product_id) DO NOTHING;
+
+-- transaksi dummy data (harga sudah termasuk PPN 11%)
+INSERT INTO transactions (invoice_number, transaction_date, gross_amount, tax_amount, total_amount, paid_amount, change_amount) VALUES
+('INV/STORE01/20240115/0001', '2024-01-15 10:30:00', 8750000.00, 867117.11, 8750000.00, 8750000.00, 0),
+('INV/STORE01/20240115/0002', '2024-01-15 14:45:00', 3200000.00, 317117.11, 3200000.00, 3200000.00, 0),
+('INV/STORE01/20240116/0001', '2024-01-16 09:15:00', 600000.00, 59459.45, 600000.00, 650000.00, 50000.00);
+
+INSERT INTO invoice_sequences (sequence_key, last_number) VALUES
+('INV/STORE01/20240115/{SEQ:4}', 2),
+('INV/STORE01/20240116/{SEQ:4}', 1);
+
+--  transaction items dummy data
+INSERT INTO transaction_items (transaction_id, product_id, product_name, unit_price, quantity, gross_amount, subtotal,
+    tax_class, tax_rate, taxable_amount, tax_amount, total_amount) VALUES
+-- Transaction 1
+(1, 1, 'Laptop Dell Inspiron 15', 8500000.00, 1, 8500000.00, 8500000.00, 'STANDARD', 11.00, 7657657.66, 842342.34, 8500000.00),
+(1, 2, 'Mouse Wireless Logitech', 250000.00, 1, 250000.00, 250000.00, 'STANDARD', 11.00, 225225.23, 24774.77, 250000.00),
+
+-- Transaction 2
+(2, 4, 'Monitor LED 24 inch', 2200000.00, 1, 2200000.00, 2200000.00, 'STANDARD', 11.00, 1981981.98, 218018.02, 2200000.00),
+(2, 3, 'Keyboard Mechanical RGB', 750000.00, 1, 750000.00, 750000.00, 'STANDARD', 11.00, 675675.68, 74324.32, 750000.00),
+(2, 2, 'Mouse Wireless Logitech', 250000.00, 1, 250000.00, 250000.00, 'STANDARD', 11.00, 225225.23, 24774.77, 250000.00),
+
+-- Transaction 3
+(3, 5, 'Headset Gaming', 450000.00, 1, 450000.00, 450000.00, 'STANDARD', 11.00, 405405.41, 44594.59, 450000.00),
+(3, 9, 'USB Flash Drive 32GB', 75000.00, 2, 150000.00, 150000.00, 'STANDARD', 11.00, 135135.14, 14864.86, 150000.00);
+
+-- transaction payments dummy data
+INSERT INTO transaction_payments (transaction_id, method, tendered_amount, amount, reference) VALUES
+(1, 'DEBIT_CARD', 8750000.00, 8750000.00, 'APPR-000123'),
+(2, 'QRIS', 3000000.00, 3000000.00, 'QRIS-20240115-0002'),
+(2, 'CASH', 200000.00, 200000.00, NULL),
+(3, 'CASH', 650000.00, 600000.00, NULL);
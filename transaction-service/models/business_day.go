@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// BusinessDay menandai hari usaha yang sudah ditutup (end of day)
+type BusinessDay struct {
+	BusinessDate time.Time `json:"business_date"`
+	ClosedAt     time.Time `json:"closed_at"`
+	ClosedBy     string    `json:"closed_by"`
+}
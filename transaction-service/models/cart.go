@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+	"transaction-service/quantity"
+)
+
+const (
+	CartStatusOpen        = "OPEN"
+	CartStatusParked      = "PARKED"
+	CartStatusCheckingOut = "CHECKING_OUT" // sedang diproses checkout, tidak bisa diubah
+	CartStatusCheckedOut  = "CHECKED_OUT"
+)
+
+// Cart adalah keranjang draft sebelum menjadi transaksi. Harga tidak disimpan, baris keranjang
+// hanya berisi produk, quantity dan diskon manual; harga dihitung ulang saat checkout.
+type Cart struct {
+	ID         uint   `json:"id"`
+	TerminalID string `json:"terminal_id"`
+	Status     string `json:"status"`
+	// nama keranjang saat di-park, mis. nama pelanggan
+	Label       string    `json:"label,omitempty"`
+	CustomerID  string    `json:"customer_id,omitempty"`
+	Discount    *Discount `json:"discount,omitempty"` // diskon keranjang, Amount tidak dipakai
+	VoucherCode string    `json:"voucher_code,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	// transaksi hasil checkout
+	TransactionID *uint `json:"transaction_id,omitempty"`
+	// reservasi stok keranjang di product-service (reference cart:<id>), nil jika keranjang belum menahan stok
+	StockReservationID *uint      `json:"stock_reservation_id,omitempty"`
+	ParkedAt           *time.Time `json:"parked_at,omitempty"`
+	// diperpanjang setiap kali keranjang diubah
+	ExpiresAt time.Time  `json:"expires_at"`
+	Items     []CartItem `json:"items"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (c *Cart) Expired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+type CartItem struct {
+	ID        uint              `json:"id"`
+	CartID    uint              `json:"cart_id"`
+	ProductID uint              `json:"product_id"`
+	VariantID *uint             `json:"variant_id,omitempty"`
+	Quantity  quantity.Quantity `json:"quantity"`
+	Discount  *Discount         `json:"discount,omitempty"` // diskon baris, Amount tidak dipakai
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	IdempotencyStatusProcessing = "PROCESSING"
+	IdempotencyStatusCompleted  = "COMPLETED"
+)
+
+type IdempotencyKey struct {
+	Key            string    `json:"idempotency_key"`
+	RequestHash    string    `json:"request_hash"`
+	Status         string    `json:"status"`
+	ResponseStatus int       `json:"response_status"`
+	ResponseBody   []byte    `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	// batas waktu pemegang key PROCESSING, setelah itu retry boleh mengambil alih
+	LockedUntil *time.Time `json:"locked_until"`
+	// token klaim pemegang key PROCESSING, hanya diisi untuk request yang mengklaim key
+	LeaseToken string `json:"-"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+	"transaction-service/money"
+)
+
+// metode pembayaran (tender) yang diterima kasir
+const (
+	PaymentMethodCash         = "CASH"
+	PaymentMethodDebitCard    = "DEBIT_CARD"
+	PaymentMethodCreditCard   = "CREDIT_CARD"
+	PaymentMethodQRIS         = "QRIS"
+	PaymentMethodEWallet      = "E_WALLET"
+	PaymentMethodBankTransfer = "BANK_TRANSFER"
+)
+
+var ValidPaymentMethods = map[string]bool{
+	PaymentMethodCash:         true,
+	PaymentMethodDebitCard:    true,
+	PaymentMethodCreditCard:   true,
+	PaymentMethodQRIS:         true,
+	PaymentMethodEWallet:      true,
+	PaymentMethodBankTransfer: true,
+}
+
+type TransactionPayment struct {
+	ID            uint   `json:"id"`
+	TransactionID uint   `json:"transaction_id"`
+	Method        string `json:"method"`
+	// uang yang diserahkan pelanggan untuk tender ini
+	TenderedAmount money.Money `json:"tendered_amount"`
+	// bagian yang dipakai untuk membayar transaksi (tendered dikurangi kembalian)
+	Amount    money.Money `json:"amount"`
+	Reference string      `json:"reference,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+	"transaction-service/money"
+	"transaction-service/quantity"
+)
+
+// ProductReplica adalah salinan lokal produk dari product-service, hanya dipakai untuk laporan
+type ProductReplica struct {
+	ProductID      uint              `json:"product_id"`
+	Name           string            `json:"name"`
+	Price          money.Money       `json:"price"`
+	Stock          quantity.Quantity `json:"stock"`
+	ReservedStock  quantity.Quantity `json:"reserved_stock"`
+	AvailableStock quantity.Quantity `json:"available_stock"`
+	TaxClass       string            `json:"tax_class"`
+	CategoryID     *uint             `json:"category_id,omitempty"`
+	DeletedAt      *time.Time        `json:"deleted_at,omitempty"`
+	SyncedAt       time.Time         `json:"synced_at"`
+}
+
+// CategoryReplica adalah salinan lokal kategori produk, dipakai untuk laporan penjualan per kategori
+type CategoryReplica struct {
+	CategoryID uint       `json:"category_id"`
+	Name       string     `json:"name"`
+	ParentID   *uint      `json:"parent_id,omitempty"`
+	SortOrder  int        `json:"sort_order"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	SyncedAt   time.Time  `json:"synced_at"`
+}
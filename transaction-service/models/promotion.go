@@ -0,0 +1,141 @@
+package models
+
+import (
+	"time"
+	"transaction-service/money"
+)
+
+// tipe promosi yang didukung engine
+const (
+	// beli BuyQuantity gratis GetQuantity (unit termurah) dari produk yang memenuhi syarat
+	PromotionTypeBuyXGetY = "BUY_X_GET_Y"
+	// paket produk (BundleItems) dengan harga tetap BundlePrice
+	PromotionTypeBundlePrice = "BUNDLE_PRICE"
+	// potongan DiscountPercent untuk produk yang memenuhi syarat (semua produk jika ProductIDs dan CategoryIDs kosong)
+	PromotionTypePercentOff = "PERCENT_OFF"
+)
+
+var ValidPromotionTypes = map[string]bool{
+	PromotionTypeBuyXGetY:    true,
+	PromotionTypeBundlePrice: true,
+	PromotionTypePercentOff:  true,
+}
+
+type Promotion struct {
+	ID          uint          `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Type        string        `json:"type"`
+	Rule        PromotionRule `json:"rule"`
+	// promosi dengan prioritas lebih tinggi dievaluasi lebih dulu
+	Priority int `json:"priority"`
+	// promosi stackable boleh digabung dengan promosi stackable lain pada baris yang sama
+	Stackable bool       `json:"stackable"`
+	Active    bool       `json:"active"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	// 0 = Minggu ... 6 = Sabtu, kosong berarti setiap hari
+	DaysOfWeek []int `json:"days_of_week,omitempty"`
+	// jam berlaku harian format HH:MM (mis. happy hour 15:00-17:00), kosong berarti sepanjang hari
+	StartTime string `json:"start_time,omitempty"`
+	EndTime   string `json:"end_time,omitempty"`
+	// Rule.CategoryIDs beserta semua subkategorinya dari category_replicas, diisi saat promosi aktif dimuat
+	TargetCategoryIDs []uint     `json:"-"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty"`
+}
+
+// PromotionRule disimpan sebagai JSONB, field yang dipakai tergantung Type. Produk menjadi target jika
+// termasuk ProductIDs atau CategoryIDs (termasuk subkategorinya).
+type PromotionRule struct {
+	ProductIDs      []uint        `json:"product_ids,omitempty"`
+	CategoryIDs     []uint        `json:"category_ids,omitempty"`
+	BuyQuantity     int           `json:"buy_quantity,omitempty"`
+	GetQuantity     int           `json:"get_quantity,omitempty"`
+	BundleItems     []BundleItem  `json:"bundle_items,omitempty"`
+	BundlePrice     money.Money   `json:"bundle_price,omitempty"`
+	DiscountPercent money.Percent `json:"discount_percent,omitempty"`
+}
+
+type BundleItem struct {
+	ProductID uint `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+}
+
+// IsActiveAt mengecek periode berlaku, hari dan jam promosi
+func (p *Promotion) IsActiveAt(now time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+
+	if len(p.DaysOfWeek) > 0 {
+		matched := false
+		for _, day := range p.DaysOfWeek {
+			if int(now.Weekday()) == day {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if p.StartTime != "" && p.EndTime != "" {
+		clock := now.Format("15:04")
+		if p.StartTime <= p.EndTime {
+			return clock >= p.StartTime && clock < p.EndTime
+		}
+		// jendela melewati tengah malam, mis. 22:00-02:00
+		return clock >= p.StartTime || clock < p.EndTime
+	}
+
+	return true
+}
+
+// AppliesToProduct mengecek apakah produk (dengan kategorinya, nil jika tanpa kategori) termasuk target promosi
+func (p *Promotion) AppliesToProduct(productID uint, categoryID *uint) bool {
+	switch p.Type {
+	case PromotionTypeBundlePrice:
+		for _, item := range p.Rule.BundleItems {
+			if item.ProductID == productID {
+				return true
+			}
+		}
+		return false
+	default:
+		if len(p.Rule.ProductIDs) == 0 && len(p.Rule.CategoryIDs) == 0 {
+			return true
+		}
+		for _, id := range p.Rule.ProductIDs {
+			if id == productID {
+				return true
+			}
+		}
+		if categoryID == nil {
+			return false
+		}
+		for _, id := range p.TargetCategoryIDs {
+			if id == *categoryID {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AppliedPromotion mencatat promosi yang dipakai pada satu baris transaksi
+type AppliedPromotion struct {
+	ID                uint        `json:"id"`
+	TransactionItemID uint        `json:"transaction_item_id"`
+	PromotionID       uint        `json:"promotion_id"`
+	PromotionName     string      `json:"promotion_name"`
+	DiscountAmount    money.Money `json:"discount_amount"`
+}
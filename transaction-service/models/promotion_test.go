@@ -0,0 +1,84 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPromotionIsActiveAt(t *testing.T) {
+	// Sabtu 17 Oktober 2026
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, time.October, 17, hour, minute, 0, 0, time.UTC)
+	}
+	starts := at(9, 0)
+	ends := at(12, 0)
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		now       time.Time
+		want      bool
+	}{
+		{"always active", Promotion{Active: true}, at(10, 0), true},
+		{"disabled", Promotion{Active: false}, at(10, 0), false},
+		{"before starts_at", Promotion{Active: true, StartsAt: &starts}, at(8, 59), false},
+		{"at starts_at", Promotion{Active: true, StartsAt: &starts}, at(9, 0), true},
+		{"at ends_at", Promotion{Active: true, EndsAt: &ends}, at(12, 0), false},
+		{"weekend day", Promotion{Active: true, DaysOfWeek: []int{0, 6}}, at(10, 0), true},
+		{"weekdays only", Promotion{Active: true, DaysOfWeek: []int{1, 2, 3, 4, 5}}, at(10, 0), false},
+		{"inside happy hour", Promotion{Active: true, StartTime: "15:00", EndTime: "17:00"}, at(16, 30), true},
+		{"end of happy hour excluded", Promotion{Active: true, StartTime: "15:00", EndTime: "17:00"}, at(17, 0), false},
+		{"overnight window after midnight", Promotion{Active: true, StartTime: "22:00", EndTime: "02:00"}, at(1, 0), true},
+		{"overnight window before start", Promotion{Active: true, StartTime: "22:00", EndTime: "02:00"}, at(21, 59), false},
+		{"only start time set", Promotion{Active: true, StartTime: "15:00"}, at(10, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promotion.IsActiveAt(tt.now); got != tt.want {
+				t.Errorf("IsActiveAt(%s) = %v, want %v", tt.now.Format("Mon 15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionAppliesToProduct(t *testing.T) {
+	category := func(id uint) *uint { return &id }
+	byCategory := Promotion{
+		Type:              PromotionTypePercentOff,
+		Rule:              PromotionRule{ProductIDs: []uint{9}, CategoryIDs: []uint{3}},
+		TargetCategoryIDs: []uint{3, 7},
+	}
+	bundle := Promotion{
+		Type:              PromotionTypeBundlePrice,
+		Rule:              PromotionRule{BundleItems: []BundleItem{{ProductID: 1, Quantity: 1}}, CategoryIDs: []uint{3}},
+		TargetCategoryIDs: []uint{3},
+	}
+
+	tests := []struct {
+		name       string
+		promotion  Promotion
+		productID  uint
+		categoryID *uint
+		want       bool
+	}{
+		{"no target means every product", Promotion{Type: PromotionTypePercentOff}, 1, nil, true},
+		{"listed product", Promotion{Type: PromotionTypePercentOff, Rule: PromotionRule{ProductIDs: []uint{1}}}, 1, nil, true},
+		{"unlisted product", Promotion{Type: PromotionTypePercentOff, Rule: PromotionRule{ProductIDs: []uint{1}}}, 2, category(3), false},
+		{"target category", byCategory, 1, category(3), true},
+		{"subcategory", byCategory, 1, category(7), true},
+		{"other category", byCategory, 1, category(4), false},
+		{"listed product outside the category", byCategory, 9, category(4), true},
+		{"product without category", byCategory, 1, nil, false},
+		{"bundle ignores categories", bundle, 2, category(3), false},
+		{"bundle item", bundle, 1, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promotion.AppliesToProduct(tt.productID, tt.categoryID); got != tt.want {
+				t.Errorf("AppliesToProduct(%d) = %v, want %v", tt.productID, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+	"transaction-service/money"
+)
+
+// kelas pajak bawaan
+const (
+	TaxClassStandard = "STANDARD" // PPN tarif normal
+	TaxClassExempt   = "EXEMPT"   // bebas PPN
+)
+
+// TaxRate adalah tarif pajak per kelas pajak produk
+type TaxRate struct {
+	TaxClass  string        `json:"tax_class"`
+	Name      string        `json:"name"`
+	Rate      money.Percent `json:"rate"` // persen, mis. 11 untuk PPN 11%
+	Active    bool          `json:"active"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// TaxBreakdown adalah rekap pajak per kelas dan tarif dalam satu transaksi
+type TaxBreakdown struct {
+	TaxClass      string        `json:"tax_class"`
+	Rate          money.Percent `json:"rate"`
+	TaxableAmount money.Money   `json:"taxable_amount"` // DPP (dasar pengenaan pajak)
+	TaxAmount     money.Money   `json:"tax_amount"`
+}
@@ -2,25 +2,114 @@ package models
 
 import (
 	"time"
+	"transaction-service/money"
+	"transaction-service/quantity"
 )
 
 type Transaction struct {
-	ID               uint              `json:"id"`
-	TransactionDate  time.Time         `json:"transaction_date"`
-	TotalAmount      float64           `json:"total_amount"`
-	TransactionItems []TransactionItem `json:"transaction_items"`
-	CreatedAt        time.Time         `json:"created_at"`
-	UpdatedAt        time.Time         `json:"updated_at"`
-	DeletedAt        *time.Time        `json:"deleted_at,omitempty"`
+	ID                      uint                 `json:"id"`
+	InvoiceNumber           string               `json:"invoice_number"` // nomor urut per toko & periode, mis. INV/STORE01/20261017/0001
+	TransactionDate         time.Time            `json:"transaction_date"`
+	GrossAmount             money.Money          `json:"gross_amount"`
+	DiscountAmount          money.Money          `json:"discount_amount"` // promosi + diskon baris + diskon keranjang + voucher
+	PromotionDiscountAmount money.Money          `json:"promotion_discount_amount"`
+	CartDiscount            *Discount            `json:"cart_discount,omitempty"`
+	CustomerID              string               `json:"customer_id,omitempty"`
+	Voucher                 *VoucherRedemption   `json:"voucher,omitempty"` // potongan voucher dialokasikan ke baris seperti diskon keranjang
+	PricesIncludeTax        bool                 `json:"prices_include_tax"`
+	TaxAmount               money.Money          `json:"tax_amount"`
+	TotalAmount             money.Money          `json:"total_amount"` // jumlah yang dibayar, termasuk pajak
+	PaidAmount              money.Money          `json:"paid_amount"`
+	ChangeAmount            money.Money          `json:"change_amount"`
+	TransactionItems        []TransactionItem    `json:"transaction_items"`
+	Payments                []TransactionPayment `json:"payments"`
+	StockReservationID      *uint                `json:"stock_reservation_id,omitempty"` // reservasi stok di product-service yang dikonfirmasi untuk transaksi ini
+	StockPendingAt          *time.Time           `json:"stock_pending_at,omitempty"`     // konfirmasi stok belum pasti, diulang oleh rekonsiliasi
+	CartID                  *uint                `json:"-"`                              // keranjang asal, ditandai CHECKED_OUT di DB transaction yang sama
+	VoidedAt                *time.Time           `json:"voided_at,omitempty"`
+	VoidedBy                string               `json:"voided_by,omitempty"`
+	VoidReason              string               `json:"void_reason,omitempty"`
+	CreatedAt               time.Time            `json:"created_at"`
+	UpdatedAt               time.Time            `json:"updated_at"`
+	DeletedAt               *time.Time           `json:"deleted_at,omitempty"`
+}
+
+const (
+	DiscountTypePercentage = "PERCENTAGE"
+	DiscountTypeFixed      = "FIXED"
+)
+
+// Discount adalah diskon manual yang diminta kasir, beserta nilai rupiah hasil perhitungannya
+type Discount struct {
+	Type   string      `json:"type"`
+	Value  money.Money `json:"value"` // persen untuk PERCENTAGE, rupiah untuk FIXED
+	Amount money.Money `json:"amount"`
+}
+
+const (
+	TransactionStatusCompleted = "COMPLETED"
+	TransactionStatusVoided    = "VOIDED"
+)
+
+func (t *Transaction) Status() string {
+	if t.VoidedAt != nil {
+		return TransactionStatusVoided
+	}
+	return TransactionStatusCompleted
 }
 
 type TransactionItem struct {
-	ID            uint      `json:"id"`
-	TransactionID uint      `json:"transaction_id"`
-	ProductID     uint      `json:"product_id"`
-	Quantity      int       `json:"quantity"`
-	Subtotal      float64   `json:"subtotal"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            uint              `json:"id"`
+	TransactionID uint              `json:"transaction_id"`
+	ProductID     uint              `json:"product_id"`
+	ProductName   string            `json:"product_name"` // snapshot nama produk saat transaksi
+	ProductSKU    string            `json:"product_sku,omitempty"`
+	VariantID     *uint             `json:"variant_id,omitempty"`   // varian produk, nil untuk produk tanpa varian
+	VariantName   string            `json:"variant_name,omitempty"` // snapshot judul varian, mis. "M / Black"
+	UnitPrice     money.Money       `json:"unit_price"`             // snapshot harga satuan saat transaksi
+	Quantity      quantity.Quantity `json:"quantity"`
+	SoldByWeight  bool              `json:"sold_by_weight"` // quantity dari label timbangan, boleh pecahan
+	CategoryID    *uint             `json:"-"`              // kategori produk saat dihitung, hanya untuk target promosi
+	GrossAmount   money.Money       `json:"gross_amount"`   // unit_price * quantity
+	// potongan dari promosi otomatis, dihitung sebelum diskon manual
+	PromotionDiscountAmount money.Money        `json:"promotion_discount_amount"`
+	Promotions              []AppliedPromotion `json:"promotions,omitempty"`
+	LineDiscount            *Discount          `json:"line_discount,omitempty"`
+	// total diskon baris ini: promosi, diskon baris dan bagian diskon keranjang/voucher yang dialokasikan
+	DiscountAmount money.Money   `json:"discount_amount"`
+	Subtotal       money.Money   `json:"subtotal"` // net: gross_amount - discount_amount
+	TaxClass       string        `json:"tax_class"`
+	TaxRate        money.Percent `json:"tax_rate"`
+	TaxableAmount  money.Money   `json:"taxable_amount"` // DPP baris
+	TaxAmount      money.Money   `json:"tax_amount"`
+	// jumlah yang dibayar untuk baris ini: subtotal (harga termasuk pajak) atau subtotal + pajak
+	TotalAmount money.Money `json:"total_amount"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
 }
 
+// DisplayName adalah nama baris untuk struk dan invoice, mis. "T-Shirt Basic (M / Black)"
+func (i *TransactionItem) DisplayName() string {
+	if i.VariantName == "" {
+		return i.ProductName
+	}
+	return i.ProductName + " (" + i.VariantName + ")"
+}
+
+// TaxBreakdown merekap pajak per kelas dan tarif dari baris-baris transaksi
+func (t *Transaction) TaxBreakdown() []TaxBreakdown {
+	var breakdown []TaxBreakdown
+	index := make(map[string]int)
+	for _, item := range t.TransactionItems {
+		key := item.TaxClass + "|" + item.TaxRate.String()
+		i, exists := index[key]
+		if !exists {
+			i = len(breakdown)
+			index[key] = i
+			breakdown = append(breakdown, TaxBreakdown{TaxClass: item.TaxClass, Rate: item.TaxRate})
+		}
+		breakdown[i].TaxableAmount += item.TaxableAmount
+		breakdown[i].TaxAmount += item.TaxAmount
+	}
+	return breakdown
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+	"transaction-service/money"
+	"transaction-service/quantity"
+)
+
+// kode alasan retur yang diterima
+const (
+	ReturnReasonDefective          = "DEFECTIVE"
+	ReturnReasonWrongItem          = "WRONG_ITEM"
+	ReturnReasonCustomerChangeMind = "CUSTOMER_CHANGED_MIND"
+	ReturnReasonExpired            = "EXPIRED"
+	ReturnReasonOther              = "OTHER"
+)
+
+var ValidReturnReasons = map[string]bool{
+	ReturnReasonDefective:          true,
+	ReturnReasonWrongItem:          true,
+	ReturnReasonCustomerChangeMind: true,
+	ReturnReasonExpired:            true,
+	ReturnReasonOther:              true,
+}
+
+type TransactionReturn struct {
+	ID            uint                    `json:"id"`
+	TransactionID uint                    `json:"transaction_id"`
+	ReasonCode    string                  `json:"reason_code"`
+	ReasonNote    string                  `json:"reason_note,omitempty"`
+	TotalAmount   money.Money             `json:"total_amount"`
+	ReturnDate    time.Time               `json:"return_date"`
+	Items         []TransactionReturnItem `json:"items"`
+	// stok belum pasti dikembalikan ke product-service, restock diulang oleh rekonsiliasi
+	RestockPendingAt *time.Time `json:"restock_pending_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+type TransactionReturnItem struct {
+	ID                uint              `json:"id"`
+	ReturnID          uint              `json:"return_id"`
+	TransactionItemID uint              `json:"transaction_item_id"`
+	ProductID         uint              `json:"product_id"`
+	VariantID         *uint             `json:"variant_id,omitempty"` // varian baris yang dijual, stoknya dikembalikan ke varian ini
+	ProductName       string            `json:"product_name"`
+	UnitPrice         money.Money       `json:"unit_price"`
+	Quantity          quantity.Quantity `json:"quantity"`
+	Amount            money.Money       `json:"amount"`
+	TaxAmount         money.Money       `json:"tax_amount"` // bagian pajak dari amount
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"time"
+	"transaction-service/money"
+)
+
+type Voucher struct {
+	ID   uint   `json:"id"`
+	Code string `json:"code"`
+	// kode batch jika voucher dibuat massal
+	BatchCode    string      `json:"batch_code,omitempty"`
+	DiscountType string      `json:"discount_type"` // PERCENTAGE atau FIXED
+	Value        money.Money `json:"value"`
+	// batas potongan untuk voucher persentase, 0 berarti tanpa batas
+	MaxDiscountAmount money.Money `json:"max_discount_amount"`
+	MinSpend          money.Money `json:"min_spend"`
+	ExpiresAt         *time.Time  `json:"expires_at,omitempty"`
+	// jumlah pemakaian maksimum kode ini, 0 berarti tanpa batas
+	UsageLimit int `json:"usage_limit"`
+	// jumlah pemakaian maksimum per pelanggan, 0 berarti tanpa batas
+	PerCustomerLimit int       `json:"per_customer_limit"`
+	UsedCount        int       `json:"used_count"`
+	Active           bool      `json:"active"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// CheckRedeemable mengecek status, masa berlaku dan kuota kode (tanpa batas per pelanggan)
+func (v *Voucher) CheckRedeemable(now time.Time) error {
+	if !v.Active {
+		return fmt.Errorf("voucher %s is not active", v.Code)
+	}
+	if v.ExpiresAt != nil && !now.Before(*v.ExpiresAt) {
+		return fmt.Errorf("voucher %s has expired", v.Code)
+	}
+	if v.UsageLimit > 0 && v.UsedCount >= v.UsageLimit {
+		return fmt.Errorf("voucher %s has reached its usage limit", v.Code)
+	}
+	return nil
+}
+
+// VoucherRedemption mencatat pemakaian voucher pada satu transaksi
+type VoucherRedemption struct {
+	ID             uint        `json:"id"`
+	VoucherID      uint        `json:"voucher_id"`
+	VoucherCode    string      `json:"voucher_code"`
+	TransactionID  uint        `json:"transaction_id"`
+	CustomerID     string      `json:"customer_id,omitempty"`
+	DiscountAmount money.Money `json:"discount_amount"`
+	RedeemedAt     time.Time   `json:"redeemed_at"`
+	ReleasedAt     *time.Time  `json:"released_at,omitempty"` // diisi saat transaksi di-void
+}
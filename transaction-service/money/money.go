@@ -0,0 +1,310 @@
+// Package money menyimpan nilai uang sebagai bilangan bulat sen (2 desimal, sama dengan kolom
+// DECIMAL(15,2)) sehingga penjumlahan tidak pernah drift seperti float64.
+//
+// Aturan pembulatan: setiap perkalian/pembagian (persentase, proporsi, pajak) dibulatkan ke sen
+// terdekat, setengah dibulatkan menjauhi nol (half away from zero). Input dengan lebih dari
+// 2 desimal dibulatkan dengan aturan yang sama. Penjumlahan dan pengurangan selalu eksak.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Money adalah nilai uang dalam sen, 150000 rupiah = Money(15000000)
+type Money int64
+
+// Percent adalah persentase dalam seperseratus persen, 11% = Percent(1100)
+type Percent int64
+
+const (
+	// jumlah sen dalam satu rupiah
+	Scale = 100
+	// Percent untuk 100%
+	Hundred Percent = 100 * Scale
+)
+
+var ErrInvalidAmount = errors.New("invalid decimal amount")
+
+// New membuat Money dari rupiah utuh
+func New(rupiah int64) Money {
+	return Money(rupiah * Scale)
+}
+
+// Parse membaca desimal seperti "15000", "-2500.5" atau "1234.567" (dibulatkan ke sen)
+func Parse(s string) (Money, error) {
+	value, err := parseFixed(s)
+	return Money(value), err
+}
+
+// FromFloat hanya untuk input yang memang float (mis. environment), dibulatkan ke sen
+func FromFloat(value float64) Money {
+	return Money(math.Round(value * Scale))
+}
+
+// Times mengalikan dengan jumlah unit, eksak
+func (m Money) Times(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// MulDiv menghitung m * num / den dengan pembulatan ke sen
+func (m Money) MulDiv(num, den int64) Money {
+	if den == 0 {
+		panic("money: division by zero")
+	}
+	product := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(num))
+	return Money(divRound(product, big.NewInt(den)))
+}
+
+// Percent menghitung p persen dari m, dibulatkan ke sen
+func (m Money) Percent(p Percent) Money {
+	return m.MulDiv(int64(p), int64(Hundred))
+}
+
+// IncludedTax mengekstrak pajak dari harga yang sudah termasuk pajak: m * rate / (100 + rate)
+func (m Money) IncludedTax(rate Percent) Money {
+	return m.MulDiv(int64(rate), int64(Hundred+rate))
+}
+
+// PercentOf menghitung berapa persen m terhadap base, dibulatkan ke 0,01%
+func (m Money) PercentOf(base Money) Percent {
+	if base == 0 {
+		return 0
+	}
+	return Percent(m.MulDiv(int64(Hundred), int64(base)))
+}
+
+func (m Money) String() string {
+	return formatFixed(int64(m), false)
+}
+
+// MarshalJSON menulis Money sebagai string "15000.00" agar client tidak membacanya sebagai float
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// UnmarshalJSON menerima angka (15000.5) maupun string ("15000.50")
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalFixed(data)
+	if err != nil {
+		return err
+	}
+	*m = Money(value)
+	return nil
+}
+
+// Scan membaca kolom DECIMAL/NUMERIC, NULL dibaca sebagai 0
+func (m *Money) Scan(src interface{}) error {
+	value, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*m = Money(value)
+	return nil
+}
+
+// Value menulis Money sebagai teks desimal agar PostgreSQL menyimpannya tanpa konversi float
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Sum menjumlahkan nilai secara eksak
+func Sum(values []Money) Money {
+	var total Money
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+// Allocate membagi amount sesuai bobot weights (bobot <= 0 tidak mendapat bagian). Tiap bagian
+// dibulatkan ke bawah, sisa sen dibagikan ke bagian dengan sisa pecahan terbesar (baris lebih
+// awal didahulukan jika sama), sehingga jumlah alokasi selalu tepat sama dengan amount.
+func Allocate(amount Money, weights []Money) []Money {
+	shares := make([]Money, len(weights))
+
+	var total Money
+	for _, weight := range weights {
+		if weight > 0 {
+			total += weight
+		}
+	}
+	if total <= 0 || amount <= 0 {
+		return shares
+	}
+
+	type remainder struct {
+		index int
+		value *big.Int
+	}
+	var remainders []remainder
+	var allocated Money
+	bigAmount := big.NewInt(int64(amount))
+	bigTotal := big.NewInt(int64(total))
+	for i, weight := range weights {
+		if weight <= 0 {
+			continue
+		}
+		quotient, rest := new(big.Int).QuoRem(new(big.Int).Mul(bigAmount, big.NewInt(int64(weight))), bigTotal, new(big.Int))
+		shares[i] = Money(quotient.Int64())
+		allocated += shares[i]
+		remainders = append(remainders, remainder{index: i, value: rest})
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].value.Cmp(remainders[j].value) > 0
+	})
+	for i := 0; allocated < amount; i++ {
+		shares[remainders[i%len(remainders)].index]++
+		allocated++
+	}
+
+	return shares
+}
+
+// ParsePercent membaca persentase seperti "11" atau "12.5"
+func ParsePercent(s string) (Percent, error) {
+	value, err := parseFixed(s)
+	return Percent(value), err
+}
+
+func (p Percent) String() string {
+	return formatFixed(int64(p), true)
+}
+
+// MarshalJSON menulis Percent sebagai angka JSON, mis. 11 atau 12.5
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalFixed(data)
+	if err != nil {
+		return err
+	}
+	*p = Percent(value)
+	return nil
+}
+
+func (p *Percent) Scan(src interface{}) error {
+	value, err := scanFixed(src)
+	if err != nil {
+		return err
+	}
+	*p = Percent(value)
+	return nil
+}
+
+func (p Percent) Value() (driver.Value, error) {
+	return formatFixed(int64(p), false), nil
+}
+
+// parseFixed membaca desimal menjadi bilangan bulat berskala 2 tanpa melewati float
+func parseFixed(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty value", ErrInvalidAmount)
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	for _, part := range []string{whole, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+			}
+		}
+	}
+
+	// digit ketiga setelah koma menentukan pembulatan
+	roundUp := len(fraction) > 2 && fraction[2] >= '5'
+	fraction = (fraction + "00")[:2]
+
+	value, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidAmount, s)
+	}
+	if roundUp {
+		value++
+	}
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// formatFixed menulis bilangan berskala 2, trim menghapus nol di belakang koma
+func formatFixed(value int64, trim bool) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	text := fmt.Sprintf("%s%d.%02d", sign, value/Scale, value%Scale)
+	if trim {
+		text = strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+	}
+	return text
+}
+
+func unmarshalFixed(data []byte) (int64, error) {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		return 0, nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return 0, err
+		}
+	}
+	return parseFixed(text)
+}
+
+func scanFixed(src interface{}) (int64, error) {
+	switch value := src.(type) {
+	case nil:
+		return 0, nil
+	case []byte:
+		return parseFixed(string(value))
+	case string:
+		return parseFixed(value)
+	case int64:
+		return value * Scale, nil
+	case float64:
+		return int64(math.Round(value * Scale)), nil
+	default:
+		return 0, fmt.Errorf("money: cannot scan %T", src)
+	}
+}
+
+// divRound membagi dengan pembulatan half away from zero
+func divRound(num, den *big.Int) int64 {
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	// |remainder| * 2 >= |den| berarti dibulatkan menjauhi nol
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(new(big.Int).Abs(den)) >= 0 {
+		if (num.Sign() < 0) != (den.Sign() < 0) {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient.Int64()
+}
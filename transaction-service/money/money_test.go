@@ -0,0 +1,81 @@
+package money
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMulDiv(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Money
+		num, den int64
+		want     Money
+	}{
+		{"exact", 1000, 3, 1, 3000},
+		{"round down", 1000, 1, 3, 333},
+		{"round up", 1000, 2, 3, 667},
+		{"half rounds away from zero", 5, 1, 2, 3},
+		{"below half", 14, 1, 10, 1},
+		{"exactly half", 15, 1, 10, 2},
+		{"negative amount", -5, 1, 2, -3},
+		{"negative numerator", 5, -1, 2, -3},
+		{"negative denominator", 5, 1, -2, -3},
+		{"both negative", -5, 1, -2, 3},
+		{"negative below half", -14, 1, 10, -1},
+		{"zero amount", 0, 7, 3, 0},
+		{"intermediate overflow", math.MaxInt64 / 2, 4, 4, math.MaxInt64 / 2},
+		{"tax included 11%", 11100, 1100, 11100, 1100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.MulDiv(tt.num, tt.den); got != tt.want {
+				t.Errorf("Money(%d).MulDiv(%d, %d) = %d, want %d", tt.m, tt.num, tt.den, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMulDivZeroDenominator(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MulDiv with zero denominator did not panic")
+		}
+	}()
+	Money(100).MulDiv(1, 0)
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		weights []Money
+		want    []Money
+	}{
+		{"even split", 300, []Money{1, 1, 1}, []Money{100, 100, 100}},
+		{"equal remainders favour earlier lines", 100, []Money{1, 1, 1}, []Money{34, 33, 33}},
+		{"largest remainder first", 1001, []Money{100, 200, 300}, []Money{167, 334, 500}},
+		{"proportional", 10, []Money{3, 0, 7}, []Money{3, 0, 7}},
+		{"non-positive weights get nothing", 100, []Money{-5, 0, 5}, []Money{0, 0, 100}},
+		{"single weight takes all", 999, []Money{42}, []Money{999}},
+		{"amount smaller than lines", 2, []Money{1, 1, 1}, []Money{1, 1, 0}},
+		{"zero amount", 0, []Money{1, 2}, []Money{0, 0}},
+		{"negative amount", -100, []Money{1, 2}, []Money{0, 0}},
+		{"no positive weight", 100, []Money{0, -1}, []Money{0, 0}},
+		{"no weights", 100, []Money{}, []Money{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Allocate(tt.amount, tt.weights)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Allocate(%d, %v) = %v, want %v", tt.amount, tt.weights, got, tt.want)
+			}
+			if tt.amount > 0 && Sum(tt.weights) > 0 && Sum(got) != tt.amount {
+				t.Errorf("Allocate(%d, %v) sums to %d", tt.amount, tt.weights, Sum(got))
+			}
+		})
+	}
+}
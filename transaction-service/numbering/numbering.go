@@ -0,0 +1,125 @@
+// Package numbering menyusun nomor invoice dari pola yang bisa dikonfigurasi, mis.
+// "INV/{STORE}/{YYYYMMDD}/{SEQ:4}" menjadi "INV/STORE01/20261017/0001".
+//
+// Token yang didukung: {STORE}, {YYYY}, {YY}, {MM}, {DD}, {YYYYMMDD}, {SEQ} dan {SEQ:n}
+// (nomor urut dengan padding nol sampai n digit). Nomor urut dihitung per sequence key, yaitu
+// pola dengan semua token selain {SEQ} sudah terisi. Karena itu periode reset nomor urut
+// mengikuti token tanggal yang dipakai: harian untuk {YYYYMMDD}, bulanan untuk {YYYY}{MM}, dst.
+package numbering
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultPattern = "INV/{STORE}/{YYYYMMDD}/{SEQ:4}"
+
+type Pattern struct {
+	raw      string
+	store    string
+	segments []segment
+}
+
+type segment struct {
+	literal string
+	token   string
+	width   int // khusus SEQ
+}
+
+// Parse memvalidasi pola, pola harus memuat tepat satu token {SEQ}
+func Parse(pattern, store string) (Pattern, error) {
+	p := Pattern{raw: pattern, store: store}
+	sequences := 0
+
+	rest := pattern
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			p.segments = append(p.segments, segment{literal: rest})
+			break
+		}
+		if start > 0 {
+			p.segments = append(p.segments, segment{literal: rest[:start]})
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return Pattern{}, fmt.Errorf("unterminated token in invoice number pattern %q", pattern)
+		}
+
+		token := rest[start+1 : start+end]
+		seg := segment{token: token}
+		switch {
+		case token == "STORE", token == "YYYY", token == "YY", token == "MM", token == "DD", token == "YYYYMMDD":
+		case token == "SEQ":
+			sequences++
+		case strings.HasPrefix(token, "SEQ:"):
+			width, err := strconv.Atoi(strings.TrimPrefix(token, "SEQ:"))
+			if err != nil || width < 1 || width > 12 {
+				return Pattern{}, fmt.Errorf("invalid sequence width in invoice number pattern %q", pattern)
+			}
+			seg.token, seg.width = "SEQ", width
+			sequences++
+		default:
+			return Pattern{}, fmt.Errorf("unknown token {%s} in invoice number pattern %q", token, pattern)
+		}
+		p.segments = append(p.segments, seg)
+		rest = rest[start+end+1:]
+	}
+
+	if sequences != 1 {
+		return Pattern{}, errors.New("invoice number pattern must contain exactly one {SEQ} token")
+	}
+	if strings.Contains(pattern, "{STORE}") && store == "" {
+		return Pattern{}, errors.New("store code is required by the invoice number pattern")
+	}
+	return p, nil
+}
+
+func (p Pattern) String() string {
+	return p.raw
+}
+
+// SequenceKey adalah pola dengan token selain {SEQ} terisi, nomor urut dihitung per key ini
+func (p Pattern) SequenceKey(date time.Time) string {
+	return p.render(date, func(seg segment) string {
+		if seg.width > 0 {
+			return "{SEQ:" + strconv.Itoa(seg.width) + "}"
+		}
+		return "{SEQ}"
+	})
+}
+
+// Format menghasilkan nomor invoice untuk tanggal dan nomor urut tertentu
+func (p Pattern) Format(date time.Time, sequence int64) string {
+	return p.render(date, func(seg segment) string {
+		return fmt.Sprintf("%0*d", seg.width, sequence)
+	})
+}
+
+func (p Pattern) render(date time.Time, sequence func(segment) string) string {
+	var b strings.Builder
+	for _, seg := range p.segments {
+		switch seg.token {
+		case "":
+			b.WriteString(seg.literal)
+		case "STORE":
+			b.WriteString(p.store)
+		case "YYYY":
+			b.WriteString(date.Format("2006"))
+		case "YY":
+			b.WriteString(date.Format("06"))
+		case "MM":
+			b.WriteString(date.Format("01"))
+		case "DD":
+			b.WriteString(date.Format("02"))
+		case "YYYYMMDD":
+			b.WriteString(date.Format("20060102"))
+		case "SEQ":
+			b.WriteString(sequence(seg))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package numbering
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	date := time.Date(2026, time.October, 7, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		pattern     string
+		store       string
+		sequence    int64
+		want        string
+		wantKey     string
+		wantInvalid bool
+	}{
+		{"default pattern", DefaultPattern, "STORE01", 1, "INV/STORE01/20261007/0001", "INV/STORE01/20261007/{SEQ:4}", false},
+		{"unpadded sequence", "INV-{SEQ}", "", 42, "INV-42", "INV-{SEQ}", false},
+		{"sequence wider than padding", "{SEQ:2}", "", 1234, "1234", "{SEQ:2}", false},
+		{"monthly reset", "{STORE}-{YY}{MM}-{SEQ:5}", "S1", 7, "S1-2610-00007", "S1-2610-{SEQ:5}", false},
+		{"separate date tokens", "{YYYY}/{MM}/{DD}/{SEQ:3}", "", 12, "2026/10/07/012", "2026/10/07/{SEQ:3}", false},
+		{"sequence only", "{SEQ:1}", "", 9, "9", "{SEQ:1}", false},
+		{"missing sequence", "INV/{YYYYMMDD}", "", 0, "", "", true},
+		{"two sequences", "{SEQ}-{SEQ:2}", "", 0, "", "", true},
+		{"unknown token", "INV/{HH}/{SEQ}", "", 0, "", "", true},
+		{"unterminated token", "INV/{SEQ", "", 0, "", "", true},
+		{"zero width", "{SEQ:0}", "", 0, "", "", true},
+		{"width too large", "{SEQ:13}", "", 0, "", "", true},
+		{"non-numeric width", "{SEQ:x}", "", 0, "", "", true},
+		{"store required", "{STORE}/{SEQ}", "", 0, "", "", true},
+		{"lowercase token", "{seq}", "", 0, "", "", true},
+		{"empty pattern", "", "", 0, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := Parse(tt.pattern, tt.store)
+			if tt.wantInvalid {
+				if err == nil {
+					t.Fatalf("Parse(%q) succeeded, want error", tt.pattern)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.pattern, err)
+			}
+
+			if got := pattern.Format(date, tt.sequence); got != tt.want {
+				t.Errorf("Format = %q, want %q", got, tt.want)
+			}
+			if got := pattern.SequenceKey(date); got != tt.wantKey {
+				t.Errorf("SequenceKey = %q, want %q", got, tt.wantKey)
+			}
+			if got := pattern.String(); got != tt.pattern {
+				t.Errorf("String = %q, want %q", got, tt.pattern)
+			}
+		})
+	}
+}
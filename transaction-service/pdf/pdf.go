@@ -0,0 +1,224 @@
+// Package pdf adalah penulis PDF 1.4 minimal untuk dokumen teks (invoice): teks dengan font
+// standar Helvetica, garis dan kotak berwarna. Output tidak dikompres dan tidak memuat tanggal
+// pembuatan atau ID acak, sehingga input yang sama selalu menghasilkan byte yang sama.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ukuran halaman dalam point (1/72 inci)
+type PageSize struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	A4 = PageSize{Width: 595.28, Height: 841.89}
+	A5 = PageSize{Width: 419.53, Height: 595.28}
+)
+
+// Font standar PDF, tidak perlu di-embed
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+// Color adalah warna RGB 0-1
+type Color struct {
+	R, G, B float64
+}
+
+var Black = Color{}
+
+type Document struct {
+	size   PageSize
+	pages  []*bytes.Buffer
+	active int
+}
+
+func New(size PageSize) *Document {
+	return &Document{size: size}
+}
+
+func (d *Document) Size() PageSize {
+	return d.size
+}
+
+// AddPage menambah halaman baru, perintah gambar berikutnya masuk ke halaman ini
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.active = len(d.pages) - 1
+}
+
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage memilih halaman (mulai dari 1) yang akan digambar, mis. untuk nomor halaman
+func (d *Document) SetPage(page int) {
+	if page >= 1 && page <= len(d.pages) {
+		d.active = page - 1
+	}
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[d.active]
+}
+
+// Text menulis teks dengan baseline di (x, y), y diukur dari atas halaman
+func (d *Document) Text(x, y float64, font Font, size float64, color Color, text string) {
+	fmt.Fprintf(d.current(), "BT %s rg /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		formatColor(color), font+1, number(size), number(x), number(d.size.Height-y), escape(encode(text)))
+}
+
+// TextRight menulis teks rata kanan dengan ujung kanan di x
+func (d *Document) TextRight(x, y float64, font Font, size float64, color Color, text string) {
+	d.Text(x-TextWidth(text, font, size), y, font, size, color, text)
+}
+
+// Line menggambar garis lurus dengan ketebalan width
+func (d *Document) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(d.current(), "%s RG %s w %s %s m %s %s l S\n",
+		formatColor(color), number(width), number(x1), number(d.size.Height-y1), number(x2), number(d.size.Height-y2))
+}
+
+// FillRect mengisi kotak dengan sudut kiri atas (x, y)
+func (d *Document) FillRect(x, y, width, height float64, color Color) {
+	fmt.Fprintf(d.current(), "%s rg %s %s %s %s re f\n",
+		formatColor(color), number(x), number(d.size.Height-y-height), number(width), number(height))
+}
+
+// Bytes menyusun file PDF lengkap dengan tabel xref
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	// 1 katalog, 2 daftar halaman, 3-4 font, lalu pasangan halaman + content stream
+	kids := make([]string, 0, len(d.pages))
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+i*2))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			number(d.size.Width), number(d.size.Height), 6+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// TextWidth menghitung lebar teks dalam point memakai metrik font standar
+func TextWidth(text string, font Font, size float64) float64 {
+	widths := helveticaWidths
+	if font == HelveticaBold {
+		widths = helveticaBoldWidths
+	}
+
+	var total int
+	for _, c := range []byte(encode(text)) {
+		if c >= 32 && c <= 126 {
+			total += widths[c-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Fit memotong teks (dengan "...") agar lebarnya tidak melebihi maxWidth
+func Fit(text string, font Font, size, maxWidth float64) string {
+	if TextWidth(text, font, size) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimRight(string(runes), " ") + "..."
+		if TextWidth(candidate, font, size) <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// encode mengubah teks ke WinAnsi: ASCII dan Latin-1 apa adanya, karakter lain menjadi '?'
+func encode(text string) string {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r == '\t':
+			encoded = append(encoded, ' ')
+		case r >= 32 && r <= 126, r >= 0xA0 && r <= 0xFF:
+			encoded = append(encoded, byte(r))
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return string(encoded)
+}
+
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(text)
+}
+
+func formatColor(color Color) string {
+	return number(color.R) + " " + number(color.G) + " " + number(color.B)
+}
+
+// number menulis angka dengan maksimal 2 desimal tanpa nol di belakang
+func number(value float64) string {
+	text := fmt.Sprintf("%.2f", value)
+	text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	if text == "-0" || text == "" {
+		return "0"
+	}
+	return text
+}
+
+// lebar karakter 32-126 dalam 1/1000 em (AFM Helvetica dan Helvetica-Bold)
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
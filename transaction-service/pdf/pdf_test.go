@@ -0,0 +1,111 @@
+package pdf
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// go test ./pdf -update menulis ulang file golden di testdata
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func TestDocumentBytes(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() *Document
+	}{
+		{"empty", func() *Document {
+			return New(A4)
+		}},
+		{"drawing", func() *Document {
+			doc := New(A5)
+			doc.FillRect(20, 20, 100, 30, Color{R: 0.12, G: 0.31, B: 0.47})
+			doc.Text(25, 40, HelveticaBold, 12, Color{R: 1, G: 1, B: 1}, "INVOICE")
+			doc.Line(20, 60, 399.53, 60, 0.5, Black)
+			doc.Text(20, 80, Helvetica, 9, Black, `Kurung (dan) garis \ miring`)
+			doc.Text(20, 95, Helvetica, 9, Black, "Café\ttab, 日本 tidak didukung")
+			doc.TextRight(399.53, 110, Helvetica, 9, Black, "Rp 1.234.567")
+			return doc
+		}},
+		{"pages", func() *Document {
+			doc := New(A4)
+			doc.AddPage()
+			doc.Text(40, 40, Helvetica, 10, Black, "Halaman 1")
+			doc.AddPage()
+			doc.Text(40, 40, Helvetica, 10, Black, "Halaman 2")
+			// nomor halaman ditulis belakangan ke halaman pertama
+			doc.SetPage(1)
+			doc.TextRight(555, 800, Helvetica, 8, Black, "1 / 2")
+			doc.SetPage(3) // di luar jangkauan, halaman aktif tidak berubah
+			doc.TextRight(555, 810, Helvetica, 8, Black, "akhir")
+			return doc
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.build().Bytes()
+			path := filepath.Join("testdata", tt.name+".pdf")
+			if *update {
+				if err := os.MkdirAll("testdata", 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s differs from the golden file, run with -update if the change is intended", path)
+			}
+		})
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		text string
+		font Font
+		size float64
+		want float64
+	}{
+		{"", Helvetica, 10, 0},
+		{"A", Helvetica, 10, 6.67},
+		{"A", HelveticaBold, 10, 7.22},
+		{"iii", Helvetica, 10, 6.66},
+		{"é", Helvetica, 10, 5.56}, // di luar ASCII memakai lebar default
+		{"Rp 1.000", Helvetica, 1000, 4058},
+	}
+
+	for _, tt := range tests {
+		got := TextWidth(tt.text, tt.font, tt.size)
+		if diff := got - tt.want; diff > 0.001 || diff < -0.001 {
+			t.Errorf("TextWidth(%q, %d, %v) = %v, want %v", tt.text, tt.font, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxWidth float64
+		want     string
+	}{
+		{"Kopi", 100, "Kopi"},
+		{"Kopi Susu Gula Aren", 40, "Kopi S..."},
+		{"Kopi Susu", 33, "Kopi..."},
+		{"Kopi", 1, ""},
+	}
+
+	for _, tt := range tests {
+		if got := Fit(tt.text, Helvetica, 10, tt.maxWidth); got != tt.want {
+			t.Errorf("Fit(%q, %v) = %q, want %q", tt.text, tt.maxWidth, got, tt.want)
+		}
+	}
+}
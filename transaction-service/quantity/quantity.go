@@ -0,0 +1,159 @@
+// Package quantity menyimpan jumlah barang sebagai bilangan bulat perseribu (3 desimal, sama dengan
+// kolom DECIMAL(15,3)) sehingga stok barang timbang (kg) tetap eksak tanpa float64. Barang satuan
+// selalu bernilai bulat. Input dengan lebih dari 3 desimal dibulatkan ke perseribu terdekat,
+// setengah dibulatkan menjauhi nol, sama seperti package money.
+package quantity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"transaction-service/money"
+)
+
+// Quantity adalah jumlah dalam perseribu, 2 unit = Quantity(2000), 0,735 kg = Quantity(735)
+type Quantity int64
+
+// jumlah perseribu dalam satu unit
+const Scale = 1000
+
+var ErrInvalidQuantity = errors.New("invalid quantity")
+
+// New membuat Quantity dari jumlah unit utuh
+func New(units int64) Quantity {
+	return Quantity(units * Scale)
+}
+
+// Parse membaca desimal seperti "2", "0.735" atau "1.2345" (dibulatkan ke perseribu)
+func Parse(s string) (Quantity, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty value", ErrInvalidQuantity)
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidQuantity, s)
+	}
+	for _, part := range []string{whole, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidQuantity, s)
+			}
+		}
+	}
+
+	// digit keempat setelah koma menentukan pembulatan
+	roundUp := len(fraction) > 3 && fraction[3] >= '5'
+	fraction = (fraction + "000")[:3]
+
+	value, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidQuantity, s)
+	}
+	if roundUp {
+		value++
+	}
+	if negative {
+		value = -value
+	}
+	return Quantity(value), nil
+}
+
+// IsWhole bernilai true jika tidak ada pecahan, mis. 2 atau 15
+func (q Quantity) IsWhole() bool {
+	return q%Scale == 0
+}
+
+// String menulis desimal tanpa nol di belakang koma: "2", "0.735", "1.5"
+func (q Quantity) String() string {
+	value := int64(q)
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	text := fmt.Sprintf("%s%d.%03d", sign, value/Scale, value%Scale)
+	return strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+}
+
+// MarshalJSON menulis Quantity sebagai angka JSON (2 atau 0.735) agar client lama tetap bisa membacanya
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalJSON menerima angka (0.735) maupun string ("0.735")
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		*q = 0
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	value, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*q = value
+	return nil
+}
+
+// Scan membaca kolom DECIMAL/NUMERIC atau INTEGER, NULL dibaca sebagai 0
+func (q *Quantity) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*q = 0
+	case []byte:
+		return q.scanText(string(value))
+	case string:
+		return q.scanText(value)
+	case int64:
+		*q = New(value)
+	case float64:
+		*q = Quantity(math.Round(value * Scale))
+	default:
+		return fmt.Errorf("quantity: cannot scan %T", src)
+	}
+	return nil
+}
+
+func (q *Quantity) scanText(text string) error {
+	value, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*q = value
+	return nil
+}
+
+// Value menulis Quantity sebagai teks desimal agar PostgreSQL menyimpannya tanpa konversi float
+func (q Quantity) Value() (driver.Value, error) {
+	return q.String(), nil
+}
+
+// Amount menghitung harga q dengan harga satuan unitPrice, dibulatkan ke sen terdekat
+func (q Quantity) Amount(unitPrice money.Money) money.Money {
+	return unitPrice.MulDiv(int64(q), Scale)
+}
+
+// Units adalah jumlah unit utuh, pecahan dibuang, mis. 2.5 menjadi 2
+func (q Quantity) Units() int {
+	return int(q / Scale)
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"transaction-service/config"
+	"transaction-service/models"
+)
+
+type BusinessDayRepository interface {
+	Close(businessDate time.Time, closedBy string) (*models.BusinessDay, error)
+	IsClosed(businessDate time.Time) (bool, error)
+	GetAll(limit int) ([]models.BusinessDay, error)
+}
+
+type businessDayRepository struct {
+	db *sql.DB
+}
+
+func NewBusinessDayRepository() BusinessDayRepository {
+	return &businessDayRepository{
+		db: config.DB,
+	}
+}
+
+// Close menutup hari usaha dengan FOR UPDATE pada baris harinya, sehingga menunggu void yang sedang
+// berjalan (FOR SHARE pada baris yang sama, lihat transactionRepository.Void) dan sebaliknya
+func (r *businessDayRepository) Close(businessDate time.Time, closedBy string) (*models.BusinessDay, error) {
+	date := businessDate.Format("2006-01-02")
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := ensureBusinessDay(tx, date); err != nil {
+		return nil, err
+	}
+
+	var closed bool
+	err = tx.QueryRow(
+		`SELECT closed_at IS NOT NULL FROM business_days WHERE business_date = $1 FOR UPDATE`,
+		date,
+	).Scan(&closed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock business day: %w", err)
+	}
+	if closed {
+		return nil, fmt.Errorf("business day %s has already been closed", date)
+	}
+
+	query := `
+		UPDATE business_days
+		SET closed_at = $2, closed_by = $3
+		WHERE business_date = $1
+		RETURNING business_date, closed_at, closed_by`
+
+	var day models.BusinessDay
+	err = tx.QueryRow(query, date, time.Now(), closedBy).Scan(
+		&day.BusinessDate,
+		&day.ClosedAt,
+		&day.ClosedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &day, nil
+}
+
+// ensureBusinessDay membuat baris hari usaha yang belum ditutup agar selalu ada baris yang bisa dikunci
+func ensureBusinessDay(tx *sql.Tx, date string) error {
+	_, err := tx.Exec(`INSERT INTO business_days (business_date) VALUES ($1) ON CONFLICT (business_date) DO NOTHING`, date)
+	if err != nil {
+		return fmt.Errorf("failed to create business day: %w", err)
+	}
+	return nil
+}
+
+func (r *businessDayRepository) IsClosed(businessDate time.Time) (bool, error) {
+	var closed bool
+	err := r.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM business_days WHERE business_date = $1 AND closed_at IS NOT NULL)`,
+		businessDate.Format("2006-01-02"),
+	).Scan(&closed)
+	return closed, err
+}
+
+func (r *businessDayRepository) GetAll(limit int) ([]models.BusinessDay, error) {
+	query := `
+		SELECT business_date, closed_at, closed_by
+		FROM business_days
+		WHERE closed_at IS NOT NULL
+		ORDER BY business_date DESC
+		LIMIT $1`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []models.BusinessDay
+	for rows.Next() {
+		var day models.BusinessDay
+		if err := rows.Scan(&day.BusinessDate, &day.ClosedAt, &day.ClosedBy); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
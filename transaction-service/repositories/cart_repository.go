@@ -0,0 +1,523 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-service/config"
+	"transaction-service/models"
+	"transaction-service/money"
+
+	"github.com/lib/pq"
+)
+
+// ErrCartNotOpen dikembalikan perubahan keranjang yang statusnya bukan (lagi) OPEN, mis. karena checkout
+// mengklaimnya lebih dulu
+var ErrCartNotOpen = errors.New("cart is not open")
+
+// ErrCartNotParked dikembalikan Resume untuk keranjang yang tidak sedang di-park
+var ErrCartNotParked = errors.New("cart is not parked")
+
+type CartRepository interface {
+	Create(cart *models.Cart) error
+	GetByID(id uint) (*models.Cart, error)
+	// GetAll mengembalikan keranjang yang belum kedaluwarsa, status kosong berarti OPEN dan PARKED
+	GetAll(terminalID, status string, now time.Time) ([]models.Cart, error)
+	// EditOpen mengunci keranjang OPEN (FOR NO KEY UPDATE) selama fn berjalan. Perubahan lewat repo di dalam fn
+	// tersimpan bersama, atau dibatalkan jika fn gagal. Klaim checkout menunggu kunci ini, jadi stok yang
+	// ditahan di dalam fn tidak bisa menggantikan reservasi checkout yang sedang berjalan.
+	EditOpen(id uint, fn func(repo CartRepository, cart *models.Cart) error) error
+	// Update menyimpan header keranjang OPEN, status tidak ikut diubah
+	Update(cart *models.Cart) error
+	// Park mengubah keranjang OPEN menjadi PARKED beserta label dan parked_at-nya
+	Park(cart *models.Cart) error
+	// Resume membuka lagi keranjang PARKED, terminal_id ikut disimpan
+	Resume(cart *models.Cart) error
+	// Delete menghapus keranjang OPEN atau PARKED
+	Delete(id uint) error
+	// DeleteExpired menghapus keranjang kedaluwarsa. Keranjang CHECKING_OUT ikut dihapus hanya jika klaimnya
+	// lebih lama dari checkoutStaleBefore.
+	DeleteExpired(now, checkoutStaleBefore time.Time) error
+	// AddItem, UpdateItem dan DeleteItem hanya mengubah baris keranjang OPEN
+	AddItem(item *models.CartItem) error
+	UpdateItem(item *models.CartItem) error
+	DeleteItem(cartID, itemID uint) error
+	// ClaimForCheckout mengubah status OPEN menjadi CHECKING_OUT secara atomik, false jika keranjang
+	// sudah diproses request lain, tidak OPEN atau sudah kedaluwarsa
+	ClaimForCheckout(id uint, now time.Time) (bool, error)
+	// ReleaseCheckout mengembalikan keranjang ke OPEN jika checkout gagal, beserta reservasi stoknya yang baru.
+	// Keranjang yang transaksinya sudah di-void sistem (stok gagal dikonfirmasi) juga dibuka lagi.
+	ReleaseCheckout(id uint, stockReservationID *uint) error
+	// ReleaseStaleCheckout membuka lagi keranjang yang klaim checkout-nya lebih lama dari before, mis. karena
+	// proses checkout mati sebelum selesai. Transaksi keranjang dan status CHECKED_OUT disimpan bersamaan, jadi
+	// keranjang yang masih CHECKING_OUT belum punya transaksi.
+	ReleaseStaleCheckout(id uint, before time.Time) (bool, error)
+}
+
+// cartExecutor dipenuhi *sql.DB dan *sql.Tx, sehingga repository yang sama dipakai di dalam EditOpen
+type cartExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+type cartRepository struct {
+	db cartExecutor
+}
+
+func NewCartRepository() CartRepository {
+	return &cartRepository{
+		db: config.DB,
+	}
+}
+
+const cartColumns = `id, terminal_id, status, COALESCE(label, ''), COALESCE(customer_id, ''), discount_type, discount_value,
+	COALESCE(voucher_code, ''), COALESCE(created_by, ''), transaction_id, stock_reservation_id, parked_at, expires_at,
+	created_at, updated_at`
+
+func (r *cartRepository) Create(cart *models.Cart) error {
+	query := `
+		INSERT INTO carts (terminal_id, status, customer_id, discount_type, discount_value, voucher_code, created_by,
+			expires_at, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, $9)
+		RETURNING id, created_at, updated_at`
+
+	discountType, discountValue, _ := discountToNull(cart.Discount)
+
+	err := r.db.QueryRow(
+		query,
+		cart.TerminalID,
+		cart.Status,
+		cart.CustomerID,
+		discountType,
+		discountValue,
+		cart.VoucherCode,
+		cart.CreatedBy,
+		cart.ExpiresAt,
+		time.Now(),
+	).Scan(&cart.ID, &cart.CreatedAt, &cart.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert cart: %w", err)
+	}
+	return nil
+}
+
+func (r *cartRepository) GetByID(id uint) (*models.Cart, error) {
+	cart, err := scanCart(r.db.QueryRow(`SELECT `+cartColumns+` FROM carts WHERE id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := r.getItems([]uint{cart.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart items: %w", err)
+	}
+	cart.Items = items[cart.ID]
+
+	return cart, nil
+}
+
+func (r *cartRepository) GetAll(terminalID, status string, now time.Time) ([]models.Cart, error) {
+	query := `
+		SELECT ` + cartColumns + `
+		FROM carts
+		WHERE expires_at > $1
+		  AND ($2::TEXT = '' OR terminal_id = $2)
+		  AND (status = $3 OR ($3::TEXT = '' AND status IN ($4, $5)))
+		ORDER BY updated_at DESC`
+
+	rows, err := r.db.Query(query, now, terminalID, status, models.CartStatusOpen, models.CartStatusParked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch carts: %w", err)
+	}
+	defer rows.Close()
+
+	var carts []models.Cart
+	var ids []uint
+	for rows.Next() {
+		cart, err := scanCart(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cart: %w", err)
+		}
+		carts = append(carts, *cart)
+		ids = append(ids, cart.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := r.getItems(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart items: %w", err)
+	}
+	for i := range carts {
+		carts[i].Items = items[carts[i].ID]
+	}
+
+	return carts, nil
+}
+
+func (r *cartRepository) EditOpen(id uint, fn func(repo CartRepository, cart *models.Cart) error) error {
+	db, ok := r.db.(*sql.DB)
+	if !ok {
+		return errors.New("cart edit cannot be nested")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// FOR NO KEY UPDATE tidak menghalangi foreign key cart_items yang ditulis di dalam fn
+	cart, err := scanCart(tx.QueryRow(`SELECT `+cartColumns+` FROM carts WHERE id = $1 FOR NO KEY UPDATE`, id))
+	if err != nil {
+		return err
+	}
+	if cart.Status != models.CartStatusOpen {
+		return ErrCartNotOpen
+	}
+
+	repo := &cartRepository{db: tx}
+	items, err := repo.getItems([]uint{cart.ID})
+	if err != nil {
+		return fmt.Errorf("failed to get cart items: %w", err)
+	}
+	cart.Items = items[cart.ID]
+
+	if err := fn(repo, cart); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *cartRepository) Update(cart *models.Cart) error {
+	query := `
+		UPDATE carts
+		SET terminal_id = $1, label = NULLIF($2, ''), customer_id = NULLIF($3, ''), discount_type = $4,
+			discount_value = $5, voucher_code = NULLIF($6, ''), expires_at = $7, stock_reservation_id = $8,
+			updated_at = $9
+		WHERE id = $10 AND status = $11
+		RETURNING updated_at`
+
+	discountType, discountValue, _ := discountToNull(cart.Discount)
+
+	err := r.db.QueryRow(
+		query,
+		cart.TerminalID,
+		cart.Label,
+		cart.CustomerID,
+		discountType,
+		discountValue,
+		cart.VoucherCode,
+		cart.ExpiresAt,
+		cart.StockReservationID,
+		time.Now(),
+		cart.ID,
+		models.CartStatusOpen,
+	).Scan(&cart.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return r.notChanged(cart.ID)
+		}
+		return fmt.Errorf("failed to update cart: %w", err)
+	}
+	return nil
+}
+
+func (r *cartRepository) Park(cart *models.Cart) error {
+	query := `
+		UPDATE carts
+		SET status = $1, label = $2, parked_at = $3, expires_at = $4, stock_reservation_id = $5, updated_at = $6
+		WHERE id = $7 AND status = $8
+		RETURNING updated_at`
+
+	err := r.db.QueryRow(
+		query,
+		models.CartStatusParked,
+		cart.Label,
+		cart.ParkedAt,
+		cart.ExpiresAt,
+		cart.StockReservationID,
+		time.Now(),
+		cart.ID,
+		models.CartStatusOpen,
+	).Scan(&cart.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return r.notChanged(cart.ID)
+		}
+		return fmt.Errorf("failed to park cart: %w", err)
+	}
+	cart.Status = models.CartStatusParked
+	return nil
+}
+
+func (r *cartRepository) Resume(cart *models.Cart) error {
+	query := `
+		UPDATE carts
+		SET status = $1, terminal_id = $2, parked_at = NULL, updated_at = $3
+		WHERE id = $4 AND status = $5
+		RETURNING updated_at`
+
+	err := r.db.QueryRow(
+		query,
+		models.CartStatusOpen,
+		cart.TerminalID,
+		time.Now(),
+		cart.ID,
+		models.CartStatusParked,
+	).Scan(&cart.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			if _, err := r.status(cart.ID); err != nil {
+				return err
+			}
+			return ErrCartNotParked
+		}
+		return fmt.Errorf("failed to resume cart: %w", err)
+	}
+	cart.Status = models.CartStatusOpen
+	cart.ParkedAt = nil
+	return nil
+}
+
+func (r *cartRepository) Delete(id uint) error {
+	res, err := r.db.Exec(
+		`DELETE FROM carts WHERE id = $1 AND status IN ($2, $3)`,
+		id, models.CartStatusOpen, models.CartStatusParked,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete cart: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		if _, err := r.status(id); err != nil {
+			return err
+		}
+		return ErrCartNotOpen
+	}
+	return nil
+}
+
+// DeleteExpired menghapus keranjang kedaluwarsa, kecuali yang sedang diproses checkout
+func (r *cartRepository) DeleteExpired(now, checkoutStaleBefore time.Time) error {
+	_, err := r.db.Exec(
+		`DELETE FROM carts WHERE expires_at <= $1 AND (status <> $2 OR updated_at <= $3)`,
+		now, models.CartStatusCheckingOut, checkoutStaleBefore,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to purge expired carts: %w", err)
+	}
+	return nil
+}
+
+func (r *cartRepository) AddItem(item *models.CartItem) error {
+	query := `
+		INSERT INTO cart_items (cart_id, product_id, variant_id, quantity, discount_type, discount_value, created_at, updated_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $7
+		WHERE EXISTS (SELECT 1 FROM carts WHERE id = $1 AND status = $8)
+		RETURNING id, created_at, updated_at`
+
+	discountType, discountValue, _ := discountToNull(item.Discount)
+
+	err := r.db.QueryRow(
+		query,
+		item.CartID,
+		item.ProductID,
+		item.VariantID,
+		item.Quantity,
+		discountType,
+		discountValue,
+		time.Now(),
+		models.CartStatusOpen,
+	).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return r.notChanged(item.CartID)
+		}
+		return fmt.Errorf("failed to insert cart item: %w", err)
+	}
+	return nil
+}
+
+func (r *cartRepository) UpdateItem(item *models.CartItem) error {
+	query := `
+		UPDATE cart_items
+		SET quantity = $1, discount_type = $2, discount_value = $3, updated_at = $4
+		WHERE id = $5 AND cart_id = $6 AND cart_id IN (SELECT id FROM carts WHERE status = $7)
+		RETURNING updated_at`
+
+	discountType, discountValue, _ := discountToNull(item.Discount)
+
+	err := r.db.QueryRow(
+		query, item.Quantity, discountType, discountValue, time.Now(), item.ID, item.CartID, models.CartStatusOpen,
+	).Scan(&item.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return r.notChanged(item.CartID)
+		}
+		return fmt.Errorf("failed to update cart item: %w", err)
+	}
+	return nil
+}
+
+func (r *cartRepository) DeleteItem(cartID, itemID uint) error {
+	res, err := r.db.Exec(
+		`DELETE FROM cart_items WHERE id = $1 AND cart_id = $2 AND cart_id IN (SELECT id FROM carts WHERE status = $3)`,
+		itemID, cartID, models.CartStatusOpen,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete cart item: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return r.notChanged(cartID)
+	}
+	return nil
+}
+
+// notChanged menjelaskan perubahan yang tidak mengenai baris: ErrCartNotOpen jika keranjangnya ada tetapi
+// tidak OPEN, selain itu sql.ErrNoRows (keranjang atau barisnya tidak ada)
+func (r *cartRepository) notChanged(cartID uint) error {
+	status, err := r.status(cartID)
+	if err != nil {
+		return err
+	}
+	if status != models.CartStatusOpen {
+		return ErrCartNotOpen
+	}
+	return sql.ErrNoRows
+}
+
+// status mengembalikan status keranjang, sql.ErrNoRows jika keranjangnya tidak ada
+func (r *cartRepository) status(cartID uint) (string, error) {
+	var status string
+	err := r.db.QueryRow(`SELECT status FROM carts WHERE id = $1`, cartID).Scan(&status)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to check cart status: %w", err)
+	}
+	return status, err
+}
+
+func (r *cartRepository) ClaimForCheckout(id uint, now time.Time) (bool, error) {
+	query := `
+		UPDATE carts
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND expires_at > $2`
+
+	res, err := r.db.Exec(query, models.CartStatusCheckingOut, now, id, models.CartStatusOpen)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim cart for checkout: %w", err)
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+func (r *cartRepository) ReleaseCheckout(id uint, stockReservationID *uint) error {
+	query := `
+		UPDATE carts
+		SET status = $1, transaction_id = NULL, stock_reservation_id = $2, updated_at = $3
+		WHERE id = $4 AND (status = $5 OR (status = $6 AND transaction_id IN (
+			SELECT id FROM transactions WHERE voided_at IS NOT NULL)))`
+
+	_, err := r.db.Exec(query, models.CartStatusOpen, stockReservationID, time.Now(), id,
+		models.CartStatusCheckingOut, models.CartStatusCheckedOut)
+	return err
+}
+
+func (r *cartRepository) ReleaseStaleCheckout(id uint, before time.Time) (bool, error) {
+	query := `UPDATE carts SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4 AND updated_at <= $5`
+
+	res, err := r.db.Exec(query, models.CartStatusOpen, time.Now(), id, models.CartStatusCheckingOut, before)
+	if err != nil {
+		return false, fmt.Errorf("failed to release stale cart checkout: %w", err)
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// getItems mengambil baris keranjang, dikelompokkan per cart_id
+func (r *cartRepository) getItems(cartIDs []uint) (map[uint][]models.CartItem, error) {
+	items := make(map[uint][]models.CartItem)
+	if len(cartIDs) == 0 {
+		return items, nil
+	}
+
+	ids := make([]int64, 0, len(cartIDs))
+	for _, id := range cartIDs {
+		ids = append(ids, int64(id))
+	}
+
+	query := `
+		SELECT id, cart_id, product_id, variant_id, quantity, discount_type, discount_value, created_at, updated_at
+		FROM cart_items
+		WHERE cart_id = ANY($1)
+		ORDER BY id ASC`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.CartItem
+		var discountType sql.NullString
+		var discountValue money.Money
+		err := rows.Scan(
+			&item.ID,
+			&item.CartID,
+			&item.ProductID,
+			&item.VariantID,
+			&item.Quantity,
+			&discountType,
+			&discountValue,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.Discount = discountFromNull(discountType, discountValue, 0)
+		items[item.CartID] = append(items[item.CartID], item)
+	}
+
+	return items, rows.Err()
+}
+
+func scanCart(row rowScanner) (*models.Cart, error) {
+	var cart models.Cart
+	var discountType sql.NullString
+	var discountValue money.Money
+	var transactionID, stockReservationID sql.NullInt64
+	err := row.Scan(
+		&cart.ID,
+		&cart.TerminalID,
+		&cart.Status,
+		&cart.Label,
+		&cart.CustomerID,
+		&discountType,
+		&discountValue,
+		&cart.VoucherCode,
+		&cart.CreatedBy,
+		&transactionID,
+		&stockReservationID,
+		&cart.ParkedAt,
+		&cart.ExpiresAt,
+		&cart.CreatedAt,
+		&cart.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	cart.Discount = discountFromNull(discountType, discountValue, 0)
+	if transactionID.Valid {
+		id := uint(transactionID.Int64)
+		cart.TransactionID = &id
+	}
+	if stockReservationID.Valid {
+		id := uint(stockReservationID.Int64)
+		cart.StockReservationID = &id
+	}
+	return &cart, nil
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"transaction-service/config"
+	"transaction-service/models"
+)
+
+// ErrIdempotencyLeaseLost dikembalikan Complete dan Release jika key sudah tidak dipegang token tersebut,
+// mis. lease-nya habis dan diambil alih retry
+var ErrIdempotencyLeaseLost = errors.New("idempotency key lease has been lost")
+
+type IdempotencyRepository interface {
+	// Reserve mencoba mengklaim key sampai lockedUntil. Key PROCESSING dengan request yang sama dan lease yang
+	// sudah lewat ikut diklaim. created=true berarti record.LeaseToken adalah token klaim baru untuk Complete
+	// atau Release. created=false berarti key dipegang atau sudah diselesaikan request sebelumnya dan record
+	// yang dikembalikan adalah milik request tersebut.
+	Reserve(key, requestHash string, lockedUntil, expiresAt time.Time) (record *models.IdempotencyKey, created bool, err error)
+	Complete(key, leaseToken string, responseStatus int, responseBody []byte) error
+	Release(key, leaseToken string) error
+}
+
+type idempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository() IdempotencyRepository {
+	return &idempotencyRepository{
+		db: config.DB,
+	}
+}
+
+func (r *idempotencyRepository) Reserve(key, requestHash string, lockedUntil, expiresAt time.Time) (*models.IdempotencyKey, bool, error) {
+	now := time.Now()
+
+	// key yang sudah kadaluarsa boleh dipakai ulang
+	if _, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE expires_at <= $1`, now); err != nil {
+		return nil, false, fmt.Errorf("failed to purge expired idempotency keys: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO idempotency_keys (idempotency_key, request_hash, status, created_at, updated_at, expires_at, locked_until,
+			lease_token)
+		VALUES ($1, $2, $3, $4, $4, $5, $6, gen_random_uuid())
+		ON CONFLICT (idempotency_key) DO NOTHING
+		RETURNING idempotency_key, request_hash, status, created_at, updated_at, expires_at, locked_until, lease_token`
+
+	record := models.IdempotencyKey{}
+	err := r.db.QueryRow(insertQuery, key, requestHash, models.IdempotencyStatusProcessing, now, expiresAt, lockedUntil).Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.Status,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+		&record.ExpiresAt,
+		&record.LockedUntil,
+		&record.LeaseToken,
+	)
+	if err == nil {
+		return &record, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	// pemegang sebelumnya tidak menyelesaikan key sebelum lease-nya habis (mis. proses mati), ambil alih.
+	// Baris tanpa locked_until berasal dari sebelum lease ada dan dianggap sudah habis.
+	takeoverQuery := `
+		UPDATE idempotency_keys
+		SET locked_until = $1, updated_at = $2, lease_token = gen_random_uuid()
+		WHERE idempotency_key = $3 AND request_hash = $4 AND status = $5
+			AND (locked_until IS NULL OR locked_until <= $2)
+		RETURNING idempotency_key, request_hash, status, created_at, updated_at, expires_at, locked_until, lease_token`
+
+	err = r.db.QueryRow(takeoverQuery, lockedUntil, now, key, requestHash, models.IdempotencyStatusProcessing).Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.Status,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+		&record.ExpiresAt,
+		&record.LockedUntil,
+		&record.LeaseToken,
+	)
+	if err == nil {
+		return &record, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("failed to take over idempotency key: %w", err)
+	}
+
+	// key sudah dipakai request lain
+	selectQuery := `
+		SELECT idempotency_key, request_hash, status, COALESCE(response_status, 0), response_body,
+			created_at, updated_at, expires_at, locked_until
+		FROM idempotency_keys
+		WHERE idempotency_key = $1`
+
+	err = r.db.QueryRow(selectQuery, key).Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.Status,
+		&record.ResponseStatus,
+		&record.ResponseBody,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+		&record.ExpiresAt,
+		&record.LockedUntil,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load idempotency key: %w", err)
+	}
+
+	return &record, false, nil
+}
+
+func (r *idempotencyRepository) Complete(key, leaseToken string, responseStatus int, responseBody []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status = $1, response_status = $2, response_body = $3, updated_at = $4, locked_until = NULL, lease_token = NULL
+		WHERE idempotency_key = $5 AND status = $6 AND lease_token = $7`
+
+	res, err := r.db.Exec(query, models.IdempotencyStatusCompleted, responseStatus, responseBody, time.Now(), key,
+		models.IdempotencyStatusProcessing, leaseToken)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return ErrIdempotencyLeaseLost
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Release(key, leaseToken string) error {
+	query := `DELETE FROM idempotency_keys WHERE idempotency_key = $1 AND status = $2 AND lease_token = $3`
+
+	res, err := r.db.Exec(query, key, models.IdempotencyStatusProcessing, leaseToken)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return ErrIdempotencyLeaseLost
+	}
+	return nil
+}
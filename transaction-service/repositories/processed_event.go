@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"transaction-service/events"
+)
+
+// markProcessed mencatat event untuk consumer di DB transaction pemanggil. false berarti event sudah
+// pernah diproses (pengiriman ulang at-least-once), perubahan tidak boleh diterapkan lagi.
+func markProcessed(tx *sql.Tx, consumer string, event events.Event) (bool, error) {
+	res, err := tx.Exec(`
+		INSERT INTO processed_events (consumer, event_id, event_type)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (consumer, event_id) DO NOTHING`,
+		consumer, event.ID, event.Type,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record processed event: %w", err)
+	}
+	affected, _ := res.RowsAffected()
+	return affected == 1, nil
+}
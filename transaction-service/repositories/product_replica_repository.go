@@ -0,0 +1,211 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"transaction-service/config"
+	"transaction-service/events"
+	"transaction-service/models"
+	"transaction-service/quantity"
+)
+
+type ProductReplicaRepository interface {
+	// Sync menyimpan snapshot produk dan kategori dari product-service; yang tidak ada di snapshot ditandai terhapus
+	Sync(products []models.ProductReplica, categories []models.CategoryReplica, syncedAt time.Time) error
+	// ApplyProduct menyimpan data produk dari event product.*; false jika event sudah pernah diproses consumer
+	ApplyProduct(consumer string, event events.Event, product models.ProductReplica) (bool, error)
+	// ApplyStock menyimpan stok dari event stock.changed; reservedDelta mengurangi stok yang ditahan (penjualan)
+	ApplyStock(consumer string, event events.Event, productID uint, stock, reservedDelta quantity.Quantity) (bool, error)
+	// ApplyCategory menyimpan data kategori dari event category.*
+	ApplyCategory(consumer string, event events.Event, category models.CategoryReplica) (bool, error)
+}
+
+type productReplicaRepository struct {
+	db *sql.DB
+}
+
+func NewProductReplicaRepository() ProductReplicaRepository {
+	return &productReplicaRepository{
+		db: config.DB,
+	}
+}
+
+func (r *productReplicaRepository) Sync(products []models.ProductReplica, categories []models.CategoryReplica, syncedAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	upsertQuery := `
+		INSERT INTO product_replicas (product_id, name, price, stock, reserved_stock, available_stock, tax_class, category_id, deleted_at, synced_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULL, $9)
+		ON CONFLICT (product_id) DO UPDATE
+		SET name = EXCLUDED.name,
+			price = EXCLUDED.price,
+			stock = EXCLUDED.stock,
+			reserved_stock = EXCLUDED.reserved_stock,
+			available_stock = EXCLUDED.available_stock,
+			tax_class = EXCLUDED.tax_class,
+			category_id = EXCLUDED.category_id,
+			deleted_at = NULL,
+			synced_at = EXCLUDED.synced_at`
+
+	for _, product := range products {
+		_, err := tx.Exec(upsertQuery,
+			product.ProductID,
+			product.Name,
+			product.Price,
+			product.Stock,
+			product.ReservedStock,
+			product.AvailableStock,
+			product.TaxClass,
+			product.CategoryID,
+			syncedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to upsert product replica %d: %w", product.ProductID, err)
+		}
+	}
+
+	// produk yang tidak ikut tersinkron sudah dihapus di product-service
+	_, err = tx.Exec(
+		`UPDATE product_replicas SET deleted_at = $1 WHERE synced_at < $1 AND deleted_at IS NULL`,
+		syncedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark deleted product replicas: %w", err)
+	}
+
+	categoryQuery := `
+		INSERT INTO category_replicas (category_id, name, parent_id, sort_order, deleted_at, synced_at)
+		VALUES ($1, $2, $3, $4, NULL, $5)
+		ON CONFLICT (category_id) DO UPDATE
+		SET name = EXCLUDED.name,
+			parent_id = EXCLUDED.parent_id,
+			sort_order = EXCLUDED.sort_order,
+			deleted_at = NULL,
+			synced_at = EXCLUDED.synced_at`
+
+	for _, category := range categories {
+		_, err := tx.Exec(categoryQuery, category.CategoryID, category.Name, category.ParentID, category.SortOrder, syncedAt)
+		if err != nil {
+			return fmt.Errorf("failed to upsert category replica %d: %w", category.CategoryID, err)
+		}
+	}
+
+	_, err = tx.Exec(
+		`UPDATE category_replicas SET deleted_at = $1 WHERE synced_at < $1 AND deleted_at IS NULL`,
+		syncedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark deleted category replicas: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// event yang lebih lama dari sinkronisasi terakhir (synced_at) tidak menimpa data yang lebih baru
+func (r *productReplicaRepository) ApplyProduct(consumer string, event events.Event, product models.ProductReplica) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if first, err := markProcessed(tx, consumer, event); err != nil || !first {
+		return false, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO product_replicas (product_id, name, price, stock, reserved_stock, available_stock, tax_class, category_id, deleted_at, synced_at)
+		VALUES ($1, $2, $3, $4, 0, $4, $5, $6, $7, $8)
+		ON CONFLICT (product_id) DO UPDATE
+		SET name = EXCLUDED.name,
+			price = EXCLUDED.price,
+			stock = EXCLUDED.stock,
+			available_stock = GREATEST(EXCLUDED.stock - product_replicas.reserved_stock, 0),
+			tax_class = EXCLUDED.tax_class,
+			category_id = EXCLUDED.category_id,
+			deleted_at = EXCLUDED.deleted_at,
+			synced_at = EXCLUDED.synced_at
+		WHERE product_replicas.synced_at <= EXCLUDED.synced_at`,
+		product.ProductID,
+		product.Name,
+		product.Price,
+		product.Stock,
+		product.TaxClass,
+		product.CategoryID,
+		product.DeletedAt,
+		event.OccurredAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply product event: %w", err)
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *productReplicaRepository) ApplyStock(consumer string, event events.Event, productID uint, stock, reservedDelta quantity.Quantity) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if first, err := markProcessed(tx, consumer, event); err != nil || !first {
+		return false, err
+	}
+
+	// produk yang belum ada di salinan menunggu sinkronisasi berikutnya
+	_, err = tx.Exec(`
+		UPDATE product_replicas
+		SET stock = $1,
+			reserved_stock = GREATEST(reserved_stock + $2, 0),
+			available_stock = GREATEST($1 - GREATEST(reserved_stock + $2, 0), 0),
+			synced_at = $3
+		WHERE product_id = $4 AND synced_at <= $3`,
+		stock, reservedDelta, event.OccurredAt, productID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply stock event: %w", err)
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *productReplicaRepository) ApplyCategory(consumer string, event events.Event, category models.CategoryReplica) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if first, err := markProcessed(tx, consumer, event); err != nil || !first {
+		return false, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO category_replicas (category_id, name, parent_id, sort_order, deleted_at, synced_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (category_id) DO UPDATE
+		SET name = EXCLUDED.name,
+			parent_id = EXCLUDED.parent_id,
+			sort_order = EXCLUDED.sort_order,
+			deleted_at = EXCLUDED.deleted_at,
+			synced_at = EXCLUDED.synced_at
+		WHERE category_replicas.synced_at <= EXCLUDED.synced_at`,
+		category.CategoryID,
+		category.Name,
+		category.ParentID,
+		category.SortOrder,
+		category.DeletedAt,
+		event.OccurredAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply category event: %w", err)
+	}
+
+	return true, tx.Commit()
+}
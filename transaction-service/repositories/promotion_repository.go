@@ -0,0 +1,259 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"transaction-service/config"
+	"transaction-service/models"
+
+	"github.com/lib/pq"
+)
+
+type PromotionRepository interface {
+	Create(promotion *models.Promotion) error
+	GetAll(activeOnly bool) ([]models.Promotion, error)
+	GetByID(id uint) (*models.Promotion, error)
+	Update(id uint, promotion *models.Promotion) error
+	Delete(id uint) error
+	// GetActive mengambil promosi aktif dalam periode berlaku beserta TargetCategoryIDs, filter hari/jam
+	// dilakukan oleh engine
+	GetActive(now time.Time) ([]models.Promotion, error)
+}
+
+type promotionRepository struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository() PromotionRepository {
+	return &promotionRepository{
+		db: config.DB,
+	}
+}
+
+const promotionColumns = `id, name, COALESCE(description, ''), type, rule, priority, stackable, active,
+	starts_at, ends_at, days_of_week, COALESCE(to_char(start_time, 'HH24:MI'), ''), COALESCE(to_char(end_time, 'HH24:MI'), ''),
+	created_at, updated_at`
+
+func (r *promotionRepository) Create(promotion *models.Promotion) error {
+	rule, err := json.Marshal(promotion.Rule)
+	if err != nil {
+		return fmt.Errorf("failed to encode promotion rule: %w", err)
+	}
+
+	query := `
+		INSERT INTO promotions (name, description, type, rule, priority, stackable, active, starts_at, ends_at,
+			days_of_week, start_time, end_time, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, '')::TIME, NULLIF($12, '')::TIME, $13, $13)
+		RETURNING id, created_at, updated_at`
+
+	return r.db.QueryRow(
+		query,
+		promotion.Name,
+		promotion.Description,
+		promotion.Type,
+		rule,
+		promotion.Priority,
+		promotion.Stackable,
+		promotion.Active,
+		promotion.StartsAt,
+		promotion.EndsAt,
+		pq.Array(intsToInt64(promotion.DaysOfWeek)),
+		promotion.StartTime,
+		promotion.EndTime,
+		time.Now(),
+	).Scan(&promotion.ID, &promotion.CreatedAt, &promotion.UpdatedAt)
+}
+
+func (r *promotionRepository) GetAll(activeOnly bool) ([]models.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE deleted_at IS NULL`
+	if activeOnly {
+		query += ` AND active = TRUE`
+	}
+	query += ` ORDER BY priority DESC, id ASC`
+
+	return r.queryPromotions(query)
+}
+
+func (r *promotionRepository) GetActive(now time.Time) ([]models.Promotion, error) {
+	query := `SELECT ` + promotionColumns + `
+		FROM promotions
+		WHERE deleted_at IS NULL
+			AND active = TRUE
+			AND (starts_at IS NULL OR starts_at <= $1)
+			AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY priority DESC, id ASC`
+
+	promotions, err := r.queryPromotions(query, now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range promotions {
+		if len(promotions[i].Rule.CategoryIDs) == 0 {
+			continue
+		}
+		if promotions[i].TargetCategoryIDs, err = r.categoryTree(promotions[i].Rule.CategoryIDs); err != nil {
+			return nil, fmt.Errorf("failed to load categories of promotion %d: %w", promotions[i].ID, err)
+		}
+	}
+	return promotions, nil
+}
+
+// categoryTree mengembalikan kategori beserta semua subkategorinya dari category_replicas. UNION (bukan
+// UNION ALL) membuang kategori yang sudah dikunjungi sehingga data parent yang berputar tetap berhenti.
+func (r *promotionRepository) categoryTree(categoryIDs []uint) ([]uint, error) {
+	ids := make([]int64, 0, len(categoryIDs))
+	for _, id := range categoryIDs {
+		ids = append(ids, int64(id))
+	}
+
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT UNNEST($1::INTEGER[]) AS category_id
+			UNION
+			SELECT c.category_id
+			FROM category_replicas c
+			JOIN tree t ON c.parent_id = t.category_id
+			WHERE c.deleted_at IS NULL
+		)
+		SELECT category_id FROM tree`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tree []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		tree = append(tree, id)
+	}
+	return tree, rows.Err()
+}
+
+func (r *promotionRepository) GetByID(id uint) (*models.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE id = $1 AND deleted_at IS NULL`
+
+	promotion, err := scanPromotion(r.db.QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (r *promotionRepository) Update(id uint, promotion *models.Promotion) error {
+	rule, err := json.Marshal(promotion.Rule)
+	if err != nil {
+		return fmt.Errorf("failed to encode promotion rule: %w", err)
+	}
+
+	query := `
+		UPDATE promotions
+		SET name = $1, description = NULLIF($2, ''), type = $3, rule = $4, priority = $5, stackable = $6, active = $7,
+			starts_at = $8, ends_at = $9, days_of_week = $10, start_time = NULLIF($11, '')::TIME,
+			end_time = NULLIF($12, '')::TIME, updated_at = $13
+		WHERE id = $14 AND deleted_at IS NULL`
+
+	_, err = r.db.Exec(
+		query,
+		promotion.Name,
+		promotion.Description,
+		promotion.Type,
+		rule,
+		promotion.Priority,
+		promotion.Stackable,
+		promotion.Active,
+		promotion.StartsAt,
+		promotion.EndsAt,
+		pq.Array(intsToInt64(promotion.DaysOfWeek)),
+		promotion.StartTime,
+		promotion.EndTime,
+		time.Now(),
+		id,
+	)
+	return err
+}
+
+func (r *promotionRepository) Delete(id uint) error {
+	query := `
+		UPDATE promotions
+		SET deleted_at = $1, active = FALSE
+		WHERE id = $2 AND deleted_at IS NULL`
+
+	_, err := r.db.Exec(query, time.Now(), id)
+	return err
+}
+
+func (r *promotionRepository) queryPromotions(query string, args ...interface{}) ([]models.Promotion, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []models.Promotion
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, *promotion)
+	}
+
+	return promotions, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPromotion(row rowScanner) (*models.Promotion, error) {
+	var promotion models.Promotion
+	var rule []byte
+	var days pq.Int64Array
+
+	err := row.Scan(
+		&promotion.ID,
+		&promotion.Name,
+		&promotion.Description,
+		&promotion.Type,
+		&rule,
+		&promotion.Priority,
+		&promotion.Stackable,
+		&promotion.Active,
+		&promotion.StartsAt,
+		&promotion.EndsAt,
+		&days,
+		&promotion.StartTime,
+		&promotion.EndTime,
+		&promotion.CreatedAt,
+		&promotion.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(rule, &promotion.Rule); err != nil {
+		return nil, fmt.Errorf("failed to decode rule of promotion %d: %w", promotion.ID, err)
+	}
+	for _, day := range days {
+		promotion.DaysOfWeek = append(promotion.DaysOfWeek, int(day))
+	}
+	promotion.Type = strings.ToUpper(promotion.Type)
+
+	return &promotion, nil
+}
+
+func intsToInt64(values []int) []int64 {
+	result := make([]int64, 0, len(values))
+	for _, value := range values {
+		result = append(result, int64(value))
+	}
+	return result
+}
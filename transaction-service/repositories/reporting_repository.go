@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"transaction-service/config"
 	"transaction-service/dto"
+
+	"github.com/lib/pq"
 )
 
 type ReportingRepository interface {
 	GetTransactionSummary(filter dto.ReportingFilterDTO) ([]dto.TransactionSummaryDTO, error)
 	GetProductSalesReport(filter dto.ReportingFilterDTO) ([]dto.ProductSalesReportDTO, error)
 	GetLowStockAlert() ([]dto.LowStockAlertDTO, error)
+	GetPaymentMethodSummary(filter dto.ReportingFilterDTO) ([]dto.PaymentMethodSummaryDTO, error)
+	GetTaxSummary(filter dto.ReportingFilterDTO) ([]dto.TaxSummaryDTO, error)
+	GetCategorySalesReport(filter dto.ReportingFilterDTO) ([]dto.CategorySalesReportDTO, error)
 }
 
 type reportingRepository struct{}
@@ -22,10 +27,17 @@ func (r *reportingRepository) GetTransactionSummary(filter dto.ReportingFilterDT
 	query := `
 		SELECT 
 			id,
+			return_id,
 			transaction_date,
+			gross_amount,
+			discount_amount,
+			tax_amount,
 			total_amount,
 			total_items,
-			total_quantity
+			total_quantity,
+			refunded_amount,
+			net_amount,
+			status
 		FROM v_transaction_summary
 		WHERE 1=1
 	`
@@ -33,6 +45,13 @@ func (r *reportingRepository) GetTransactionSummary(filter dto.ReportingFilterDT
 	args := []interface{}{}
 	argIndex := 1
 
+	switch filter.Entry {
+	case dto.SummaryEntrySale:
+		query += " AND return_id IS NULL"
+	case dto.SummaryEntryReturn:
+		query += " AND return_id IS NOT NULL"
+	}
+
 	// filter berdasarkan tanggal
 	if filter.StartDate != nil {
 		query += " AND transaction_date >= $" + fmt.Sprintf("%d", argIndex)
@@ -41,8 +60,9 @@ func (r *reportingRepository) GetTransactionSummary(filter dto.ReportingFilterDT
 	}
 
 	if filter.EndDate != nil {
-		query += " AND transaction_date <= $" + fmt.Sprintf("%d", argIndex)
-		args = append(args, *filter.EndDate)
+		// end_date mencakup seluruh hari terakhir
+		query += " AND transaction_date < $" + fmt.Sprintf("%d", argIndex) + "::date + 1"
+		args = append(args, filter.EndDate.Format("2006-01-02"))
 		argIndex++
 	}
 
@@ -69,10 +89,17 @@ func (r *reportingRepository) GetTransactionSummary(filter dto.ReportingFilterDT
 		var summary dto.TransactionSummaryDTO
 		err := rows.Scan(
 			&summary.ID,
+			&summary.ReturnID,
 			&summary.TransactionDate,
+			&summary.GrossAmount,
+			&summary.DiscountAmount,
+			&summary.TaxAmount,
 			&summary.TotalAmount,
 			&summary.TotalItems,
 			&summary.TotalQuantity,
+			&summary.RefundedAmount,
+			&summary.NetAmount,
+			&summary.Status,
 		)
 		if err != nil {
 			return nil, err
@@ -91,7 +118,11 @@ func (r *reportingRepository) GetProductSalesReport(filter dto.ReportingFilterDT
 			current_price,
 			current_stock,
 			total_sold,
-			total_revenue
+			total_discount,
+			total_returned,
+			total_refunded,
+			total_revenue,
+			total_voided
 		FROM v_product_sales_report
 		ORDER BY total_sold DESC
 	`
@@ -124,22 +155,111 @@ func (r *reportingRepository) GetProductSalesReport(filter dto.ReportingFilterDT
 			&report.CurrentPrice,
 			&report.CurrentStock,
 			&report.TotalSold,
+			&report.TotalDiscount,
+			&report.TotalReturned,
+			&report.TotalRefunded,
 			&report.TotalRevenue,
+			&report.TotalVoided,
 		)
 		if err != nil {
 			return nil, err
 		}
 		reports = append(reports, report)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := attachVariantSales(reports); err != nil {
+		return nil, err
+	}
 
 	return reports, nil
 }
 
+// attachVariantSales mengisi rincian per varian dengan aturan yang sama seperti v_product_sales_report
+func attachVariantSales(reports []dto.ProductSalesReportDTO) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(reports))
+	index := make(map[uint]int, len(reports))
+	for i, report := range reports {
+		ids = append(ids, int64(report.ID))
+		index[report.ID] = i
+	}
+
+	query := `
+		SELECT
+			s.product_id,
+			s.variant_id,
+			s.variant_name,
+			COALESCE(s.total_sold, 0),
+			COALESCE(s.total_discount, 0),
+			COALESCE(ri.total_returned, 0),
+			COALESCE(ri.total_refunded, 0),
+			COALESCE(s.gross_revenue, 0) - COALESCE(ri.total_refunded, 0),
+			COALESCE(s.total_voided, 0)
+		FROM (
+			SELECT
+				ti.product_id,
+				ti.variant_id,
+				(ARRAY_AGG(COALESCE(ti.variant_name, '') ORDER BY ti.id DESC))[1] AS variant_name,
+				SUM(ti.quantity) FILTER (WHERE t.voided_at IS NULL) AS total_sold,
+				SUM(ti.total_amount) FILTER (WHERE t.voided_at IS NULL) AS gross_revenue,
+				SUM(ti.discount_amount) FILTER (WHERE t.voided_at IS NULL) AS total_discount,
+				SUM(ti.quantity) FILTER (WHERE t.voided_at IS NOT NULL) AS total_voided
+			FROM transaction_items ti
+			JOIN transactions t ON t.id = ti.transaction_id
+			WHERE t.deleted_at IS NULL
+				AND ti.variant_id IS NOT NULL
+				AND ti.product_id = ANY($1)
+			GROUP BY ti.product_id, ti.variant_id
+		) s
+		LEFT JOIN (
+			SELECT ti.product_id, ti.variant_id, SUM(ri.quantity) AS total_returned, SUM(ri.amount) AS total_refunded
+			FROM transaction_return_items ri
+			JOIN transaction_items ti ON ti.id = ri.transaction_item_id
+			WHERE ti.variant_id IS NOT NULL
+			GROUP BY ti.product_id, ti.variant_id
+		) ri ON ri.product_id = s.product_id AND ri.variant_id = s.variant_id
+		ORDER BY s.product_id, 4 DESC, s.variant_id`
+
+	rows, err := config.DB.Query(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID uint
+		var variant dto.VariantSalesDTO
+		err := rows.Scan(
+			&productID,
+			&variant.VariantID,
+			&variant.VariantName,
+			&variant.TotalSold,
+			&variant.TotalDiscount,
+			&variant.TotalReturned,
+			&variant.TotalRefunded,
+			&variant.TotalRevenue,
+			&variant.TotalVoided,
+		)
+		if err != nil {
+			return err
+		}
+		if i, ok := index[productID]; ok {
+			reports[i].Variants = append(reports[i].Variants, variant)
+		}
+	}
+	return rows.Err()
+}
+
 func (r *reportingRepository) GetLowStockAlert() ([]dto.LowStockAlertDTO, error) {
 	query := `
-		SELECT  id, name, price, stock, stock_status
+		SELECT  id, name, price, stock, reserved_stock, available_stock, stock_status
 		FROM v_low_stock_alert
-		ORDER BY stock ASC
+		ORDER BY available_stock ASC
 	`
 
 	rows, err := config.DB.Query(query)
@@ -156,6 +276,8 @@ func (r *reportingRepository) GetLowStockAlert() ([]dto.LowStockAlertDTO, error)
 			&alert.Name,
 			&alert.Price,
 			&alert.Stock,
+			&alert.ReservedStock,
+			&alert.AvailableStock,
 			&alert.StockStatus,
 		)
 		if err != nil {
@@ -166,3 +288,286 @@ func (r *reportingRepository) GetLowStockAlert() ([]dto.LowStockAlertDTO, error)
 
 	return alerts, nil
 }
+
+func (r *reportingRepository) GetPaymentMethodSummary(filter dto.ReportingFilterDTO) ([]dto.PaymentMethodSummaryDTO, error) {
+	salesCondition := ""
+	refundCondition := ""
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.StartDate != nil {
+		salesCondition += " AND t.transaction_date >= $" + fmt.Sprintf("%d", argIndex)
+		refundCondition += " AND tr.return_date >= $" + fmt.Sprintf("%d", argIndex)
+		args = append(args, *filter.StartDate)
+		argIndex++
+	}
+
+	if filter.EndDate != nil {
+		salesCondition += " AND t.transaction_date < $" + fmt.Sprintf("%d", argIndex) + "::date + 1"
+		refundCondition += " AND tr.return_date < $" + fmt.Sprintf("%d", argIndex) + "::date + 1"
+		args = append(args, filter.EndDate.Format("2006-01-02"))
+	}
+
+	// transaksi void tidak dihitung sebagai pendapatan. Retur tidak mencatat metode refund, jadi refund tiap retur
+	// dibagi ke tender transaksinya sebanding amount: bagian kumulatif dikurangi bagian kumulatif sebelumnya,
+	// sehingga pembulatan per tender selalu berjumlah tepat refund returnya.
+	query := fmt.Sprintf(`
+		WITH sales AS (
+			SELECT tp.method, COUNT(DISTINCT tp.transaction_id) AS transaction_count, SUM(tp.amount) AS total_amount
+			FROM transaction_payments tp
+			JOIN transactions t ON t.id = tp.transaction_id
+			WHERE t.deleted_at IS NULL
+				AND t.voided_at IS NULL%s
+			GROUP BY tp.method
+		),
+		refund_shares AS (
+			SELECT
+				tp.method,
+				tr.id AS return_id,
+				ROUND(tr.total_amount * SUM(tp.amount) OVER w / t.total_amount, 2)
+					- ROUND(tr.total_amount * (SUM(tp.amount) OVER w - tp.amount) / t.total_amount, 2) AS amount
+			FROM transaction_returns tr
+			JOIN transactions t ON t.id = tr.transaction_id
+			JOIN transaction_payments tp ON tp.transaction_id = t.id
+			WHERE t.deleted_at IS NULL
+				AND t.total_amount > 0%s
+			WINDOW w AS (PARTITION BY tr.id ORDER BY tp.id)
+		),
+		refunds AS (
+			SELECT method, COUNT(DISTINCT return_id) AS return_count, SUM(amount) AS refunded_amount
+			FROM refund_shares
+			GROUP BY method
+		)
+		SELECT
+			COALESCE(s.method, f.method),
+			COALESCE(s.transaction_count, 0),
+			COALESCE(s.total_amount, 0),
+			COALESCE(f.return_count, 0),
+			COALESCE(f.refunded_amount, 0),
+			COALESCE(s.total_amount, 0) - COALESCE(f.refunded_amount, 0)
+		FROM sales s
+		FULL OUTER JOIN refunds f ON f.method = s.method
+		ORDER BY 3 DESC, 1`, salesCondition, refundCondition)
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []dto.PaymentMethodSummaryDTO
+	for rows.Next() {
+		var summary dto.PaymentMethodSummaryDTO
+		err := rows.Scan(
+			&summary.Method,
+			&summary.TransactionCount,
+			&summary.TotalAmount,
+			&summary.ReturnCount,
+			&summary.RefundedAmount,
+			&summary.NetAmount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// GetTaxSummary merekap pajak penjualan (tanpa transaksi void) dan pajak atas retur dalam periode.
+// end_date inklusif: seluruh hari pada end_date ikut dihitung.
+func (r *reportingRepository) GetTaxSummary(filter dto.ReportingFilterDTO) ([]dto.TaxSummaryDTO, error) {
+	salesCondition := ""
+	refundCondition := ""
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.StartDate != nil {
+		salesCondition += " AND t.transaction_date >= $" + fmt.Sprintf("%d", argIndex)
+		refundCondition += " AND tr.return_date >= $" + fmt.Sprintf("%d", argIndex)
+		args = append(args, *filter.StartDate)
+		argIndex++
+	}
+
+	if filter.EndDate != nil {
+		salesCondition += " AND t.transaction_date < $" + fmt.Sprintf("%d", argIndex) + "::date + 1"
+		refundCondition += " AND tr.return_date < $" + fmt.Sprintf("%d", argIndex) + "::date + 1"
+		args = append(args, filter.EndDate.Format("2006-01-02"))
+	}
+
+	query := fmt.Sprintf(`
+		WITH sales AS (
+			SELECT ti.tax_class, ti.tax_rate, SUM(ti.taxable_amount) AS taxable_amount, SUM(ti.tax_amount) AS tax_amount
+			FROM transaction_items ti
+			JOIN transactions t ON t.id = ti.transaction_id
+			WHERE t.deleted_at IS NULL
+				AND t.voided_at IS NULL %s
+			GROUP BY ti.tax_class, ti.tax_rate
+		),
+		refunds AS (
+			SELECT ti.tax_class, ti.tax_rate, SUM(ri.amount - ri.tax_amount) AS taxable_amount, SUM(ri.tax_amount) AS tax_amount
+			FROM transaction_return_items ri
+			JOIN transaction_returns tr ON tr.id = ri.return_id
+			JOIN transaction_items ti ON ti.id = ri.transaction_item_id
+			WHERE 1=1 %s
+			GROUP BY ti.tax_class, ti.tax_rate
+		)
+		SELECT
+			COALESCE(s.tax_class, f.tax_class),
+			COALESCE(s.tax_rate, f.tax_rate),
+			COALESCE(s.taxable_amount, 0),
+			COALESCE(s.tax_amount, 0),
+			COALESCE(f.taxable_amount, 0),
+			COALESCE(f.tax_amount, 0)
+		FROM sales s
+		FULL OUTER JOIN refunds f ON f.tax_class = s.tax_class AND f.tax_rate = s.tax_rate
+		ORDER BY 1, 2`, salesCondition, refundCondition)
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []dto.TaxSummaryDTO
+	for rows.Next() {
+		var summary dto.TaxSummaryDTO
+		err := rows.Scan(
+			&summary.TaxClass,
+			&summary.TaxRate,
+			&summary.TaxableAmount,
+			&summary.TaxAmount,
+			&summary.RefundedTaxableAmount,
+			&summary.RefundedTaxAmount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
+
+// GetCategorySalesReport merekap penjualan per kategori dari category_replicas, setiap kategori termasuk
+// semua subkategorinya. Penjualan (tanpa void) dihitung per transaction_date, retur per return_date;
+// end_date inklusif. Produk tanpa kategori tidak masuk laporan ini.
+func (r *reportingRepository) GetCategorySalesReport(filter dto.ReportingFilterDTO) ([]dto.CategorySalesReportDTO, error) {
+	salesCondition := ""
+	refundCondition := ""
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.StartDate != nil {
+		salesCondition += " AND t.transaction_date >= $" + fmt.Sprintf("%d", argIndex)
+		refundCondition += " AND tr.return_date >= $" + fmt.Sprintf("%d", argIndex)
+		args = append(args, *filter.StartDate)
+		argIndex++
+	}
+
+	if filter.EndDate != nil {
+		salesCondition += " AND t.transaction_date < $" + fmt.Sprintf("%d", argIndex) + "::date + 1"
+		refundCondition += " AND tr.return_date < $" + fmt.Sprintf("%d", argIndex) + "::date + 1"
+		args = append(args, filter.EndDate.Format("2006-01-02"))
+	}
+
+	// tree menyusun kategori aktif dari akar; ancestors berisi kategori itu sendiri dan semua leluhurnya,
+	// sehingga penjualan satu produk ikut dihitung di setiap leluhur kategorinya
+	query := fmt.Sprintf(`
+		WITH RECURSIVE tree AS (
+			SELECT category_id AS id, name, parent_id, 0 AS depth, name::TEXT AS path,
+				ARRAY[category_id] AS ancestors, ARRAY[sort_order, category_id] AS sort_path
+			FROM category_replicas
+			WHERE parent_id IS NULL AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.category_id, c.name, c.parent_id, t.depth + 1, t.path || ' > ' || c.name,
+				t.ancestors || c.category_id, t.sort_path || ARRAY[c.sort_order, c.category_id]
+			FROM category_replicas c
+			JOIN tree t ON c.parent_id = t.id
+			WHERE c.deleted_at IS NULL
+		),
+		sales AS (
+			SELECT
+				ti.product_id,
+				SUM(ti.quantity) FILTER (WHERE t.voided_at IS NULL) AS total_sold,
+				SUM(ti.total_amount) FILTER (WHERE t.voided_at IS NULL) AS gross_revenue,
+				SUM(ti.discount_amount) FILTER (WHERE t.voided_at IS NULL) AS total_discount,
+				SUM(ti.quantity) FILTER (WHERE t.voided_at IS NOT NULL) AS total_voided
+			FROM transaction_items ti
+			JOIN transactions t ON t.id = ti.transaction_id
+			WHERE t.deleted_at IS NULL %s
+			GROUP BY ti.product_id
+		),
+		refunds AS (
+			SELECT ri.product_id, SUM(ri.quantity) AS total_returned, SUM(ri.amount) AS total_refunded
+			FROM transaction_return_items ri
+			JOIN transaction_returns tr ON tr.id = ri.return_id
+			WHERE 1=1 %s
+			GROUP BY ri.product_id
+		),
+		product_totals AS (
+			SELECT
+				UNNEST(d.ancestors) AS category_id,
+				p.product_id,
+				p.deleted_at,
+				COALESCE(s.total_sold, 0) AS total_sold,
+				COALESCE(s.total_discount, 0) AS total_discount,
+				COALESCE(f.total_returned, 0) AS total_returned,
+				COALESCE(f.total_refunded, 0) AS total_refunded,
+				COALESCE(s.gross_revenue, 0) - COALESCE(f.total_refunded, 0) AS total_revenue,
+				COALESCE(s.total_voided, 0) AS total_voided
+			FROM product_replicas p
+			JOIN tree d ON d.id = p.category_id
+			LEFT JOIN sales s ON s.product_id = p.product_id
+			LEFT JOIN refunds f ON f.product_id = p.product_id
+		)
+		SELECT
+			t.id,
+			t.name,
+			t.parent_id,
+			t.depth,
+			t.path,
+			COUNT(pt.product_id) FILTER (WHERE pt.deleted_at IS NULL),
+			COALESCE(SUM(pt.total_sold), 0),
+			COALESCE(SUM(pt.total_discount), 0),
+			COALESCE(SUM(pt.total_returned), 0),
+			COALESCE(SUM(pt.total_refunded), 0),
+			COALESCE(SUM(pt.total_revenue), 0),
+			COALESCE(SUM(pt.total_voided), 0)
+		FROM tree t
+		LEFT JOIN product_totals pt ON pt.category_id = t.id
+		GROUP BY t.id, t.name, t.parent_id, t.depth, t.path, t.sort_path
+		ORDER BY t.sort_path`, salesCondition, refundCondition)
+
+	rows, err := config.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []dto.CategorySalesReportDTO
+	for rows.Next() {
+		var report dto.CategorySalesReportDTO
+		err := rows.Scan(
+			&report.ID,
+			&report.Name,
+			&report.ParentID,
+			&report.Depth,
+			&report.Path,
+			&report.ProductCount,
+			&report.TotalSold,
+			&report.TotalDiscount,
+			&report.TotalReturned,
+			&report.TotalRefunded,
+			&report.TotalRevenue,
+			&report.TotalVoided,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
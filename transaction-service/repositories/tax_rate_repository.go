@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+	"transaction-service/config"
+	"transaction-service/models"
+)
+
+type TaxRateRepository interface {
+	GetAll() ([]models.TaxRate, error)
+	GetByClass(taxClass string) (*models.TaxRate, error)
+	// Upsert membuat atau mengubah tarif untuk satu kelas pajak
+	Upsert(rate *models.TaxRate) error
+}
+
+type taxRateRepository struct {
+	db *sql.DB
+}
+
+func NewTaxRateRepository() TaxRateRepository {
+	return &taxRateRepository{
+		db: config.DB,
+	}
+}
+
+func (r *taxRateRepository) GetAll() ([]models.TaxRate, error) {
+	query := `
+		SELECT tax_class, name, rate, active, created_at, updated_at
+		FROM tax_rates
+		ORDER BY tax_class ASC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []models.TaxRate
+	for rows.Next() {
+		var rate models.TaxRate
+		err := rows.Scan(&rate.TaxClass, &rate.Name, &rate.Rate, &rate.Active, &rate.CreatedAt, &rate.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
+
+func (r *taxRateRepository) GetByClass(taxClass string) (*models.TaxRate, error) {
+	query := `
+		SELECT tax_class, name, rate, active, created_at, updated_at
+		FROM tax_rates
+		WHERE tax_class = $1`
+
+	var rate models.TaxRate
+	err := r.db.QueryRow(query, taxClass).Scan(&rate.TaxClass, &rate.Name, &rate.Rate, &rate.Active, &rate.CreatedAt, &rate.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *taxRateRepository) Upsert(rate *models.TaxRate) error {
+	query := `
+		INSERT INTO tax_rates (tax_class, name, rate, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (tax_class) DO UPDATE
+		SET name = EXCLUDED.name, rate = EXCLUDED.rate, active = EXCLUDED.active, updated_at = EXCLUDED.updated_at
+		RETURNING created_at, updated_at`
+
+	return r.db.QueryRow(query, rate.TaxClass, rate.Name, rate.Rate, rate.Active, time.Now()).
+		Scan(&rate.CreatedAt, &rate.UpdatedAt)
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"transaction-service/models"
+	"transaction-service/numbering"
+)
+
+// assignInvoiceNumber mengambil nomor urut berikutnya di dalam DB transaction checkout. Baris
+// invoice_sequences terkunci oleh UPSERT sampai commit, sehingga checkout bersamaan pada sequence
+// yang sama mengantre; jika checkout gagal, kenaikan nomor ikut di-rollback sehingga tidak ada
+// nomor yang terlewat. Dipanggil paling akhir supaya kunci dipegang sesingkat mungkin.
+func (r *transactionRepository) assignInvoiceNumber(tx *sql.Tx, transaction *models.Transaction, pattern numbering.Pattern, now time.Time) error {
+	date := transaction.TransactionDate.In(time.Local)
+	key := pattern.SequenceKey(date)
+
+	var sequence int64
+	err := tx.QueryRow(`
+		INSERT INTO invoice_sequences (sequence_key, last_number, updated_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (sequence_key) DO UPDATE
+		SET last_number = invoice_sequences.last_number + 1, updated_at = EXCLUDED.updated_at
+		RETURNING last_number`,
+		key, now,
+	).Scan(&sequence)
+	if err != nil {
+		return fmt.Errorf("failed to allocate invoice number: %w", err)
+	}
+
+	transaction.InvoiceNumber = pattern.Format(date, sequence)
+	_, err = tx.Exec(`UPDATE transactions SET invoice_number = $1 WHERE id = $2`, transaction.InvoiceNumber, transaction.ID)
+	if err != nil {
+		return fmt.Errorf("failed to store invoice number: %w", err)
+	}
+	return nil
+}
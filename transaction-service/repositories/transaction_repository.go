@@ -5,14 +5,33 @@ import (
 	"fmt"
 	"time"
 	"transaction-service/config"
+	"transaction-service/events"
 	"transaction-service/models"
+	"transaction-service/money"
+	"transaction-service/numbering"
+	"transaction-service/quantity"
 )
 
 type TransactionRepository interface {
-	Create(transaction *models.Transaction) error
+	Create(transaction *models.Transaction, invoicePattern numbering.Pattern) error
 	GetAll(page, limit int, search, sortBy, order string) ([]models.Transaction, int, error)
 	GetByID(id uint) (*models.Transaction, error)
 	GetTransactionItems(transactionID uint) ([]models.TransactionItem, error)
+	GetTransactionPayments(transactionID uint) ([]models.TransactionPayment, error)
+	CreateReturn(ret *models.TransactionReturn) error
+	GetReturnsByTransactionID(transactionID uint) ([]models.TransactionReturn, error)
+	GetReturnedQuantities(transactionID uint) (map[uint]quantity.Quantity, error)
+	// GetRestockPendingReturns mengembalikan retur yang stoknya belum pasti dikembalikan, terlama dulu
+	GetRestockPendingReturns(limit int) ([]models.TransactionReturn, error)
+	ClearReturnRestockPending(id uint) error
+	// Void membatalkan transaksi. allowClosedDay hanya untuk void sistem atas penjualan yang stoknya
+	// tidak pernah dikurangi, void lain ditolak jika hari usahanya sudah ditutup
+	Void(id uint, voidedBy, reason string, voidedAt time.Time, allowClosedDay bool) error
+	// MarkStockPending menandai transaksi yang hasil konfirmasi stoknya tidak diketahui, lihat GetStockPending
+	MarkStockPending(id uint, at time.Time) error
+	// GetStockPending mengembalikan transaksi yang belum di-void dan masih menunggu rekonsiliasi stok, terlama dulu
+	GetStockPending(limit int) ([]models.Transaction, error)
+	ClearStockPending(id uint) error
 }
 
 type transactionRepository struct {
@@ -25,7 +44,7 @@ func NewTransactionRepository() TransactionRepository {
 	}
 }
 
-func (r *transactionRepository) Create(transaction *models.Transaction) error {
+func (r *transactionRepository) Create(transaction *models.Transaction, invoicePattern numbering.Pattern) error {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return err
@@ -33,15 +52,34 @@ func (r *transactionRepository) Create(transaction *models.Transaction) error {
 	defer tx.Rollback()
 
 	query := `
-		INSERT INTO transactions (transaction_date, total_amount, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4) 
+		INSERT INTO transactions (transaction_date, gross_amount, discount_amount, promotion_discount_amount, cart_discount_type,
+			cart_discount_value, cart_discount_amount, customer_id, voucher_code, voucher_discount_amount, prices_include_tax,
+			tax_amount, total_amount, paid_amount, change_amount, stock_reservation_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 		RETURNING id, created_at, updated_at`
 
+	cartType, cartValue, cartAmount := discountToNull(transaction.CartDiscount)
+	voucherCode, voucherAmount := voucherToNull(transaction.Voucher)
+
 	now := time.Now()
 	err = tx.QueryRow(
 		query,
 		transaction.TransactionDate,
+		transaction.GrossAmount,
+		transaction.DiscountAmount,
+		transaction.PromotionDiscountAmount,
+		cartType,
+		cartValue,
+		cartAmount,
+		transaction.CustomerID,
+		voucherCode,
+		voucherAmount,
+		transaction.PricesIncludeTax,
+		transaction.TaxAmount,
 		transaction.TotalAmount,
+		transaction.PaidAmount,
+		transaction.ChangeAmount,
+		transaction.StockReservationID,
 		now,
 		now,
 	).Scan(&transaction.ID, &transaction.CreatedAt, &transaction.UpdatedAt)
@@ -54,16 +92,39 @@ func (r *transactionRepository) Create(transaction *models.Transaction) error {
 		item.TransactionID = transaction.ID
 
 		itemQuery := `
-			INSERT INTO transaction_items (transaction_id, product_id, quantity, subtotal, created_at, updated_at) 
-			VALUES ($1, $2, $3, $4, $5, $6) 
+			INSERT INTO transaction_items (transaction_id, product_id, product_name, product_sku, variant_id, variant_name,
+				unit_price, quantity, sold_by_weight, gross_amount, promotion_discount_amount, discount_type, discount_value,
+				line_discount_amount, discount_amount, subtotal, tax_class, tax_rate, taxable_amount, tax_amount, total_amount,
+				created_at, updated_at)
+			VALUES ($1, $2, $3, NULLIF($4, ''), $5, NULLIF($6, ''), $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18,
+				$19, $20, $21, $22, $23)
 			RETURNING id, created_at, updated_at`
 
+		lineType, lineValue, lineAmount := discountToNull(item.LineDiscount)
+
 		err = tx.QueryRow(
 			itemQuery,
 			item.TransactionID,
 			item.ProductID,
+			item.ProductName,
+			item.ProductSKU,
+			item.VariantID,
+			item.VariantName,
+			item.UnitPrice,
 			item.Quantity,
+			item.SoldByWeight,
+			item.GrossAmount,
+			item.PromotionDiscountAmount,
+			lineType,
+			lineValue,
+			lineAmount,
+			item.DiscountAmount,
 			item.Subtotal,
+			item.TaxClass,
+			item.TaxRate,
+			item.TaxableAmount,
+			item.TaxAmount,
+			item.TotalAmount,
 			now,
 			now,
 		).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt)
@@ -71,25 +132,116 @@ func (r *transactionRepository) Create(transaction *models.Transaction) error {
 			return fmt.Errorf("failed to insert transaction item: %w", err)
 		}
 
-		updateStockQuery := `
-			UPDATE products 
-			SET stock = stock - $1, updated_at = $2
-			WHERE id = $3 AND stock >= $1 AND deleted_at IS NULL`
+		for j := range item.Promotions {
+			promotion := &item.Promotions[j]
+			promotion.TransactionItemID = item.ID
+
+			err = tx.QueryRow(`
+				INSERT INTO transaction_item_promotions (transaction_item_id, promotion_id, promotion_name, discount_amount, created_at)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id`,
+				promotion.TransactionItemID,
+				promotion.PromotionID,
+				promotion.PromotionName,
+				promotion.DiscountAmount,
+				now,
+			).Scan(&promotion.ID)
+			if err != nil {
+				return fmt.Errorf("failed to insert applied promotion: %w", err)
+			}
+		}
+	}
+
+	if transaction.Voucher != nil {
+		if err := r.redeemVoucher(tx, transaction, now); err != nil {
+			return err
+		}
+	}
+
+	for i := range transaction.Payments {
+		payment := &transaction.Payments[i]
+		payment.TransactionID = transaction.ID
+
+		paymentQuery := `
+			INSERT INTO transaction_payments (transaction_id, method, tendered_amount, amount, reference, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+			RETURNING id, created_at, updated_at`
 
-		res, err := tx.Exec(updateStockQuery, item.Quantity, now, item.ProductID)
+		err = tx.QueryRow(
+			paymentQuery,
+			payment.TransactionID,
+			payment.Method,
+			payment.TenderedAmount,
+			payment.Amount,
+			payment.Reference,
+			now,
+			now,
+		).Scan(&payment.ID, &payment.CreatedAt, &payment.UpdatedAt)
 		if err != nil {
-			return fmt.Errorf("failed to update stock for product_id %d: %w", item.ProductID, err)
+			return fmt.Errorf("failed to insert transaction payment: %w", err)
 		}
+	}
+
+	if err := r.assignInvoiceNumber(tx, transaction, invoicePattern, now); err != nil {
+		return err
+	}
 
-		affected, _ := res.RowsAffected()
-		if affected == 0 {
-			return fmt.Errorf("insufficient stock for product_id %d", item.ProductID)
+	// keranjang selesai bersama transaksinya, sehingga tidak ada transaksi tanpa keranjang CHECKED_OUT atau
+	// sebaliknya. Keranjang yang klaim checkout-nya sudah dilepas (timeout) membatalkan transaksi ini.
+	if transaction.CartID != nil {
+		res, err := tx.Exec(
+			`UPDATE carts SET status = $1, transaction_id = $2, updated_at = $3 WHERE id = $4 AND status = $5`,
+			models.CartStatusCheckedOut, transaction.ID, now, *transaction.CartID, models.CartStatusCheckingOut,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to check out cart: %w", err)
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return fmt.Errorf("cart %d is no longer being checked out", *transaction.CartID)
 		}
 	}
 
+	// event ditulis di DB transaction yang sama, sehingga hanya terkirim jika transaksi tersimpan
+	if err := writeOutbox(tx, events.TransactionCreated, "transaction", transaction.ID, transactionCreatedPayload(transaction), now); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
+func transactionCreatedPayload(transaction *models.Transaction) events.TransactionCreatedPayload {
+	payload := events.TransactionCreatedPayload{
+		ID:              transaction.ID,
+		InvoiceNumber:   transaction.InvoiceNumber,
+		TransactionDate: transaction.TransactionDate,
+		CustomerID:      transaction.CustomerID,
+		GrossAmount:     transaction.GrossAmount,
+		DiscountAmount:  transaction.DiscountAmount,
+		TaxAmount:       transaction.TaxAmount,
+		TotalAmount:     transaction.TotalAmount,
+		Items:           make([]events.TransactionItemPayload, 0, len(transaction.TransactionItems)),
+		Payments:        make([]events.TransactionPaymentPayload, 0, len(transaction.Payments)),
+	}
+	for _, item := range transaction.TransactionItems {
+		payload.Items = append(payload.Items, events.TransactionItemPayload{
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			ProductName: item.ProductName,
+			VariantName: item.VariantName,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			TotalAmount: item.TotalAmount,
+		})
+	}
+	for _, payment := range transaction.Payments {
+		payload.Payments = append(payload.Payments, events.TransactionPaymentPayload{
+			Method: payment.Method,
+			Amount: payment.Amount,
+		})
+	}
+	return payload
+}
+
 func (r *transactionRepository) GetAll(page, limit int, search, sortBy, order string) ([]models.Transaction, int, error) {
 	// Set defaults
 	if page < 1 {
@@ -108,7 +260,7 @@ func (r *transactionRepository) GetAll(page, limit int, search, sortBy, order st
 	}
 
 	// Validate sort parameters
-	allowedSort := map[string]bool{"created_at": true, "transaction_date": true, "total_amount": true, "id": true}
+	allowedSort := map[string]bool{"created_at": true, "transaction_date": true, "total_amount": true, "id": true, "invoice_number": true}
 	if !allowedSort[sortBy] {
 		sortBy = "created_at"
 	}
@@ -125,29 +277,28 @@ func (r *transactionRepository) GetAll(page, limit int, search, sortBy, order st
 	if search != "" {
 		like := "%" + search + "%"
 
-		// NOTE: saya asumsikan nama kolom produk di tabel products adalah "name".
-		// Jika di DB kamu berbeda (mis. "product_name"), ganti p.name -> p.product_name.
+		// pencarian nama/SKU produk memakai snapshot di transaction_items
 		searchConditionCount = `AND (
 			CAST(t.id AS TEXT) ILIKE $1
+			OR t.invoice_number ILIKE $1
 			OR CAST(t.transaction_date AS TEXT) ILIKE $1
 			OR CAST(t.total_amount AS TEXT) ILIKE $1
 			OR EXISTS (
 				SELECT 1 FROM transaction_items ti
-				JOIN products p ON p.id = ti.product_id
 				WHERE ti.transaction_id = t.id
-				  AND p.name ILIKE $1
+				  AND (ti.product_name ILIKE $1 OR ti.product_sku ILIKE $1)
 			)
 		)`
 
 		searchConditionMain = `AND (
 			CAST(t.id AS TEXT) ILIKE $3
+			OR t.invoice_number ILIKE $3
 			OR CAST(t.transaction_date AS TEXT) ILIKE $3
 			OR CAST(t.total_amount AS TEXT) ILIKE $3
 			OR EXISTS (
 				SELECT 1 FROM transaction_items ti
-				JOIN products p ON p.id = ti.product_id
 				WHERE ti.transaction_id = t.id
-				  AND p.name ILIKE $3
+				  AND (ti.product_name ILIKE $3 OR ti.product_sku ILIKE $3)
 			)
 		)`
 
@@ -168,7 +319,11 @@ func (r *transactionRepository) GetAll(page, limit int, search, sortBy, order st
 
 	// Main query
 	query := fmt.Sprintf(`
-		SELECT id, transaction_date, total_amount, created_at, updated_at
+		SELECT id, COALESCE(invoice_number, ''), transaction_date, gross_amount, discount_amount, promotion_discount_amount,
+			cart_discount_type, cart_discount_value, cart_discount_amount, COALESCE(customer_id, ''),
+			voucher_code, voucher_discount_amount, prices_include_tax, tax_amount,
+			total_amount, paid_amount, change_amount, voided_at, COALESCE(voided_by, ''), COALESCE(void_reason, ''),
+			stock_reservation_id, stock_pending_at, created_at, updated_at
 		FROM transactions t
 		WHERE t.deleted_at IS NULL %s
 		ORDER BY %s %s
@@ -183,16 +338,39 @@ func (r *transactionRepository) GetAll(page, limit int, search, sortBy, order st
 	var transactions []models.Transaction
 	for rows.Next() {
 		var transaction models.Transaction
+		var cartType, voucherCode sql.NullString
+		var cartValue, cartAmount, voucherAmount money.Money
 		err := rows.Scan(
 			&transaction.ID,
+			&transaction.InvoiceNumber,
 			&transaction.TransactionDate,
+			&transaction.GrossAmount,
+			&transaction.DiscountAmount,
+			&transaction.PromotionDiscountAmount,
+			&cartType,
+			&cartValue,
+			&cartAmount,
+			&transaction.CustomerID,
+			&voucherCode,
+			&voucherAmount,
+			&transaction.PricesIncludeTax,
+			&transaction.TaxAmount,
 			&transaction.TotalAmount,
+			&transaction.PaidAmount,
+			&transaction.ChangeAmount,
+			&transaction.VoidedAt,
+			&transaction.VoidedBy,
+			&transaction.VoidReason,
+			&transaction.StockReservationID,
+			&transaction.StockPendingAt,
 			&transaction.CreatedAt,
 			&transaction.UpdatedAt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan transaction: %w", err)
 		}
+		transaction.CartDiscount = discountFromNull(cartType, cartValue, cartAmount)
+		transaction.Voucher = voucherFromNull(voucherCode, voucherAmount)
 
 		// Get transaction items (as you had)
 		items, err := r.GetTransactionItems(transaction.ID)
@@ -201,31 +379,62 @@ func (r *transactionRepository) GetAll(page, limit int, search, sortBy, order st
 		}
 		transaction.TransactionItems = items
 
+		payments, err := r.GetTransactionPayments(transaction.ID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get transaction payments: %w", err)
+		}
+		transaction.Payments = payments
+
 		transactions = append(transactions, transaction)
 	}
 
 	return transactions, totalCount, nil
 }
 
-
-
 func (r *transactionRepository) GetByID(id uint) (*models.Transaction, error) {
 	query := `
-		SELECT id, transaction_date, total_amount, created_at, updated_at 
-		FROM transactions 
+		SELECT id, COALESCE(invoice_number, ''), transaction_date, gross_amount, discount_amount, promotion_discount_amount,
+			cart_discount_type, cart_discount_value, cart_discount_amount, COALESCE(customer_id, ''),
+			voucher_code, voucher_discount_amount, prices_include_tax, tax_amount,
+			total_amount, paid_amount, change_amount, voided_at, COALESCE(voided_by, ''), COALESCE(void_reason, ''),
+			stock_reservation_id, stock_pending_at, created_at, updated_at
+		FROM transactions
 		WHERE id = $1 AND deleted_at IS NULL`
 
 	var transaction models.Transaction
+	var cartType, voucherCode sql.NullString
+	var cartValue, cartAmount, voucherAmount money.Money
 	err := r.db.QueryRow(query, id).Scan(
 		&transaction.ID,
+		&transaction.InvoiceNumber,
 		&transaction.TransactionDate,
+		&transaction.GrossAmount,
+		&transaction.DiscountAmount,
+		&transaction.PromotionDiscountAmount,
+		&cartType,
+		&cartValue,
+		&cartAmount,
+		&transaction.CustomerID,
+		&voucherCode,
+		&voucherAmount,
+		&transaction.PricesIncludeTax,
+		&transaction.TaxAmount,
 		&transaction.TotalAmount,
+		&transaction.PaidAmount,
+		&transaction.ChangeAmount,
+		&transaction.VoidedAt,
+		&transaction.VoidedBy,
+		&transaction.VoidReason,
+		&transaction.StockReservationID,
+		&transaction.StockPendingAt,
 		&transaction.CreatedAt,
 		&transaction.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+	transaction.CartDiscount = discountFromNull(cartType, cartValue, cartAmount)
+	transaction.Voucher = voucherFromNull(voucherCode, voucherAmount)
 
 	// Get transaction items
 	items, err := r.GetTransactionItems(transaction.ID)
@@ -234,13 +443,21 @@ func (r *transactionRepository) GetByID(id uint) (*models.Transaction, error) {
 	}
 	transaction.TransactionItems = items
 
+	payments, err := r.GetTransactionPayments(transaction.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction payments: %w", err)
+	}
+	transaction.Payments = payments
+
 	return &transaction, nil
 }
 
 func (r *transactionRepository) GetTransactionItems(transactionID uint) ([]models.TransactionItem, error) {
 	query := `
-		SELECT id, transaction_id, product_id, quantity, subtotal, created_at, updated_at 
-		FROM transaction_items 
+		SELECT id, transaction_id, product_id, product_name, COALESCE(product_sku, ''), variant_id, COALESCE(variant_name, ''), unit_price,
+			quantity, sold_by_weight, gross_amount, promotion_discount_amount, discount_type, discount_value, line_discount_amount,
+			discount_amount, subtotal, tax_class, tax_rate, taxable_amount, tax_amount, total_amount, created_at, updated_at
+		FROM transaction_items
 		WHERE transaction_id = $1 
 		ORDER BY created_at ASC`
 
@@ -253,20 +470,157 @@ func (r *transactionRepository) GetTransactionItems(transactionID uint) ([]model
 	var items []models.TransactionItem
 	for rows.Next() {
 		var item models.TransactionItem
+		var lineType sql.NullString
+		var lineValue, lineAmount money.Money
 		err := rows.Scan(
 			&item.ID,
 			&item.TransactionID,
 			&item.ProductID,
+			&item.ProductName,
+			&item.ProductSKU,
+			&item.VariantID,
+			&item.VariantName,
+			&item.UnitPrice,
 			&item.Quantity,
+			&item.SoldByWeight,
+			&item.GrossAmount,
+			&item.PromotionDiscountAmount,
+			&lineType,
+			&lineValue,
+			&lineAmount,
+			&item.DiscountAmount,
 			&item.Subtotal,
+			&item.TaxClass,
+			&item.TaxRate,
+			&item.TaxableAmount,
+			&item.TaxAmount,
+			&item.TotalAmount,
 			&item.CreatedAt,
 			&item.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		item.LineDiscount = discountFromNull(lineType, lineValue, lineAmount)
 		items = append(items, item)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	promotions, err := r.getAppliedPromotions(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Promotions = promotions[items[i].ID]
+	}
 
 	return items, nil
-}
\ No newline at end of file
+}
+
+// getAppliedPromotions mengambil promosi yang dipakai per baris, dikelompokkan per transaction_item_id
+func (r *transactionRepository) getAppliedPromotions(transactionID uint) (map[uint][]models.AppliedPromotion, error) {
+	query := `
+		SELECT tip.id, tip.transaction_item_id, tip.promotion_id, tip.promotion_name, tip.discount_amount
+		FROM transaction_item_promotions tip
+		JOIN transaction_items ti ON ti.id = tip.transaction_item_id
+		WHERE ti.transaction_id = $1
+		ORDER BY tip.id ASC`
+
+	rows, err := r.db.Query(query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := make(map[uint][]models.AppliedPromotion)
+	for rows.Next() {
+		var promotion models.AppliedPromotion
+		err := rows.Scan(
+			&promotion.ID,
+			&promotion.TransactionItemID,
+			&promotion.PromotionID,
+			&promotion.PromotionName,
+			&promotion.DiscountAmount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		promotions[promotion.TransactionItemID] = append(promotions[promotion.TransactionItemID], promotion)
+	}
+
+	return promotions, rows.Err()
+}
+
+func (r *transactionRepository) GetTransactionPayments(transactionID uint) ([]models.TransactionPayment, error) {
+	query := `
+		SELECT id, transaction_id, method, tendered_amount, amount, COALESCE(reference, ''), created_at, updated_at
+		FROM transaction_payments
+		WHERE transaction_id = $1
+		ORDER BY id ASC`
+
+	rows, err := r.db.Query(query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []models.TransactionPayment
+	for rows.Next() {
+		var payment models.TransactionPayment
+		err := rows.Scan(
+			&payment.ID,
+			&payment.TransactionID,
+			&payment.Method,
+			&payment.TenderedAmount,
+			&payment.Amount,
+			&payment.Reference,
+			&payment.CreatedAt,
+			&payment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+// discountToNull memetakan diskon opsional ke kolom nullable
+func discountToNull(discount *models.Discount) (sql.NullString, *money.Money, *money.Money) {
+	if discount == nil {
+		return sql.NullString{}, nil, nil
+	}
+	return sql.NullString{String: discount.Type, Valid: true}, &discount.Value, &discount.Amount
+}
+
+// kolom nilai yang NULL terbaca sebagai 0, keberadaan diskon ditentukan kolom type
+func discountFromNull(discountType sql.NullString, value, amount money.Money) *models.Discount {
+	if !discountType.Valid {
+		return nil
+	}
+	return &models.Discount{
+		Type:   discountType.String,
+		Value:  value,
+		Amount: amount,
+	}
+}
+
+func voucherToNull(voucher *models.VoucherRedemption) (sql.NullString, *money.Money) {
+	if voucher == nil {
+		return sql.NullString{}, nil
+	}
+	return sql.NullString{String: voucher.VoucherCode, Valid: true}, &voucher.DiscountAmount
+}
+
+func voucherFromNull(code sql.NullString, amount money.Money) *models.VoucherRedemption {
+	if !code.Valid {
+		return nil
+	}
+	return &models.VoucherRedemption{
+		VoucherCode:    code.String,
+		DiscountAmount: amount,
+	}
+}
@@ -0,0 +1,274 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"transaction-service/models"
+	"transaction-service/quantity"
+)
+
+// CreateReturn menyimpan retur dalam satu DB transaction, stok dikembalikan oleh service lewat product-service
+// sesudahnya; selama itu retur ditandai RestockPendingAt.
+// Baris transaksi dikunci sehingga dua retur bersamaan tidak bisa melebihi jumlah terjual.
+func (r *transactionRepository) CreateReturn(ret *models.TransactionReturn) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var voided bool
+	err = tx.QueryRow(
+		`SELECT voided_at IS NOT NULL FROM transactions WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
+		ret.TransactionID,
+	).Scan(&voided)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("transaction not found")
+		}
+		return fmt.Errorf("failed to lock transaction: %w", err)
+	}
+	if voided {
+		return fmt.Errorf("transaction has been voided")
+	}
+
+	remaining, err := r.remainingQuantities(tx, ret.TransactionID)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range ret.Items {
+		if item.Quantity > remaining[item.TransactionItemID] {
+			return fmt.Errorf("return quantity for transaction item %d exceeds remaining quantity %s",
+				item.TransactionItemID, remaining[item.TransactionItemID])
+		}
+		remaining[item.TransactionItemID] -= item.Quantity
+	}
+
+	now := time.Now()
+	query := `
+		INSERT INTO transaction_returns (transaction_id, reason_code, reason_note, total_amount, return_date, restock_pending_at,
+			created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`
+
+	err = tx.QueryRow(
+		query,
+		ret.TransactionID,
+		ret.ReasonCode,
+		ret.ReasonNote,
+		ret.TotalAmount,
+		ret.ReturnDate,
+		ret.RestockPendingAt,
+		now,
+		now,
+	).Scan(&ret.ID, &ret.CreatedAt, &ret.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert transaction return: %w", err)
+	}
+
+	for i := range ret.Items {
+		item := &ret.Items[i]
+		item.ReturnID = ret.ID
+
+		itemQuery := `
+			INSERT INTO transaction_return_items (return_id, transaction_item_id, product_id, product_name, unit_price, quantity, amount,
+				tax_amount, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING id, created_at, updated_at`
+
+		err = tx.QueryRow(
+			itemQuery,
+			item.ReturnID,
+			item.TransactionItemID,
+			item.ProductID,
+			item.ProductName,
+			item.UnitPrice,
+			item.Quantity,
+			item.Amount,
+			item.TaxAmount,
+			now,
+			now,
+		).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert transaction return item: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *transactionRepository) GetRestockPendingReturns(limit int) ([]models.TransactionReturn, error) {
+	query := `
+		SELECT ` + returnColumns + `
+		FROM transaction_returns
+		WHERE restock_pending_at IS NOT NULL
+		ORDER BY restock_pending_at
+		LIMIT $1`
+
+	returns, err := r.queryReturns(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending return restocks: %w", err)
+	}
+	return returns, nil
+}
+
+func (r *transactionRepository) ClearReturnRestockPending(id uint) error {
+	_, err := r.db.Exec(`UPDATE transaction_returns SET restock_pending_at = NULL, updated_at = $1 WHERE id = $2`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to clear pending return restock: %w", err)
+	}
+	return nil
+}
+
+const returnColumns = `id, transaction_id, reason_code, COALESCE(reason_note, ''), total_amount, return_date, restock_pending_at,
+	created_at, updated_at`
+
+func (r *transactionRepository) GetReturnsByTransactionID(transactionID uint) ([]models.TransactionReturn, error) {
+	query := `
+		SELECT ` + returnColumns + `
+		FROM transaction_returns
+		WHERE transaction_id = $1
+		ORDER BY return_date ASC`
+
+	return r.queryReturns(query, transactionID)
+}
+
+// queryReturns membaca retur beserta item-itemnya
+func (r *transactionRepository) queryReturns(query string, args ...interface{}) ([]models.TransactionReturn, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var returns []models.TransactionReturn
+	for rows.Next() {
+		var ret models.TransactionReturn
+		err := rows.Scan(
+			&ret.ID,
+			&ret.TransactionID,
+			&ret.ReasonCode,
+			&ret.ReasonNote,
+			&ret.TotalAmount,
+			&ret.ReturnDate,
+			&ret.RestockPendingAt,
+			&ret.CreatedAt,
+			&ret.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		returns = append(returns, ret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range returns {
+		items, err := r.getReturnItems(returns[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get return items: %w", err)
+		}
+		returns[i].Items = items
+	}
+
+	return returns, nil
+}
+
+func (r *transactionRepository) GetReturnedQuantities(transactionID uint) (map[uint]quantity.Quantity, error) {
+	query := `
+		SELECT ri.transaction_item_id, SUM(ri.quantity)
+		FROM transaction_return_items ri
+		JOIN transaction_returns tr ON tr.id = ri.return_id
+		WHERE tr.transaction_id = $1
+		GROUP BY ri.transaction_item_id`
+
+	rows, err := r.db.Query(query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	returned := make(map[uint]quantity.Quantity)
+	for rows.Next() {
+		var itemID uint
+		var returnedQuantity quantity.Quantity
+		if err := rows.Scan(&itemID, &returnedQuantity); err != nil {
+			return nil, err
+		}
+		returned[itemID] = returnedQuantity
+	}
+
+	return returned, rows.Err()
+}
+
+// remainingQuantities menghitung sisa jumlah yang masih bisa diretur per transaction item
+func (r *transactionRepository) remainingQuantities(tx *sql.Tx, transactionID uint) (map[uint]quantity.Quantity, error) {
+	query := `
+		SELECT ti.id, ti.quantity - COALESCE((
+			SELECT SUM(ri.quantity) FROM transaction_return_items ri WHERE ri.transaction_item_id = ti.id
+		), 0)
+		FROM transaction_items ti
+		WHERE ti.transaction_id = $1`
+
+	rows, err := tx.Query(query, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remaining quantities: %w", err)
+	}
+	defer rows.Close()
+
+	remaining := make(map[uint]quantity.Quantity)
+	for rows.Next() {
+		var itemID uint
+		var remainingQuantity quantity.Quantity
+		if err := rows.Scan(&itemID, &remainingQuantity); err != nil {
+			return nil, err
+		}
+		remaining[itemID] = remainingQuantity
+	}
+
+	return remaining, rows.Err()
+}
+
+func (r *transactionRepository) getReturnItems(returnID uint) ([]models.TransactionReturnItem, error) {
+	query := `
+		SELECT ri.id, ri.return_id, ri.transaction_item_id, ri.product_id, ti.variant_id, ri.product_name, ri.unit_price,
+			ri.quantity, ri.amount, ri.tax_amount, ri.created_at, ri.updated_at
+		FROM transaction_return_items ri
+		JOIN transaction_items ti ON ti.id = ri.transaction_item_id
+		WHERE ri.return_id = $1
+		ORDER BY ri.id ASC`
+
+	rows, err := r.db.Query(query, returnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.TransactionReturnItem
+	for rows.Next() {
+		var item models.TransactionReturnItem
+		err := rows.Scan(
+			&item.ID,
+			&item.ReturnID,
+			&item.TransactionItemID,
+			&item.ProductID,
+			&item.VariantID,
+			&item.ProductName,
+			&item.UnitPrice,
+			&item.Quantity,
+			&item.Amount,
+			&item.TaxAmount,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
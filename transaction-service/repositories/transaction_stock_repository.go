@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+	"transaction-service/models"
+)
+
+func (r *transactionRepository) MarkStockPending(id uint, at time.Time) error {
+	_, err := r.db.Exec(
+		`UPDATE transactions SET stock_pending_at = $1, updated_at = $1 WHERE id = $2 AND stock_pending_at IS NULL`,
+		at, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark stock as pending: %w", err)
+	}
+	return nil
+}
+
+func (r *transactionRepository) GetStockPending(limit int) ([]models.Transaction, error) {
+	rows, err := r.db.Query(`
+		SELECT id, COALESCE(invoice_number, ''), stock_reservation_id, stock_pending_at
+		FROM transactions
+		WHERE stock_pending_at IS NOT NULL AND voided_at IS NULL AND deleted_at IS NULL
+		ORDER BY stock_pending_at
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending stock confirmations: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []models.Transaction
+	for rows.Next() {
+		var transaction models.Transaction
+		if err := rows.Scan(&transaction.ID, &transaction.InvoiceNumber, &transaction.StockReservationID, &transaction.StockPendingAt); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, rows.Err()
+}
+
+func (r *transactionRepository) ClearStockPending(id uint) error {
+	_, err := r.db.Exec(`UPDATE transactions SET stock_pending_at = NULL, updated_at = $1 WHERE id = $2`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to clear pending stock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Void menandai transaksi sebagai void dan mengembalikan kuota voucher dalam satu DB transaction.
+// Stok dikembalikan oleh service lewat product-service.
+func (r *transactionRepository) Void(id uint, voidedBy, reason string, voidedAt time.Time, allowClosedDay bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var alreadyVoided bool
+	var transactionDate time.Time
+	err = tx.QueryRow(
+		`SELECT voided_at IS NOT NULL, transaction_date FROM transactions WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
+		id,
+	).Scan(&alreadyVoided, &transactionDate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("transaction not found")
+		}
+		return fmt.Errorf("failed to lock transaction: %w", err)
+	}
+	if alreadyVoided {
+		return fmt.Errorf("transaction has already been voided")
+	}
+
+	// FOR SHARE pada baris hari usaha: penutupan hari (FOR UPDATE) menunggu void ini selesai,
+	// dan void yang datang setelah penutupan melihat closed_at yang sudah terisi
+	if !allowClosedDay {
+		date := transactionDate.In(time.Local).Format("2006-01-02")
+		if err := ensureBusinessDay(tx, date); err != nil {
+			return err
+		}
+
+		var closed bool
+		err = tx.QueryRow(
+			`SELECT closed_at IS NOT NULL FROM business_days WHERE business_date = $1 FOR SHARE`,
+			date,
+		).Scan(&closed)
+		if err != nil {
+			return fmt.Errorf("failed to lock business day: %w", err)
+		}
+		if closed {
+			return fmt.Errorf("business day %s has been closed", date)
+		}
+	}
+
+	var hasReturns bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM transaction_returns WHERE transaction_id = $1)`, id).Scan(&hasReturns)
+	if err != nil {
+		return fmt.Errorf("failed to check transaction returns: %w", err)
+	}
+	if hasReturns {
+		return fmt.Errorf("transaction has returns and cannot be voided, return the remaining items instead")
+	}
+
+	query := `
+		UPDATE transactions
+		SET voided_at = $1, voided_by = $2, void_reason = $3, updated_at = $1
+		WHERE id = $4`
+
+	if _, err := tx.Exec(query, voidedAt, voidedBy, reason, id); err != nil {
+		return fmt.Errorf("failed to void transaction: %w", err)
+	}
+
+	// kuota voucher yang dipakai transaksi ini dikembalikan
+	if err := r.releaseVoucher(tx, id, voidedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
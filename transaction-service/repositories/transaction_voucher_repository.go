@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"transaction-service/models"
+)
+
+// redeemVoucher menukarkan voucher di dalam DB transaction checkout. Baris voucher dikunci
+// (SELECT ... FOR UPDATE) sehingga dua kasir tidak bisa memakai kode sekali-pakai yang sama
+// secara bersamaan; kuota dicek ulang setelah kunci didapat.
+func (r *transactionRepository) redeemVoucher(tx *sql.Tx, transaction *models.Transaction, now time.Time) error {
+	redemption := transaction.Voucher
+
+	voucher, err := scanVoucher(tx.QueryRow(
+		`SELECT `+voucherColumns+` FROM vouchers WHERE code = $1 FOR UPDATE`,
+		normalizeVoucherCode(redemption.VoucherCode),
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("voucher %s not found", redemption.VoucherCode)
+		}
+		return fmt.Errorf("failed to lock voucher: %w", err)
+	}
+
+	if err := voucher.CheckRedeemable(now); err != nil {
+		return err
+	}
+	if voucher.PerCustomerLimit > 0 {
+		if redemption.CustomerID == "" {
+			return fmt.Errorf("customer_id is required to redeem voucher %s", voucher.Code)
+		}
+		used, err := countCustomerRedemptions(tx, voucher.ID, redemption.CustomerID)
+		if err != nil {
+			return fmt.Errorf("failed to count voucher redemptions: %w", err)
+		}
+		if used >= voucher.PerCustomerLimit {
+			return fmt.Errorf("voucher %s has reached its usage limit for this customer", voucher.Code)
+		}
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE vouchers SET used_count = used_count + 1, updated_at = $1 WHERE id = $2`,
+		now, voucher.ID,
+	); err != nil {
+		return fmt.Errorf("failed to update voucher usage: %w", err)
+	}
+
+	redemption.VoucherID = voucher.ID
+	redemption.VoucherCode = voucher.Code
+	redemption.TransactionID = transaction.ID
+	redemption.CustomerID = transaction.CustomerID
+
+	err = tx.QueryRow(`
+		INSERT INTO voucher_redemptions (voucher_id, transaction_id, customer_id, discount_amount, redeemed_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		RETURNING id, redeemed_at`,
+		redemption.VoucherID,
+		redemption.TransactionID,
+		redemption.CustomerID,
+		redemption.DiscountAmount,
+		now,
+	).Scan(&redemption.ID, &redemption.RedeemedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert voucher redemption: %w", err)
+	}
+
+	return nil
+}
+
+// releaseVoucher mengembalikan kuota voucher ketika transaksi di-void
+func (r *transactionRepository) releaseVoucher(tx *sql.Tx, transactionID uint, releasedAt time.Time) error {
+	rows, err := tx.Query(`
+		UPDATE voucher_redemptions
+		SET released_at = $1
+		WHERE transaction_id = $2 AND released_at IS NULL
+		RETURNING voucher_id`,
+		releasedAt, transactionID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release voucher redemption: %w", err)
+	}
+
+	var voucherIDs []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to release voucher redemption: %w", err)
+		}
+		voucherIDs = append(voucherIDs, id)
+	}
+	rows.Close()
+
+	for _, id := range voucherIDs {
+		if _, err := tx.Exec(
+			`UPDATE vouchers SET used_count = GREATEST(used_count - 1, 0), updated_at = $1 WHERE id = $2`,
+			releasedAt, id,
+		); err != nil {
+			return fmt.Errorf("failed to release voucher usage: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"transaction-service/config"
+	"transaction-service/models"
+
+	"github.com/lib/pq"
+)
+
+type VoucherRepository interface {
+	Create(voucher *models.Voucher) error
+	// CreateBatch menyimpan semua voucher dalam satu DB transaction, gagal semua jika ada kode yang bentrok
+	CreateBatch(vouchers []models.Voucher) error
+	GetAll(batchCode string) ([]models.Voucher, error)
+	GetByCode(code string) (*models.Voucher, error)
+	Deactivate(code string) error
+	CountCustomerRedemptions(voucherID uint, customerID string) (int, error)
+	GetRedemptions(voucherID uint) ([]models.VoucherRedemption, error)
+}
+
+// ErrVoucherCodeExists dikembalikan jika kode voucher sudah dipakai
+var ErrVoucherCodeExists = errors.New("voucher code already exists")
+
+type voucherRepository struct {
+	db *sql.DB
+}
+
+func NewVoucherRepository() VoucherRepository {
+	return &voucherRepository{
+		db: config.DB,
+	}
+}
+
+const voucherColumns = `id, code, COALESCE(batch_code, ''), discount_type, value, max_discount_amount, min_spend,
+	expires_at, usage_limit, per_customer_limit, used_count, active, created_at, updated_at`
+
+const insertVoucherQuery = `
+	INSERT INTO vouchers (code, batch_code, discount_type, value, max_discount_amount, min_spend, expires_at,
+		usage_limit, per_customer_limit, active, created_at, updated_at)
+	VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11, $11)
+	RETURNING id, created_at, updated_at`
+
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func insertVoucher(db queryRower, voucher *models.Voucher, now time.Time) error {
+	err := db.QueryRow(
+		insertVoucherQuery,
+		voucher.Code,
+		voucher.BatchCode,
+		voucher.DiscountType,
+		voucher.Value,
+		voucher.MaxDiscountAmount,
+		voucher.MinSpend,
+		voucher.ExpiresAt,
+		voucher.UsageLimit,
+		voucher.PerCustomerLimit,
+		voucher.Active,
+		now,
+	).Scan(&voucher.ID, &voucher.CreatedAt, &voucher.UpdatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrVoucherCodeExists
+	}
+	return err
+}
+
+func (r *voucherRepository) Create(voucher *models.Voucher) error {
+	return insertVoucher(r.db, voucher, time.Now())
+}
+
+func (r *voucherRepository) CreateBatch(vouchers []models.Voucher) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for i := range vouchers {
+		if err := insertVoucher(tx, &vouchers[i], now); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *voucherRepository) GetAll(batchCode string) ([]models.Voucher, error) {
+	query := `SELECT ` + voucherColumns + ` FROM vouchers`
+	var args []interface{}
+	if batchCode != "" {
+		query += ` WHERE batch_code = $1`
+		args = append(args, batchCode)
+	}
+	query += ` ORDER BY id DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vouchers []models.Voucher
+	for rows.Next() {
+		voucher, err := scanVoucher(rows)
+		if err != nil {
+			return nil, err
+		}
+		vouchers = append(vouchers, *voucher)
+	}
+
+	return vouchers, rows.Err()
+}
+
+func (r *voucherRepository) GetByCode(code string) (*models.Voucher, error) {
+	query := `SELECT ` + voucherColumns + ` FROM vouchers WHERE code = $1`
+	return scanVoucher(r.db.QueryRow(query, normalizeVoucherCode(code)))
+}
+
+func (r *voucherRepository) Deactivate(code string) error {
+	_, err := r.db.Exec(`UPDATE vouchers SET active = FALSE, updated_at = $1 WHERE code = $2`, time.Now(), normalizeVoucherCode(code))
+	return err
+}
+
+// CountCustomerRedemptions menghitung pemakaian voucher oleh pelanggan, tidak termasuk yang sudah dilepas karena void
+func (r *voucherRepository) CountCustomerRedemptions(voucherID uint, customerID string) (int, error) {
+	return countCustomerRedemptions(r.db, voucherID, customerID)
+}
+
+func countCustomerRedemptions(db queryRower, voucherID uint, customerID string) (int, error) {
+	var count int
+	err := db.QueryRow(`
+		SELECT COUNT(*) FROM voucher_redemptions
+		WHERE voucher_id = $1 AND customer_id = $2 AND released_at IS NULL`,
+		voucherID, customerID,
+	).Scan(&count)
+	return count, err
+}
+
+func (r *voucherRepository) GetRedemptions(voucherID uint) ([]models.VoucherRedemption, error) {
+	query := `
+		SELECT vr.id, vr.voucher_id, v.code, vr.transaction_id, COALESCE(vr.customer_id, ''), vr.discount_amount,
+			vr.redeemed_at, vr.released_at
+		FROM voucher_redemptions vr
+		JOIN vouchers v ON v.id = vr.voucher_id
+		WHERE vr.voucher_id = $1
+		ORDER BY vr.id ASC`
+
+	rows, err := r.db.Query(query, voucherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var redemptions []models.VoucherRedemption
+	for rows.Next() {
+		var redemption models.VoucherRedemption
+		err := rows.Scan(
+			&redemption.ID,
+			&redemption.VoucherID,
+			&redemption.VoucherCode,
+			&redemption.TransactionID,
+			&redemption.CustomerID,
+			&redemption.DiscountAmount,
+			&redemption.RedeemedAt,
+			&redemption.ReleasedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		redemptions = append(redemptions, redemption)
+	}
+
+	return redemptions, rows.Err()
+}
+
+func scanVoucher(row rowScanner) (*models.Voucher, error) {
+	var voucher models.Voucher
+	err := row.Scan(
+		&voucher.ID,
+		&voucher.Code,
+		&voucher.BatchCode,
+		&voucher.DiscountType,
+		&voucher.Value,
+		&voucher.MaxDiscountAmount,
+		&voucher.MinSpend,
+		&voucher.ExpiresAt,
+		&voucher.UsageLimit,
+		&voucher.PerCustomerLimit,
+		&voucher.UsedCount,
+		&voucher.Active,
+		&voucher.CreatedAt,
+		&voucher.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
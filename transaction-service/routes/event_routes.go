@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"log"
+	"time"
+	"transaction-service/events"
+	"transaction-service/repositories"
+	"transaction-service/services"
+)
+
+// SetupEvents membuat broker dari EVENT_BROKER (file atau memory), menjalankan relay outbox dan
+// mendaftarkan consumer event produk. Dengan broker file, EVENT_BROKER_FILE harus sama dengan product-service.
+func SetupEvents() events.Broker {
+	broker, err := events.NewBroker(getStringEnv("EVENT_BROKER", "file"), getStringEnv("EVENT_BROKER_FILE", "data/events.jsonl"))
+	if err != nil {
+		log.Fatalf("Invalid event broker configuration: %v", err)
+	}
+
+	relay := services.NewOutboxRelay(repositories.NewOutboxRepository(), broker, getIntEnv("OUTBOX_BATCH_SIZE", 100))
+	relay.Start(getDurationEnv("OUTBOX_RELAY_INTERVAL", time.Second))
+
+	consumer := services.NewProductEventConsumer(repositories.NewProductReplicaRepository())
+	if err := broker.Subscribe(services.ProductEventConsumerName, consumer.Handle); err != nil {
+		log.Fatalf("Failed to subscribe to product events: %v", err)
+	}
+
+	return broker
+}
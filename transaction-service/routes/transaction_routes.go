@@ -1,9 +1,15 @@
 package routes
 
 import (
+	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 	"transaction-service/clients"
 	"transaction-service/handlers"
+	"transaction-service/money"
+	"transaction-service/numbering"
 	"transaction-service/repositories"
 	"transaction-service/services"
 
@@ -16,25 +22,206 @@ func SetupTransactionRoutes(app *fiber.App) {
 		productServiceURL = "http://localhost:8081"
 	}
 
+	// header identitas (X-User-ID, X-User-Role) hanya dipercaya jika service ini tidak bisa diakses selain lewat
+	// API gateway
+	trustUserHeaders := getBoolEnv("TRUST_USER_HEADERS", false)
+
 	productClient := clients.NewProductClient(productServiceURL)
+	businessDayRepo := repositories.NewBusinessDayRepository()
+	businessDayService := services.NewBusinessDayService(businessDayRepo)
+	businessDayHandler := handlers.NewBusinessDayHandler(businessDayService)
+
+	promotionRepo := repositories.NewPromotionRepository()
+	promotionService := services.NewPromotionService(promotionRepo)
+	promotionHandler := handlers.NewPromotionHandler(promotionService)
+
+	voucherRepo := repositories.NewVoucherRepository()
+	voucherService := services.NewVoucherService(voucherRepo)
+	voucherHandler := handlers.NewVoucherHandler(voucherService)
+
+	taxRateRepo := repositories.NewTaxRateRepository()
+	taxRateService := services.NewTaxRateService(taxRateRepo)
+	taxRateHandler := handlers.NewTaxRateHandler(taxRateService)
+
+	// pola nomor invoice yang salah menghentikan service, nomor invoice tidak boleh berganti format diam-diam
+	invoicePattern, err := numbering.Parse(getStringEnv("INVOICE_NUMBER_PATTERN", numbering.DefaultPattern), getStringEnv("STORE_CODE", "STORE01"))
+	if err != nil {
+		log.Fatalf("Invalid INVOICE_NUMBER_PATTERN: %v", err)
+	}
+
 	transactionRepo := repositories.NewTransactionRepository()
-	transactionService := services.NewTransactionService(transactionRepo, productClient)
+	transactionService := services.NewTransactionService(transactionRepo, productClient, businessDayRepo, promotionRepo, voucherRepo, taxRateRepo, services.TransactionOptions{
+		VoidWindow: getDurationEnv("VOID_WINDOW", 24*time.Hour),
+		MaxDiscountPercent: map[string]money.Percent{
+			"CASHIER":    getPercentEnv("MAX_DISCOUNT_PERCENT_CASHIER", 10*money.Scale),
+			"SUPERVISOR": getPercentEnv("MAX_DISCOUNT_PERCENT_SUPERVISOR", 30*money.Scale),
+			"MANAGER":    getPercentEnv("MAX_DISCOUNT_PERCENT_MANAGER", money.Hundred),
+		},
+		TrustRoleHeader:      trustUserHeaders,
+		PricesIncludeTax:     getBoolEnv("PRICES_INCLUDE_TAX", true),
+		InvoiceNumberPattern: invoicePattern,
+	})
 	transactionHandler := handlers.NewTransactionHandler(transactionService)
+	transactionService.StartStockReconciler(getDurationEnv("STOCK_RECONCILE_INTERVAL", time.Minute))
+
+	cartRepo := repositories.NewCartRepository()
+	cartService := services.NewCartService(cartRepo, productClient, transactionService,
+		getDurationEnv("CART_TTL", 4*time.Hour), getDurationEnv("CART_CHECKOUT_TIMEOUT", 5*time.Minute))
+	cartHandler := handlers.NewCartHandler(cartService)
+
+	store := services.StoreInfo{
+		Name:    getStringEnv("STORE_NAME", "Mini POS"),
+		Address: os.Getenv("STORE_ADDRESS"),
+		Phone:   os.Getenv("STORE_PHONE"),
+		TaxID:   os.Getenv("STORE_TAX_ID"),
+		Footer:  getStringEnv("RECEIPT_FOOTER", "Terima kasih atas kunjungan Anda"),
+	}
+	receiptService := services.NewReceiptService(transactionRepo, services.ReceiptOptions{
+		Store:        store,
+		DefaultWidth: getIntEnv("RECEIPT_WIDTH", 58),
+		DefaultCode:  strings.ToUpper(getStringEnv("RECEIPT_CODE", services.ReceiptCodeQR)),
+	})
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
+
+	invoiceService := services.NewInvoiceService(transactionRepo, services.InvoiceOptions{
+		Store:           store,
+		AccentColor:     getStringEnv("INVOICE_ACCENT_COLOR", "#1F4E79"),
+		LogoURL:         os.Getenv("INVOICE_LOGO_URL"),
+		DefaultPageSize: getStringEnv("INVOICE_PAGE_SIZE", services.InvoicePageA4),
+	})
+	invoiceHandler := handlers.NewInvoiceHandler(invoiceService)
+
+	idempotencyRepo := repositories.NewIdempotencyRepository()
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo,
+		getDurationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour), getDurationEnv("IDEMPOTENCY_LOCK_TIMEOUT", 2*time.Minute))
+
+	// laporan membaca salinan produk lokal, bukan database product-service
+	productSyncService := services.NewProductSyncService(repositories.NewProductReplicaRepository(), productClient)
+	productSyncService.Start(getDurationEnv("PRODUCT_SYNC_INTERVAL", 5*time.Minute))
 
 	reportingRepo := repositories.NewReportingRepository()
 	reportingService := services.NewReportingService(reportingRepo)
 	reportingHandler := handlers.NewReportingHandler(reportingService)
 
-	api := app.Group("/api")
+	api := app.Group("/api", handlers.UserHeadersMiddleware(trustUserHeaders))
 
 	transactions := api.Group("/transactions")
-	transactions.Post("/", transactionHandler.CreateTransaction)
+	transactions.Post("/", handlers.IdempotencyMiddleware(idempotencyService), transactionHandler.CreateTransaction)
+	transactions.Post("/preview", transactionHandler.PreviewPricing)
 	transactions.Get("/", transactionHandler.GetAllTransactions)
 	transactions.Get("/:id", transactionHandler.GetTransaction)
+	transactions.Get("/:id/receipt", receiptHandler.GetReceipt)
+	transactions.Get("/:id/invoice.pdf", invoiceHandler.GetInvoicePDF)
+	transactions.Get("/:id/invoice.html", invoiceHandler.GetInvoiceHTML)
+	transactions.Post("/:id/returns", handlers.IdempotencyMiddleware(idempotencyService), transactionHandler.CreateReturn)
+	transactions.Get("/:id/returns", transactionHandler.GetReturns)
+	transactions.Post("/:id/void", transactionHandler.VoidTransaction)
+
+	carts := api.Group("/carts")
+	carts.Post("/", cartHandler.CreateCart)
+	carts.Get("/", cartHandler.GetCarts)
+	carts.Get("/:id", cartHandler.GetCart)
+	carts.Put("/:id", cartHandler.UpdateCart)
+	carts.Delete("/:id", cartHandler.DeleteCart)
+	carts.Post("/:id/items", cartHandler.AddItem)
+	carts.Put("/:id/items/:itemId", cartHandler.UpdateItem)
+	carts.Delete("/:id/items/:itemId", cartHandler.RemoveItem)
+	carts.Post("/:id/park", cartHandler.ParkCart)
+	carts.Post("/:id/resume", cartHandler.ResumeCart)
+	carts.Post("/:id/checkout", handlers.IdempotencyMiddleware(idempotencyService), cartHandler.Checkout)
+
+	promotions := api.Group("/promotions")
+	promotions.Post("/", promotionHandler.CreatePromotion)
+	promotions.Get("/", promotionHandler.GetAllPromotions)
+	promotions.Get("/:id", promotionHandler.GetPromotion)
+	promotions.Put("/:id", promotionHandler.UpdatePromotion)
+	promotions.Delete("/:id", promotionHandler.DeletePromotion)
+
+	vouchers := api.Group("/vouchers")
+	vouchers.Post("/", voucherHandler.CreateVoucher)
+	vouchers.Post("/batch", voucherHandler.CreateVoucherBatch)
+	vouchers.Get("/", voucherHandler.GetAllVouchers)
+	vouchers.Get("/:code", voucherHandler.GetVoucher)
+	vouchers.Get("/:code/redemptions", voucherHandler.GetRedemptions)
+	vouchers.Post("/:code/deactivate", voucherHandler.DeactivateVoucher)
+
+	taxRates := api.Group("/tax-rates")
+	taxRates.Get("/", taxRateHandler.GetAllTaxRates)
+	taxRates.Put("/:class", taxRateHandler.SetTaxRate)
+
+	businessDays := api.Group("/business-days")
+	businessDays.Get("/", businessDayHandler.GetClosedBusinessDays)
+	businessDays.Post("/close", businessDayHandler.CloseBusinessDay)
 
 	reports := api.Group("/reports")
 	reports.Get("/transactions", reportingHandler.GetTransactionSummary)
 	reports.Get("/products", reportingHandler.GetProductSalesReport)
 	reports.Get("/low-stock", reportingHandler.GetLowStockAlert)
 	reports.Get("/dashboard", reportingHandler.GetDashboardSummary)
+	reports.Get("/payments", reportingHandler.GetPaymentMethodSummary)
+	reports.Get("/tax", reportingHandler.GetTaxSummary)
+	reports.Get("/categories", reportingHandler.GetCategorySalesReport)
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("Invalid %s value %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+func getStringEnv(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		log.Printf("Invalid %s value %q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return number
+}
+
+func getPercentEnv(key string, defaultValue money.Percent) money.Percent {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	percent, err := money.ParsePercent(value)
+	if err != nil || percent < 0 {
+		log.Printf("Invalid %s value %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return percent
+}
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid %s value %q, using default %v", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
 }
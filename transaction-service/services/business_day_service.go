@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"transaction-service/dto"
+	"transaction-service/models"
+	"transaction-service/repositories"
+)
+
+type BusinessDayService interface {
+	CloseBusinessDay(req *dto.CloseBusinessDayRequest, closedBy string) (*dto.BusinessDayResponse, error)
+	GetClosedBusinessDays(limit int) ([]dto.BusinessDayResponse, error)
+}
+
+type businessDayService struct {
+	repo repositories.BusinessDayRepository
+}
+
+func NewBusinessDayService(repo repositories.BusinessDayRepository) BusinessDayService {
+	return &businessDayService{repo: repo}
+}
+
+func (s *businessDayService) CloseBusinessDay(req *dto.CloseBusinessDayRequest, closedBy string) (*dto.BusinessDayResponse, error) {
+	closedBy = strings.TrimSpace(closedBy)
+	if closedBy == "" {
+		return nil, errors.New("user is required to close a business day")
+	}
+
+	businessDate := time.Now()
+	if req.BusinessDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", req.BusinessDate, time.Local)
+		if err != nil {
+			return nil, errors.New("invalid business_date, expected format YYYY-MM-DD")
+		}
+		if parsed.After(time.Now()) {
+			return nil, errors.New("cannot close a business day in the future")
+		}
+		businessDate = parsed
+	}
+
+	day, err := s.repo.Close(businessDate, closedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.modelToResponse(day), nil
+}
+
+func (s *businessDayService) GetClosedBusinessDays(limit int) ([]dto.BusinessDayResponse, error) {
+	if limit <= 0 {
+		limit = 30
+	}
+
+	days, err := s.repo.GetAll(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.BusinessDayResponse, 0, len(days))
+	for i := range days {
+		responses = append(responses, *s.modelToResponse(&days[i]))
+	}
+
+	return responses, nil
+}
+
+func (s *businessDayService) modelToResponse(day *models.BusinessDay) *dto.BusinessDayResponse {
+	return &dto.BusinessDayResponse{
+		BusinessDate: day.BusinessDate.Format("2006-01-02"),
+		ClosedAt:     day.ClosedAt.Format("2006-01-02 15:04:05"),
+		ClosedBy:     day.ClosedBy,
+	}
+}
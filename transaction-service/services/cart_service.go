@@ -0,0 +1,596 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"transaction-service/clients"
+	"transaction-service/dto"
+	"transaction-service/models"
+	"transaction-service/repositories"
+)
+
+type CartService interface {
+	CreateCart(req *dto.CreateCartRequest, operator dto.Operator) (*dto.CartResponse, error)
+	GetCarts(terminalID, status string) ([]dto.CartResponse, error)
+	GetCart(id uint, operator dto.Operator) (*dto.CartResponse, error)
+	UpdateCart(id uint, req *dto.UpdateCartRequest, operator dto.Operator) (*dto.CartResponse, error)
+	DeleteCart(id uint) error
+	AddItem(id uint, req *dto.CartItemRequest, operator dto.Operator) (*dto.CartResponse, error)
+	UpdateItem(id, itemID uint, req *dto.UpdateCartItemRequest, operator dto.Operator) (*dto.CartResponse, error)
+	RemoveItem(id, itemID uint, operator dto.Operator) (*dto.CartResponse, error)
+	ParkCart(id uint, req *dto.ParkCartRequest, operator dto.Operator) (*dto.CartResponse, error)
+	ResumeCart(id uint, req *dto.ResumeCartRequest, operator dto.Operator) (*dto.CartResponse, error)
+	Checkout(id uint, req *dto.CheckoutCartRequest, operator dto.Operator) (*dto.TransactionResponse, error)
+}
+
+type cartService struct {
+	repo               repositories.CartRepository
+	productClient      clients.ProductClient
+	transactionService TransactionService
+	// keranjang kedaluwarsa setelah ttl tanpa perubahan
+	ttl time.Duration
+	// klaim checkout yang lebih lama dari ini dianggap gagal dan keranjangnya dibuka lagi
+	checkoutTimeout time.Duration
+}
+
+func NewCartService(repo repositories.CartRepository, productClient clients.ProductClient, transactionService TransactionService, ttl, checkoutTimeout time.Duration) CartService {
+	return &cartService{
+		repo:               repo,
+		productClient:      productClient,
+		transactionService: transactionService,
+		ttl:                ttl,
+		checkoutTimeout:    checkoutTimeout,
+	}
+}
+
+func (s *cartService) CreateCart(req *dto.CreateCartRequest, operator dto.Operator) (*dto.CartResponse, error) {
+	discount, err := requestedDiscount(req.Discount)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	// keranjang kedaluwarsa dibersihkan saat keranjang baru dibuat
+	if err := s.repo.DeleteExpired(now, now.Add(-s.checkoutTimeout)); err != nil {
+		return nil, err
+	}
+
+	cart := &models.Cart{
+		TerminalID:  strings.TrimSpace(req.TerminalID),
+		Status:      models.CartStatusOpen,
+		CustomerID:  strings.TrimSpace(req.CustomerID),
+		Discount:    discount,
+		VoucherCode: strings.TrimSpace(req.VoucherCode),
+		CreatedBy:   operator.UserID,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+	if cart.TerminalID == "" {
+		return nil, errors.New("terminal_id is required")
+	}
+
+	if err := s.repo.Create(cart); err != nil {
+		return nil, fmt.Errorf("failed to create cart: %w", err)
+	}
+
+	return s.respond(cart, operator), nil
+}
+
+func (s *cartService) GetCarts(terminalID, status string) ([]dto.CartResponse, error) {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	switch status {
+	case "", models.CartStatusOpen, models.CartStatusParked, models.CartStatusCheckingOut, models.CartStatusCheckedOut:
+	default:
+		return nil, fmt.Errorf("invalid cart status %s", status)
+	}
+
+	carts, err := s.repo.GetAll(strings.TrimSpace(terminalID), status, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.CartResponse, 0, len(carts))
+	for i := range carts {
+		responses = append(responses, *cartToResponse(&carts[i]))
+	}
+	return responses, nil
+}
+
+func (s *cartService) GetCart(id uint, operator dto.Operator) (*dto.CartResponse, error) {
+	cart, err := s.getCart(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.respond(cart, operator), nil
+}
+
+func (s *cartService) UpdateCart(id uint, req *dto.UpdateCartRequest, operator dto.Operator) (*dto.CartResponse, error) {
+	discount, err := requestedDiscount(req.Discount)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.editCart(id, operator, func(repo repositories.CartRepository, cart *models.Cart) error {
+		cart.CustomerID = strings.TrimSpace(req.CustomerID)
+		cart.Discount = discount
+		cart.VoucherCode = strings.TrimSpace(req.VoucherCode)
+		s.refreshStockHold(cart)
+		return nil
+	})
+}
+
+func (s *cartService) DeleteCart(id uint) error {
+	cart, err := s.getCart(id)
+	if err != nil {
+		return err
+	}
+	if cart.Status == models.CartStatusCheckingOut || cart.Status == models.CartStatusCheckedOut {
+		return fmt.Errorf("cart %d has already been checked out", id)
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		if err == repositories.ErrCartNotOpen {
+			return fmt.Errorf("cart %d has already been checked out", id)
+		}
+		if err == sql.ErrNoRows {
+			return errors.New("cart not found")
+		}
+		return err
+	}
+	s.releaseStockHold(cart)
+	return nil
+}
+
+func (s *cartService) AddItem(id uint, req *dto.CartItemRequest, operator dto.Operator) (*dto.CartResponse, error) {
+	if req.Quantity <= 0 {
+		return nil, errors.New("quantity must be greater than 0")
+	}
+	if !req.Quantity.IsWhole() {
+		return nil, errors.New("quantity must be a whole number, fractional quantities come from scale labels")
+	}
+	discount, err := requestedDiscount(req.Discount)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.productClient.GetByID(req.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("product with ID %d not found or service unavailable", req.ProductID)
+	}
+	variant, err := selectVariant(product, req.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	variantID := variantIDOf(variant)
+
+	return s.editCart(id, operator, func(repo repositories.CartRepository, cart *models.Cart) error {
+		// scan ulang produk (dan varian) yang sama menambah quantity baris tanpa diskon
+		items := append([]models.CartItem{}, cart.Items...)
+		merged := -1
+		if discount == nil {
+			for i := range items {
+				if items[i].ProductID == req.ProductID && sameVariant(items[i].VariantID, variantID) && items[i].Discount == nil {
+					merged = i
+					break
+				}
+			}
+		}
+
+		var err error
+		if merged >= 0 {
+			items[merged].Quantity += req.Quantity
+			err = repo.UpdateItem(&items[merged])
+		} else {
+			items = append(items, models.CartItem{
+				CartID:    cart.ID,
+				ProductID: req.ProductID,
+				VariantID: variantID,
+				Quantity:  req.Quantity,
+				Discount:  discount,
+			})
+			err = repo.AddItem(&items[len(items)-1])
+		}
+		if err != nil {
+			return err
+		}
+
+		// baris batal tersimpan (rollback) jika stoknya tidak cukup
+		if err := s.holdStock(cart, items); err != nil {
+			return err
+		}
+		cart.Items = items
+		return nil
+	})
+}
+
+func (s *cartService) UpdateItem(id, itemID uint, req *dto.UpdateCartItemRequest, operator dto.Operator) (*dto.CartResponse, error) {
+	if req.Quantity <= 0 {
+		return nil, errors.New("quantity must be greater than 0")
+	}
+	if !req.Quantity.IsWhole() {
+		return nil, errors.New("quantity must be a whole number, fractional quantities come from scale labels")
+	}
+	discount, err := requestedDiscount(req.Discount)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.editCart(id, operator, func(repo repositories.CartRepository, cart *models.Cart) error {
+		items := append([]models.CartItem{}, cart.Items...)
+		var item *models.CartItem
+		for i := range items {
+			if items[i].ID == itemID {
+				item = &items[i]
+				break
+			}
+		}
+		if item == nil {
+			return errors.New("cart item not found")
+		}
+		item.Quantity = req.Quantity
+		item.Discount = discount
+
+		if err := repo.UpdateItem(item); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New("cart item not found")
+			}
+			return err
+		}
+		// quantity batal tersimpan (rollback) jika stoknya tidak cukup
+		if err := s.holdStock(cart, items); err != nil {
+			return err
+		}
+		cart.Items = items
+		return nil
+	})
+}
+
+func (s *cartService) RemoveItem(id, itemID uint, operator dto.Operator) (*dto.CartResponse, error) {
+	return s.editCart(id, operator, func(repo repositories.CartRepository, cart *models.Cart) error {
+		if err := repo.DeleteItem(cart.ID, itemID); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New("cart item not found")
+			}
+			return err
+		}
+		for i := range cart.Items {
+			if cart.Items[i].ID == itemID {
+				cart.Items = append(cart.Items[:i], cart.Items[i+1:]...)
+				break
+			}
+		}
+		s.refreshStockHold(cart)
+		return nil
+	})
+}
+
+// ParkCart menyimpan keranjang dengan label agar terminal bisa melayani pelanggan berikutnya
+func (s *cartService) ParkCart(id uint, req *dto.ParkCartRequest, operator dto.Operator) (*dto.CartResponse, error) {
+	label := strings.TrimSpace(req.Label)
+	if label == "" {
+		return nil, errors.New("label is required to park a cart")
+	}
+	if _, err := s.getOpenCart(id); err != nil {
+		return nil, err
+	}
+
+	var parked *models.Cart
+	err := s.repo.EditOpen(id, func(repo repositories.CartRepository, cart *models.Cart) error {
+		now := time.Now()
+		cart.Label = label
+		cart.ParkedAt = &now
+		cart.ExpiresAt = now.Add(s.ttl)
+		// keranjang yang di-park tetap menahan stoknya selama belum kedaluwarsa
+		s.refreshStockHold(cart)
+		if err := repo.Park(cart); err != nil {
+			return err
+		}
+		parked = cart
+		return nil
+	})
+	if err != nil {
+		return nil, cartEditError(id, err)
+	}
+	return s.respond(parked, operator), nil
+}
+
+// ResumeCart membuka kembali keranjang yang di-park, bisa di terminal lain
+func (s *cartService) ResumeCart(id uint, req *dto.ResumeCartRequest, operator dto.Operator) (*dto.CartResponse, error) {
+	cart, err := s.getCart(id)
+	if err != nil {
+		return nil, err
+	}
+	if cart.Status != models.CartStatusParked {
+		return nil, fmt.Errorf("cart %d is not parked", id)
+	}
+
+	if terminalID := strings.TrimSpace(req.TerminalID); terminalID != "" {
+		cart.TerminalID = terminalID
+	}
+	if err := s.repo.Resume(cart); err != nil {
+		return nil, cartEditError(id, err)
+	}
+
+	// stok ditahan ulang setelah keranjang OPEN, di bawah kunci yang sama dengan perubahan lainnya
+	return s.editCart(id, operator, func(repo repositories.CartRepository, cart *models.Cart) error {
+		s.refreshStockHold(cart)
+		return nil
+	})
+}
+
+// Checkout mengubah keranjang menjadi transaksi lewat CreateTransaction. Keranjang dikunci dengan
+// status CHECKING_OUT lebih dulu sehingga checkout ganda tidak membuat dua transaksi; jika
+// transaksi gagal keranjang kembali OPEN dan bisa diperbaiki.
+func (s *cartService) Checkout(id uint, req *dto.CheckoutCartRequest, operator dto.Operator) (*dto.TransactionResponse, error) {
+	cart, err := s.getOpenCart(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, errors.New("cart has no items")
+	}
+
+	claimed, err := s.repo.ClaimForCheckout(cart.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("cart %d is already being checked out or is no longer open", id)
+	}
+
+	// baris dibaca ulang setelah klaim: perubahan yang masuk sebelum klaim ikut terjual
+	claimedCart, err := s.repo.GetByID(id)
+	if err == nil && len(claimedCart.Items) == 0 {
+		err = errors.New("cart has no items")
+	}
+	if err != nil {
+		if releaseErr := s.repo.ReleaseCheckout(cart.ID, cart.StockReservationID); releaseErr != nil {
+			log.Printf("Failed to release cart %d after checkout error: %v", cart.ID, releaseErr)
+		}
+		return nil, err
+	}
+	cart = claimedCart
+
+	// reservasi keranjang diganti dengan baris yang dijual lalu dikonfirmasi, bukan reservasi kedua di sampingnya
+	transaction, err := s.transactionService.CreateCartTransaction(cartToTransactionRequest(cart, req.Payments), operator, cart.ID)
+	if err != nil {
+		// checkout sudah mengganti reservasi keranjang, tahan ulang baris keranjang untuk checkout berikutnya
+		s.refreshStockHold(cart)
+		if releaseErr := s.repo.ReleaseCheckout(cart.ID, cart.StockReservationID); releaseErr != nil {
+			log.Printf("Failed to release cart %d after checkout error: %v", cart.ID, releaseErr)
+		}
+		return nil, err
+	}
+
+	// keranjang sudah ditandai CHECKED_OUT bersama transaksinya
+	return transaction, nil
+}
+
+func (s *cartService) getCart(id uint) (*models.Cart, error) {
+	cart, err := s.repo.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("cart not found")
+		}
+		return nil, err
+	}
+	if cart.Status != models.CartStatusCheckedOut && cart.Expired(time.Now()) {
+		return nil, fmt.Errorf("cart %d has expired", id)
+	}
+
+	// checkout yang tidak selesai dalam checkoutTimeout (mis. prosesnya mati) melepas klaimnya
+	if cart.Status == models.CartStatusCheckingOut {
+		released, err := s.repo.ReleaseStaleCheckout(cart.ID, time.Now().Add(-s.checkoutTimeout))
+		if err != nil {
+			return nil, err
+		}
+		if released {
+			log.Printf("Warning: cart %d was stuck in checkout, reopened", cart.ID)
+			cart.Status = models.CartStatusOpen
+		}
+	}
+	return cart, nil
+}
+
+// getOpenCart mengambil keranjang yang masih boleh diubah
+func (s *cartService) getOpenCart(id uint) (*models.Cart, error) {
+	cart, err := s.getCart(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cart.Status {
+	case models.CartStatusOpen:
+		return cart, nil
+	case models.CartStatusParked:
+		return nil, fmt.Errorf("cart %d is parked, resume it first", id)
+	default:
+		return nil, fmt.Errorf("cart %d has already been checked out", id)
+	}
+}
+
+// editCart menjalankan fn pada keranjang OPEN yang terkunci (lihat CartRepository.EditOpen), lalu menyimpan
+// headernya dan memperpanjang masa berlakunya. Baris dan stok diubah di dalam fn, sehingga checkout yang
+// mengklaim keranjang di tengah perubahan menunggu sampai perubahan dan reservasinya selesai.
+func (s *cartService) editCart(id uint, operator dto.Operator, fn func(repo repositories.CartRepository, cart *models.Cart) error) (*dto.CartResponse, error) {
+	// pembacaan biasa lebih dulu untuk pesan error keranjang yang kedaluwarsa, di-park atau checkout yang macet
+	if _, err := s.getOpenCart(id); err != nil {
+		return nil, err
+	}
+
+	var edited *models.Cart
+	err := s.repo.EditOpen(id, func(repo repositories.CartRepository, cart *models.Cart) error {
+		if err := fn(repo, cart); err != nil {
+			return err
+		}
+		cart.ExpiresAt = time.Now().Add(s.ttl)
+		if err := repo.Update(cart); err != nil {
+			return err
+		}
+		edited = cart
+		return nil
+	})
+	if err != nil {
+		return nil, cartEditError(id, err)
+	}
+	return s.respond(edited, operator), nil
+}
+
+// cartEditError menerjemahkan error repository keranjang menjadi pesan yang dipetakan handler
+func cartEditError(id uint, err error) error {
+	switch err {
+	case sql.ErrNoRows:
+		return errors.New("cart not found")
+	case repositories.ErrCartNotOpen:
+		return fmt.Errorf("cart %d is no longer open", id)
+	case repositories.ErrCartNotParked:
+		return fmt.Errorf("cart %d is not parked", id)
+	}
+	return err
+}
+
+// respond menghitung ulang harga keranjang (harga terbaru dari product-service, promosi, diskon,
+// voucher, pajak dan warning stok). Perubahan keranjang sudah tersimpan, jadi kegagalan hitung
+// harga dikembalikan sebagai pricing_error, bukan error request.
+func (s *cartService) respond(cart *models.Cart, operator dto.Operator) *dto.CartResponse {
+	response := cartToResponse(cart)
+	if len(cart.Items) == 0 {
+		response.Pricing = &dto.PricingPreviewResponse{
+			Items:        []dto.TransactionItemResponse{},
+			TaxBreakdown: []dto.TaxBreakdownResponse{},
+		}
+		return response
+	}
+
+	pricing, err := s.transactionService.PreviewCartPricing(&cartToTransactionRequest(cart, nil).PricingRequest, operator)
+	if err != nil {
+		log.Printf("Failed to price cart %d: %v", cart.ID, err)
+		response.PricingError = err.Error()
+		return response
+	}
+	response.Pricing = pricing
+	return response
+}
+
+// holdStock menahan stok items di product-service dengan reference keranjang selama ttl keranjang, menggantikan
+// reservasi keranjang sebelumnya. Keranjang tanpa baris melepas reservasinya.
+func (s *cartService) holdStock(cart *models.Cart, items []models.CartItem) error {
+	if len(items) == 0 {
+		s.releaseStockHold(cart)
+		return nil
+	}
+
+	stock := make([]clients.StockItem, 0, len(items))
+	for _, item := range items {
+		line := clients.StockItem{ProductID: item.ProductID, Quantity: item.Quantity}
+		if item.VariantID != nil {
+			line.VariantID = *item.VariantID
+		}
+		stock = append(stock, line)
+	}
+	reservation, err := s.productClient.Reserve(CartStockReference(cart.ID), stock, s.ttl)
+	if err != nil {
+		return err
+	}
+	cart.StockReservationID = &reservation.ID
+	return nil
+}
+
+// refreshStockHold menahan ulang baris keranjang dan memperpanjang reservasinya bersama masa berlaku keranjang.
+// Kegagalan tidak menggagalkan request: reservasi lama dilepas, masalahnya (mis. varian yang dihapus) terlihat
+// di pricing.warnings dan stok ditahan lagi saat checkout.
+func (s *cartService) refreshStockHold(cart *models.Cart) {
+	if err := s.holdStock(cart, cart.Items); err != nil {
+		log.Printf("Failed to hold stock for cart %d: %v", cart.ID, err)
+		s.releaseStockHold(cart)
+	}
+}
+
+func (s *cartService) releaseStockHold(cart *models.Cart) {
+	if cart.StockReservationID == nil {
+		return
+	}
+	if _, err := s.productClient.ReleaseReservation(*cart.StockReservationID); err != nil {
+		log.Printf("Warning: failed to release stock reservation %d of cart %d: %v", *cart.StockReservationID, cart.ID, err)
+	}
+	cart.StockReservationID = nil
+}
+
+func discountToRequest(discount *models.Discount) *dto.DiscountRequest {
+	if discount == nil {
+		return nil
+	}
+	return &dto.DiscountRequest{Type: discount.Type, Value: discount.Value}
+}
+
+func cartToTransactionRequest(cart *models.Cart, payments []dto.PaymentRequest) *dto.CreateTransactionRequest {
+	req := &dto.CreateTransactionRequest{
+		PricingRequest: dto.PricingRequest{
+			Discount:    discountToRequest(cart.Discount),
+			VoucherCode: cart.VoucherCode,
+			CustomerID:  cart.CustomerID,
+			StockHeld:   cart.StockReservationID != nil,
+		},
+		Payments: payments,
+	}
+	for _, item := range cart.Items {
+		line := dto.TransactionItemRequest{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Discount:  discountToRequest(item.Discount),
+		}
+		if item.VariantID != nil {
+			line.VariantID = *item.VariantID
+		}
+		req.Items = append(req.Items, line)
+	}
+	return req
+}
+
+func sameVariant(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func cartToResponse(cart *models.Cart) *dto.CartResponse {
+	response := &dto.CartResponse{
+		ID:                 cart.ID,
+		TerminalID:         cart.TerminalID,
+		Status:             cart.Status,
+		Label:              cart.Label,
+		CustomerID:         cart.CustomerID,
+		Discount:           cartDiscountToResponse(cart.Discount),
+		VoucherCode:        cart.VoucherCode,
+		CreatedBy:          cart.CreatedBy,
+		TransactionID:      cart.TransactionID,
+		StockReservationID: cart.StockReservationID,
+		Items:              make([]dto.CartItemResponse, 0, len(cart.Items)),
+		ExpiresAt:          cart.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:          cart.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:          cart.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if cart.ParkedAt != nil {
+		response.ParkedAt = cart.ParkedAt.Format("2006-01-02 15:04:05")
+	}
+	for _, item := range cart.Items {
+		response.Items = append(response.Items, dto.CartItemResponse{
+			ID:        item.ID,
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			Discount:  cartDiscountToResponse(item.Discount),
+		})
+	}
+	return response
+}
+
+func cartDiscountToResponse(discount *models.Discount) *dto.CartDiscount {
+	if discount == nil {
+		return nil
+	}
+	return &dto.CartDiscount{Type: discount.Type, Value: discount.Value}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"time"
+	"transaction-service/models"
+	"transaction-service/repositories"
+)
+
+var (
+	ErrIdempotencyKeyReused   = errors.New("idempotency key has already been used with a different request")
+	ErrIdempotencyKeyInFlight = errors.New("a request with this idempotency key is still being processed")
+	// Complete atau Abort setelah lease key diambil alih retry lain
+	ErrIdempotencyLeaseLost = repositories.ErrIdempotencyLeaseLost
+)
+
+type IdempotencyService interface {
+	// Begin mengembalikan response tersimpan jika key sudah selesai diproses, atau nil beserta token lease
+	// jika request boleh diproses. Complete dan Abort hanya berlaku selama key masih dipegang token tersebut.
+	Begin(key, requestHash string) (stored *models.IdempotencyKey, leaseToken string, err error)
+	Complete(key, leaseToken string, responseStatus int, responseBody []byte) error
+	Abort(key, leaseToken string) error
+}
+
+type idempotencyService struct {
+	repo repositories.IdempotencyRepository
+	ttl  time.Duration
+	// lease harus lebih lama dari request terlama, retry baru boleh mengambil alih key setelah lease habis
+	lease time.Duration
+}
+
+func NewIdempotencyService(repo repositories.IdempotencyRepository, ttl, lease time.Duration) IdempotencyService {
+	return &idempotencyService{
+		repo:  repo,
+		ttl:   ttl,
+		lease: lease,
+	}
+}
+
+func (s *idempotencyService) Begin(key, requestHash string) (*models.IdempotencyKey, string, error) {
+	now := time.Now()
+	record, created, err := s.repo.Reserve(key, requestHash, now.Add(s.lease), now.Add(s.ttl))
+	if err != nil {
+		return nil, "", err
+	}
+	if created {
+		return nil, record.LeaseToken, nil
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, "", ErrIdempotencyKeyReused
+	}
+	if record.Status != models.IdempotencyStatusCompleted {
+		return nil, "", ErrIdempotencyKeyInFlight
+	}
+
+	return record, "", nil
+}
+
+func (s *idempotencyService) Complete(key, leaseToken string, responseStatus int, responseBody []byte) error {
+	return s.repo.Complete(key, leaseToken, responseStatus, responseBody)
+}
+
+func (s *idempotencyService) Abort(key, leaseToken string) error {
+	return s.repo.Release(key, leaseToken)
+}
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strconv"
+	"transaction-service/models"
+	"transaction-service/money"
+	"transaction-service/pdf"
+)
+
+// format dan ukuran kertas invoice yang didukung
+const (
+	InvoiceFormatPDF  = "pdf"
+	InvoiceFormatHTML = "html"
+
+	InvoicePageA4 = "A4"
+	InvoicePageA5 = "A5"
+)
+
+const defaultInvoiceAccentColor = "#1F4E79"
+
+var invoicePageSizes = map[string]pdf.PageSize{
+	InvoicePageA4: pdf.A4,
+	InvoicePageA5: pdf.A5,
+}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// invoiceView adalah isi invoice yang sudah diformat, dipakai bersama oleh renderer HTML dan PDF
+type invoiceView struct {
+	Store            StoreInfo
+	AccentColor      string
+	LogoURL          string
+	PageSize         string
+	Number           string
+	Date             string
+	CustomerID       string
+	Voided           bool
+	VoidReason       string
+	PricesIncludeTax bool
+	Items            []invoiceItemView
+	Totals           []invoiceAmountView
+	Payments         []invoiceAmountView
+}
+
+type invoiceItemView struct {
+	No        int
+	Name      string
+	SKU       string
+	Quantity  string
+	UnitPrice string
+	Discount  string
+	TaxRate   string
+	Amount    string
+}
+
+type invoiceAmountView struct {
+	Label  string
+	Amount string
+	Bold   bool
+}
+
+func buildInvoiceView(transaction *models.Transaction, options InvoiceOptions, pageSize string) *invoiceView {
+	view := &invoiceView{
+		Store:            options.Store,
+		AccentColor:      options.AccentColor,
+		LogoURL:          options.LogoURL,
+		PageSize:         pageSize,
+		Number:           transaction.InvoiceNumber,
+		Date:             transaction.TransactionDate.Format("02/01/2006 15:04"),
+		CustomerID:       transaction.CustomerID,
+		Voided:           transaction.VoidedAt != nil,
+		VoidReason:       transaction.VoidReason,
+		PricesIncludeTax: transaction.PricesIncludeTax,
+	}
+	if !hexColorPattern.MatchString(view.AccentColor) {
+		view.AccentColor = defaultInvoiceAccentColor
+	}
+
+	for i, item := range transaction.TransactionItems {
+		view.Items = append(view.Items, invoiceItemView{
+			No:        i + 1,
+			Name:      item.DisplayName(),
+			SKU:       item.ProductSKU,
+			Quantity:  item.Quantity.String(),
+			UnitPrice: formatAmount(item.UnitPrice),
+			Discount:  formatAmount(item.DiscountAmount),
+			TaxRate:   item.TaxRate.String() + "%",
+			Amount:    formatAmount(item.TotalAmount),
+		})
+	}
+
+	var voucherAmount money.Money
+	if transaction.Voucher != nil {
+		voucherAmount = transaction.Voucher.DiscountAmount
+	}
+	manualDiscount := transaction.DiscountAmount - transaction.PromotionDiscountAmount - voucherAmount
+
+	total := func(label string, amount money.Money) {
+		view.Totals = append(view.Totals, invoiceAmountView{Label: label, Amount: formatAmount(amount)})
+	}
+	total("Subtotal", transaction.GrossAmount)
+	if transaction.PromotionDiscountAmount > 0 {
+		total("Promo", -transaction.PromotionDiscountAmount)
+	}
+	if manualDiscount > 0 {
+		total("Diskon", -manualDiscount)
+	}
+	if transaction.Voucher != nil {
+		total("Voucher "+transaction.Voucher.VoucherCode, -voucherAmount)
+	}
+	for _, tax := range transaction.TaxBreakdown() {
+		if tax.TaxAmount == 0 {
+			continue
+		}
+		total("DPP "+tax.Rate.String()+"%", tax.TaxableAmount)
+		total("PPN "+tax.Rate.String()+"%", tax.TaxAmount)
+	}
+	totalLabel := "Total"
+	if transaction.PricesIncludeTax {
+		totalLabel = "Total (termasuk PPN)"
+	}
+	view.Totals = append(view.Totals, invoiceAmountView{Label: totalLabel, Amount: formatAmount(transaction.TotalAmount), Bold: true})
+
+	for _, payment := range transaction.Payments {
+		label := paymentMethodLabels[payment.Method]
+		if label == "" {
+			label = payment.Method
+		}
+		if payment.Reference != "" {
+			label += " (" + payment.Reference + ")"
+		}
+		view.Payments = append(view.Payments, invoiceAmountView{Label: label, Amount: formatAmount(payment.TenderedAmount)})
+	}
+	if transaction.ChangeAmount > 0 {
+		view.Payments = append(view.Payments, invoiceAmountView{Label: "Kembali", Amount: formatAmount(transaction.ChangeAmount)})
+	}
+
+	return view
+}
+
+var invoiceTemplate = template.Must(template.New("invoice").Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Number}}</title>
+<style>
+@page { size: {{.PageSize}}; margin: 15mm; }
+body { font-family: Helvetica, Arial, sans-serif; font-size: 12px; color: #222; margin: 0; padding: 24px; }
+.header { display: flex; justify-content: space-between; border-bottom: 3px solid {{.AccentColor}}; padding-bottom: 12px; }
+.store h1 { margin: 0 0 4px; font-size: 20px; color: {{.AccentColor}}; }
+.store img { max-height: 60px; margin-bottom: 8px; }
+.meta { text-align: right; }
+.meta h2 { margin: 0 0 4px; font-size: 22px; color: {{.AccentColor}}; }
+.void { color: #B00020; font-weight: bold; }
+table { width: 100%; border-collapse: collapse; margin-top: 16px; }
+th { background: {{.AccentColor}}; color: #FFF; text-align: left; padding: 6px; }
+td { padding: 6px; border-bottom: 1px solid #DDD; }
+.num { text-align: right; white-space: nowrap; }
+.sku { color: #777; font-size: 10px; }
+.totals { width: 45%; margin-left: auto; }
+.totals td { border: none; padding: 3px 6px; }
+.bold td { font-weight: bold; border-top: 2px solid {{.AccentColor}}; }
+.footer { margin-top: 24px; color: #555; text-align: center; }
+</style>
+</head>
+<body>
+<div class="header">
+<div class="store">
+{{- if .LogoURL}}
+<img src="{{.LogoURL}}" alt="{{.Store.Name}}">
+{{- end}}
+<h1>{{.Store.Name}}</h1>
+{{- if .Store.Address}}
+<div>{{.Store.Address}}</div>
+{{- end}}
+{{- if .Store.Phone}}
+<div>Telp. {{.Store.Phone}}</div>
+{{- end}}
+{{- if .Store.TaxID}}
+<div>NPWP {{.Store.TaxID}}</div>
+{{- end}}
+</div>
+<div class="meta">
+<h2>INVOICE</h2>
+<div>No. {{.Number}}</div>
+<div>Tanggal {{.Date}}</div>
+{{- if .CustomerID}}
+<div>Pelanggan {{.CustomerID}}</div>
+{{- end}}
+{{- if .Voided}}
+<div class="void">VOID{{if .VoidReason}}: {{.VoidReason}}{{end}}</div>
+{{- end}}
+</div>
+</div>
+<table class="items">
+<thead>
+<tr><th>No</th><th>Deskripsi</th><th class="num">Qty</th><th class="num">Harga</th><th class="num">Diskon</th><th class="num">PPN</th><th class="num">Jumlah</th></tr>
+</thead>
+<tbody>
+{{- range .Items}}
+<tr><td>{{.No}}</td><td>{{.Name}}{{if .SKU}}<div class="sku">{{.SKU}}</div>{{end}}</td><td class="num">{{.Quantity}}</td><td class="num">{{.UnitPrice}}</td><td class="num">{{.Discount}}</td><td class="num">{{.TaxRate}}</td><td class="num">{{.Amount}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<table class="totals">
+{{- range .Totals}}
+<tr{{if .Bold}} class="bold"{{end}}><td>{{.Label}}</td><td class="num">{{.Amount}}</td></tr>
+{{- end}}
+{{- range .Payments}}
+<tr><td>{{.Label}}</td><td class="num">{{.Amount}}</td></tr>
+{{- end}}
+</table>
+{{- if .Store.Footer}}
+<div class="footer">{{.Store.Footer}}</div>
+{{- end}}
+</body>
+</html>
+`))
+
+func renderInvoiceHTML(view *invoiceView) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := invoiceTemplate.Execute(&buf, view); err != nil {
+		return nil, fmt.Errorf("failed to render invoice: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// kolom tabel item PDF: proporsi lebar dan rata kanan
+var invoicePDFColumns = []struct {
+	title string
+	width float64
+	right bool
+}{
+	{"No", 0.05, false},
+	{"Deskripsi", 0.37, false},
+	{"Qty", 0.08, true},
+	{"Harga", 0.14, true},
+	{"Diskon", 0.12, true},
+	{"PPN", 0.08, true},
+	{"Jumlah", 0.16, true},
+}
+
+// renderInvoicePDF menggambar invoice dengan layout yang sama untuk A4 dan A5 (diskalakan).
+// Item yang tidak muat dilanjutkan ke halaman berikutnya dengan header tabel diulang.
+func renderInvoicePDF(view *invoiceView, size pdf.PageSize) []byte {
+	doc := pdf.New(size)
+	scale := size.Width / pdf.A4.Width
+	margin := 40 * scale
+	fontSize := 9 * scale
+	lineHeight := fontSize * 1.6
+	contentWidth := size.Width - 2*margin
+	bottom := size.Height - margin - 2*lineHeight // ruang untuk footer halaman
+	accent := parseHexColor(view.AccentColor)
+	white := pdf.Color{R: 1, G: 1, B: 1}
+	grey := pdf.Color{R: 0.4, G: 0.4, B: 0.4}
+
+	doc.AddPage()
+	y := margin + 16*scale
+
+	// header toko di kiri, identitas invoice di kanan
+	doc.Text(margin, y, pdf.HelveticaBold, 16*scale, accent, pdf.Fit(view.Store.Name, pdf.HelveticaBold, 16*scale, contentWidth*0.55))
+	doc.TextRight(margin+contentWidth, y, pdf.HelveticaBold, 18*scale, accent, "INVOICE")
+	left := []string{}
+	if view.Store.Address != "" {
+		left = append(left, view.Store.Address)
+	}
+	if view.Store.Phone != "" {
+		left = append(left, "Telp. "+view.Store.Phone)
+	}
+	if view.Store.TaxID != "" {
+		left = append(left, "NPWP "+view.Store.TaxID)
+	}
+	right := []string{"No. " + view.Number, "Tanggal " + view.Date}
+	if view.CustomerID != "" {
+		right = append(right, "Pelanggan "+view.CustomerID)
+	}
+	headerY := y + 4*scale
+	for i := 0; i < len(left) || i < len(right); i++ {
+		headerY += lineHeight
+		if i < len(left) {
+			doc.Text(margin, headerY, pdf.Helvetica, fontSize, pdf.Black, pdf.Fit(left[i], pdf.Helvetica, fontSize, contentWidth*0.55))
+		}
+		if i < len(right) {
+			doc.TextRight(margin+contentWidth, headerY, pdf.Helvetica, fontSize, pdf.Black, right[i])
+		}
+	}
+	if view.Voided {
+		headerY += lineHeight
+		text := "VOID"
+		if view.VoidReason != "" {
+			text += ": " + view.VoidReason
+		}
+		doc.TextRight(margin+contentWidth, headerY, pdf.HelveticaBold, fontSize, pdf.Color{R: 0.69}, pdf.Fit(text, pdf.HelveticaBold, fontSize, contentWidth*0.45))
+	}
+	y = headerY + lineHeight*0.6
+	doc.Line(margin, y, margin+contentWidth, y, 2*scale, accent)
+	y += lineHeight
+
+	tableHeader := func() {
+		doc.FillRect(margin, y, contentWidth, lineHeight+2*scale, accent)
+		x := margin
+		for _, column := range invoicePDFColumns {
+			width := contentWidth * column.width
+			textY := y + lineHeight - 2*scale
+			if column.right {
+				doc.TextRight(x+width-3*scale, textY, pdf.HelveticaBold, fontSize, white, column.title)
+			} else {
+				doc.Text(x+3*scale, textY, pdf.HelveticaBold, fontSize, white, column.title)
+			}
+			x += width
+		}
+		y += lineHeight + 2*scale
+	}
+	newPage := func() {
+		doc.AddPage()
+		y = margin + 12*scale
+		doc.Text(margin, y, pdf.HelveticaBold, fontSize, accent, view.Store.Name)
+		doc.TextRight(margin+contentWidth, y, pdf.Helvetica, fontSize, pdf.Black, "Invoice No. "+view.Number+" (lanjutan)")
+		y += lineHeight
+	}
+
+	tableHeader()
+	for _, item := range view.Items {
+		rowHeight := lineHeight
+		if item.SKU != "" {
+			rowHeight += lineHeight * 0.8
+		}
+		if y+rowHeight > bottom {
+			newPage()
+			tableHeader()
+		}
+
+		values := []string{strconv.Itoa(item.No), item.Name, item.Quantity, item.UnitPrice, item.Discount, item.TaxRate, item.Amount}
+		x := margin
+		textY := y + lineHeight - 3*scale
+		for i, column := range invoicePDFColumns {
+			width := contentWidth * column.width
+			text := pdf.Fit(values[i], pdf.Helvetica, fontSize, width-6*scale)
+			if column.right {
+				doc.TextRight(x+width-3*scale, textY, pdf.Helvetica, fontSize, pdf.Black, text)
+			} else {
+				doc.Text(x+3*scale, textY, pdf.Helvetica, fontSize, pdf.Black, text)
+			}
+			if i == 1 && item.SKU != "" {
+				doc.Text(x+3*scale, textY+lineHeight*0.8, pdf.Helvetica, fontSize*0.8, grey, pdf.Fit(item.SKU, pdf.Helvetica, fontSize*0.8, width-6*scale))
+			}
+			x += width
+		}
+		y += rowHeight
+		doc.Line(margin, y, margin+contentWidth, y, 0.5*scale, pdf.Color{R: 0.85, G: 0.85, B: 0.85})
+	}
+
+	// blok total rata kanan, pindah halaman jika tidak muat
+	rows := append(append([]invoiceAmountView{}, view.Totals...), view.Payments...)
+	if y+lineHeight*float64(len(rows)+1) > bottom {
+		newPage()
+	}
+	y += lineHeight * 0.5
+	labelX := margin + contentWidth*0.55
+	for i, row := range rows {
+		y += lineHeight
+		font := pdf.Helvetica
+		if row.Bold {
+			font = pdf.HelveticaBold
+			doc.Line(labelX, y-lineHeight+3*scale, margin+contentWidth, y-lineHeight+3*scale, 1*scale, accent)
+		}
+		if i == len(view.Totals) {
+			// jarak antara total dan pembayaran
+			y += lineHeight * 0.5
+		}
+		doc.Text(labelX, y, font, fontSize, pdf.Black, pdf.Fit(row.Label, font, fontSize, contentWidth*0.25))
+		doc.TextRight(margin+contentWidth, y, font, fontSize, pdf.Black, row.Amount)
+	}
+
+	// footer dan nomor halaman di setiap halaman
+	pages := doc.PageCount()
+	footerY := size.Height - margin
+	for page := 1; page <= pages; page++ {
+		doc.SetPage(page)
+		doc.Line(margin, footerY-lineHeight, margin+contentWidth, footerY-lineHeight, 0.5*scale, grey)
+		if view.Store.Footer != "" {
+			doc.Text(margin, footerY, pdf.Helvetica, fontSize*0.9, grey, pdf.Fit(view.Store.Footer, pdf.Helvetica, fontSize*0.9, contentWidth*0.75))
+		}
+		doc.TextRight(margin+contentWidth, footerY, pdf.Helvetica, fontSize*0.9, grey, fmt.Sprintf("Halaman %d dari %d", page, pages))
+	}
+
+	return doc.Bytes()
+}
+
+func parseHexColor(hex string) pdf.Color {
+	value, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return pdf.Black
+	}
+	return pdf.Color{
+		R: float64(value>>16&0xFF) / 255,
+		G: float64(value>>8&0xFF) / 255,
+		B: float64(value&0xFF) / 255,
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"transaction-service/models"
+	"transaction-service/money"
+)
+
+// go test ./services -run TestRenderInvoice -update menulis ulang file golden di testdata
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file, run with -update if the change is intended", path)
+	}
+}
+
+func invoiceFixture() *models.Transaction {
+	variantID := uint(7)
+	return &models.Transaction{
+		ID:                      42,
+		InvoiceNumber:           "INV/STORE01/20261017/0042",
+		TransactionDate:         time.Date(2026, time.October, 17, 14, 30, 0, 0, time.FixedZone("WIB", 7*60*60)),
+		GrossAmount:             money.New(147500),
+		DiscountAmount:          money.New(23000),
+		PromotionDiscountAmount: money.New(5000),
+		CustomerID:              "CUST-001",
+		Voucher:                 &models.VoucherRedemption{VoucherCode: "HEMAT10", DiscountAmount: money.New(10000)},
+		PricesIncludeTax:        true,
+		TaxAmount:               1060360,
+		TotalAmount:             money.New(124500),
+		PaidAmount:              money.New(130000),
+		ChangeAmount:            money.New(5500),
+		TransactionItems: []models.TransactionItem{
+			{
+				ProductName:             "Kopi Susu (Gula Aren)",
+				ProductSKU:              "KOPI-001",
+				UnitPrice:               money.New(25000),
+				Quantity:                2000,
+				GrossAmount:             money.New(50000),
+				PromotionDiscountAmount: money.New(5000),
+				DiscountAmount:          money.New(5000),
+				Subtotal:                money.New(45000),
+				TaxClass:                "STANDARD",
+				TaxRate:                 1100,
+				TaxableAmount:           4054054,
+				TaxAmount:               445946,
+				TotalAmount:             money.New(45000),
+			},
+			{
+				ProductName:    "Kaos Polos",
+				ProductSKU:     "KAOS-M-HITAM",
+				VariantID:      &variantID,
+				VariantName:    "M / Hitam",
+				UnitPrice:      money.New(80000),
+				Quantity:       1000,
+				GrossAmount:    money.New(80000),
+				DiscountAmount: money.New(18000),
+				Subtotal:       money.New(62000),
+				TaxClass:       "STANDARD",
+				TaxRate:        1100,
+				TaxableAmount:  5585586,
+				TaxAmount:      614414,
+				TotalAmount:    money.New(62000),
+			},
+			{
+				ProductName:   "Beras Pandan Wangi",
+				UnitPrice:     money.New(14000),
+				Quantity:      1250,
+				GrossAmount:   money.New(17500),
+				Subtotal:      money.New(17500),
+				TaxClass:      "EXEMPT",
+				TaxableAmount: money.New(17500),
+				TotalAmount:   money.New(17500),
+			},
+		},
+		Payments: []models.TransactionPayment{
+			{Method: models.PaymentMethodQRIS, TenderedAmount: money.New(100000), Amount: money.New(100000), Reference: "QR123"},
+			{Method: models.PaymentMethodCash, TenderedAmount: money.New(30000), Amount: money.New(24500)},
+		},
+	}
+}
+
+// voidedInvoiceFixture cukup panjang untuk dilanjutkan ke halaman berikutnya di A5
+func voidedInvoiceFixture() *models.Transaction {
+	transaction := invoiceFixture()
+	voidedAt := transaction.TransactionDate.Add(time.Hour)
+	transaction.VoidedAt = &voidedAt
+	transaction.VoidReason = "Salah input <harga>"
+	base := transaction.TransactionItems[2]
+	for i := 1; i <= 40; i++ {
+		item := base
+		item.ProductName = fmt.Sprintf("Barang dengan nama yang cukup panjang untuk dipotong nomor %d", i)
+		transaction.TransactionItems = append(transaction.TransactionItems, item)
+	}
+	return transaction
+}
+
+func invoiceFixtureOptions() InvoiceOptions {
+	return InvoiceOptions{
+		Store: StoreInfo{
+			Name:    "Toko Maju & Jaya",
+			Address: "Jl. Merdeka No. 1, Bandung",
+			Phone:   "022-123456",
+			TaxID:   "01.234.567.8-901.000",
+			Footer:  "Terima kasih atas kunjungan Anda",
+		},
+		AccentColor: "#1F4E79",
+		LogoURL:     "https://example.com/logo.png",
+	}
+}
+
+func TestRenderInvoiceHTML(t *testing.T) {
+	tests := []struct {
+		name        string
+		transaction *models.Transaction
+		pageSize    string
+	}{
+		{"invoice_a4", invoiceFixture(), InvoicePageA4},
+		{"invoice_voided_a5", voidedInvoiceFixture(), InvoicePageA5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderInvoiceHTML(buildInvoiceView(tt.transaction, invoiceFixtureOptions(), tt.pageSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertGolden(t, tt.name+".html", got)
+		})
+	}
+}
+
+func TestRenderInvoicePDF(t *testing.T) {
+	tests := []struct {
+		name        string
+		transaction *models.Transaction
+		pageSize    string
+		accentColor string
+	}{
+		{"invoice_a4", invoiceFixture(), InvoicePageA4, "#1F4E79"},
+		{"invoice_a5", invoiceFixture(), InvoicePageA5, "#1F4E79"},
+		// warna yang tidak valid memakai warna default
+		{"invoice_voided_a5", voidedInvoiceFixture(), InvoicePageA5, "merah"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := invoiceFixtureOptions()
+			options.AccentColor = tt.accentColor
+			got := renderInvoicePDF(buildInvoiceView(tt.transaction, options, tt.pageSize), invoicePageSizes[tt.pageSize])
+			assertGolden(t, tt.name+".pdf", got)
+
+			// render ulang harus menghasilkan byte yang sama
+			again := renderInvoicePDF(buildInvoiceView(tt.transaction, options, tt.pageSize), invoicePageSizes[tt.pageSize])
+			if !bytes.Equal(got, again) {
+				t.Error("rendering the same invoice twice produced different bytes")
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"transaction-service/dto"
+	"transaction-service/repositories"
+)
+
+// InvoiceOptions berisi branding toko untuk invoice dari environment
+type InvoiceOptions struct {
+	Store StoreInfo
+	// warna utama format #RRGGBB
+	AccentColor string
+	// URL logo, hanya dipakai pada invoice HTML
+	LogoURL string
+	// ukuran kertas default: A4 atau A5
+	DefaultPageSize string
+}
+
+type InvoiceService interface {
+	RenderInvoice(transactionID uint, req *dto.InvoiceRequest) (*dto.Document, error)
+}
+
+type invoiceService struct {
+	repo    repositories.TransactionRepository
+	options InvoiceOptions
+}
+
+func NewInvoiceService(repo repositories.TransactionRepository, options InvoiceOptions) InvoiceService {
+	return &invoiceService{repo: repo, options: options}
+}
+
+// RenderInvoice merender invoice dari transaksi tersimpan. Output hanya bergantung pada data
+// transaksi dan konfigurasi, sehingga render ulang menghasilkan byte yang sama.
+func (s *invoiceService) RenderInvoice(transactionID uint, req *dto.InvoiceRequest) (*dto.Document, error) {
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	if format != InvoiceFormatPDF && format != InvoiceFormatHTML {
+		return nil, fmt.Errorf("invalid invoice format '%s', must be %s or %s", req.Format, InvoiceFormatPDF, InvoiceFormatHTML)
+	}
+
+	pageSize := strings.ToUpper(strings.TrimSpace(req.PageSize))
+	if pageSize == "" {
+		pageSize = strings.ToUpper(s.options.DefaultPageSize)
+	}
+	size, exists := invoicePageSizes[pageSize]
+	if !exists {
+		return nil, fmt.Errorf("invalid invoice page size '%s', must be %s or %s", req.PageSize, InvoicePageA4, InvoicePageA5)
+	}
+
+	transaction, err := s.repo.GetByID(transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, err
+	}
+
+	view := buildInvoiceView(transaction, s.options, pageSize)
+	document := &dto.Document{
+		FileName: strings.ReplaceAll(view.Number, "/", "-"),
+	}
+	if format == InvoiceFormatHTML {
+		content, err := renderInvoiceHTML(view)
+		if err != nil {
+			return nil, err
+		}
+		document.ContentType = "text/html; charset=utf-8"
+		document.FileName += ".html"
+		document.Content = content
+	} else {
+		document.ContentType = "application/pdf"
+		document.FileName += ".pdf"
+		document.Content = renderInvoicePDF(view, size)
+	}
+
+	return document, nil
+}
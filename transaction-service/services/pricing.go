@@ -0,0 +1,498 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"transaction-service/clients"
+	"transaction-service/dto"
+	"transaction-service/models"
+	"transaction-service/money"
+	"transaction-service/quantity"
+)
+
+// role default jika header role tidak dikirim
+const DefaultOperatorRole = "CASHIER"
+
+// priceBasket menghitung harga keranjang tanpa menyimpan apa pun. Semua nilai memakai money.Money
+// (sen) sehingga total transaksi selalu sama persis dengan jumlah baris. Urutan perhitungan:
+//  1. gross per baris = harga produk * quantity
+//  2. promosi otomatis yang sedang aktif (lihat applyPromotions)
+//  3. diskon baris (persentase atau nominal) dari gross baris setelah promosi
+//  4. diskon keranjang dari total setelah diskon baris, dialokasikan proporsional ke tiap baris
+//  5. batas diskon maksimum per role dicek per baris, hanya untuk diskon manual
+//  6. voucher dari total setelah semua diskon, dialokasikan proporsional ke tiap baris
+//  7. pajak per baris sesuai kelas pajak produk, dari harga termasuk pajak (diekstrak) atau belum termasuk pajak (ditambahkan)
+//
+// Jika warnings tidak nil (preview keranjang), masalah yang bisa diperbaiki kasir tidak menggagalkan
+// perhitungan tetapi dicatat sebagai warning: produk tidak tersedia (baris dilewati), stok kurang,
+// diskon tidak valid (diabaikan), diskon melebihi batas role dan voucher yang tidak bisa dipakai (diabaikan).
+func (s *transactionService) priceBasket(req *dto.PricingRequest, operator dto.Operator, warnings *[]dto.PricingWarning) (*models.Transaction, error) {
+	if len(req.Items) == 0 {
+		return nil, errors.New("transaction must have at least one item")
+	}
+
+	// Validate request items
+	for _, item := range req.Items {
+		identifiers := 0
+		for _, set := range []bool{item.ProductID != 0, item.Barcode != "", item.SKU != ""} {
+			if set {
+				identifiers++
+			}
+		}
+		if identifiers == 0 {
+			return nil, errors.New("product_id, barcode or sku is required")
+		}
+		if identifiers > 1 {
+			return nil, errors.New("use only one of product_id, barcode or sku per item")
+		}
+		// varian dari barcode/sku sudah ditentukan oleh lookup
+		if item.VariantID != 0 && item.ProductID == 0 {
+			return nil, errors.New("invalid item, variant_id can only be used with product_id")
+		}
+		// quantity baris label timbangan boleh kosong, diambil dari label
+		if item.Quantity < 0 || (item.Quantity == 0 && item.Barcode == "") {
+			return nil, errors.New("quantity must be greater than 0")
+		}
+	}
+
+	transaction := &models.Transaction{
+		TransactionDate: time.Now(),
+	}
+
+	// stok yang sudah ditahan reservasi keranjang ini tersedia lagi untuk barisnya sendiri
+	held := make(map[stockKey]quantity.Quantity)
+	if req.StockHeld {
+		for _, item := range req.Items {
+			held[stockKey{productID: item.ProductID, variantID: item.VariantID}] += item.Quantity
+		}
+	}
+
+	// baris request yang ikut dihitung, sejajar dengan transaction.TransactionItems
+	var requested []dto.TransactionItemRequest
+	for _, item := range req.Items {
+		// Get product details from product service
+		product, err := s.resolveProduct(&item)
+		if err != nil {
+			err = fmt.Errorf("product %s not found or service unavailable", describeItemProduct(item))
+			if err := warnOrFail(warnings, dto.PricingWarning{Code: dto.WarningProductUnavailable, ProductID: item.ProductID}, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// produk bervarian dijual per varian: harga, SKU dan stok tersedia (di luar reservasi aktif) diambil dari varian
+		price, sku, stock, variantName := product.Price, product.SKU, product.AvailableStock, ""
+		variant, err := selectVariant(product, item.VariantID)
+		if err != nil {
+			// keranjang yang disimpan bisa berisi produk yang kemudian diberi varian atau varian yang dihapus
+			code := dto.WarningProductUnavailable
+			if item.VariantID == 0 {
+				code = dto.WarningVariantRequired
+			}
+			if err := warnOrFail(warnings, dto.PricingWarning{Code: code, ProductID: item.ProductID}, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if variant != nil {
+			price, stock, variantName = variant.Price, variant.AvailableStock, variant.Title
+			if variant.SKU != "" {
+				sku = variant.SKU
+			}
+		}
+
+		// label timbangan menentukan quantity (berat) dan harga baris; barang lain hanya dijual per unit utuh
+		grossAmount := item.Quantity.Amount(price)
+		if product.Scale != nil {
+			if item.Quantity != 0 && item.Quantity != product.Scale.Quantity {
+				return nil, fmt.Errorf("quantity of scale label %s is taken from the label (%s), omit it", item.Barcode, product.Scale.Quantity)
+			}
+			item.Quantity = product.Scale.Quantity
+			grossAmount = product.Scale.Amount
+		} else if item.Quantity == 0 {
+			return nil, errors.New("quantity must be greater than 0")
+		} else if !item.Quantity.IsWhole() {
+			return nil, fmt.Errorf("quantity %s of product '%s' must be a whole number, fractional quantities come from scale labels",
+				item.Quantity, product.Name)
+		}
+
+		// Check stock availability
+		key := stockKey{productID: product.ID}
+		if variant != nil {
+			key.variantID = variant.ID
+		}
+		stock += held[key]
+		if stock < item.Quantity {
+			err := fmt.Errorf("insufficient stock for product '%s'. Available: %s, Requested: %s",
+				describeVariant(product.Name, variantName), stock, item.Quantity)
+			warning := dto.PricingWarning{Code: dto.WarningInsufficientStock, ProductID: item.ProductID, Available: &stock}
+			if err := warnOrFail(warnings, warning, err); err != nil {
+				return nil, err
+			}
+		}
+
+		// simpan snapshot nama & harga saat transaksi
+		requested = append(requested, item)
+		transaction.TransactionItems = append(transaction.TransactionItems, models.TransactionItem{
+			ProductID:    item.ProductID,
+			ProductName:  product.Name,
+			ProductSKU:   sku,
+			VariantID:    variantIDOf(variant),
+			VariantName:  variantName,
+			UnitPrice:    price,
+			Quantity:     item.Quantity,
+			SoldByWeight: product.Scale != nil,
+			CategoryID:   product.CategoryID,
+			GrossAmount:  grossAmount,
+			TaxClass:     taxClassOrDefault(product.TaxClass),
+		})
+	}
+
+	promotions, err := s.promotionRepo.GetActive(transaction.TransactionDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load promotions: %w", err)
+	}
+	applyPromotions(promotions, transaction.TransactionItems, transaction.TransactionDate)
+
+	for i, item := range requested {
+		line := &transaction.TransactionItems[i]
+		line.DiscountAmount = line.PromotionDiscountAmount
+
+		lineDiscount, err := calculateDiscount(line.GrossAmount-line.PromotionDiscountAmount, item.Discount)
+		if err != nil {
+			err = fmt.Errorf("invalid discount for product '%s': %w", line.ProductName, err)
+			if err := warnOrFail(warnings, dto.PricingWarning{Code: dto.WarningInvalidDiscount, ProductID: line.ProductID}, err); err != nil {
+				return nil, err
+			}
+		}
+		if lineDiscount != nil {
+			line.LineDiscount = lineDiscount
+			line.DiscountAmount += lineDiscount.Amount
+		}
+	}
+
+	// diskon keranjang dihitung dari total setelah diskon baris
+	bases := make([]money.Money, len(transaction.TransactionItems))
+	for i, line := range transaction.TransactionItems {
+		bases[i] = line.GrossAmount - line.DiscountAmount
+	}
+
+	cartDiscount, err := calculateDiscount(money.Sum(bases), req.Discount)
+	if err != nil {
+		err = fmt.Errorf("invalid cart discount: %w", err)
+		if err := warnOrFail(warnings, dto.PricingWarning{Code: dto.WarningInvalidDiscount}, err); err != nil {
+			return nil, err
+		}
+	}
+	if cartDiscount != nil {
+		transaction.CartDiscount = cartDiscount
+		for i, share := range money.Allocate(cartDiscount.Amount, bases) {
+			transaction.TransactionItems[i].DiscountAmount += share
+		}
+	}
+
+	if err := s.checkDiscountLimit(transaction.TransactionItems, operator); err != nil {
+		if err := warnOrFail(warnings, dto.PricingWarning{Code: dto.WarningDiscountLimitExceeded}, err); err != nil {
+			return nil, err
+		}
+	}
+
+	transaction.CustomerID = strings.TrimSpace(req.CustomerID)
+	if code := strings.TrimSpace(req.VoucherCode); code != "" {
+		if err := s.applyVoucher(transaction, code); err != nil {
+			if strings.HasPrefix(err.Error(), "failed to") {
+				return nil, err
+			}
+			if err := warnOrFail(warnings, dto.PricingWarning{Code: dto.WarningVoucherNotApplicable}, err); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i := range transaction.TransactionItems {
+		line := &transaction.TransactionItems[i]
+		line.Subtotal = line.GrossAmount - line.DiscountAmount
+	}
+
+	if err := s.applyTax(transaction); err != nil {
+		return nil, err
+	}
+
+	for _, line := range transaction.TransactionItems {
+		transaction.GrossAmount += line.GrossAmount
+		transaction.PromotionDiscountAmount += line.PromotionDiscountAmount
+		transaction.DiscountAmount += line.DiscountAmount
+		transaction.TaxAmount += line.TaxAmount
+		transaction.TotalAmount += line.TotalAmount
+	}
+
+	return transaction, nil
+}
+
+// warnOrFail mengembalikan err saat checkout, atau mencatatnya sebagai warning saat preview keranjang
+func warnOrFail(warnings *[]dto.PricingWarning, warning dto.PricingWarning, err error) error {
+	if warnings == nil {
+		return err
+	}
+	warning.Message = err.Error()
+	*warnings = append(*warnings, warning)
+	return nil
+}
+
+// checkDiscountLimit memastikan diskon manual tiap baris tidak melebihi batas role kasir.
+// Potongan promosi tidak dihitung karena bukan keputusan kasir. Role dari request hanya dipakai jika
+// sumbernya dipercaya (TrustRoleHeader), selain itu diskon yang butuh role lebih tinggi ditolak.
+func (s *transactionService) checkDiscountLimit(lines []models.TransactionItem, operator dto.Operator) error {
+	role := strings.ToUpper(strings.TrimSpace(operator.Role))
+	untrusted := ""
+	if !s.options.TrustRoleHeader && role != "" && role != DefaultOperatorRole {
+		untrusted = fmt.Sprintf(" (role %s is not trusted)", role)
+		role = DefaultOperatorRole
+	}
+	if role == "" {
+		role = DefaultOperatorRole
+	}
+	maxPercent := s.options.MaxDiscountPercent[role]
+
+	for _, line := range lines {
+		manual := line.DiscountAmount - line.PromotionDiscountAmount
+		base := line.GrossAmount - line.PromotionDiscountAmount
+		if manual <= 0 || base <= 0 {
+			continue
+		}
+
+		// batas dibulatkan ke sen dengan aturan yang sama seperti perhitungan diskon
+		if manual > base.Percent(maxPercent) {
+			return fmt.Errorf("discount of %s%% on '%s' exceeds the maximum of %s%% allowed for role %s%s",
+				manual.PercentOf(base), line.ProductName, maxPercent, role, untrusted)
+		}
+	}
+
+	return nil
+}
+
+// calculateDiscount menghitung nominal diskon dari base. Mengembalikan nil jika tidak ada diskon.
+func calculateDiscount(base money.Money, req *dto.DiscountRequest) (*models.Discount, error) {
+	discount, err := requestedDiscount(req)
+	if err != nil || discount == nil {
+		return nil, err
+	}
+
+	switch discount.Type {
+	case models.DiscountTypePercentage:
+		discount.Amount = base.Percent(money.Percent(discount.Value))
+	case models.DiscountTypeFixed:
+		discount.Amount = discount.Value
+		if discount.Amount > base {
+			return nil, fmt.Errorf("fixed discount %s exceeds the amount %s", discount.Amount, base)
+		}
+	}
+
+	return discount, nil
+}
+
+// requestedDiscount memvalidasi diskon keranjang/baris, nominalnya baru dihitung saat checkout
+func requestedDiscount(req *dto.DiscountRequest) (*models.Discount, error) {
+	if req == nil {
+		return nil, nil
+	}
+	if req.Value <= 0 {
+		return nil, errors.New("discount value must be greater than 0")
+	}
+
+	discount := &models.Discount{
+		Type:  strings.ToUpper(strings.TrimSpace(req.Type)),
+		Value: req.Value,
+	}
+	switch discount.Type {
+	case models.DiscountTypePercentage:
+		// Value berskala 2 desimal, sama dengan money.Percent
+		if money.Percent(req.Value) > money.Hundred {
+			return nil, errors.New("percentage discount cannot exceed 100")
+		}
+	case models.DiscountTypeFixed:
+	default:
+		return nil, fmt.Errorf("discount type must be %s or %s", models.DiscountTypePercentage, models.DiscountTypeFixed)
+	}
+	return discount, nil
+}
+
+// applyVoucher memvalidasi voucher (status, kedaluwarsa, kuota, minimum belanja) dan mengalokasikan
+// potongannya ke baris. Kuota dicek ulang dengan row lock saat transaksi disimpan.
+func (s *transactionService) applyVoucher(transaction *models.Transaction, code string) error {
+	voucher, err := s.voucherRepo.GetByCode(code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("voucher %s not found", strings.ToUpper(code))
+		}
+		return fmt.Errorf("failed to load voucher: %w", err)
+	}
+
+	if err := voucher.CheckRedeemable(transaction.TransactionDate); err != nil {
+		return err
+	}
+	if voucher.PerCustomerLimit > 0 {
+		if transaction.CustomerID == "" {
+			return fmt.Errorf("customer_id is required to redeem voucher %s", voucher.Code)
+		}
+		used, err := s.voucherRepo.CountCustomerRedemptions(voucher.ID, transaction.CustomerID)
+		if err != nil {
+			return fmt.Errorf("failed to count voucher redemptions: %w", err)
+		}
+		if used >= voucher.PerCustomerLimit {
+			return fmt.Errorf("voucher %s has reached its usage limit for this customer", voucher.Code)
+		}
+	}
+
+	bases := make([]money.Money, len(transaction.TransactionItems))
+	for i, line := range transaction.TransactionItems {
+		bases[i] = line.GrossAmount - line.DiscountAmount
+	}
+	total := money.Sum(bases)
+
+	if total < voucher.MinSpend {
+		return fmt.Errorf("voucher %s requires a minimum spend of %s", voucher.Code, voucher.MinSpend)
+	}
+
+	var amount money.Money
+	switch voucher.DiscountType {
+	case models.DiscountTypePercentage:
+		amount = total.Percent(money.Percent(voucher.Value))
+		if voucher.MaxDiscountAmount > 0 && amount > voucher.MaxDiscountAmount {
+			amount = voucher.MaxDiscountAmount
+		}
+	default:
+		amount = voucher.Value
+	}
+	// voucher nominal yang lebih besar dari total hanya memotong sampai 0
+	if amount > total {
+		amount = total
+	}
+
+	for i, share := range money.Allocate(amount, bases) {
+		transaction.TransactionItems[i].DiscountAmount += share
+	}
+	transaction.Voucher = &models.VoucherRedemption{
+		VoucherID:      voucher.ID,
+		VoucherCode:    voucher.Code,
+		CustomerID:     transaction.CustomerID,
+		DiscountAmount: amount,
+	}
+
+	return nil
+}
+
+// applyTax menghitung pajak tiap baris dari subtotal (setelah semua diskon). Jika harga sudah
+// termasuk pajak, pajak diekstrak dari subtotal: pajak = subtotal * tarif / (100 + tarif).
+// Jika belum, pajak = subtotal * tarif / 100 dan ditambahkan ke total baris. Pajak dibulatkan ke sen per baris.
+func (s *transactionService) applyTax(transaction *models.Transaction) error {
+	rates, err := s.taxRateRepo.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to load tax rates: %w", err)
+	}
+	rateByClass := make(map[string]models.TaxRate, len(rates))
+	for _, rate := range rates {
+		rateByClass[rate.TaxClass] = rate
+	}
+
+	transaction.PricesIncludeTax = s.options.PricesIncludeTax
+	for i := range transaction.TransactionItems {
+		line := &transaction.TransactionItems[i]
+
+		rate, exists := rateByClass[line.TaxClass]
+		if !exists || !rate.Active {
+			return fmt.Errorf("tax rate for class %s on '%s' is not configured", line.TaxClass, line.ProductName)
+		}
+		line.TaxRate = rate.Rate
+
+		if transaction.PricesIncludeTax {
+			line.TaxAmount = line.Subtotal.IncludedTax(rate.Rate)
+			line.TaxableAmount = line.Subtotal - line.TaxAmount
+			line.TotalAmount = line.Subtotal
+		} else {
+			line.TaxableAmount = line.Subtotal
+			line.TaxAmount = line.Subtotal.Percent(rate.Rate)
+			line.TotalAmount = line.Subtotal + line.TaxAmount
+		}
+	}
+
+	return nil
+}
+
+func taxClassOrDefault(taxClass string) string {
+	taxClass = strings.ToUpper(strings.TrimSpace(taxClass))
+	if taxClass == "" {
+		return models.TaxClassStandard
+	}
+	return taxClass
+}
+
+// resolveProduct mengambil produk baris dari product-service. Baris yang memakai barcode atau SKU
+// diisi ProductID-nya sehingga langkah berikutnya (reservasi, penyimpanan) cukup memakai ID.
+func (s *transactionService) resolveProduct(item *dto.TransactionItemRequest) (*clients.ProductResponse, error) {
+	if item.ProductID != 0 {
+		return s.productClient.GetByID(item.ProductID)
+	}
+
+	product, err := s.productClient.Lookup(item.Barcode, item.SKU)
+	if err != nil {
+		return nil, err
+	}
+	item.ProductID = product.ID
+	if product.Variant != nil {
+		item.VariantID = product.Variant.ID
+	}
+	return product, nil
+}
+
+// selectVariant memilih varian baris; nil untuk produk tanpa varian
+func selectVariant(product *clients.ProductResponse, variantID uint) (*clients.VariantResponse, error) {
+	if variantID == 0 {
+		if len(product.Variants) > 0 {
+			return nil, fmt.Errorf("invalid item, product '%s' has variants, variant_id is required", product.Name)
+		}
+		return nil, nil
+	}
+	variant := product.FindVariant(variantID)
+	if variant == nil {
+		return nil, fmt.Errorf("invalid item, variant %d of product '%s' not found", variantID, product.Name)
+	}
+	return variant, nil
+}
+
+// stockKey menunjuk stok satu produk, atau satu variannya jika variantID tidak 0
+type stockKey struct {
+	productID uint
+	variantID uint
+}
+
+func variantIDOf(variant *clients.VariantResponse) *uint {
+	if variant == nil {
+		return nil
+	}
+	id := variant.ID
+	return &id
+}
+
+// describeVariant menyebut produk beserta variannya untuk pesan error, mis. "T-Shirt Basic (M / Black)"
+func describeVariant(productName, variantName string) string {
+	if variantName == "" {
+		return productName
+	}
+	return productName + " (" + variantName + ")"
+}
+
+// describeItemProduct menyebut produk baris sesuai identitas yang dikirim kasir, untuk pesan error
+func describeItemProduct(item dto.TransactionItemRequest) string {
+	switch {
+	case item.Barcode != "":
+		return "with barcode " + item.Barcode
+	case item.SKU != "":
+		return "with SKU " + item.SKU
+	default:
+		return fmt.Sprintf("with ID %d", item.ProductID)
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"transaction-service/events"
+	"transaction-service/models"
+	"transaction-service/quantity"
+	"transaction-service/repositories"
+)
+
+// ProductEventConsumerName adalah nama consumer untuk deduplikasi dan offset broker
+const ProductEventConsumerName = "transaction-service.product-replicas"
+
+// ProductEventConsumer memperbarui product_replicas dan category_replicas dari event product-service,
+// di antara sinkronisasi berkala
+type ProductEventConsumer interface {
+	Handle(event events.Event) error
+}
+
+type productEventConsumer struct {
+	repo repositories.ProductReplicaRepository
+}
+
+func NewProductEventConsumer(repo repositories.ProductReplicaRepository) ProductEventConsumer {
+	return &productEventConsumer{repo: repo}
+}
+
+func (c *productEventConsumer) Handle(event events.Event) error {
+	var applied bool
+	var err error
+
+	switch event.Type {
+	case events.ProductCreated, events.ProductUpdated, events.ProductDeleted:
+		var payload events.ProductPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.Type, err)
+		}
+		applied, err = c.repo.ApplyProduct(ProductEventConsumerName, event, models.ProductReplica{
+			ProductID:  payload.ID,
+			Name:       payload.Name,
+			Price:      payload.Price,
+			Stock:      payload.Stock,
+			TaxClass:   payload.TaxClass,
+			CategoryID: payload.CategoryID,
+			DeletedAt:  payload.DeletedAt,
+		})
+	case events.StockChanged:
+		var payload events.StockChangedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.Type, err)
+		}
+		// penjualan mengonfirmasi reservasi, jadi stok yang ditahan ikut berkurang sebanyak stok terjual
+		var reservedDelta quantity.Quantity
+		if payload.Reason == events.StockReasonSale {
+			reservedDelta = payload.Delta
+		}
+		applied, err = c.repo.ApplyStock(ProductEventConsumerName, event, payload.ProductID, payload.Stock, reservedDelta)
+	case events.CategoryCreated, events.CategoryUpdated, events.CategoryDeleted:
+		var payload events.CategoryPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.Type, err)
+		}
+		applied, err = c.repo.ApplyCategory(ProductEventConsumerName, event, models.CategoryReplica{
+			CategoryID: payload.ID,
+			Name:       payload.Name,
+			ParentID:   payload.ParentID,
+			SortOrder:  payload.SortOrder,
+			DeletedAt:  payload.DeletedAt,
+		})
+	default:
+		// event lain (mis. transaction.created dari service ini) tidak relevan untuk salinan produk
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+	if !applied {
+		log.Printf("product event consumer: duplicate event %s (%s) skipped", event.ID, event.Type)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"log"
+	"time"
+	"transaction-service/clients"
+	"transaction-service/models"
+	"transaction-service/repositories"
+)
+
+// ProductSyncService menyalin data produk dan kategori dari product-service ke product_replicas dan
+// category_replicas, sehingga laporan tidak perlu membaca database product-service
+type ProductSyncService interface {
+	Sync() (int, error)
+	// Start menyinkronkan sekali saat service naik lalu setiap interval di goroutine terpisah
+	Start(interval time.Duration)
+}
+
+type productSyncService struct {
+	repo          repositories.ProductReplicaRepository
+	productClient clients.ProductClient
+}
+
+func NewProductSyncService(repo repositories.ProductReplicaRepository, productClient clients.ProductClient) ProductSyncService {
+	return &productSyncService{
+		repo:          repo,
+		productClient: productClient,
+	}
+}
+
+func (s *productSyncService) Sync() (int, error) {
+	products, err := s.productClient.ListAll()
+	if err != nil {
+		return 0, err
+	}
+
+	replicas := make([]models.ProductReplica, 0, len(products))
+	for _, product := range products {
+		replicas = append(replicas, models.ProductReplica{
+			ProductID:      product.ID,
+			Name:           product.Name,
+			Price:          product.Price,
+			Stock:          product.Stock,
+			ReservedStock:  product.ReservedStock,
+			AvailableStock: product.AvailableStock,
+			TaxClass:       product.TaxClass,
+			CategoryID:     product.CategoryID,
+		})
+	}
+
+	categories, err := s.productClient.ListCategories()
+	if err != nil {
+		return 0, err
+	}
+
+	categoryReplicas := make([]models.CategoryReplica, 0, len(categories))
+	for _, category := range categories {
+		categoryReplicas = append(categoryReplicas, models.CategoryReplica{
+			CategoryID: category.ID,
+			Name:       category.Name,
+			ParentID:   category.ParentID,
+			SortOrder:  category.SortOrder,
+		})
+	}
+
+	if err := s.repo.Sync(replicas, categoryReplicas, time.Now()); err != nil {
+		return 0, err
+	}
+	return len(replicas), nil
+}
+
+func (s *productSyncService) Start(interval time.Duration) {
+	go func() {
+		s.sync()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.sync()
+		}
+	}()
+}
+
+func (s *productSyncService) sync() {
+	count, err := s.Sync()
+	if err != nil {
+		log.Printf("product sync: %v", err)
+		return
+	}
+	log.Printf("product sync: %d product(s) synced", count)
+}
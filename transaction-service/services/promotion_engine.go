@@ -0,0 +1,209 @@
+package services
+
+import (
+	"sort"
+	"time"
+	"transaction-service/models"
+	"transaction-service/money"
+	"transaction-service/quantity"
+)
+
+// applyPromotions mengevaluasi promosi terhadap keranjang dan mengisi Promotions serta
+// PromotionDiscountAmount tiap baris. Aturan:
+//   - promosi dievaluasi berurutan dari prioritas tertinggi
+//   - promosi non-stackable hanya berlaku pada baris yang belum kena promosi lain,
+//     dan setelah itu baris terkunci untuk promosi berikutnya
+//   - promosi stackable boleh menumpuk dengan promosi stackable lain
+//   - potongan tidak pernah melebihi sisa nilai baris
+func applyPromotions(promotions []models.Promotion, lines []models.TransactionItem, now time.Time) {
+	active := make([]models.Promotion, 0, len(promotions))
+	for _, promotion := range promotions {
+		if promotion.IsActiveAt(now) {
+			active = append(active, promotion)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		if active[i].Priority != active[j].Priority {
+			return active[i].Priority > active[j].Priority
+		}
+		return active[i].ID < active[j].ID
+	})
+
+	promoted := make([]bool, len(lines))
+	locked := make([]bool, len(lines))
+
+	for i := range active {
+		promotion := &active[i]
+
+		eligible := make([]int, 0, len(lines))
+		for idx, line := range lines {
+			if locked[idx] || (!promotion.Stackable && promoted[idx]) {
+				continue
+			}
+			if promotion.AppliesToProduct(line.ProductID, line.CategoryID) && remainingLineAmount(line) > 0 {
+				eligible = append(eligible, idx)
+			}
+		}
+		if len(eligible) == 0 {
+			continue
+		}
+
+		var discounts map[int]money.Money
+		switch promotion.Type {
+		case models.PromotionTypePercentOff:
+			discounts = percentOffDiscounts(promotion, lines, eligible)
+		case models.PromotionTypeBuyXGetY:
+			discounts = buyXGetYDiscounts(promotion, lines, eligible)
+		case models.PromotionTypeBundlePrice:
+			discounts = bundlePriceDiscounts(promotion, lines, eligible)
+		}
+
+		for _, idx := range eligible {
+			amount := discounts[idx]
+			if remaining := remainingLineAmount(lines[idx]); amount > remaining {
+				amount = remaining
+			}
+			if amount <= 0 {
+				continue
+			}
+
+			lines[idx].Promotions = append(lines[idx].Promotions, models.AppliedPromotion{
+				PromotionID:    promotion.ID,
+				PromotionName:  promotion.Name,
+				DiscountAmount: amount,
+			})
+			lines[idx].PromotionDiscountAmount += amount
+			promoted[idx] = true
+			if !promotion.Stackable {
+				locked[idx] = true
+			}
+		}
+	}
+}
+
+func remainingLineAmount(line models.TransactionItem) money.Money {
+	return line.GrossAmount - line.PromotionDiscountAmount
+}
+
+func percentOffDiscounts(promotion *models.Promotion, lines []models.TransactionItem, eligible []int) map[int]money.Money {
+	discounts := make(map[int]money.Money, len(eligible))
+	for _, idx := range eligible {
+		discounts[idx] = remainingLineAmount(lines[idx]).Percent(promotion.Rule.DiscountPercent)
+	}
+	return discounts
+}
+
+// buyXGetYDiscounts: setiap kelipatan (buy+get) unit, get unit termurah gratis. Hanya unit utuh yang
+// dihitung, jadi barang timbang 2.5 kg dihitung 2 unit.
+func buyXGetYDiscounts(promotion *models.Promotion, lines []models.TransactionItem, eligible []int) map[int]money.Money {
+	discounts := make(map[int]money.Money)
+	groupSize := promotion.Rule.BuyQuantity + promotion.Rule.GetQuantity
+	if promotion.Rule.BuyQuantity <= 0 || promotion.Rule.GetQuantity <= 0 {
+		return discounts
+	}
+
+	type unit struct {
+		lineIdx int
+		price   money.Money
+	}
+	var units []unit
+	for _, idx := range eligible {
+		price := lines[idx].UnitPrice
+		if lines[idx].Quantity > 0 {
+			// harga efektif setelah promosi sebelumnya (untuk promosi stackable), hanya untuk urutan
+			price = remainingLineAmount(lines[idx]).MulDiv(quantity.Scale, int64(lines[idx].Quantity))
+		}
+		for q := 0; q < lines[idx].Quantity.Units(); q++ {
+			units = append(units, unit{lineIdx: idx, price: price})
+		}
+	}
+
+	freeUnits := (len(units) / groupSize) * promotion.Rule.GetQuantity
+	if freeUnits == 0 {
+		return discounts
+	}
+
+	// unit termurah yang digratiskan, potongan dihitung dari sisa nilai baris agar tidak ada selisih pembulatan
+	sort.SliceStable(units, func(i, j int) bool { return units[i].price < units[j].price })
+	freeByLine := make(map[int]int)
+	for _, u := range units[:freeUnits] {
+		freeByLine[u.lineIdx]++
+	}
+	for idx, free := range freeByLine {
+		discounts[idx] = remainingLineAmount(lines[idx]).MulDiv(int64(quantity.New(int64(free))), int64(lines[idx].Quantity))
+	}
+
+	return discounts
+}
+
+// bundlePriceDiscounts: jumlah paket = min(qty tersedia / qty per paket), selisih harga normal
+// dengan harga paket dialokasikan proporsional ke baris-baris produk dalam paket
+func bundlePriceDiscounts(promotion *models.Promotion, lines []models.TransactionItem, eligible []int) map[int]money.Money {
+	discounts := make(map[int]money.Money)
+	if len(promotion.Rule.BundleItems) == 0 {
+		return discounts
+	}
+
+	quantityByProduct := make(map[uint]quantity.Quantity)
+	linesByProduct := make(map[uint][]int)
+	for _, idx := range eligible {
+		productID := lines[idx].ProductID
+		quantityByProduct[productID] += lines[idx].Quantity
+		linesByProduct[productID] = append(linesByProduct[productID], idx)
+	}
+
+	bundles := -1
+	for _, item := range promotion.Rule.BundleItems {
+		if item.Quantity <= 0 {
+			return discounts
+		}
+		count := int(quantityByProduct[item.ProductID] / quantity.New(int64(item.Quantity)))
+		if bundles == -1 || count < bundles {
+			bundles = count
+		}
+	}
+	if bundles <= 0 {
+		return discounts
+	}
+
+	// harga normal satu paket memakai harga efektif per unit dari baris termurah produk tersebut
+	var normalPrice money.Money
+	bundleValue := make(map[uint]money.Money)
+	for _, item := range promotion.Rule.BundleItems {
+		cheapest := money.Money(-1)
+		for _, idx := range linesByProduct[item.ProductID] {
+			value := remainingLineAmount(lines[idx]).MulDiv(int64(quantity.New(int64(item.Quantity))), int64(lines[idx].Quantity))
+			if cheapest < 0 || value < cheapest {
+				cheapest = value
+			}
+		}
+		bundleValue[item.ProductID] = cheapest
+		normalPrice += cheapest
+	}
+
+	saving := normalPrice - promotion.Rule.BundlePrice
+	if saving <= 0 {
+		return discounts
+	}
+	totalSaving := saving.Times(bundles)
+
+	productIDs := make([]uint, 0, len(promotion.Rule.BundleItems))
+	weights := make([]money.Money, 0, len(promotion.Rule.BundleItems))
+	for _, item := range promotion.Rule.BundleItems {
+		productIDs = append(productIDs, item.ProductID)
+		weights = append(weights, bundleValue[item.ProductID])
+	}
+
+	for i, share := range money.Allocate(totalSaving, weights) {
+		productLines := linesByProduct[productIDs[i]]
+		lineWeights := make([]money.Money, 0, len(productLines))
+		for _, idx := range productLines {
+			lineWeights = append(lineWeights, remainingLineAmount(lines[idx]))
+		}
+		for j, lineShare := range money.Allocate(share, lineWeights) {
+			discounts[productLines[j]] += lineShare
+		}
+	}
+
+	return discounts
+}
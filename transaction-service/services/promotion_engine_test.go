@@ -0,0 +1,170 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"transaction-service/models"
+	"transaction-service/money"
+	"transaction-service/quantity"
+)
+
+func promotionLine(productID uint, unitPrice money.Money, qty quantity.Quantity) models.TransactionItem {
+	return models.TransactionItem{ProductID: productID, UnitPrice: unitPrice, Quantity: qty, GrossAmount: qty.Amount(unitPrice)}
+}
+
+func percentOff(id uint, priority int, stackable bool, percent money.Percent, productIDs ...uint) models.Promotion {
+	return models.Promotion{
+		ID: id, Type: models.PromotionTypePercentOff, Priority: priority, Stackable: stackable, Active: true,
+		Rule: models.PromotionRule{DiscountPercent: percent, ProductIDs: productIDs},
+	}
+}
+
+func TestApplyPromotions(t *testing.T) {
+	// Sabtu
+	now := time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC)
+
+	buy2Get1 := models.Promotion{
+		ID: 1, Type: models.PromotionTypeBuyXGetY, Active: true,
+		Rule: models.PromotionRule{BuyQuantity: 2, GetQuantity: 1},
+	}
+	bundle := models.Promotion{
+		ID: 1, Type: models.PromotionTypeBundlePrice, Active: true,
+		Rule: models.PromotionRule{
+			BundleItems: []models.BundleItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}},
+			BundlePrice: money.New(20000),
+		},
+	}
+	inactive := percentOff(1, 0, false, 50*money.Scale)
+	inactive.Active = false
+	weekdaysOnly := percentOff(1, 0, false, 50*money.Scale)
+	weekdaysOnly.DaysOfWeek = []int{1, 2, 3, 4, 5}
+	// 10% kategori 3 (dengan subkategori 7) di akhir pekan
+	weekendCategory := percentOff(1, 0, false, 10*money.Scale)
+	weekendCategory.Rule.CategoryIDs = []uint{3}
+	weekendCategory.TargetCategoryIDs = []uint{3, 7}
+	weekendCategory.DaysOfWeek = []int{0, 6}
+	inCategory := func(line models.TransactionItem, categoryID uint) models.TransactionItem {
+		line.CategoryID = &categoryID
+		return line
+	}
+
+	tests := []struct {
+		name        string
+		promotions  []models.Promotion
+		lines       []models.TransactionItem
+		want        []money.Money
+		wantApplied [][]uint
+	}{
+		{"percent off every product",
+			[]models.Promotion{percentOff(1, 0, false, 10*money.Scale)},
+			[]models.TransactionItem{promotionLine(1, money.New(15000), quantity.New(2)), promotionLine(2, money.New(8000), quantity.New(1))},
+			[]money.Money{money.New(3000), money.New(800)}, [][]uint{{1}, {1}}},
+		{"percent off rounds each line half away from zero",
+			[]models.Promotion{percentOff(1, 0, false, 15*money.Scale)},
+			[]models.TransactionItem{promotionLine(1, 103, quantity.New(1)), promotionLine(2, 10, quantity.New(1))},
+			[]money.Money{15, 2}, [][]uint{{1}, {1}}},
+		{"percent off only targeted products",
+			[]models.Promotion{percentOff(1, 0, false, 50*money.Scale, 2)},
+			[]models.TransactionItem{promotionLine(1, money.New(10000), quantity.New(1)), promotionLine(2, money.New(10000), quantity.New(1))},
+			[]money.Money{0, money.New(5000)}, [][]uint{nil, {1}}},
+		{"buy 2 get 1 frees the cheapest unit across lines",
+			[]models.Promotion{buy2Get1},
+			[]models.TransactionItem{promotionLine(1, money.New(10000), quantity.New(2)), promotionLine(2, money.New(4000), quantity.New(1))},
+			[]money.Money{0, money.New(4000)}, [][]uint{nil, {1}}},
+		{"buy 2 get 1 twice",
+			[]models.Promotion{buy2Get1},
+			[]models.TransactionItem{promotionLine(1, money.New(10000), quantity.New(5)), promotionLine(2, money.New(6000), quantity.New(1))},
+			[]money.Money{money.New(10000), money.New(6000)}, [][]uint{{1}, {1}}},
+		{"buy 2 get 1 counts whole units of weighed goods",
+			[]models.Promotion{buy2Get1},
+			[]models.TransactionItem{promotionLine(1, money.New(10000), 2500)},
+			[]money.Money{0}, [][]uint{nil}},
+		{"bundle saving split by normal price",
+			[]models.Promotion{bundle},
+			[]models.TransactionItem{promotionLine(1, money.New(15000), quantity.New(2)), promotionLine(2, money.New(10000), quantity.New(1))},
+			[]money.Money{money.New(3000), money.New(2000)}, [][]uint{{1}, {1}}},
+		{"bundle split over lines of the same product",
+			[]models.Promotion{bundle},
+			[]models.TransactionItem{
+				promotionLine(1, money.New(15000), quantity.New(1)),
+				promotionLine(2, money.New(10000), quantity.New(1)),
+				promotionLine(1, money.New(15000), quantity.New(1)),
+				promotionLine(2, money.New(10000), quantity.New(1)),
+			},
+			[]money.Money{money.New(3000), money.New(2000), money.New(3000), money.New(2000)}, [][]uint{{1}, {1}, {1}, {1}}},
+		{"incomplete bundle",
+			[]models.Promotion{bundle},
+			[]models.TransactionItem{promotionLine(1, money.New(15000), quantity.New(3))},
+			[]money.Money{0}, [][]uint{nil}},
+		{"bundle cheaper than its price",
+			[]models.Promotion{bundle},
+			[]models.TransactionItem{promotionLine(1, money.New(9000), quantity.New(1)), promotionLine(2, money.New(9000), quantity.New(1))},
+			[]money.Money{0, 0}, [][]uint{nil, nil}},
+		{"non-stackable promotion locks its lines",
+			[]models.Promotion{percentOff(1, 10, false, 10*money.Scale), percentOff(2, 5, true, 50*money.Scale)},
+			[]models.TransactionItem{promotionLine(1, money.New(10000), quantity.New(1))},
+			[]money.Money{money.New(1000)}, [][]uint{{1}}},
+		{"stackable promotions apply to the remaining amount",
+			[]models.Promotion{percentOff(2, 5, true, 50*money.Scale), percentOff(1, 10, true, 10*money.Scale)},
+			[]models.TransactionItem{promotionLine(1, money.New(10000), quantity.New(1))},
+			[]money.Money{money.New(5500)}, [][]uint{{1, 2}}},
+		{"non-stackable promotion skips promoted lines",
+			[]models.Promotion{percentOff(1, 10, true, 10*money.Scale, 1), percentOff(2, 5, false, 20*money.Scale)},
+			[]models.TransactionItem{promotionLine(1, money.New(10000), quantity.New(1)), promotionLine(2, money.New(10000), quantity.New(1))},
+			[]money.Money{money.New(1000), money.New(2000)}, [][]uint{{1}, {2}}},
+		{"same priority evaluated by id",
+			[]models.Promotion{percentOff(2, 0, false, 10*money.Scale), percentOff(1, 0, false, 30*money.Scale)},
+			[]models.TransactionItem{promotionLine(1, money.New(10000), quantity.New(1))},
+			[]money.Money{money.New(3000)}, [][]uint{{1}}},
+		{"stacked discounts stop at the line amount",
+			[]models.Promotion{percentOff(1, 10, true, money.Hundred), percentOff(2, 5, true, 50*money.Scale)},
+			[]models.TransactionItem{promotionLine(1, money.New(10000), quantity.New(1))},
+			[]money.Money{money.New(10000)}, [][]uint{{1}}},
+		{"percent off a category and its subcategories",
+			[]models.Promotion{weekendCategory},
+			[]models.TransactionItem{
+				inCategory(promotionLine(1, money.New(10000), quantity.New(1)), 3),
+				inCategory(promotionLine(2, money.New(20000), quantity.New(1)), 7),
+				inCategory(promotionLine(3, money.New(10000), quantity.New(1)), 4),
+				promotionLine(4, money.New(10000), quantity.New(1)),
+			},
+			[]money.Money{money.New(1000), money.New(2000), 0, 0}, [][]uint{{1}, {1}, nil, nil}},
+		{"inactive promotion",
+			[]models.Promotion{inactive},
+			[]models.TransactionItem{promotionLine(1, money.New(10000), quantity.New(1))},
+			[]money.Money{0}, [][]uint{nil}},
+		{"promotion outside its days",
+			[]models.Promotion{weekdaysOnly},
+			[]models.TransactionItem{promotionLine(1, money.New(10000), quantity.New(1))},
+			[]money.Money{0}, [][]uint{nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applyPromotions(tt.promotions, tt.lines, now)
+
+			var got []money.Money
+			var applied [][]uint
+			for _, line := range tt.lines {
+				got = append(got, line.PromotionDiscountAmount)
+				var ids []uint
+				var sum money.Money
+				for _, promotion := range line.Promotions {
+					ids = append(ids, promotion.PromotionID)
+					sum += promotion.DiscountAmount
+				}
+				applied = append(applied, ids)
+				if sum != line.PromotionDiscountAmount {
+					t.Errorf("applied promotions of product %d add up to %s, want %s", line.ProductID, sum, line.PromotionDiscountAmount)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("discounts = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(applied, tt.wantApplied) {
+				t.Errorf("applied promotions = %v, want %v", applied, tt.wantApplied)
+			}
+		})
+	}
+}
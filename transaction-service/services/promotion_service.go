@@ -0,0 +1,242 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"transaction-service/dto"
+	"transaction-service/models"
+	"transaction-service/money"
+	"transaction-service/repositories"
+)
+
+type PromotionService interface {
+	CreatePromotion(req *dto.PromotionRequest) (*dto.PromotionResponse, error)
+	GetAllPromotions(activeOnly bool) ([]dto.PromotionResponse, error)
+	GetPromotionByID(id uint) (*dto.PromotionResponse, error)
+	UpdatePromotion(id uint, req *dto.PromotionRequest) (*dto.PromotionResponse, error)
+	DeletePromotion(id uint) error
+}
+
+type promotionService struct {
+	repo repositories.PromotionRepository
+}
+
+func NewPromotionService(repo repositories.PromotionRepository) PromotionService {
+	return &promotionService{repo: repo}
+}
+
+func (s *promotionService) CreatePromotion(req *dto.PromotionRequest) (*dto.PromotionResponse, error) {
+	promotion, err := s.requestToModel(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(promotion); err != nil {
+		return nil, fmt.Errorf("failed to create promotion: %w", err)
+	}
+
+	return s.modelToResponse(promotion), nil
+}
+
+func (s *promotionService) GetAllPromotions(activeOnly bool) ([]dto.PromotionResponse, error) {
+	promotions, err := s.repo.GetAll(activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.PromotionResponse, 0, len(promotions))
+	for i := range promotions {
+		responses = append(responses, *s.modelToResponse(&promotions[i]))
+	}
+
+	return responses, nil
+}
+
+func (s *promotionService) GetPromotionByID(id uint) (*dto.PromotionResponse, error) {
+	promotion, err := s.repo.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("promotion not found")
+		}
+		return nil, err
+	}
+
+	return s.modelToResponse(promotion), nil
+}
+
+func (s *promotionService) UpdatePromotion(id uint, req *dto.PromotionRequest) (*dto.PromotionResponse, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("promotion not found")
+		}
+		return nil, err
+	}
+
+	promotion, err := s.requestToModel(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(id, promotion); err != nil {
+		return nil, fmt.Errorf("failed to update promotion: %w", err)
+	}
+
+	return s.GetPromotionByID(id)
+}
+
+func (s *promotionService) DeletePromotion(id uint) error {
+	if _, err := s.repo.GetByID(id); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("promotion not found")
+		}
+		return err
+	}
+
+	return s.repo.Delete(id)
+}
+
+// requestToModel memvalidasi aturan sesuai tipe promosi dan mengubah request menjadi model
+func (s *promotionService) requestToModel(req *dto.PromotionRequest) (*models.Promotion, error) {
+	promotion := &models.Promotion{
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Type:        strings.ToUpper(strings.TrimSpace(req.Type)),
+		Priority:    req.Priority,
+		Stackable:   req.Stackable,
+		Active:      true,
+		DaysOfWeek:  req.DaysOfWeek,
+		StartTime:   strings.TrimSpace(req.StartTime),
+		EndTime:     strings.TrimSpace(req.EndTime),
+		Rule: models.PromotionRule{
+			ProductIDs:      req.Rule.ProductIDs,
+			CategoryIDs:     req.Rule.CategoryIDs,
+			BuyQuantity:     req.Rule.BuyQuantity,
+			GetQuantity:     req.Rule.GetQuantity,
+			BundlePrice:     req.Rule.BundlePrice,
+			DiscountPercent: req.Rule.DiscountPercent,
+		},
+	}
+	if req.Active != nil {
+		promotion.Active = *req.Active
+	}
+	if promotion.Name == "" {
+		return nil, errors.New("promotion name is required")
+	}
+	if !models.ValidPromotionTypes[promotion.Type] {
+		return nil, fmt.Errorf("invalid promotion type %s", req.Type)
+	}
+
+	switch promotion.Type {
+	case models.PromotionTypeBuyXGetY:
+		if req.Rule.BuyQuantity < 1 || req.Rule.GetQuantity < 1 {
+			return nil, errors.New("buy_quantity and get_quantity are required for BUY_X_GET_Y promotion")
+		}
+	case models.PromotionTypeBundlePrice:
+		if len(req.Rule.BundleItems) == 0 {
+			return nil, errors.New("bundle_items are required for BUNDLE_PRICE promotion")
+		}
+		if req.Rule.BundlePrice <= 0 {
+			return nil, errors.New("bundle_price must be greater than 0 for BUNDLE_PRICE promotion")
+		}
+		seen := make(map[uint]bool)
+		for _, item := range req.Rule.BundleItems {
+			if item.ProductID == 0 || item.Quantity <= 0 {
+				return nil, errors.New("invalid bundle item, product_id and quantity are required")
+			}
+			if seen[item.ProductID] {
+				return nil, fmt.Errorf("invalid bundle item, product %d is listed more than once", item.ProductID)
+			}
+			seen[item.ProductID] = true
+			promotion.Rule.BundleItems = append(promotion.Rule.BundleItems, models.BundleItem{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+			})
+		}
+	case models.PromotionTypePercentOff:
+		if req.Rule.DiscountPercent <= 0 || req.Rule.DiscountPercent > money.Hundred {
+			return nil, errors.New("discount_percent must be between 0 and 100 for PERCENT_OFF promotion")
+		}
+	}
+
+	if req.StartsAt != "" {
+		startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+		if err != nil {
+			return nil, errors.New("invalid starts_at, expected RFC3339 format")
+		}
+		promotion.StartsAt = &startsAt
+	}
+	if req.EndsAt != "" {
+		endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+		if err != nil {
+			return nil, errors.New("invalid ends_at, expected RFC3339 format")
+		}
+		promotion.EndsAt = &endsAt
+	}
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		return nil, errors.New("invalid period, ends_at must be after starts_at")
+	}
+
+	for _, day := range promotion.DaysOfWeek {
+		if day < 0 || day > 6 {
+			return nil, errors.New("invalid days_of_week, expected values 0 (Sunday) to 6 (Saturday)")
+		}
+	}
+
+	if (promotion.StartTime == "") != (promotion.EndTime == "") {
+		return nil, errors.New("start_time and end_time must be set together")
+	}
+	for _, clock := range []string{promotion.StartTime, promotion.EndTime} {
+		if clock == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", clock); err != nil {
+			return nil, errors.New("invalid start_time/end_time, expected format HH:MM")
+		}
+	}
+
+	return promotion, nil
+}
+
+func (s *promotionService) modelToResponse(promotion *models.Promotion) *dto.PromotionResponse {
+	rule := dto.PromotionRuleRequest{
+		ProductIDs:      promotion.Rule.ProductIDs,
+		CategoryIDs:     promotion.Rule.CategoryIDs,
+		BuyQuantity:     promotion.Rule.BuyQuantity,
+		GetQuantity:     promotion.Rule.GetQuantity,
+		BundlePrice:     promotion.Rule.BundlePrice,
+		DiscountPercent: promotion.Rule.DiscountPercent,
+	}
+	for _, item := range promotion.Rule.BundleItems {
+		rule.BundleItems = append(rule.BundleItems, dto.BundleItemRequest{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	response := &dto.PromotionResponse{
+		ID:          promotion.ID,
+		Name:        promotion.Name,
+		Description: promotion.Description,
+		Type:        promotion.Type,
+		Rule:        rule,
+		Priority:    promotion.Priority,
+		Stackable:   promotion.Stackable,
+		Active:      promotion.Active,
+		DaysOfWeek:  promotion.DaysOfWeek,
+		StartTime:   promotion.StartTime,
+		EndTime:     promotion.EndTime,
+		CreatedAt:   promotion.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   promotion.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if promotion.StartsAt != nil {
+		response.StartsAt = promotion.StartsAt.Format(time.RFC3339)
+	}
+	if promotion.EndsAt != nil {
+		response.EndsAt = promotion.EndsAt.Format(time.RFC3339)
+	}
+
+	return response
+}
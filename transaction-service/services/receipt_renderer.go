@@ -0,0 +1,396 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"transaction-service/models"
+	"transaction-service/money"
+)
+
+// format struk yang didukung
+const (
+	ReceiptFormatESCPOS = "escpos"
+	ReceiptFormatText   = "text"
+)
+
+// kode yang dicetak di bawah struk, berisi nomor transaksi
+const (
+	ReceiptCodeQR      = "QR"
+	ReceiptCodeBarcode = "BARCODE"
+	ReceiptCodeNone    = "NONE"
+)
+
+// jumlah karakter per baris (font A) untuk lebar kertas printer thermal dalam mm
+var receiptColumns = map[int]int{
+	58: 32,
+	80: 48,
+}
+
+// perintah ESC/POS
+var (
+	escInit       = []byte{0x1B, 0x40}
+	escCodePage   = []byte{0x1B, 0x74, 0x00} // PC437
+	escBoldOn     = []byte{0x1B, 0x45, 0x01}
+	escBoldOff    = []byte{0x1B, 0x45, 0x00}
+	escSizeLarge  = []byte{0x1D, 0x21, 0x11} // lebar dan tinggi ganda
+	escSizeNormal = []byte{0x1D, 0x21, 0x00}
+	escDrawerKick = []byte{0x1B, 0x70, 0x00, 0x19, 0xFA} // pulsa ke pin 2 laci kas
+	escCut        = []byte{0x1D, 0x56, 0x42, 0x00}       // feed lalu potong sebagian
+)
+
+const (
+	alignLeft byte = iota
+	alignCenter
+	alignRight
+)
+
+// StoreInfo adalah identitas toko yang dicetak di header struk
+type StoreInfo struct {
+	Name    string
+	Address string
+	Phone   string
+	TaxID   string // NPWP
+	Footer  string
+}
+
+type receiptLine struct {
+	text  string
+	align byte
+	bold  bool
+	large bool
+}
+
+// receiptDocument adalah isi struk yang sudah di-layout, dirender ke ESC/POS atau teks
+type receiptDocument struct {
+	columns    int
+	lines      []receiptLine
+	codeType   string
+	code       string
+	openDrawer bool
+}
+
+var paymentMethodLabels = map[string]string{
+	models.PaymentMethodCash:         "Tunai",
+	models.PaymentMethodDebitCard:    "Kartu Debit",
+	models.PaymentMethodCreditCard:   "Kartu Kredit",
+	models.PaymentMethodQRIS:         "QRIS",
+	models.PaymentMethodEWallet:      "E-Wallet",
+	models.PaymentMethodBankTransfer: "Transfer Bank",
+}
+
+// layoutReceipt menyusun baris struk: header toko, item, potongan, pajak, tender, kembalian dan footer
+func layoutReceipt(transaction *models.Transaction, store StoreInfo, columns int, codeType string) *receiptDocument {
+	doc := &receiptDocument{columns: columns, codeType: codeType}
+
+	if store.Name != "" {
+		doc.add(receiptLine{text: truncate(store.Name, columns/2), align: alignCenter, bold: true, large: true})
+	}
+	for _, text := range []string{store.Address, store.Phone} {
+		for _, line := range wrapText(text, columns) {
+			doc.add(receiptLine{text: line, align: alignCenter})
+		}
+	}
+	if store.TaxID != "" {
+		doc.add(receiptLine{text: "NPWP " + store.TaxID, align: alignCenter})
+	}
+	doc.separator()
+
+	doc.pair("No.", transaction.InvoiceNumber)
+	doc.pair("Tanggal", transaction.TransactionDate.Format("02/01/2006 15:04"))
+	if transaction.CustomerID != "" {
+		doc.pair("Pelanggan", transaction.CustomerID)
+	}
+	if transaction.VoidedAt != nil {
+		doc.add(receiptLine{text: "*** VOID ***", align: alignCenter, bold: true})
+		for _, line := range wrapText(transaction.VoidReason, columns) {
+			doc.add(receiptLine{text: line, align: alignCenter})
+		}
+	}
+	doc.separator()
+
+	var lineDiscounts money.Money
+	for _, item := range transaction.TransactionItems {
+		for _, line := range wrapText(item.DisplayName(), columns) {
+			doc.add(receiptLine{text: line})
+		}
+		doc.pair(fmt.Sprintf("  %s x %s", item.Quantity, formatAmount(item.UnitPrice)), formatAmount(item.GrossAmount))
+		for _, promotion := range item.Promotions {
+			doc.pair("  "+promotion.PromotionName, formatAmount(-promotion.DiscountAmount))
+		}
+		if item.LineDiscount != nil {
+			doc.pair("  "+discountLabel("Diskon", item.LineDiscount), formatAmount(-item.LineDiscount.Amount))
+			lineDiscounts += item.LineDiscount.Amount
+		}
+	}
+	doc.separator()
+
+	doc.pair("Subtotal", formatAmount(transaction.GrossAmount))
+	if transaction.PromotionDiscountAmount > 0 {
+		doc.pair("Promo", formatAmount(-transaction.PromotionDiscountAmount))
+	}
+	if lineDiscounts > 0 {
+		doc.pair("Diskon Item", formatAmount(-lineDiscounts))
+	}
+	if transaction.CartDiscount != nil {
+		doc.pair(discountLabel("Diskon", transaction.CartDiscount), formatAmount(-transaction.CartDiscount.Amount))
+	}
+	if transaction.Voucher != nil {
+		doc.pair("Voucher "+transaction.Voucher.VoucherCode, formatAmount(-transaction.Voucher.DiscountAmount))
+	}
+
+	breakdown := transaction.TaxBreakdown()
+	if !transaction.PricesIncludeTax {
+		for _, tax := range breakdown {
+			if tax.TaxAmount != 0 {
+				doc.pair("PPN "+tax.Rate.String()+"%", formatAmount(tax.TaxAmount))
+			}
+		}
+	}
+	doc.add(receiptLine{text: pairText("TOTAL", formatAmount(transaction.TotalAmount), columns), bold: true})
+
+	for _, payment := range transaction.Payments {
+		label := paymentMethodLabels[payment.Method]
+		if label == "" {
+			label = payment.Method
+		}
+		doc.pair(label, formatAmount(payment.TenderedAmount))
+		if payment.Reference != "" {
+			doc.add(receiptLine{text: truncate("  Ref: "+payment.Reference, columns)})
+		}
+	}
+	if transaction.ChangeAmount > 0 {
+		doc.pair("Kembali", formatAmount(transaction.ChangeAmount))
+	}
+
+	// harga termasuk pajak: rincian DPP dan PPN hanya informasi
+	if transaction.PricesIncludeTax {
+		for _, tax := range breakdown {
+			if tax.TaxAmount == 0 {
+				continue
+			}
+			doc.pair("DPP "+tax.Rate.String()+"%", formatAmount(tax.TaxableAmount))
+			doc.pair("Termasuk PPN "+tax.Rate.String()+"%", formatAmount(tax.TaxAmount))
+		}
+	}
+
+	if store.Footer != "" {
+		doc.separator()
+		for _, line := range wrapText(store.Footer, columns) {
+			doc.add(receiptLine{text: line, align: alignCenter})
+		}
+	}
+
+	if codeType != ReceiptCodeNone {
+		doc.code = strconv.FormatUint(uint64(transaction.ID), 10)
+	}
+
+	return doc
+}
+
+func (d *receiptDocument) add(line receiptLine) {
+	d.lines = append(d.lines, line)
+}
+
+func (d *receiptDocument) separator() {
+	d.add(receiptLine{text: strings.Repeat("-", d.columns)})
+}
+
+// pair menulis label rata kiri dan nilai rata kanan dalam satu baris
+func (d *receiptDocument) pair(label, value string) {
+	d.add(receiptLine{text: pairText(label, value, d.columns)})
+}
+
+func pairText(label, value string, columns int) string {
+	space := columns - len(value) - 1
+	if space < 1 {
+		return truncate(value, columns)
+	}
+	label = truncate(label, space)
+	return label + strings.Repeat(" ", columns-len(label)-len(value)) + value
+}
+
+// renderESCPOS menghasilkan byte stream untuk printer thermal
+func (d *receiptDocument) renderESCPOS() []byte {
+	var buf bytes.Buffer
+	buf.Write(escInit)
+	buf.Write(escCodePage)
+
+	for _, line := range d.lines {
+		buf.Write([]byte{0x1B, 0x61, line.align})
+		if line.bold {
+			buf.Write(escBoldOn)
+		}
+		if line.large {
+			buf.Write(escSizeLarge)
+		}
+		buf.WriteString(asciiOnly(line.text))
+		buf.WriteByte('\n')
+		if line.large {
+			buf.Write(escSizeNormal)
+		}
+		if line.bold {
+			buf.Write(escBoldOff)
+		}
+	}
+
+	if d.code != "" {
+		buf.Write([]byte{0x1B, 0x61, alignCenter})
+		buf.WriteByte('\n')
+		switch d.codeType {
+		case ReceiptCodeBarcode:
+			writeBarcode(&buf, d.code)
+		case ReceiptCodeQR:
+			writeQRCode(&buf, d.code)
+		}
+		buf.Write([]byte{0x1B, 0x61, alignLeft})
+	}
+
+	// feed beberapa baris agar footer tidak terpotong
+	buf.Write([]byte{0x1B, 0x64, 0x03})
+	if d.openDrawer {
+		buf.Write(escDrawerKick)
+	}
+	buf.Write(escCut)
+
+	return buf.Bytes()
+}
+
+// renderText menghasilkan preview struk dalam teks biasa, untuk testing tanpa printer
+func (d *receiptDocument) renderText() []byte {
+	var buf bytes.Buffer
+	for _, line := range d.lines {
+		buf.WriteString(alignText(line.text, line.align, d.columns))
+		buf.WriteByte('\n')
+	}
+	if d.code != "" {
+		buf.WriteByte('\n')
+		buf.WriteString(alignText("["+d.codeType+" "+d.code+"]", alignCenter, d.columns))
+		buf.WriteByte('\n')
+	}
+	if d.openDrawer {
+		buf.WriteString(alignText("[BUKA LACI]", alignCenter, d.columns))
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(strings.Repeat("=", d.columns))
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+// writeBarcode mencetak CODE128 (code set B) dengan teks di bawah barcode
+func writeBarcode(buf *bytes.Buffer, data string) {
+	buf.Write([]byte{0x1D, 0x68, 0x50}) // tinggi 80 dot
+	buf.Write([]byte{0x1D, 0x77, 0x02}) // lebar modul 2
+	buf.Write([]byte{0x1D, 0x48, 0x02}) // HRI di bawah
+	payload := "{B" + data
+	buf.Write([]byte{0x1D, 0x6B, 0x49, byte(len(payload))})
+	buf.WriteString(payload)
+	buf.WriteByte('\n')
+}
+
+// writeQRCode mencetak QR code model 2 lewat perintah GS ( k
+func writeQRCode(buf *bytes.Buffer, data string) {
+	buf.Write([]byte{0x1D, 0x28, 0x6B, 0x04, 0x00, 0x31, 0x41, 0x32, 0x00}) // model 2
+	buf.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x43, 0x06})       // ukuran modul 6
+	buf.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x45, 0x31})       // koreksi error M
+	length := len(data) + 3
+	buf.Write([]byte{0x1D, 0x28, 0x6B, byte(length % 256), byte(length / 256), 0x31, 0x50, 0x30})
+	buf.WriteString(data)
+	buf.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x51, 0x30}) // cetak
+	buf.WriteByte('\n')
+}
+
+// formatAmount menulis rupiah dengan pemisah ribuan titik, sen hanya ditulis jika ada: 10.500 atau 10.500,50
+func formatAmount(amount money.Money) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(int64(amount/money.Scale), 10)
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	if cents := amount % money.Scale; cents != 0 {
+		return fmt.Sprintf("%s%s,%02d", sign, grouped.String(), cents)
+	}
+	return sign + grouped.String()
+}
+
+func discountLabel(label string, discount *models.Discount) string {
+	if discount.Type == models.DiscountTypePercentage {
+		return label + " " + money.Percent(discount.Value).String() + "%"
+	}
+	return label
+}
+
+func alignText(text string, align byte, columns int) string {
+	padding := columns - len(text)
+	if padding <= 0 {
+		return text
+	}
+	switch align {
+	case alignCenter:
+		return strings.Repeat(" ", padding/2) + text
+	case alignRight:
+		return strings.Repeat(" ", padding) + text
+	}
+	return text
+}
+
+// wrapText memecah teks per kata agar muat dalam columns karakter
+func wrapText(text string, columns int) []string {
+	var lines []string
+	var current string
+	for _, word := range strings.Fields(asciiOnly(text)) {
+		for len(word) > columns {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			lines = append(lines, word[:columns])
+			word = word[columns:]
+		}
+		switch {
+		case current == "":
+			current = word
+		case len(current)+1+len(word) <= columns:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+func truncate(text string, columns int) string {
+	text = asciiOnly(text)
+	if len(text) > columns {
+		return text[:columns]
+	}
+	return text
+}
+
+// asciiOnly mengganti karakter di luar ASCII karena code page printer tidak mendukung UTF-8
+func asciiOnly(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if r < 0x20 || r > 0x7E {
+			return '?'
+		}
+		return r
+	}, text)
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"transaction-service/dto"
+	"transaction-service/repositories"
+)
+
+// ReceiptOptions berisi identitas toko dan default struk dari environment
+type ReceiptOptions struct {
+	Store StoreInfo
+	// lebar kertas default dalam mm (58 atau 80)
+	DefaultWidth int
+	// kode default di bawah struk: QR, BARCODE atau NONE
+	DefaultCode string
+}
+
+type ReceiptService interface {
+	RenderReceipt(transactionID uint, req *dto.ReceiptRequest) (*dto.Document, error)
+}
+
+type receiptService struct {
+	repo    repositories.TransactionRepository
+	options ReceiptOptions
+}
+
+func NewReceiptService(repo repositories.TransactionRepository, options ReceiptOptions) ReceiptService {
+	return &receiptService{repo: repo, options: options}
+}
+
+func (s *receiptService) RenderReceipt(transactionID uint, req *dto.ReceiptRequest) (*dto.Document, error) {
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	if format == "" {
+		format = ReceiptFormatESCPOS
+	}
+	if format != ReceiptFormatESCPOS && format != ReceiptFormatText {
+		return nil, fmt.Errorf("invalid receipt format '%s', must be %s or %s", req.Format, ReceiptFormatESCPOS, ReceiptFormatText)
+	}
+
+	width := req.Width
+	if width == 0 {
+		width = s.options.DefaultWidth
+	}
+	columns, exists := receiptColumns[width]
+	if !exists {
+		return nil, fmt.Errorf("invalid receipt width %d, must be 58 or 80", width)
+	}
+
+	codeType := strings.ToUpper(strings.TrimSpace(req.Code))
+	if codeType == "" {
+		codeType = s.options.DefaultCode
+	}
+	if codeType != ReceiptCodeQR && codeType != ReceiptCodeBarcode && codeType != ReceiptCodeNone {
+		return nil, fmt.Errorf("invalid receipt code '%s', must be %s, %s or %s", req.Code, ReceiptCodeQR, ReceiptCodeBarcode, ReceiptCodeNone)
+	}
+
+	transaction, err := s.repo.GetByID(transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, err
+	}
+
+	doc := layoutReceipt(transaction, s.options.Store, columns, codeType)
+	doc.openDrawer = req.OpenDrawer
+
+	receipt := &dto.Document{
+		FileName: fmt.Sprintf("receipt-%d", transaction.ID),
+	}
+	if format == ReceiptFormatText {
+		receipt.ContentType = "text/plain; charset=utf-8"
+		receipt.FileName += ".txt"
+		receipt.Content = doc.renderText()
+	} else {
+		receipt.ContentType = "application/octet-stream"
+		receipt.FileName += ".bin"
+		receipt.Content = doc.renderESCPOS()
+	}
+
+	return receipt, nil
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"transaction-service/dto"
+	"transaction-service/models"
+	"transaction-service/money"
 	"transaction-service/repositories"
 )
 
@@ -10,6 +12,9 @@ type ReportingService interface {
 	GetProductSalesReport(filter dto.ReportingFilterDTO) ([]dto.ProductSalesReportDTO, error)
 	GetLowStockAlert() ([]dto.LowStockAlertDTO, error)
 	GetDashboardSummary() (map[string]interface{}, error)
+	GetPaymentMethodSummary(filter dto.ReportingFilterDTO) ([]dto.PaymentMethodSummaryDTO, error)
+	GetTaxSummary(filter dto.ReportingFilterDTO) ([]dto.TaxSummaryDTO, error)
+	GetCategorySalesReport(filter dto.ReportingFilterDTO) ([]dto.CategorySalesReportDTO, error)
 }
 
 type reportingService struct {
@@ -42,14 +47,47 @@ func (s *reportingService) GetLowStockAlert() ([]dto.LowStockAlertDTO, error) {
 	return s.reportingRepo.GetLowStockAlert()
 }
 
+func (s *reportingService) GetPaymentMethodSummary(filter dto.ReportingFilterDTO) ([]dto.PaymentMethodSummaryDTO, error) {
+	return s.reportingRepo.GetPaymentMethodSummary(filter)
+}
+
+func (s *reportingService) GetTaxSummary(filter dto.ReportingFilterDTO) ([]dto.TaxSummaryDTO, error) {
+	summaries, err := s.reportingRepo.GetTaxSummary(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range summaries {
+		summaries[i].NetTaxableAmount = summaries[i].TaxableAmount - summaries[i].RefundedTaxableAmount
+		summaries[i].NetTaxAmount = summaries[i].TaxAmount - summaries[i].RefundedTaxAmount
+	}
+
+	return summaries, nil
+}
+
+func (s *reportingService) GetCategorySalesReport(filter dto.ReportingFilterDTO) ([]dto.CategorySalesReportDTO, error) {
+	return s.reportingRepo.GetCategorySalesReport(filter)
+}
+
 func (s *reportingService) GetDashboardSummary() (map[string]interface{}, error) {
-	// mendapatkan 10 transaksi terbaru
-	recentFilter := dto.ReportingFilterDTO{Limit: 10}
+	// mendapatkan 10 transaksi terbaru, baris retur tidak termasuk
+	recentFilter := dto.ReportingFilterDTO{Limit: 10, Entry: dto.SummaryEntrySale}
 	recentTransactions, err := s.reportingRepo.GetTransactionSummary(recentFilter)
 	if err != nil {
 		return nil, err
 	}
 
+	// retur sejak transaksi terlama di daftar terbaru dihitung terpisah
+	var returns []dto.TransactionSummaryDTO
+	if len(recentTransactions) > 0 {
+		since := recentTransactions[len(recentTransactions)-1].TransactionDate
+		returnFilter := dto.ReportingFilterDTO{StartDate: &since, Entry: dto.SummaryEntryReturn}
+		returns, err = s.reportingRepo.GetTransactionSummary(returnFilter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// mendapatkan 5 produk teratas
 	topProductsFilter := dto.ReportingFilterDTO{Limit: 5}
 	topProducts, err := s.reportingRepo.GetProductSalesReport(topProductsFilter)
@@ -63,17 +101,36 @@ func (s *reportingService) GetDashboardSummary() (map[string]interface{}, error)
 		return nil, err
 	}
 
-	// hitung total transaksi dan total revenue
-	var totalRevenue float64
-	var totalTransactions int
+	// hitung total transaksi dan total revenue, refund dari baris retur dihitung sebagai revenue negatif
+	// dan transaksi void dihitung terpisah
+	var totalRevenue, totalDiscounts, totalTax, totalRefunds, voidedAmount money.Money
+	var totalTransactions, totalReturns, voidedTransactions int
 	for _, transaction := range recentTransactions {
+		if transaction.Status == models.TransactionStatusVoided {
+			voidedAmount += transaction.TotalAmount
+			voidedTransactions++
+			continue
+		}
 		totalRevenue += transaction.TotalAmount
+		totalDiscounts += transaction.DiscountAmount
+		totalTax += transaction.TaxAmount
 		totalTransactions++
 	}
+	for _, ret := range returns {
+		totalRefunds += ret.RefundedAmount
+		totalReturns++
+	}
 
 	dashboard := map[string]interface{}{
 		"total_transactions":  totalTransactions,
 		"total_revenue":       totalRevenue,
+		"total_discounts":     totalDiscounts,
+		"total_tax":           totalTax,
+		"total_returns":       totalReturns,
+		"total_refunds":       totalRefunds,
+		"net_revenue":         totalRevenue - totalRefunds,
+		"voided_transactions": voidedTransactions,
+		"voided_amount":       voidedAmount,
 		"recent_transactions": recentTransactions,
 		"top_products":        topProducts,
 		"low_stock_alerts":    lowStockAlerts,
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"transaction-service/dto"
+	"transaction-service/models"
+	"transaction-service/money"
+	"transaction-service/repositories"
+)
+
+var taxClassPattern = regexp.MustCompile(`^[A-Z0-9_]{1,30}$`)
+
+type TaxRateService interface {
+	GetAllTaxRates() ([]dto.TaxRateResponse, error)
+	SetTaxRate(taxClass string, req *dto.TaxRateRequest) (*dto.TaxRateResponse, error)
+}
+
+type taxRateService struct {
+	repo repositories.TaxRateRepository
+}
+
+func NewTaxRateService(repo repositories.TaxRateRepository) TaxRateService {
+	return &taxRateService{repo: repo}
+}
+
+func (s *taxRateService) GetAllTaxRates() ([]dto.TaxRateResponse, error) {
+	rates, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TaxRateResponse, 0, len(rates))
+	for i := range rates {
+		responses = append(responses, *s.modelToResponse(&rates[i]))
+	}
+
+	return responses, nil
+}
+
+func (s *taxRateService) SetTaxRate(taxClass string, req *dto.TaxRateRequest) (*dto.TaxRateResponse, error) {
+	taxClass = strings.ToUpper(strings.TrimSpace(taxClass))
+	if !taxClassPattern.MatchString(taxClass) {
+		return nil, errors.New("invalid tax class, use letters, digits and underscores only")
+	}
+	if req.Rate < 0 || req.Rate > money.Hundred {
+		return nil, errors.New("invalid rate, must be between 0 and 100")
+	}
+
+	rate := &models.TaxRate{
+		TaxClass: taxClass,
+		Name:     strings.TrimSpace(req.Name),
+		Rate:     req.Rate,
+		Active:   true,
+	}
+	if req.Active != nil {
+		rate.Active = *req.Active
+	}
+
+	if err := s.repo.Upsert(rate); err != nil {
+		return nil, fmt.Errorf("failed to save tax rate: %w", err)
+	}
+
+	return s.modelToResponse(rate), nil
+}
+
+func (s *taxRateService) modelToResponse(rate *models.TaxRate) *dto.TaxRateResponse {
+	return &dto.TaxRateResponse{
+		TaxClass:  rate.TaxClass,
+		Name:      rate.Name,
+		Rate:      rate.Rate,
+		Active:    rate.Active,
+		UpdatedAt: rate.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
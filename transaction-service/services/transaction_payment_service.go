@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"transaction-service/dto"
+	"transaction-service/models"
+	"transaction-service/money"
+)
+
+// allocatePayments memvalidasi tender terhadap total transaksi dan menghitung kembalian.
+// Hanya CASH yang boleh melebihi sisa tagihan; kembalian diambil dari tender CASH terakhir.
+func allocatePayments(totalAmount money.Money, requests []dto.PaymentRequest) ([]models.TransactionPayment, money.Money, money.Money, error) {
+	if len(requests) == 0 {
+		return nil, 0, 0, fmt.Errorf("at least one payment is required")
+	}
+
+	payments := make([]models.TransactionPayment, 0, len(requests))
+	var paidAmount, cashAmount, nonCashAmount money.Money
+	for _, req := range requests {
+		method := strings.ToUpper(strings.TrimSpace(req.Method))
+		if !models.ValidPaymentMethods[method] {
+			return nil, 0, 0, fmt.Errorf("invalid payment method '%s'", req.Method)
+		}
+
+		amount := req.Amount
+		if amount <= 0 {
+			return nil, 0, 0, fmt.Errorf("payment amount must be greater than 0")
+		}
+
+		if method == models.PaymentMethodCash {
+			cashAmount += amount
+		} else {
+			nonCashAmount += amount
+		}
+		paidAmount += amount
+
+		payments = append(payments, models.TransactionPayment{
+			Method:         method,
+			TenderedAmount: amount,
+			Amount:         amount,
+			Reference:      strings.TrimSpace(req.Reference),
+		})
+	}
+
+	if nonCashAmount > totalAmount {
+		return nil, 0, 0, fmt.Errorf("non-cash payments (%s) exceed the total amount (%s)", nonCashAmount, totalAmount)
+	}
+	if paidAmount < totalAmount {
+		return nil, 0, 0, fmt.Errorf("insufficient payment: total amount %s, paid %s", totalAmount, paidAmount)
+	}
+
+	changeAmount := paidAmount - totalAmount
+
+	// kurangi kembalian dari tender cash, mulai dari yang terakhir
+	remainingChange := changeAmount
+	for i := len(payments) - 1; i >= 0 && remainingChange > 0; i-- {
+		if payments[i].Method != models.PaymentMethodCash {
+			continue
+		}
+		deduct := payments[i].Amount
+		if deduct > remainingChange {
+			deduct = remainingChange
+		}
+		payments[i].Amount -= deduct
+		remainingChange -= deduct
+	}
+
+	return payments, paidAmount, changeAmount, nil
+}
+
+func paymentsToResponse(payments []models.TransactionPayment) []dto.PaymentResponse {
+	responses := make([]dto.PaymentResponse, 0, len(payments))
+	for _, payment := range payments {
+		responses = append(responses, dto.PaymentResponse{
+			ID:             payment.ID,
+			Method:         payment.Method,
+			TenderedAmount: payment.TenderedAmount,
+			Amount:         payment.Amount,
+			Reference:      payment.Reference,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"transaction-service/dto"
+	"transaction-service/money"
+)
+
+func TestAllocatePayments(t *testing.T) {
+	tender := func(method string, rupiah int64) dto.PaymentRequest {
+		return dto.PaymentRequest{Method: method, Amount: money.New(rupiah)}
+	}
+
+	tests := []struct {
+		name       string
+		total      money.Money
+		requests   []dto.PaymentRequest
+		wantAmount []money.Money
+		wantPaid   money.Money
+		wantChange money.Money
+		wantErr    string
+	}{
+		{"exact cash", money.New(50000),
+			[]dto.PaymentRequest{tender("CASH", 50000)},
+			[]money.Money{money.New(50000)}, money.New(50000), 0, ""},
+		{"cash with change", money.New(47500),
+			[]dto.PaymentRequest{tender("CASH", 50000)},
+			[]money.Money{money.New(47500)}, money.New(50000), money.New(2500), ""},
+		{"method is normalized", money.New(10000),
+			[]dto.PaymentRequest{tender(" qris ", 10000)},
+			[]money.Money{money.New(10000)}, money.New(10000), 0, ""},
+		{"split card and cash", money.New(120000),
+			[]dto.PaymentRequest{tender("DEBIT_CARD", 100000), tender("CASH", 50000)},
+			[]money.Money{money.New(100000), money.New(20000)}, money.New(150000), money.New(30000), ""},
+		{"change taken from the last cash tender", money.New(70000),
+			[]dto.PaymentRequest{tender("CASH", 50000), tender("QRIS", 10000), tender("CASH", 20000)},
+			[]money.Money{money.New(50000), money.New(10000), money.New(10000)}, money.New(80000), money.New(10000), ""},
+		{"change spans several cash tenders", money.New(60000),
+			[]dto.PaymentRequest{tender("CASH", 50000), tender("CASH", 20000)},
+			[]money.Money{money.New(50000), money.New(10000)}, money.New(70000), money.New(10000), ""},
+		{"change larger than the last cash tender", money.New(55000),
+			[]dto.PaymentRequest{tender("E_WALLET", 5000), tender("CASH", 50000), tender("CASH", 10000)},
+			[]money.Money{money.New(5000), money.New(50000), 0}, money.New(65000), money.New(10000), ""},
+		{"split non-cash tenders", money.New(75000),
+			[]dto.PaymentRequest{tender("CREDIT_CARD", 50000), tender("BANK_TRANSFER", 25000)},
+			[]money.Money{money.New(50000), money.New(25000)}, money.New(75000), 0, ""},
+		{"no payment", money.New(10000), nil, nil, 0, 0, "at least one payment is required"},
+		{"unknown method", money.New(10000),
+			[]dto.PaymentRequest{tender("VOUCHER", 10000)},
+			nil, 0, 0, "invalid payment method"},
+		{"zero tender", money.New(10000),
+			[]dto.PaymentRequest{tender("CASH", 10000), tender("QRIS", 0)},
+			nil, 0, 0, "payment amount must be greater than 0"},
+		{"non-cash overpayment", money.New(10000),
+			[]dto.PaymentRequest{tender("CASH", 5000), tender("DEBIT_CARD", 15000)},
+			nil, 0, 0, "non-cash payments"},
+		{"insufficient split payment", money.New(100000),
+			[]dto.PaymentRequest{tender("QRIS", 40000), tender("CASH", 50000)},
+			nil, 0, 0, "insufficient payment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payments, paid, change, err := allocatePayments(tt.total, tt.requests)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var amounts []money.Money
+			var used, tendered money.Money
+			for i, payment := range payments {
+				amounts = append(amounts, payment.Amount)
+				used += payment.Amount
+				tendered += payment.TenderedAmount
+				if payment.TenderedAmount != tt.requests[i].Amount {
+					t.Errorf("payment %d tendered %s, want %s", i, payment.TenderedAmount, tt.requests[i].Amount)
+				}
+			}
+			if !reflect.DeepEqual(amounts, tt.wantAmount) {
+				t.Errorf("amounts = %v, want %v", amounts, tt.wantAmount)
+			}
+			if paid != tt.wantPaid || change != tt.wantChange {
+				t.Errorf("paid, change = %s, %s, want %s, %s", paid, change, tt.wantPaid, tt.wantChange)
+			}
+			if used != tt.total || tendered != paid {
+				t.Errorf("payments use %s of %s tendered, want %s of %s", used, tendered, tt.total, paid)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"transaction-service/clients"
+	"transaction-service/dto"
+	"transaction-service/models"
+	"transaction-service/money"
+	"transaction-service/quantity"
+)
+
+func (s *transactionService) CreateReturn(transactionID uint, req *dto.CreateReturnRequest) (*dto.ReturnResponse, error) {
+	reasonCode := strings.ToUpper(strings.TrimSpace(req.ReasonCode))
+	if !models.ValidReturnReasons[reasonCode] {
+		return nil, fmt.Errorf("invalid reason code '%s'", req.ReasonCode)
+	}
+	if reasonCode == models.ReturnReasonOther && strings.TrimSpace(req.ReasonNote) == "" {
+		return nil, errors.New("reason note is required for reason code OTHER")
+	}
+
+	transaction, err := s.repo.GetByID(transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, err
+	}
+	if transaction.VoidedAt != nil {
+		return nil, errors.New("transaction has been voided and cannot be returned")
+	}
+	if transaction.StockPendingAt != nil {
+		return nil, errors.New("transaction stock confirmation is still pending, try again later")
+	}
+
+	returned, err := s.repo.GetReturnedQuantities(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	soldItems := make(map[uint]models.TransactionItem, len(transaction.TransactionItems))
+	for _, item := range transaction.TransactionItems {
+		soldItems[item.ID] = item
+	}
+
+	// gabungkan baris dengan transaction_item_id yang sama, urutan tetap mengikuti request
+	requested := make(map[uint]quantity.Quantity)
+	var order []uint
+	if len(req.Items) == 0 {
+		// retur penuh: semua sisa item
+		for _, item := range transaction.TransactionItems {
+			if remaining := item.Quantity - returned[item.ID]; remaining > 0 {
+				requested[item.ID] = remaining
+				order = append(order, item.ID)
+			}
+		}
+		if len(order) == 0 {
+			return nil, errors.New("transaction has already been fully returned")
+		}
+	} else {
+		for _, item := range req.Items {
+			if item.Quantity <= 0 {
+				return nil, errors.New("return quantity must be greater than 0")
+			}
+			if _, exists := requested[item.TransactionItemID]; !exists {
+				order = append(order, item.TransactionItemID)
+			}
+			requested[item.TransactionItemID] += item.Quantity
+		}
+	}
+
+	now := time.Now()
+	ret := &models.TransactionReturn{
+		TransactionID:    transactionID,
+		ReasonCode:       reasonCode,
+		ReasonNote:       strings.TrimSpace(req.ReasonNote),
+		ReturnDate:       now,
+		RestockPendingAt: &now,
+	}
+
+	for _, itemID := range order {
+		sold, exists := soldItems[itemID]
+		if !exists {
+			return nil, fmt.Errorf("transaction item %d not found in transaction %d", itemID, transactionID)
+		}
+
+		returnQuantity := requested[itemID]
+		// sama seperti penjualan, hanya baris dari label timbangan yang boleh diretur dalam pecahan
+		if !sold.SoldByWeight && !returnQuantity.IsWhole() {
+			return nil, fmt.Errorf("return quantity %s of '%s' must be a whole number, only items sold by weight can be returned in fractions",
+				returnQuantity, sold.DisplayName())
+		}
+		remaining := sold.Quantity - returned[itemID]
+		if returnQuantity > remaining {
+			return nil, fmt.Errorf("cannot return %s of '%s', only %s remaining", returnQuantity, sold.DisplayName(), remaining)
+		}
+
+		// nilai refund (termasuk pajak) proporsional terhadap jumlah yang dibayar untuk baris ini,
+		// dihitung kumulatif sehingga retur terakhir mengembalikan tepat sisa nilai baris
+		amount := proportionalRefund(sold.TotalAmount, returned[itemID], returnQuantity, sold.Quantity)
+		taxAmount := proportionalRefund(sold.TaxAmount, returned[itemID], returnQuantity, sold.Quantity)
+
+		ret.Items = append(ret.Items, models.TransactionReturnItem{
+			TransactionItemID: sold.ID,
+			ProductID:         sold.ProductID,
+			VariantID:         sold.VariantID,
+			ProductName:       sold.ProductName,
+			UnitPrice:         sold.UnitPrice,
+			Quantity:          returnQuantity,
+			Amount:            amount,
+			TaxAmount:         taxAmount,
+		})
+		ret.TotalAmount += amount
+	}
+
+	// retur disimpan lebih dulu agar reference restock memakai id retur: restock yang diulang untuk retur yang
+	// sama tidak menambah stok dua kali. Retur tersimpan dengan tanda restock pending, sehingga restock yang
+	// gagal atau hasilnya tidak diketahui diulang oleh rekonsiliasi, lihat ReconcileStock.
+	if err := s.repo.CreateReturn(ret); err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "exceeds remaining") ||
+			strings.Contains(err.Error(), "voided") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create return: %w", err)
+	}
+
+	if err := s.restockReturn(ret); err != nil {
+		log.Printf("Warning: restock of return %d is pending reconciliation: %v", ret.ID, err)
+	}
+
+	return s.returnToResponse(ret), nil
+}
+
+// restockReturn mengembalikan stok retur ke product-service lalu menghapus tanda pending-nya
+func (s *transactionService) restockReturn(ret *models.TransactionReturn) error {
+	items := make([]clients.StockItem, 0, len(ret.Items))
+	for _, item := range ret.Items {
+		stockItem := clients.StockItem{ProductID: item.ProductID, Quantity: item.Quantity}
+		// stok varian yang dijual ikut dikembalikan ke varian tersebut
+		if item.VariantID != nil {
+			stockItem.VariantID = *item.VariantID
+		}
+		items = append(items, stockItem)
+	}
+
+	if _, err := s.restock(returnStockReference(ret), "RETURN", items); err != nil {
+		return err
+	}
+	if err := s.repo.ClearReturnRestockPending(ret.ID); err != nil {
+		return err
+	}
+	ret.RestockPendingAt = nil
+	return nil
+}
+
+func returnStockReference(ret *models.TransactionReturn) string {
+	return fmt.Sprintf("transaction:%d:return:%d", ret.TransactionID, ret.ID)
+}
+
+func (s *transactionService) GetReturns(transactionID uint) ([]dto.ReturnResponse, error) {
+	if _, err := s.repo.GetByID(transactionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, err
+	}
+
+	returns, err := s.repo.GetReturnsByTransactionID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ReturnResponse, 0, len(returns))
+	for i := range returns {
+		responses = append(responses, *s.returnToResponse(&returns[i]))
+	}
+
+	return responses, nil
+}
+
+func (s *transactionService) returnToResponse(ret *models.TransactionReturn) *dto.ReturnResponse {
+	items := make([]dto.ReturnItemResponse, 0, len(ret.Items))
+	for _, item := range ret.Items {
+		items = append(items, dto.ReturnItemResponse{
+			ID:                item.ID,
+			TransactionItemID: item.TransactionItemID,
+			ProductID:         item.ProductID,
+			ProductName:       item.ProductName,
+			UnitPrice:         item.UnitPrice,
+			Quantity:          item.Quantity,
+			Amount:            item.Amount,
+			TaxAmount:         item.TaxAmount,
+		})
+	}
+
+	return &dto.ReturnResponse{
+		ID:             ret.ID,
+		TransactionID:  ret.TransactionID,
+		ReasonCode:     ret.ReasonCode,
+		ReasonNote:     ret.ReasonNote,
+		TotalAmount:    ret.TotalAmount,
+		ReturnDate:     ret.ReturnDate.Format("2006-01-02 15:04:05"),
+		Items:          items,
+		RestockPending: ret.RestockPendingAt != nil,
+	}
+}
+
+// proportionalRefund menghitung bagian amount untuk returnQuantity setelah alreadyReturned
+// diretur sebelumnya: bagian kumulatif sesudah dikurangi bagian kumulatif sebelum retur ini
+func proportionalRefund(amount money.Money, alreadyReturned, returnQuantity, soldQuantity quantity.Quantity) money.Money {
+	before := amount.MulDiv(int64(alreadyReturned), int64(soldQuantity))
+	after := amount.MulDiv(int64(alreadyReturned+returnQuantity), int64(soldQuantity))
+	return after - before
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"transaction-service/money"
+	"transaction-service/quantity"
+)
+
+func TestProportionalRefund(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  money.Money
+		sold    quantity.Quantity
+		returns []quantity.Quantity
+		want    []money.Money
+	}{
+		{"full return", money.New(30000), quantity.New(3),
+			[]quantity.Quantity{quantity.New(3)}, []money.Money{money.New(30000)}},
+		{"single partial return", money.New(30000), quantity.New(3),
+			[]quantity.Quantity{quantity.New(1)}, []money.Money{money.New(10000)}},
+		{"partial returns round to the cumulative share", 1000, quantity.New(3),
+			[]quantity.Quantity{quantity.New(1), quantity.New(1), quantity.New(1)}, []money.Money{333, 334, 333}},
+		{"uneven partial returns", 1001, quantity.New(7),
+			[]quantity.Quantity{quantity.New(2), quantity.New(4), quantity.New(1)}, []money.Money{286, 572, 143}},
+		{"weighed goods", money.New(25000), 1250,
+			[]quantity.Quantity{500, 750}, []money.Money{money.New(10000), money.New(15000)}},
+		{"line fully discounted", 0, quantity.New(2),
+			[]quantity.Quantity{quantity.New(1), quantity.New(1)}, []money.Money{0, 0}},
+		{"half cents round away from zero", 5, quantity.New(2),
+			[]quantity.Quantity{quantity.New(1), quantity.New(1)}, []money.Money{3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []money.Money
+			var returned quantity.Quantity
+			var refunded money.Money
+			for _, qty := range tt.returns {
+				refund := proportionalRefund(tt.amount, returned, qty, tt.sold)
+				got = append(got, refund)
+				returned += qty
+				refunded += refund
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("refunds = %v, want %v", got, tt.want)
+			}
+			if returned == tt.sold && refunded != tt.amount {
+				t.Errorf("fully returned line refunded %s, want %s", refunded, tt.amount)
+			}
+		})
+	}
+}
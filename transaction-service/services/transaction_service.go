@@ -4,90 +4,156 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
 	"time"
 	"transaction-service/clients"
 	"transaction-service/dto"
 	"transaction-service/models"
+	"transaction-service/money"
+	"transaction-service/numbering"
 	"transaction-service/repositories"
 )
 
 type TransactionService interface {
-	CreateTransaction(req *dto.CreateTransactionRequest) (*dto.TransactionResponse, error)
+	CreateTransaction(req *dto.CreateTransactionRequest, operator dto.Operator) (*dto.TransactionResponse, error)
+	// CreateCartTransaction seperti CreateTransaction untuk checkout keranjang: reservasi stok keranjang
+	// (CartStockReference) yang dikonfirmasi, dan tetap ditahan untuk keranjang jika transaksi gagal disimpan
+	CreateCartTransaction(req *dto.CreateTransactionRequest, operator dto.Operator, cartID uint) (*dto.TransactionResponse, error)
+	PreviewPricing(req *dto.PricingRequest, operator dto.Operator) (*dto.PricingPreviewResponse, error)
+	// PreviewCartPricing seperti PreviewPricing, tetapi stok kurang, produk tidak tersedia, diskon dan
+	// voucher yang bermasalah dikembalikan sebagai warnings, bukan error
+	PreviewCartPricing(req *dto.PricingRequest, operator dto.Operator) (*dto.PricingPreviewResponse, error)
 	GetAllTransactions(page, limit int, search, sortBy, order string) ([]dto.TransactionResponse, int, error)
 	GetTransactionByID(id uint) (*dto.TransactionResponse, error)
+	CreateReturn(transactionID uint, req *dto.CreateReturnRequest) (*dto.ReturnResponse, error)
+	GetReturns(transactionID uint) ([]dto.ReturnResponse, error)
+	VoidTransaction(id uint, voidedBy string, req *dto.VoidTransactionRequest) (*dto.TransactionResponse, error)
+	// ReconcileStock mengulang konfirmasi stok yang hasilnya belum pasti, StartStockReconciler menjalankannya berkala
+	ReconcileStock() (int, error)
+	StartStockReconciler(interval time.Duration)
+}
+
+// TransactionOptions berisi aturan bisnis yang bisa dikonfigurasi lewat environment
+type TransactionOptions struct {
+	// batas waktu sejak transaksi dibuat sampai masih boleh di-void
+	VoidWindow time.Duration
+	// diskon maksimum (persen dari gross baris) per role kasir, role yang tidak terdaftar tidak boleh memberi diskon
+	MaxDiscountPercent map[string]money.Percent
+	// true jika header role hanya bisa diisi API gateway. Selama false, role dari request diabaikan dan diskon
+	// dibatasi seperti role DefaultOperatorRole
+	TrustRoleHeader bool
+	// true jika harga produk sudah termasuk pajak (PPN diekstrak dari harga), false jika pajak ditambahkan
+	PricesIncludeTax bool
+	// pola nomor invoice, lihat package numbering
+	InvoiceNumberPattern numbering.Pattern
 }
 
 type transactionService struct {
-	repo          repositories.TransactionRepository
-	productClient clients.ProductClient
+	repo            repositories.TransactionRepository
+	productClient   clients.ProductClient
+	businessDayRepo repositories.BusinessDayRepository
+	promotionRepo   repositories.PromotionRepository
+	voucherRepo     repositories.VoucherRepository
+	taxRateRepo     repositories.TaxRateRepository
+	options         TransactionOptions
 }
 
-func NewTransactionService(repo repositories.TransactionRepository, productClient clients.ProductClient) TransactionService {
+func NewTransactionService(repo repositories.TransactionRepository, productClient clients.ProductClient, businessDayRepo repositories.BusinessDayRepository, promotionRepo repositories.PromotionRepository, voucherRepo repositories.VoucherRepository, taxRateRepo repositories.TaxRateRepository, options TransactionOptions) TransactionService {
 	return &transactionService{
-		repo:          repo,
-		productClient: productClient,
+		repo:            repo,
+		productClient:   productClient,
+		businessDayRepo: businessDayRepo,
+		promotionRepo:   promotionRepo,
+		voucherRepo:     voucherRepo,
+		taxRateRepo:     taxRateRepo,
+		options:         options,
 	}
 }
 
-func (s *transactionService) CreateTransaction(req *dto.CreateTransactionRequest) (*dto.TransactionResponse, error) {
-	if len(req.Items) == 0 {
-		return nil, errors.New("transaction must have at least one item")
+func (s *transactionService) CreateTransaction(req *dto.CreateTransactionRequest, operator dto.Operator) (*dto.TransactionResponse, error) {
+	return s.createTransaction(req, operator, nil)
+}
+
+func (s *transactionService) CreateCartTransaction(req *dto.CreateTransactionRequest, operator dto.Operator, cartID uint) (*dto.TransactionResponse, error) {
+	return s.createTransaction(req, operator, &cartID)
+}
+
+// createTransaction menyimpan penjualan. Untuk checkout keranjang (cartID tidak nil) stok ditahan di bawah
+// reference keranjang dan keranjangnya ditandai CHECKED_OUT bersama transaksinya.
+func (s *transactionService) createTransaction(req *dto.CreateTransactionRequest, operator dto.Operator, cartID *uint) (*dto.TransactionResponse, error) {
+	stockReference := ""
+	if cartID != nil {
+		stockReference = CartStockReference(*cartID)
 	}
 
-	// Validate request items
-	for _, item := range req.Items {
-		if item.ProductID == 0 {
-			return nil, errors.New("product ID is required")
-		}
-		if item.Quantity <= 0 {
-			return nil, errors.New("quantity must be greater than 0")
-		}
+	transaction, err := s.priceBasket(&req.PricingRequest, operator, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	transaction := &models.Transaction{
-		TransactionDate: time.Now(),
-		TotalAmount:     0,
+	payments, paidAmount, changeAmount, err := allocatePayments(transaction.TotalAmount, req.Payments)
+	if err != nil {
+		return nil, err
 	}
+	transaction.Payments = payments
+	transaction.PaidAmount = paidAmount
+	transaction.ChangeAmount = changeAmount
 
-	// Process each item and calculate total
-	var totalAmount float64
-	for _, item := range req.Items {
-		// Get product details from product service
-		product, err := s.productClient.GetByID(item.ProductID)
-		if err != nil {
-			return nil, fmt.Errorf("product with ID %d not found or service unavailable", item.ProductID)
-		}
+	// stok ditahan dulu di product-service, baru dikurangi setelah transaksi tersimpan
+	if err := s.reserveStock(transaction, stockReference); err != nil {
+		return nil, err
+	}
 
-		// Check stock availability
-		if product.Stock < item.Quantity {
-			return nil, fmt.Errorf("insufficient stock for product '%s'. Available: %d, Requested: %d", 
-				product.Name, product.Stock, item.Quantity)
+	// Save transaction
+	transaction.CartID = cartID
+	if err := s.repo.Create(transaction, s.options.InvoiceNumberPattern); err != nil {
+		// reservasi keranjang tetap menahan barangnya selama keranjang masih bisa di-checkout ulang
+		if stockReference == "" {
+			s.releaseStock(transaction)
 		}
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
 
-		// Calculate subtotal
-		subtotal := product.Price * float64(item.Quantity)
+	if err := s.confirmStock(transaction); err != nil {
+		return nil, err
+	}
 
-		// Create transaction item
-		transactionItem := models.TransactionItem{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Subtotal:  subtotal,
-		}
+	return s.modelToResponse(transaction), nil
+}
 
-		transaction.TransactionItems = append(transaction.TransactionItems, transactionItem)
-		totalAmount += subtotal
+// PreviewPricing menghitung keranjang (promosi, diskon, total) tanpa menyimpan transaksi
+func (s *transactionService) PreviewPricing(req *dto.PricingRequest, operator dto.Operator) (*dto.PricingPreviewResponse, error) {
+	transaction, err := s.priceBasket(req, operator, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	transaction.TotalAmount = totalAmount
+	return previewResponse(transaction, nil), nil
+}
 
-	// Save transaction
-	err := s.repo.Create(transaction)
+func (s *transactionService) PreviewCartPricing(req *dto.PricingRequest, operator dto.Operator) (*dto.PricingPreviewResponse, error) {
+	warnings := []dto.PricingWarning{}
+	transaction, err := s.priceBasket(req, operator, &warnings)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %w", err)
+		return nil, err
 	}
 
-	return s.modelToResponse(transaction)
+	return previewResponse(transaction, warnings), nil
+}
+
+func previewResponse(transaction *models.Transaction, warnings []dto.PricingWarning) *dto.PricingPreviewResponse {
+	return &dto.PricingPreviewResponse{
+		Items:                   itemsToResponse(transaction.TransactionItems),
+		GrossAmount:             transaction.GrossAmount,
+		PromotionDiscountAmount: transaction.PromotionDiscountAmount,
+		DiscountAmount:          transaction.DiscountAmount,
+		CartDiscount:            discountToResponse(transaction.CartDiscount),
+		Voucher:                 voucherToResponse(transaction.Voucher),
+		PricesIncludeTax:        transaction.PricesIncludeTax,
+		TaxAmount:               transaction.TaxAmount,
+		TaxBreakdown:            taxBreakdownToResponse(transaction.TaxBreakdown()),
+		TotalAmount:             transaction.TotalAmount,
+		Warnings:                warnings,
+	}
 }
 
 func (s *transactionService) GetAllTransactions(page, limit int, search, sortBy, order string) ([]dto.TransactionResponse, int, error) {
@@ -98,13 +164,7 @@ func (s *transactionService) GetAllTransactions(page, limit int, search, sortBy,
 
 	var responses []dto.TransactionResponse
 	for _, transaction := range transactions {
-		response, err := s.modelToResponseWithFallback(&transaction)
-		if err != nil {
-			log.Printf("Warning: Failed to get complete product details for transaction %d: %v", transaction.ID, err)
-			// Continue with partial data instead of failing completely
-			continue
-		}
-		responses = append(responses, *response)
+		responses = append(responses, *s.modelToResponse(&transaction))
 	}
 
 	return responses, total, nil
@@ -119,104 +179,110 @@ func (s *transactionService) GetTransactionByID(id uint) (*dto.TransactionRespon
 		return nil, err
 	}
 
-	return s.modelToResponseWithFallback(transaction)
+	return s.modelToResponse(transaction), nil
 }
 
-func (s *transactionService) modelToResponse(transaction *models.Transaction) (*dto.TransactionResponse, error) {
-	var items []dto.TransactionItemResponse
-
-	// Collect unique product IDs
-	productIDs := make([]uint, 0, len(transaction.TransactionItems))
-	for _, item := range transaction.TransactionItems {
-		productIDs = append(productIDs, item.ProductID)
+// modelToResponse membangun response dari snapshot yang tersimpan di transaction_items,
+// sehingga struk lama tidak berubah ketika produk diubah atau dihapus.
+func (s *transactionService) modelToResponse(transaction *models.Transaction) *dto.TransactionResponse {
+	response := &dto.TransactionResponse{
+		ID:                      transaction.ID,
+		InvoiceNumber:           transaction.InvoiceNumber,
+		TransactionDate:         transaction.TransactionDate.Format("2006-01-02 15:04:05"),
+		GrossAmount:             transaction.GrossAmount,
+		DiscountAmount:          transaction.DiscountAmount,
+		PromotionDiscountAmount: transaction.PromotionDiscountAmount,
+		CartDiscount:            discountToResponse(transaction.CartDiscount),
+		CustomerID:              transaction.CustomerID,
+		Voucher:                 voucherToResponse(transaction.Voucher),
+		PricesIncludeTax:        transaction.PricesIncludeTax,
+		TaxAmount:               transaction.TaxAmount,
+		TaxBreakdown:            taxBreakdownToResponse(transaction.TaxBreakdown()),
+		TotalAmount:             transaction.TotalAmount,
+		PaidAmount:              transaction.PaidAmount,
+		ChangeAmount:            transaction.ChangeAmount,
+		Status:                  transaction.Status(),
+		TransactionItems:        itemsToResponse(transaction.TransactionItems),
+		Payments:                paymentsToResponse(transaction.Payments),
+		VoidedBy:                transaction.VoidedBy,
+		VoidReason:              transaction.VoidReason,
+		StockPending:            transaction.StockPendingAt != nil,
+		CreatedAt:               transaction.CreatedAt.Format("2006-01-02 15:04:05"),
 	}
-
-	// Fetch product details in batch
-	products, err := s.productClient.GetMultiple(productIDs)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch product details: %w", err)
+	if transaction.VoidedAt != nil {
+		response.VoidedAt = transaction.VoidedAt.Format("2006-01-02 15:04:05")
 	}
 
-	// Build response items with product details
-	for _, item := range transaction.TransactionItems {
-		product, exists := products[item.ProductID]
-		if !exists {
-			return nil, fmt.Errorf("product details not found for product ID %d", item.ProductID)
+	return response
+}
+
+func itemsToResponse(lines []models.TransactionItem) []dto.TransactionItemResponse {
+	items := make([]dto.TransactionItemResponse, 0, len(lines))
+	for _, item := range lines {
+		var promotions []dto.AppliedPromotionResponse
+		for _, promotion := range item.Promotions {
+			promotions = append(promotions, dto.AppliedPromotionResponse{
+				PromotionID:    promotion.PromotionID,
+				PromotionName:  promotion.PromotionName,
+				DiscountAmount: promotion.DiscountAmount,
+			})
 		}
 
 		items = append(items, dto.TransactionItemResponse{
-			ID:          item.ID,
-			ProductID:   item.ProductID,
-			ProductName: product.Name,
-			Price:       product.Price,
-			Quantity:    item.Quantity,
-			Subtotal:    item.Subtotal,
+			ID:                      item.ID,
+			ProductID:               item.ProductID,
+			ProductName:             item.ProductName,
+			ProductSKU:              item.ProductSKU,
+			VariantID:               item.VariantID,
+			VariantName:             item.VariantName,
+			Price:                   item.UnitPrice,
+			Quantity:                item.Quantity,
+			GrossAmount:             item.GrossAmount,
+			PromotionDiscountAmount: item.PromotionDiscountAmount,
+			Promotions:              promotions,
+			LineDiscount:            discountToResponse(item.LineDiscount),
+			DiscountAmount:          item.DiscountAmount,
+			Subtotal:                item.Subtotal,
+			TaxClass:                item.TaxClass,
+			TaxRate:                 item.TaxRate,
+			TaxableAmount:           item.TaxableAmount,
+			TaxAmount:               item.TaxAmount,
+			TotalAmount:             item.TotalAmount,
 		})
 	}
+	return items
+}
 
-	return &dto.TransactionResponse{
-		ID:               transaction.ID,
-		TransactionDate:  transaction.TransactionDate.Format("2006-01-02 15:04:05"),
-		TotalAmount:      transaction.TotalAmount,
-		TransactionItems: items,
-		CreatedAt:        transaction.CreatedAt.Format("2006-01-02 15:04:05"),
-	}, nil
-}
-
-// New method with fallback for deleted products
-func (s *transactionService) modelToResponseWithFallback(transaction *models.Transaction) (*dto.TransactionResponse, error) {
-	var items []dto.TransactionItemResponse
-
-	// Collect unique product IDs
-	productIDs := make([]uint, 0, len(transaction.TransactionItems))
-	for _, item := range transaction.TransactionItems {
-		productIDs = append(productIDs, item.ProductID)
-	}
-
-	// Try to fetch product details
-	products := make(map[uint]*clients.ProductResponse)
-	
-	// Fetch products individually to handle deleted products gracefully
-	for _, productID := range productIDs {
-		product, err := s.productClient.GetByID(productID)
-		if err != nil {
-			// Log warning but continue with fallback data
-			log.Printf("Warning: Could not fetch product %d: %v", productID, err)
-		} else {
-			products[productID] = product
-		}
+func discountToResponse(discount *models.Discount) *dto.DiscountResponse {
+	if discount == nil {
+		return nil
 	}
+	return &dto.DiscountResponse{
+		Type:   discount.Type,
+		Value:  discount.Value,
+		Amount: discount.Amount,
+	}
+}
 
-	// Build response items with fallback for deleted products
-	for _, item := range transaction.TransactionItems {
-		var productName string
-		var productPrice float64
-
-		if product, exists := products[item.ProductID]; exists {
-			// Product still exists, use current data
-			productName = product.Name
-			productPrice = product.Price
-		} else {
-			// Product deleted or unavailable, use fallback
-			productName = fmt.Sprintf("Product ID %d (Deleted)", item.ProductID)
-			productPrice = item.Subtotal / float64(item.Quantity) // Calculate from subtotal
-		}
+func voucherToResponse(voucher *models.VoucherRedemption) *dto.AppliedVoucherResponse {
+	if voucher == nil {
+		return nil
+	}
+	return &dto.AppliedVoucherResponse{
+		Code:           voucher.VoucherCode,
+		DiscountAmount: voucher.DiscountAmount,
+	}
+}
 
-		items = append(items, dto.TransactionItemResponse{
-			ID:          item.ID,
-			ProductID:   item.ProductID,
-			ProductName: productName,
-			Price:       productPrice,
-			Quantity:    item.Quantity,
-			Subtotal:    item.Subtotal,
+func taxBreakdownToResponse(breakdown []models.TaxBreakdown) []dto.TaxBreakdownResponse {
+	responses := make([]dto.TaxBreakdownResponse, 0, len(breakdown))
+	for _, tax := range breakdown {
+		responses = append(responses, dto.TaxBreakdownResponse{
+			TaxClass:      tax.TaxClass,
+			Rate:          tax.Rate,
+			TaxableAmount: tax.TaxableAmount,
+			TaxAmount:     tax.TaxAmount,
 		})
 	}
-
-	return &dto.TransactionResponse{
-		ID:               transaction.ID,
-		TransactionDate:  transaction.TransactionDate.Format("2006-01-02 15:04:05"),
-		TotalAmount:      transaction.TotalAmount,
-		TransactionItems: items,
-		CreatedAt:        transaction.CreatedAt.Format("2006-01-02 15:04:05"),
-	}, nil
-}
\ No newline at end of file
+	return responses
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+	"transaction-service/clients"
+	"transaction-service/models"
+)
+
+// Stok dimiliki product-service dan hanya diubah lewat API-nya. Checkout berjalan sebagai saga:
+// stok ditahan (reserve), transaksi disimpan, lalu reservasi dikonfirmasi; langkah yang gagal dikompensasi.
+// Konfirmasi yang hasilnya tidak diketahui tidak dikompensasi, transaksinya menunggu rekonsiliasi.
+// Void menambah stok lewat restock dengan reference unik, dibatalkan jika penyimpanan lokal gagal. Retur disimpan
+// lebih dulu dengan tanda restock pending; restock-nya diulang oleh rekonsiliasi sampai berhasil.
+
+// operator untuk perubahan yang dilakukan service sendiri, mis. void kompensasi
+const systemOperator = "system"
+
+// jumlah transaksi dan retur yang direkonsiliasi per putaran
+const stockReconcileBatch = 100
+
+func stockItems(lines []models.TransactionItem) []clients.StockItem {
+	items := make([]clients.StockItem, 0, len(lines))
+	for _, line := range lines {
+		item := clients.StockItem{ProductID: line.ProductID, Quantity: line.Quantity}
+		if line.VariantID != nil {
+			item.VariantID = *line.VariantID
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// CartStockReference adalah reference reservasi stok milik keranjang. Reservasi baru dengan reference yang
+// sama menggantikan yang lama dalam satu transaksi di product-service, unit yang sudah ditahan keranjang
+// ikut tersedia untuk penggantinya.
+func CartStockReference(cartID uint) string {
+	return fmt.Sprintf("cart:%d", cartID)
+}
+
+// reserveStock menahan stok semua baris transaksi, all or nothing. reference kosong untuk transaksi langsung,
+// reference keranjang untuk checkout keranjang sehingga reservasi keranjang diganti dengan baris yang dijual.
+func (s *transactionService) reserveStock(transaction *models.Transaction, reference string) error {
+	reservation, err := s.productClient.Reserve(reference, stockItems(transaction.TransactionItems), 0)
+	if err != nil {
+		return err
+	}
+	transaction.StockReservationID = &reservation.ID
+	return nil
+}
+
+// releaseStock melepas reservasi yang tidak jadi dipakai. Kegagalan hanya dicatat,
+// reservasi yang tidak dikonfirmasi tetap kedaluwarsa dengan sendirinya.
+func (s *transactionService) releaseStock(transaction *models.Transaction) {
+	id := *transaction.StockReservationID
+	if _, err := s.productClient.ReleaseReservation(id); err != nil {
+		log.Printf("Warning: failed to release stock reservation %d: %v", id, err)
+	}
+}
+
+// confirmStock mengurangi stok yang sudah ditahan. Transaksi yang sudah tersimpan hanya di-void (tanpa restock)
+// jika product-service memastikan stoknya tidak dikurangi. Jika hasilnya tidak diketahui, transaksi tetap
+// berlaku dan ditandai menunggu rekonsiliasi, lihat StartStockReconciler. Void sistem ini tetap berjalan di hari
+// usaha yang sudah ditutup karena penjualannya memang tidak pernah mengurangi stok.
+func (s *transactionService) confirmStock(transaction *models.Transaction) error {
+	status, err := s.settleReservation(*transaction.StockReservationID)
+	switch status {
+	case clients.ReservationConfirmed:
+		return nil
+	case clients.ReservationReleased, clients.ReservationExpired:
+		if voidErr := s.repo.Void(transaction.ID, systemOperator, "stock confirmation failed", time.Now(), true); voidErr != nil {
+			log.Printf("Warning: failed to void transaction %d after stock confirmation failed: %v", transaction.ID, voidErr)
+		}
+		return fmt.Errorf("failed to confirm stock reservation: %w", err)
+	}
+
+	now := time.Now()
+	log.Printf("Warning: stock reservation %d of transaction %d is pending reconciliation: %v", *transaction.StockReservationID, transaction.ID, err)
+	if markErr := s.repo.MarkStockPending(transaction.ID, now); markErr != nil {
+		log.Printf("Warning: failed to mark transaction %d for stock reconciliation: %v", transaction.ID, markErr)
+	}
+	transaction.StockPendingAt = &now
+	return nil
+}
+
+// settleReservation mengonfirmasi reservasi; jika gagal, reservasi yang mungkin masih ACTIVE dilepas agar
+// hasilnya pasti. Mengembalikan status akhir reservasi (CONFIRMED, RELEASED atau EXPIRED) beserta error
+// konfirmasinya, status kosong jika product-service tidak menjawab dengan pasti.
+func (s *transactionService) settleReservation(id uint) (string, error) {
+	reservation, err := s.productClient.ConfirmReservation(id)
+	if err == nil {
+		return clients.ReservationConfirmed, nil
+	}
+
+	// konfirmasi bisa saja sudah diterapkan walau responsenya hilang, pelepasan lalu ditolak dengan status CONFIRMED
+	if reservation == nil || reservation.Status == clients.ReservationActive {
+		released, releaseErr := s.productClient.ReleaseReservation(id)
+		if released == nil {
+			log.Printf("Warning: failed to release stock reservation %d: %v", id, releaseErr)
+			return "", err
+		}
+		reservation = released
+	}
+	if reservation.Status == clients.ReservationActive {
+		return "", err
+	}
+	return reservation.Status, err
+}
+
+func (s *transactionService) StartStockReconciler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			settled, err := s.ReconcileStock()
+			if err != nil {
+				log.Printf("stock reconciler: %v", err)
+				continue
+			}
+			if settled > 0 {
+				log.Printf("stock reconciler: %d transaction(s) and return(s) settled", settled)
+			}
+		}
+	}()
+}
+
+// ReconcileStock mengulang konfirmasi stok transaksi yang menunggu rekonsiliasi. Reservasi yang ternyata
+// CONFIRMED hanya menghapus tanda pending; yang dilepas atau kedaluwarsa membuat transaksinya di-void oleh system.
+// Sesudahnya restock retur yang masih pending diulang, lihat reconcileReturnRestocks.
+func (s *transactionService) ReconcileStock() (int, error) {
+	transactions, err := s.repo.GetStockPending(stockReconcileBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, transaction := range transactions {
+		if transaction.StockReservationID == nil {
+			continue
+		}
+		status, err := s.settleReservation(*transaction.StockReservationID)
+		switch status {
+		case clients.ReservationConfirmed:
+		case clients.ReservationReleased, clients.ReservationExpired:
+			log.Printf("Warning: stock of transaction %s was not deducted (%v), voiding it", transaction.InvoiceNumber, err)
+			if err := s.repo.Void(transaction.ID, systemOperator, "stock confirmation failed", time.Now(), true); err != nil {
+				log.Printf("Warning: failed to void transaction %d after stock confirmation failed: %v", transaction.ID, err)
+				continue
+			}
+		default:
+			continue
+		}
+
+		if err := s.repo.ClearStockPending(transaction.ID); err != nil {
+			return settled, err
+		}
+		settled++
+	}
+
+	restocked, err := s.reconcileReturnRestocks()
+	return settled + restocked, err
+}
+
+// reconcileReturnRestocks mengulang restock retur yang belum berhasil. Reference-nya sama dengan restock
+// pertama, sehingga restock yang ternyata sudah diterapkan tidak menambah stok lagi.
+func (s *transactionService) reconcileReturnRestocks() (int, error) {
+	returns, err := s.repo.GetRestockPendingReturns(stockReconcileBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	restocked := 0
+	for i := range returns {
+		if err := s.restockReturn(&returns[i]); err != nil {
+			log.Printf("Warning: restock of return %d is still pending: %v", returns[i].ID, err)
+			continue
+		}
+		restocked++
+	}
+	return restocked, nil
+}
+
+// restock mengembalikan barang ke stok. Reference yang sama tidak menambah stok dua kali.
+func (s *transactionService) restock(reference, reason string, items []clients.StockItem) (*clients.RestockResponse, error) {
+	restock, err := s.productClient.Restock(reference, reason, items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restock items: %w", err)
+	}
+	return restock, nil
+}
+
+// cancelRestock mengompensasi restock jika langkah lokal sesudahnya gagal. Restock yang tidak
+// diterapkan oleh request ini (reference sudah ada sebelumnya) dibiarkan.
+func (s *transactionService) cancelRestock(restock *clients.RestockResponse) {
+	if !restock.Applied {
+		return
+	}
+	if err := s.productClient.CancelRestock(restock.ID); err != nil {
+		log.Printf("Warning: failed to cancel restock %d (%s): %v", restock.ID, restock.Reference, err)
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"transaction-service/dto"
+)
+
+func (s *transactionService) VoidTransaction(id uint, voidedBy string, req *dto.VoidTransactionRequest) (*dto.TransactionResponse, error) {
+	voidedBy = strings.TrimSpace(voidedBy)
+	if voidedBy == "" {
+		return nil, errors.New("user is required to void a transaction")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.New("void reason is required")
+	}
+
+	transaction, err := s.repo.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, err
+	}
+	if transaction.VoidedAt != nil {
+		return nil, errors.New("transaction has already been voided")
+	}
+	// restock hanya benar jika stoknya sudah pasti dikurangi
+	if transaction.StockPendingAt != nil {
+		return nil, errors.New("transaction stock confirmation is still pending, try again later")
+	}
+
+	now := time.Now()
+	if s.options.VoidWindow > 0 && now.Sub(transaction.TransactionDate) > s.options.VoidWindow {
+		return nil, fmt.Errorf("void window of %s has passed for this transaction", s.options.VoidWindow)
+	}
+
+	// transaksi di hari usaha yang sudah ditutup tidak boleh diubah. Ini hanya penolakan awal sebelum restock,
+	// pemeriksaan yang menentukan dilakukan lagi di dalam tx repo.Void
+	closed, err := s.businessDayRepo.IsClosed(transaction.TransactionDate.In(time.Local))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check business day: %w", err)
+	}
+	if closed {
+		return nil, fmt.Errorf("business day %s has been closed", transaction.TransactionDate.In(time.Local).Format("2006-01-02"))
+	}
+
+	// reference per transaksi: void yang diulang tidak mengembalikan stok dua kali
+	restock, err := s.restock(fmt.Sprintf("transaction:%d:void", id), "VOID", stockItems(transaction.TransactionItems))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Void(id, voidedBy, reason, now, false); err != nil {
+		s.cancelRestock(restock)
+		return nil, err
+	}
+
+	return s.GetTransactionByID(id)
+}
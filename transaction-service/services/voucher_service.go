@@ -0,0 +1,298 @@
+package services
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+	"transaction-service/dto"
+	"transaction-service/models"
+	"transaction-service/money"
+	"transaction-service/repositories"
+)
+
+type VoucherService interface {
+	CreateVoucher(req *dto.CreateVoucherRequest) (*dto.VoucherResponse, error)
+	CreateVoucherBatch(req *dto.CreateVoucherBatchRequest) (*dto.VoucherBatchResponse, error)
+	GetAllVouchers(batchCode string) ([]dto.VoucherResponse, error)
+	GetVoucherByCode(code string) (*dto.VoucherResponse, error)
+	DeactivateVoucher(code string) (*dto.VoucherResponse, error)
+	GetRedemptions(code string) ([]dto.VoucherRedemptionResponse, error)
+}
+
+// karakter kode voucher, tanpa 0/O dan 1/I agar tidak tertukar saat diketik kasir
+const voucherCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+const (
+	voucherCodeLength = 8
+	// percobaan ulang jika kode acak bentrok dengan kode yang sudah ada
+	voucherCodeAttempts = 3
+)
+
+type voucherService struct {
+	repo repositories.VoucherRepository
+}
+
+func NewVoucherService(repo repositories.VoucherRepository) VoucherService {
+	return &voucherService{repo: repo}
+}
+
+func (s *voucherService) CreateVoucher(req *dto.CreateVoucherRequest) (*dto.VoucherResponse, error) {
+	voucher, err := s.ruleToModel(&req.VoucherRuleRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if code != "" {
+		voucher.Code = code
+		if err := s.repo.Create(voucher); err != nil {
+			if err == repositories.ErrVoucherCodeExists {
+				return nil, fmt.Errorf("voucher code %s already exists", code)
+			}
+			return nil, fmt.Errorf("failed to create voucher: %w", err)
+		}
+		return s.modelToResponse(voucher), nil
+	}
+
+	for attempt := 0; attempt < voucherCodeAttempts; attempt++ {
+		voucher.Code, err = generateVoucherCode("")
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.repo.Create(voucher)
+		if err == nil {
+			return s.modelToResponse(voucher), nil
+		}
+		if err != repositories.ErrVoucherCodeExists {
+			return nil, fmt.Errorf("failed to create voucher: %w", err)
+		}
+	}
+
+	return nil, errors.New("failed to create voucher: could not generate a unique code")
+}
+
+func (s *voucherService) CreateVoucherBatch(req *dto.CreateVoucherBatchRequest) (*dto.VoucherBatchResponse, error) {
+	template, err := s.ruleToModel(&req.VoucherRuleRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := strings.ToUpper(strings.TrimSpace(req.Prefix))
+	if strings.ContainsAny(prefix, " \t") {
+		return nil, errors.New("invalid prefix, spaces are not allowed")
+	}
+
+	for attempt := 0; attempt < voucherCodeAttempts; attempt++ {
+		batchCode, err := generateVoucherCode("BATCH")
+		if err != nil {
+			return nil, err
+		}
+
+		vouchers := make([]models.Voucher, 0, req.Count)
+		seen := make(map[string]bool, req.Count)
+		for len(vouchers) < req.Count {
+			code, err := generateVoucherCode(prefix)
+			if err != nil {
+				return nil, err
+			}
+			if seen[code] {
+				continue
+			}
+			seen[code] = true
+
+			voucher := *template
+			voucher.Code = code
+			voucher.BatchCode = batchCode
+			vouchers = append(vouchers, voucher)
+		}
+
+		err = s.repo.CreateBatch(vouchers)
+		if err == repositories.ErrVoucherCodeExists {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create voucher batch: %w", err)
+		}
+
+		response := &dto.VoucherBatchResponse{
+			BatchCode: batchCode,
+			Vouchers:  make([]dto.VoucherResponse, 0, len(vouchers)),
+		}
+		for i := range vouchers {
+			response.Vouchers = append(response.Vouchers, *s.modelToResponse(&vouchers[i]))
+		}
+		return response, nil
+	}
+
+	return nil, errors.New("failed to create voucher batch: could not generate unique codes")
+}
+
+func (s *voucherService) GetAllVouchers(batchCode string) ([]dto.VoucherResponse, error) {
+	vouchers, err := s.repo.GetAll(strings.ToUpper(strings.TrimSpace(batchCode)))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.VoucherResponse, 0, len(vouchers))
+	for i := range vouchers {
+		responses = append(responses, *s.modelToResponse(&vouchers[i]))
+	}
+
+	return responses, nil
+}
+
+func (s *voucherService) GetVoucherByCode(code string) (*dto.VoucherResponse, error) {
+	voucher, err := s.getVoucher(code)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.modelToResponse(voucher), nil
+}
+
+func (s *voucherService) DeactivateVoucher(code string) (*dto.VoucherResponse, error) {
+	voucher, err := s.getVoucher(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Deactivate(voucher.Code); err != nil {
+		return nil, fmt.Errorf("failed to deactivate voucher: %w", err)
+	}
+	voucher.Active = false
+
+	return s.modelToResponse(voucher), nil
+}
+
+func (s *voucherService) GetRedemptions(code string) ([]dto.VoucherRedemptionResponse, error) {
+	voucher, err := s.getVoucher(code)
+	if err != nil {
+		return nil, err
+	}
+
+	redemptions, err := s.repo.GetRedemptions(voucher.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.VoucherRedemptionResponse, 0, len(redemptions))
+	for _, redemption := range redemptions {
+		response := dto.VoucherRedemptionResponse{
+			ID:             redemption.ID,
+			TransactionID:  redemption.TransactionID,
+			CustomerID:     redemption.CustomerID,
+			DiscountAmount: redemption.DiscountAmount,
+			RedeemedAt:     redemption.RedeemedAt.Format("2006-01-02 15:04:05"),
+		}
+		if redemption.ReleasedAt != nil {
+			response.ReleasedAt = redemption.ReleasedAt.Format("2006-01-02 15:04:05")
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+func (s *voucherService) getVoucher(code string) (*models.Voucher, error) {
+	voucher, err := s.repo.GetByCode(code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("voucher not found")
+		}
+		return nil, err
+	}
+	return voucher, nil
+}
+
+func (s *voucherService) ruleToModel(req *dto.VoucherRuleRequest) (*models.Voucher, error) {
+	voucher := &models.Voucher{
+		DiscountType:      strings.ToUpper(strings.TrimSpace(req.DiscountType)),
+		Value:             req.Value,
+		MaxDiscountAmount: req.MaxDiscountAmount,
+		MinSpend:          req.MinSpend,
+		UsageLimit:        1,
+		PerCustomerLimit:  req.PerCustomerLimit,
+		Active:            true,
+	}
+	if req.UsageLimit != nil {
+		voucher.UsageLimit = *req.UsageLimit
+	}
+
+	switch voucher.DiscountType {
+	case models.DiscountTypePercentage:
+		if money.Percent(voucher.Value) > money.Hundred {
+			return nil, errors.New("percentage voucher value cannot exceed 100")
+		}
+	case models.DiscountTypeFixed:
+		if voucher.MaxDiscountAmount > 0 {
+			return nil, errors.New("max_discount_amount only applies to PERCENTAGE vouchers")
+		}
+	default:
+		return nil, fmt.Errorf("discount_type must be %s or %s", models.DiscountTypePercentage, models.DiscountTypeFixed)
+	}
+	if voucher.Value <= 0 {
+		return nil, errors.New("voucher value must be greater than 0")
+	}
+	if voucher.UsageLimit < 0 || voucher.PerCustomerLimit < 0 {
+		return nil, errors.New("usage limits cannot be negative")
+	}
+
+	if req.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return nil, errors.New("invalid expires_at, expected RFC3339 format")
+		}
+		if !expiresAt.After(time.Now()) {
+			return nil, errors.New("invalid expires_at, must be in the future")
+		}
+		voucher.ExpiresAt = &expiresAt
+	}
+
+	return voucher, nil
+}
+
+func (s *voucherService) modelToResponse(voucher *models.Voucher) *dto.VoucherResponse {
+	response := &dto.VoucherResponse{
+		ID:                voucher.ID,
+		Code:              voucher.Code,
+		BatchCode:         voucher.BatchCode,
+		DiscountType:      voucher.DiscountType,
+		Value:             voucher.Value,
+		MaxDiscountAmount: voucher.MaxDiscountAmount,
+		MinSpend:          voucher.MinSpend,
+		UsageLimit:        voucher.UsageLimit,
+		PerCustomerLimit:  voucher.PerCustomerLimit,
+		UsedCount:         voucher.UsedCount,
+		Active:            voucher.Active,
+		CreatedAt:         voucher.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if voucher.ExpiresAt != nil {
+		response.ExpiresAt = voucher.ExpiresAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+// generateVoucherCode membuat kode acak, dengan awalan "PREFIX-" jika prefix diisi
+func generateVoucherCode(prefix string) (string, error) {
+	var sb strings.Builder
+	if prefix != "" {
+		sb.WriteString(prefix)
+		sb.WriteByte('-')
+	}
+
+	max := big.NewInt(int64(len(voucherCodeAlphabet)))
+	for i := 0; i < voucherCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate voucher code: %w", err)
+		}
+		sb.WriteByte(voucherCodeAlphabet[n.Int64()])
+	}
+
+	return sb.String(), nil
+}